@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"github.com/gin-gonic/gin"
+	"log"
 	"net/http"
 	"time"
 )
@@ -25,6 +26,15 @@ func main() {
 
 	//Start the server
 	router := gin.Default()
+
+	// Apply CORS, security headers and the request size limit to every route
+	security, err := securityConfigFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	router.Use(securityHeadersMiddleware(security))
+	router.Use(corsMiddleware(security))
+	router.Use(maxBodySizeMiddleware(security))
 	router.GET("/ping", func(ctx *gin.Context) {
 		ctx.JSON(200, gin.H{
 			"message": "pong",
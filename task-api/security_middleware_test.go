@@ -0,0 +1,87 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newSecureRouter returns a router with the security middleware in front of
+// an empty route, in the order main applies it
+func newSecureRouter(cfg securityConfig) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(securityHeadersMiddleware(cfg), corsMiddleware(cfg), maxBodySizeMiddleware(cfg))
+	r.POST("/tasks", func(c *gin.Context) {
+		c.Status(http.StatusCreated)
+	})
+	return r
+}
+
+func serve(r *gin.Engine, req *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestCORS(t *testing.T) {
+	r := newSecureRouter(securityConfig{AllowedOrigins: []string{"https://app.example.com"}, AllowCredentials: true})
+
+	preflight := httptest.NewRequest(http.MethodOptions, "/tasks", nil)
+	preflight.Header.Set("Origin", "https://app.example.com")
+	preflight.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	w := serve(r, preflight)
+	if w.Code != http.StatusNoContent || w.Header().Get("Access-Control-Allow-Origin") != "https://app.example.com" || w.Header().Get("Access-Control-Allow-Credentials") != "true" {
+		t.Errorf("allowed preflight: status %d, headers %v", w.Code, w.Header())
+	}
+
+	preflight.Header.Set("Origin", "https://evil.example.com")
+	if w := serve(r, preflight); w.Code != http.StatusForbidden {
+		t.Errorf("other origin preflight status = %d, want %d", w.Code, http.StatusForbidden)
+	}
+
+	// Any origin gets the wildcard, never its own origin
+	r = newSecureRouter(securityConfig{AllowedOrigins: []string{"*"}})
+	req := httptest.NewRequest(http.MethodPost, "/tasks", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	if got := serve(r, req).Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("Access-Control-Allow-Origin = %q, want *", got)
+	}
+}
+
+func TestMaxBodySize(t *testing.T) {
+	w := serve(newSecureRouter(securityConfig{MaxBodyBytes: 16}), httptest.NewRequest(http.MethodPost, "/tasks", strings.NewReader(strings.Repeat("a", 17))))
+	if w.Code != http.StatusRequestEntityTooLarge || w.Header().Get("Content-Type") != "application/problem+json" {
+		t.Errorf("large body: status %d, content type %q", w.Code, w.Header().Get("Content-Type"))
+	}
+}
+
+func TestSecurityConfigFromEnv(t *testing.T) {
+	t.Setenv("CORS_ALLOWED_ORIGINS", " https://a.example.com ,, https://b.example.com")
+	t.Setenv("CORS_ALLOW_CREDENTIALS", "true")
+	cfg, err := securityConfigFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.AllowedOrigins) != 2 || cfg.AllowedOrigins[0] != "https://a.example.com" || cfg.AllowedOrigins[1] != "https://b.example.com" || !cfg.AllowCredentials {
+		t.Errorf("AllowedOrigins = %q, AllowCredentials = %v", cfg.AllowedOrigins, cfg.AllowCredentials)
+	}
+
+	invalid := map[string]map[string]string{
+		"credentials for any origin": {"CORS_ALLOWED_ORIGINS": "*"},
+		"invalid body limit":         {"MAX_BODY_BYTES": "0"},
+	}
+	for name, env := range invalid {
+		t.Run(name, func(t *testing.T) {
+			for key, value := range env {
+				t.Setenv(key, value)
+			}
+			if _, err := securityConfigFromEnv(); err == nil {
+				t.Error("no error")
+			}
+		})
+	}
+}
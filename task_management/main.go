@@ -1,12 +1,20 @@
 package main
 
 import (
+	"log"
+	"task_management/middleware"
 	"task_management/router"
 )
 
 func main() {
+	// Read the CORS, security header and request size settings
+	security, err := middleware.SecurityConfigFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	// Set up the router using the SetupRouter function from the router package
-	r := router.SetupRouter()
+	r := router.SetupRouter(security)
 
 	// Run the Gin router on the default port 8080
 	r.Run(":8080")
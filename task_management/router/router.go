@@ -3,14 +3,20 @@ package router
 import (
 	"task_management/controllers"
 	"task_management/data"
+	"task_management/middleware"
 
 	"github.com/gin-gonic/gin"
 )
 
 // SetupRouter sets up the Gin router with task management routes and returns the router instance
-func SetupRouter() *gin.Engine {
+func SetupRouter(security middleware.SecurityConfig) *gin.Engine {
 	r := gin.Default()
 
+	// Apply CORS, security headers and the request size limit to every route
+	r.Use(middleware.SecurityHeadersMiddleware(security))
+	r.Use(middleware.CORSMiddleware(security))
+	r.Use(middleware.MaxBodySizeMiddleware(security))
+
 	// Initialize the TaskService
 	service := data.NewTaskService()
 
@@ -4,6 +4,7 @@ import (
 	"log"
 	"os"
 	"task_management_wz_mongodb/database"
+	"task_management_wz_mongodb/middleware"
 	"task_management_wz_mongodb/router"
 
 	"github.com/joho/godotenv"
@@ -16,6 +17,12 @@ func main() {
 		log.Fatal("Error loading .env file")
 	}
 
+	// Read the CORS, security header and request size settings
+	security, err := middleware.SecurityConfigFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	// Connect to the MongoDB database
 	uri := os.Getenv("MONGO_DB_URI")
 	client, err := database.ConnectToMongoDB(uri)
@@ -28,6 +35,6 @@ func main() {
 
 
 	// Set up the router and start the application
-	r := router.SetupRouter(client)
+	r := router.SetupRouter(client, security)
 	r.Run(":8080")
 }
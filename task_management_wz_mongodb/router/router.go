@@ -3,15 +3,21 @@ package router
 import (
 	"task_management_wz_mongodb/controllers"
 	"task_management_wz_mongodb/data"
+	"task_management_wz_mongodb/middleware"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
 
-func SetupRouter(client *mongo.Client) *gin.Engine {
+func SetupRouter(client *mongo.Client, security middleware.SecurityConfig) *gin.Engine {
 	r := gin.Default()
 
+	// Apply CORS, security headers and the request size limit to every route
+	r.Use(middleware.SecurityHeadersMiddleware(security))
+	r.Use(middleware.CORSMiddleware(security))
+	r.Use(middleware.MaxBodySizeMiddleware(security))
+
 	// Initialize the TaskService
 	service := data.NewTaskService(client.Database("taskdb").Collection("tasks"))
 
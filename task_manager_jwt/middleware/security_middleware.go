@@ -0,0 +1,205 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SecurityConfig configures CORS, the security headers and the request size limit.
+type SecurityConfig struct {
+	// AllowedOrigins lists the browser origins allowed to call the API; "*" allows any
+	AllowedOrigins []string
+	// AllowCredentials lets the listed origins send cookies. It cannot be
+	// combined with "*".
+	AllowCredentials bool
+	// CORSMaxAge is how long browsers may cache preflight responses
+	CORSMaxAge time.Duration
+	// HSTSMaxAge is sent in Strict-Transport-Security; zero disables HSTS
+	HSTSMaxAge            time.Duration
+	ContentSecurityPolicy string
+	// MaxBodyBytes is the largest request body accepted
+	MaxBodyBytes int64
+}
+
+// SecurityConfigFromEnv reads the security settings from CORS_ALLOWED_ORIGINS
+// (comma-separated), CORS_ALLOW_CREDENTIALS, CORS_MAX_AGE, HSTS_MAX_AGE,
+// CONTENT_SECURITY_POLICY and MAX_BODY_BYTES. Unset variables keep their
+// defaults; invalid ones are an error.
+func SecurityConfigFromEnv() (SecurityConfig, error) {
+	cfg := SecurityConfig{
+		CORSMaxAge:            10 * time.Minute,
+		HSTSMaxAge:            180 * 24 * time.Hour,
+		ContentSecurityPolicy: "default-src 'none'; frame-ancestors 'none'",
+		MaxBodyBytes:          1 << 20,
+	}
+
+	for _, origin := range strings.Split(os.Getenv("CORS_ALLOWED_ORIGINS"), ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			cfg.AllowedOrigins = append(cfg.AllowedOrigins, origin)
+		}
+	}
+	if value := os.Getenv("CORS_ALLOW_CREDENTIALS"); value != "" {
+		allow, err := strconv.ParseBool(value)
+		if err != nil {
+			return cfg, fmt.Errorf("CORS_ALLOW_CREDENTIALS: %w", err)
+		}
+		cfg.AllowCredentials = allow
+	}
+	for key, target := range map[string]*time.Duration{
+		"CORS_MAX_AGE": &cfg.CORSMaxAge,
+		"HSTS_MAX_AGE": &cfg.HSTSMaxAge,
+	} {
+		if value := os.Getenv(key); value != "" {
+			duration, err := time.ParseDuration(value)
+			if err != nil || duration < 0 {
+				return cfg, fmt.Errorf("%s must be a duration such as \"10m\", got %q", key, value)
+			}
+			*target = duration
+		}
+	}
+	if csp := os.Getenv("CONTENT_SECURITY_POLICY"); csp != "" {
+		cfg.ContentSecurityPolicy = csp
+	}
+	if value := os.Getenv("MAX_BODY_BYTES"); value != "" {
+		limit, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || limit <= 0 {
+			return cfg, fmt.Errorf("MAX_BODY_BYTES must be a positive number of bytes, got %q", value)
+		}
+		cfg.MaxBodyBytes = limit
+	}
+
+	// Allowing credentials for any origin would let every site act for the user
+	for _, origin := range cfg.AllowedOrigins {
+		if origin == "*" && cfg.AllowCredentials {
+			return cfg, errors.New("CORS credentials cannot be allowed for any origin (*)")
+		}
+	}
+	return cfg, nil
+}
+
+// CORSMiddleware adds the CORS headers for requests from an allowed origin and
+// answers preflight requests itself. Preflight requests from other origins are
+// rejected with 403.
+func CORSMiddleware(cfg SecurityConfig) gin.HandlerFunc {
+	allowed := make(map[string]bool, len(cfg.AllowedOrigins))
+	for _, origin := range cfg.AllowedOrigins {
+		allowed[strings.TrimSuffix(origin, "/")] = true
+	}
+	allowAny := allowed["*"]
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if origin == "" {
+			c.Next()
+			return
+		}
+
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+		c.Writer.Header().Add("Vary", "Origin")
+
+		if !allowAny && !allowed[origin] {
+			if preflight {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			c.Next()
+			return
+		}
+
+		// Any origin gets the wildcard, which browsers never send credentials to
+		if allowAny {
+			c.Header("Access-Control-Allow-Origin", "*")
+		} else {
+			c.Header("Access-Control-Allow-Origin", origin)
+			if cfg.AllowCredentials {
+				c.Header("Access-Control-Allow-Credentials", "true")
+			}
+		}
+
+		if !preflight {
+			c.Next()
+			return
+		}
+
+		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE")
+		// Tokens are sent in the Authorization header, so it must be allowed
+		c.Header("Access-Control-Allow-Headers", "Authorization, Content-Type")
+		if cfg.CORSMaxAge > 0 {
+			c.Header("Access-Control-Max-Age", strconv.Itoa(int(cfg.CORSMaxAge.Seconds())))
+		}
+		c.AbortWithStatus(http.StatusNoContent)
+	}
+}
+
+// SecurityHeadersMiddleware adds HSTS, CSP and the headers that stop browsers
+// from sniffing content types or framing responses.
+func SecurityHeadersMiddleware(cfg SecurityConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.Writer.Header()
+		header.Set("X-Content-Type-Options", "nosniff")
+		header.Set("X-Frame-Options", "DENY")
+		header.Set("Referrer-Policy", "no-referrer")
+		header.Set("Content-Security-Policy", cfg.ContentSecurityPolicy)
+		if cfg.HSTSMaxAge > 0 {
+			header.Set("Strict-Transport-Security", "max-age="+strconv.Itoa(int(cfg.HSTSMaxAge.Seconds()))+"; includeSubDomains")
+		}
+		c.Next()
+	}
+}
+
+// MaxBodySizeMiddleware rejects requests whose body is larger than
+// cfg.MaxBodyBytes with a 413 problem details response.
+func MaxBodySizeMiddleware(cfg SecurityConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+
+		if c.Request.ContentLength > cfg.MaxBodyBytes {
+			abortBodyTooLarge(c, cfg.MaxBodyBytes)
+			return
+		}
+		if c.Request.ContentLength >= 0 {
+			c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, cfg.MaxBodyBytes)
+			c.Next()
+			return
+		}
+
+		// Read bodies of unknown length up front so handlers never see a truncated one
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, cfg.MaxBodyBytes))
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			abortBodyTooLarge(c, cfg.MaxBodyBytes)
+			return
+		}
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "failed to read request body"})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		c.Next()
+	}
+}
+
+func abortBodyTooLarge(c *gin.Context, limit int64) {
+	body, _ := json.Marshal(gin.H{
+		"type":   "about:blank",
+		"title":  http.StatusText(http.StatusRequestEntityTooLarge),
+		"status": http.StatusRequestEntityTooLarge,
+		"detail": fmt.Sprintf("request body must not exceed %d bytes", limit),
+	})
+	c.Header("Connection", "close")
+	c.Data(http.StatusRequestEntityTooLarge, "application/problem+json", body)
+	c.Abort()
+}
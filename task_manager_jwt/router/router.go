@@ -9,10 +9,15 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
-// SetupRouter initializes the Gin router with the given MongoDB client.
-func SetupRouter(client *mongo.Client) *gin.Engine {
+// SetupRouter initializes the Gin router with the given MongoDB client and security settings.
+func SetupRouter(client *mongo.Client, security middleware.SecurityConfig) *gin.Engine {
 	r := gin.Default()
 
+	// Apply CORS, security headers and the request size limit to every route
+	r.Use(middleware.SecurityHeadersMiddleware(security))
+	r.Use(middleware.CORSMiddleware(security))
+	r.Use(middleware.MaxBodySizeMiddleware(security))
+
 	// Initialize the user service.
 	userService := data.NewUserService(client, "taskdb", "users")
 	userController := controllers.NewUserController(userService)
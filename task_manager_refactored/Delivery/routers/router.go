@@ -7,9 +7,14 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
-func SetupRouter(client *mongo.Client) *gin.Engine {
+func SetupRouter(client *mongo.Client, security infrastructure.SecurityConfig) *gin.Engine {
 	r := gin.Default()
 
+	// Apply CORS, security headers and the request size limit to every route
+	r.Use(infrastructure.SecurityHeadersMiddleware(security.Headers))
+	r.Use(infrastructure.CORSMiddleware(security.CORS))
+	r.Use(infrastructure.MaxBodySizeMiddleware(security.MaxBodyBytes))
+
 	publicRouter := r.Group("/")
 
 	NewPublicTaskRouter(client,"taskdb",publicRouter)
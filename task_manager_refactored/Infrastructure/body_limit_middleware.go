@@ -0,0 +1,60 @@
+package infrastructure
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MaxBodySizeMiddleware rejects requests whose body is larger than limit bytes
+// with a 413 problem details response. Bodies without a Content-Length are
+// read up to the limit first, so handlers never see a truncated body.
+func MaxBodySizeMiddleware(limit int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+
+		if c.Request.ContentLength > limit {
+			abortBodyTooLarge(c, limit)
+			return
+		}
+		if c.Request.ContentLength >= 0 {
+			c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, limit))
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			abortBodyTooLarge(c, limit)
+			return
+		}
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "failed to read request body"})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		c.Next()
+	}
+}
+
+func abortBodyTooLarge(c *gin.Context, limit int64) {
+	body, _ := json.Marshal(gin.H{
+		"type":   "about:blank",
+		"title":  http.StatusText(http.StatusRequestEntityTooLarge),
+		"status": http.StatusRequestEntityTooLarge,
+		"detail": fmt.Sprintf("request body must not exceed %d bytes", limit),
+	})
+	// The rest of the body is not read, so the connection cannot be reused
+	c.Header("Connection", "close")
+	c.Data(http.StatusRequestEntityTooLarge, "application/problem+json", body)
+	c.Abort()
+}
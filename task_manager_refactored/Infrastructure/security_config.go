@@ -0,0 +1,76 @@
+package infrastructure
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SecurityConfig holds the settings of the CORS, security header and body
+// size middleware.
+type SecurityConfig struct {
+	CORS         CORSOptions
+	Headers      SecurityHeadersOptions
+	MaxBodyBytes int64
+}
+
+// SecurityConfigFromEnv reads the security settings from CORS_ALLOWED_ORIGINS
+// (comma-separated), CORS_ALLOW_CREDENTIALS, CORS_MAX_AGE, HSTS_MAX_AGE,
+// CONTENT_SECURITY_POLICY and MAX_BODY_BYTES. Unset variables keep their
+// defaults; invalid ones are an error.
+func SecurityConfigFromEnv() (SecurityConfig, error) {
+	cfg := SecurityConfig{
+		CORS: CORSOptions{MaxAge: 10 * time.Minute},
+		Headers: SecurityHeadersOptions{
+			HSTSMaxAge:            180 * 24 * time.Hour,
+			ContentSecurityPolicy: "default-src 'none'; frame-ancestors 'none'",
+		},
+		MaxBodyBytes: 1 << 20,
+	}
+
+	for _, origin := range strings.Split(os.Getenv("CORS_ALLOWED_ORIGINS"), ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			cfg.CORS.AllowedOrigins = append(cfg.CORS.AllowedOrigins, origin)
+		}
+	}
+	if value := os.Getenv("CORS_ALLOW_CREDENTIALS"); value != "" {
+		allow, err := strconv.ParseBool(value)
+		if err != nil {
+			return cfg, fmt.Errorf("CORS_ALLOW_CREDENTIALS: %w", err)
+		}
+		cfg.CORS.AllowCredentials = allow
+	}
+	for key, target := range map[string]*time.Duration{
+		"CORS_MAX_AGE": &cfg.CORS.MaxAge,
+		"HSTS_MAX_AGE": &cfg.Headers.HSTSMaxAge,
+	} {
+		if value := os.Getenv(key); value != "" {
+			duration, err := time.ParseDuration(value)
+			if err != nil || duration < 0 {
+				return cfg, fmt.Errorf("%s must be a duration such as \"10m\", got %q", key, value)
+			}
+			*target = duration
+		}
+	}
+	if csp := os.Getenv("CONTENT_SECURITY_POLICY"); csp != "" {
+		cfg.Headers.ContentSecurityPolicy = csp
+	}
+	if value := os.Getenv("MAX_BODY_BYTES"); value != "" {
+		limit, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || limit <= 0 {
+			return cfg, fmt.Errorf("MAX_BODY_BYTES must be a positive number of bytes, got %q", value)
+		}
+		cfg.MaxBodyBytes = limit
+	}
+
+	// Allowing credentials for any origin would let every site act for the user
+	for _, origin := range cfg.CORS.AllowedOrigins {
+		if origin == "*" && cfg.CORS.AllowCredentials {
+			return cfg, errors.New("CORS credentials cannot be allowed for any origin (*)")
+		}
+	}
+	return cfg, nil
+}
@@ -0,0 +1,84 @@
+package infrastructure
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func serve(middleware gin.HandlerFunc, req *http.Request) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware)
+	r.POST("/tasks", func(c *gin.Context) {
+		c.Status(http.StatusCreated)
+	})
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestCORSMiddleware(t *testing.T) {
+	cors := CORSMiddleware(CORSOptions{AllowedOrigins: []string{"https://app.example.com"}, AllowCredentials: true})
+
+	preflight := httptest.NewRequest(http.MethodOptions, "/tasks", nil)
+	preflight.Header.Set("Origin", "https://app.example.com")
+	preflight.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	preflight.Header.Set("Access-Control-Request-Headers", "Authorization")
+	w := serve(cors, preflight)
+	if w.Code != http.StatusNoContent || w.Header().Get("Access-Control-Allow-Origin") != "https://app.example.com" || w.Header().Get("Access-Control-Allow-Credentials") != "true" {
+		t.Errorf("allowed preflight: status %d, headers %v", w.Code, w.Header())
+	}
+	if got := w.Header().Get("Access-Control-Allow-Headers"); !strings.Contains(got, "Authorization") {
+		t.Errorf("Access-Control-Allow-Headers = %q, want Authorization allowed", got)
+	}
+
+	preflight.Header.Set("Origin", "https://evil.example.com")
+	if w := serve(cors, preflight); w.Code != http.StatusForbidden {
+		t.Errorf("other origin preflight status = %d, want %d", w.Code, http.StatusForbidden)
+	}
+
+	// Any origin gets the wildcard, never its own origin
+	req := httptest.NewRequest(http.MethodPost, "/tasks", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	if got := serve(CORSMiddleware(CORSOptions{AllowedOrigins: []string{"*"}}), req).Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("Access-Control-Allow-Origin = %q, want *", got)
+	}
+}
+
+func TestMaxBodySizeMiddleware(t *testing.T) {
+	w := serve(MaxBodySizeMiddleware(16), httptest.NewRequest(http.MethodPost, "/tasks", strings.NewReader(strings.Repeat("a", 17))))
+	if w.Code != http.StatusRequestEntityTooLarge || w.Header().Get("Content-Type") != "application/problem+json" {
+		t.Errorf("large body: status %d, content type %q", w.Code, w.Header().Get("Content-Type"))
+	}
+}
+
+func TestSecurityConfigFromEnv(t *testing.T) {
+	t.Setenv("CORS_ALLOWED_ORIGINS", " https://a.example.com ,, https://b.example.com")
+	t.Setenv("CORS_ALLOW_CREDENTIALS", "true")
+	cfg, err := SecurityConfigFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.CORS.AllowedOrigins) != 2 || cfg.CORS.AllowedOrigins[0] != "https://a.example.com" || cfg.CORS.AllowedOrigins[1] != "https://b.example.com" || !cfg.CORS.AllowCredentials {
+		t.Errorf("CORS = %+v", cfg.CORS)
+	}
+
+	invalid := map[string]map[string]string{
+		"credentials for any origin": {"CORS_ALLOWED_ORIGINS": "*"},
+		"invalid body limit":         {"MAX_BODY_BYTES": "0"},
+	}
+	for name, env := range invalid {
+		t.Run(name, func(t *testing.T) {
+			for key, value := range env {
+				t.Setenv(key, value)
+			}
+			if _, err := SecurityConfigFromEnv(); err == nil {
+				t.Error("no error")
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"log"
 	"os"
 	"task_manager_refactored/Delivery/routers"
+	infrastructure "task_manager_refactored/Infrastructure"
 	"task_manager_refactored/config/database"
 	"github.com/joho/godotenv"
 )
@@ -15,6 +16,12 @@ func main() {
 		log.Fatal("Error loading .env file")
 	}
 	
+	// Read the CORS, security header and request size settings
+	security, err := infrastructure.SecurityConfigFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	// Connect to the MongoDB database
 	uri := os.Getenv("MONGO_DB_URI")
 	client, err := database.ConnectToMongoDB(uri)
@@ -30,6 +37,6 @@ func main() {
 
 
 	// Set up the router and start the application
-	r := routers.SetupRouter(client)
+	r := routers.SetupRouter(client, security)
 	r.Run(":8080")
 }
@@ -0,0 +1,199 @@
+package controllers
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	infrastructure "task_manager_testing/Infrastructure"
+	"task_manager_testing/domain"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// attachmentField is the multipart form field carrying the uploaded file.
+const attachmentField = "file"
+
+// AttachmentController handles the files attached to tasks.
+type AttachmentController struct {
+	AttachmentUsecase domain.AttachmentUsecase
+}
+
+// NewAttachmentController initializes a new AttachmentController.
+func NewAttachmentController(attachmentUsecase domain.AttachmentUsecase) *AttachmentController {
+	return &AttachmentController{AttachmentUsecase: attachmentUsecase}
+}
+
+// UploadAttachment stores the file sent in the "file" field of a
+// multipart/form-data request. The file is streamed to storage without being
+// buffered.
+func (ac *AttachmentController) UploadAttachment(c *gin.Context) {
+	actor, taskId, ok := ac.attachmentParams(c)
+	if !ok {
+		return
+	}
+
+	reader, err := c.Request.MultipartReader()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid upload. Please send the file as multipart/form-data."))
+		return
+	}
+	for {
+		part, err := reader.NextPart()
+		if ac.writeUploadError(c, err) {
+			return
+		}
+		if part.FormName() != attachmentField || part.FileName() == "" {
+			part.Close()
+			continue
+		}
+
+		attachment, err := ac.AttachmentUsecase.Upload(c.Request.Context(), actor, taskId, part.FileName(), part)
+		part.Close()
+		if ac.writeError(c, err, "Failed to store the attachment. Please try again later.") {
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"message": "Attachment uploaded successfully.", "attachment": attachment})
+		return
+	}
+}
+
+// GetAttachments lists the attachments of a task.
+func (ac *AttachmentController) GetAttachments(c *gin.Context) {
+	actor, taskId, ok := ac.attachmentParams(c)
+	if !ok {
+		return
+	}
+
+	attachments, err := ac.AttachmentUsecase.GetAttachments(c.Request.Context(), actor, taskId)
+	if ac.writeError(c, err, "Failed to retrieve attachments. Please try again later.") {
+		return
+	}
+	if attachments == nil {
+		attachments = []domain.Attachment{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Attachments retrieved successfully.", "attachments": attachments})
+}
+
+// DownloadAttachment sends the content of an attachment once it matches its
+// checksum. The checksum is also sent in the Content-Digest header so clients
+// can verify what they received.
+func (ac *AttachmentController) DownloadAttachment(c *gin.Context) {
+	actor, taskId, id, ok := ac.attachmentIdParams(c)
+	if !ok {
+		return
+	}
+
+	attachment, content, err := ac.AttachmentUsecase.Download(c.Request.Context(), actor, taskId, id)
+	if ac.writeError(c, err, "Failed to download the attachment. Please try again later.") {
+		return
+	}
+	defer content.Close()
+
+	sum, _ := hex.DecodeString(attachment.SHA256)
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, content, map[string]string{
+		// Content is never rendered inline, whatever its type
+		"Content-Disposition": mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}),
+		"Content-Digest":      "sha-256=:" + base64.StdEncoding.EncodeToString(sum) + ":",
+		"ETag":                `"` + attachment.SHA256 + `"`,
+	})
+}
+
+// DeleteAttachment deletes an attachment. Uploaders may delete their own files,
+// admins those of users and root users those of users and admins.
+func (ac *AttachmentController) DeleteAttachment(c *gin.Context) {
+	actor, taskId, id, ok := ac.attachmentIdParams(c)
+	if !ok {
+		return
+	}
+
+	err := ac.AttachmentUsecase.Delete(c.Request.Context(), actor, taskId, id)
+	if ac.writeError(c, err, "Failed to delete the attachment. Please try again later.") {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Attachment deleted successfully."})
+}
+
+// attachmentParams returns the caller and the task ID of the request, or
+// writes the error response and returns false.
+func (ac *AttachmentController) attachmentParams(c *gin.Context) (domain.User, primitive.ObjectID, bool) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, infrastructure.ErrorResponse(c, "Unauthorized. Please log in to manage attachments."))
+		return domain.User{}, primitive.NilObjectID, false
+	}
+
+	taskId, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid ID format. Please provide a valid task ID."))
+		return domain.User{}, primitive.NilObjectID, false
+	}
+
+	claims, _ := c.Get("user")
+	userClaims := claims.(*domain.Claims)
+	return domain.User{ID: userId, Username: userClaims.Username, Role: userClaims.Role}, taskId, true
+}
+
+// attachmentIdParams is attachmentParams that also reads the attachment ID.
+func (ac *AttachmentController) attachmentIdParams(c *gin.Context) (domain.User, primitive.ObjectID, primitive.ObjectID, bool) {
+	actor, taskId, ok := ac.attachmentParams(c)
+	if !ok {
+		return domain.User{}, primitive.NilObjectID, primitive.NilObjectID, false
+	}
+
+	id, err := primitive.ObjectIDFromHex(c.Param("attachmentId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid attachment ID format."))
+		return domain.User{}, primitive.NilObjectID, primitive.NilObjectID, false
+	}
+	return actor, taskId, id, true
+}
+
+// writeUploadError writes the response for a multipart body that could not be
+// read, including one without a file, and reports whether err was set.
+func (ac *AttachmentController) writeUploadError(c *gin.Context, err error) bool {
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case err == nil:
+		return false
+	case errors.As(err, &maxBytesErr):
+		return ac.writeError(c, err, "")
+	case errors.Is(err, io.EOF):
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid upload. Please send the file in the \""+attachmentField+"\" field."))
+	default:
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid upload. Please send the file as multipart/form-data."))
+	}
+	return true
+}
+
+// writeError writes the response for a failed attachment operation and
+// reports whether err was set.
+func (ac *AttachmentController) writeError(c *gin.Context, err error, message string) bool {
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, domain.ErrInvalidAttachment):
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, err.Error()))
+	case errors.Is(err, domain.ErrAttachmentTooLarge), errors.Is(err, domain.ErrQuotaExceeded):
+		c.JSON(http.StatusRequestEntityTooLarge, infrastructure.ErrorResponse(c, err.Error()))
+	case errors.As(err, &maxBytesErr):
+		c.Header("Connection", "close")
+		c.JSON(http.StatusRequestEntityTooLarge, infrastructure.ErrorResponse(c, "The upload is too large."))
+	case errors.Is(err, domain.ErrForbidden):
+		c.JSON(http.StatusForbidden, infrastructure.ErrorResponse(c, err.Error()))
+	case errors.Is(err, mongo.ErrNoDocuments):
+		c.JSON(http.StatusNotFound, infrastructure.ErrorResponse(c, "Task or attachment not found."))
+	case errors.Is(err, domain.ErrAttachmentCorrupted):
+		c.JSON(http.StatusInternalServerError, infrastructure.ErrorResponse(c, "The attachment is corrupted and cannot be downloaded."))
+	default:
+		c.JSON(http.StatusInternalServerError, infrastructure.ErrorResponse(c, message))
+	}
+	return true
+}
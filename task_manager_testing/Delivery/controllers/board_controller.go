@@ -0,0 +1,100 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	infrastructure "task_manager_testing/Infrastructure"
+	"task_manager_testing/domain"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// moveTaskRequest is the body of a move on the board. AfterID and BeforeID
+// are the tasks the moved task is dropped between; leave AfterID out at the
+// top of the column and BeforeID at the bottom.
+type moveTaskRequest struct {
+	Status   string `json:"status" binding:"required"`
+	AfterID  string `json:"after_id"`
+	BeforeID string `json:"before_id"`
+}
+
+// GetBoard returns the caller's tasks in a column per status, in board order.
+func (tc *TaskController) GetBoard(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, infrastructure.ErrorResponse(c, "Unauthorized. Please log in to view your board."))
+		return
+	}
+
+	board, err := tc.TaskUsecase.GetBoard(c.Request.Context(), userId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, infrastructure.ErrorResponse(c, "Failed to retrieve your board. Please try again later."))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Board retrieved successfully!", "board": board})
+}
+
+// MoveTask moves a task to another column or position on the board. It
+// performs the same authorization checks as the other task updates.
+func (tc *TaskController) MoveTask(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid ID format. Please provide a valid task ID."))
+		return
+	}
+
+	var req moveTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid input. Please provide the status of the target column."))
+		return
+	}
+	move := domain.TaskMove{Status: req.Status}
+	for _, neighbour := range []struct {
+		hex string
+		id  *primitive.ObjectID
+	}{{req.AfterID, &move.After}, {req.BeforeID, &move.Before}} {
+		if neighbour.hex == "" {
+			continue
+		}
+		if *neighbour.id, err = primitive.ObjectIDFromHex(neighbour.hex); err != nil {
+			c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid ID format. Please provide valid neighbouring task IDs."))
+			return
+		}
+	}
+
+	// Retrieve user claims from the context
+	claims, _ := c.Get("user")
+	userClaims := claims.(*domain.Claims)
+
+	task, err := tc.TaskUsecase.GetTaskById(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, infrastructure.ErrorResponse(c, "Task not found. Please ensure the task ID is correct."))
+		return
+	}
+	otherUser, _ := tc.UserUsecase.GetUserById(c.Request.Context(), task.CreatedBy)
+	if message := taskAccessError(userClaims, task.CreatedBy, otherUser, "move"); message != "" {
+		c.JSON(http.StatusForbidden, infrastructure.ErrorResponse(c, message))
+		return
+	}
+
+	task, err = tc.TaskUsecase.MoveTask(c.Request.Context(), id, move)
+	switch {
+	case errors.Is(err, domain.ErrInvalidTask):
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, err.Error()))
+		return
+	case errors.Is(err, mongo.ErrNoDocuments):
+		c.JSON(http.StatusNotFound, infrastructure.ErrorResponse(c, "Task not found. Please ensure the task ID is correct."))
+		return
+	case errors.Is(err, domain.ErrRankTaken):
+		c.JSON(http.StatusConflict, infrastructure.ErrorResponse(c, "The board changed while the task was moved. Please reload the board and try again."))
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, infrastructure.ErrorResponse(c, "Failed to move the task. Please try again later."))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Task moved successfully!", "task": task})
+}
@@ -0,0 +1,246 @@
+package controllers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	infrastructure "task_manager_testing/Infrastructure"
+	"task_manager_testing/domain"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// calendarProductID identifies the application in generated calendars.
+const calendarProductID = "-//Task Manager//Task Calendar//EN"
+
+// calendarRefresh is how often subscribed calendar apps are asked to refresh.
+const calendarRefresh = time.Hour
+
+// Calendar component types a feed can contain.
+const (
+	calendarEvents = "event"
+	calendarTodos  = "todo"
+)
+
+// CalendarController serves the iCalendar feeds of task due dates and manages
+// the secret tokens in their URLs.
+type CalendarController struct {
+	TaskUsecase domain.TaskUsecase
+	UserUsecase domain.UserUsecase
+}
+
+// NewCalendarController initializes a new CalendarController.
+func NewCalendarController(taskUsecase domain.TaskUsecase, userUsecase domain.UserUsecase) *CalendarController {
+	return &CalendarController{TaskUsecase: taskUsecase, UserUsecase: userUsecase}
+}
+
+// RotateToken issues a new calendar feed URL for the logged-in user. Any
+// previously issued URL stops working.
+func (cc *CalendarController) RotateToken(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, infrastructure.ErrorResponse(c, "Unauthorized. Please log in to manage your calendar feed."))
+		return
+	}
+
+	token, err := cc.UserUsecase.RotateCalendarToken(c.Request.Context(), userId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, infrastructure.ErrorResponse(c, "Failed to create a calendar feed. Please try again later."))
+		return
+	}
+
+	path := "/calendar/" + token + ".ics"
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Calendar feed created. Keep the URL secret; anyone with it can read your tasks.",
+		"token":   token,
+		"url":     requestScheme(c) + "://" + c.Request.Host + path,
+		"webcal":  "webcal://" + c.Request.Host + path,
+	})
+}
+
+// RevokeToken disables the logged-in user's calendar feed.
+func (cc *CalendarController) RevokeToken(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, infrastructure.ErrorResponse(c, "Unauthorized. Please log in to manage your calendar feed."))
+		return
+	}
+
+	if err := cc.UserUsecase.RevokeCalendarToken(c.Request.Context(), userId); err != nil {
+		c.JSON(http.StatusInternalServerError, infrastructure.ErrorResponse(c, "Failed to disable the calendar feed. Please try again later."))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Calendar feed disabled."})
+}
+
+// Feed serves the tasks with a due date created by the token's owner as an
+// iCalendar document. Tasks are events by default so that every calendar app
+// shows them; type=todo lists them as to-dos with their completion status.
+// The ETag lets calendar apps poll cheaply; a changed task changes the ETag
+// and its LAST-MODIFIED, so subscribers pick up the new version.
+func (cc *CalendarController) Feed(c *gin.Context) {
+	component := c.DefaultQuery("type", calendarEvents)
+	if component != calendarEvents && component != calendarTodos {
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid type. Use event or todo."))
+		return
+	}
+
+	token := strings.TrimSuffix(c.Param("token"), ".ics")
+	user, err := cc.UserUsecase.GetUserByCalendarToken(c.Request.Context(), token)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.JSON(http.StatusNotFound, infrastructure.ErrorResponse(c, "Calendar not found."))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, infrastructure.ErrorResponse(c, "Failed to load the calendar. Please try again later."))
+		return
+	}
+
+	tasks, err := cc.TaskUsecase.GetMyTasks(c.Request.Context(), user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, infrastructure.ErrorResponse(c, "Failed to load the calendar. Please try again later."))
+		return
+	}
+
+	var body bytes.Buffer
+	if err := writeTaskCalendar(&body, user, tasks, component); err != nil {
+		c.JSON(http.StatusInternalServerError, infrastructure.ErrorResponse(c, "Failed to build the calendar. Please try again later."))
+		return
+	}
+
+	sum := sha256.Sum256(body.Bytes())
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "private, max-age=300")
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Header("Content-Disposition", `inline; filename="tasks.ics"`)
+	c.Data(http.StatusOK, infrastructure.ICalendarContentType, body.Bytes())
+}
+
+// writeTaskCalendar encodes the tasks that have a due date as VEVENT or VTODO
+// components of one calendar.
+func writeTaskCalendar(buf *bytes.Buffer, user domain.User, tasks []domain.Task, component string) error {
+	cal := infrastructure.NewICalendarWriter(buf)
+	cal.Begin("VCALENDAR")
+	cal.Raw("VERSION", "2.0")
+	cal.Text("PRODID", calendarProductID)
+	cal.Raw("CALSCALE", "GREGORIAN")
+	cal.Raw("METHOD", "PUBLISH")
+	cal.Text("X-WR-CALNAME", user.Username+"'s tasks")
+	cal.Raw("REFRESH-INTERVAL;VALUE=DURATION", icalDuration(calendarRefresh))
+	cal.Raw("X-PUBLISHED-TTL", icalDuration(calendarRefresh))
+
+	for _, task := range tasks {
+		if task.DueDate == 0 {
+			continue
+		}
+		if component == calendarTodos {
+			writeTaskTodo(cal, task)
+		} else {
+			writeTaskEvent(cal, task)
+		}
+	}
+
+	cal.End("VCALENDAR")
+	return cal.Flush()
+}
+
+func writeTaskEvent(cal *infrastructure.ICalendarWriter, task domain.Task) {
+	cal.Begin("VEVENT")
+	writeTaskProperties(cal, task)
+	writeDue(cal, "DTSTART", task.DueDate.Time())
+	// Events have no completion state, so finished tasks are marked in the title
+	summary := task.Title
+	if task.Status == "Completed" {
+		summary = "✓ " + summary
+	}
+	cal.Text("SUMMARY", summary)
+	cal.Raw("STATUS", "CONFIRMED")
+	cal.Raw("TRANSP", "TRANSPARENT")
+	cal.End("VEVENT")
+}
+
+func writeTaskTodo(cal *infrastructure.ICalendarWriter, task domain.Task) {
+	cal.Begin("VTODO")
+	writeTaskProperties(cal, task)
+	writeDue(cal, "DUE", task.DueDate.Time())
+	cal.Text("SUMMARY", task.Title)
+
+	switch task.Status {
+	case "Completed":
+		cal.Raw("STATUS", "COMPLETED")
+		cal.Raw("PERCENT-COMPLETE", "100")
+		cal.DateTime("COMPLETED", taskLastModified(task))
+	case "In Progress":
+		cal.Raw("STATUS", "IN-PROCESS")
+		cal.Raw("PERCENT-COMPLETE", "50")
+	default:
+		cal.Raw("STATUS", "NEEDS-ACTION")
+		cal.Raw("PERCENT-COMPLETE", "0")
+	}
+	cal.End("VTODO")
+}
+
+// writeTaskProperties writes the properties shared by events and to-dos.
+func writeTaskProperties(cal *infrastructure.ICalendarWriter, task domain.Task) {
+	modified := taskLastModified(task)
+	cal.Text("UID", task.ID.Hex()+"@task-manager")
+	cal.DateTime("DTSTAMP", modified)
+	cal.DateTime("CREATED", task.ID.Timestamp())
+	cal.DateTime("LAST-MODIFIED", modified)
+	// SEQUENCE must grow with every change; seconds since creation do
+	cal.Raw("SEQUENCE", fmt.Sprint(int64(modified.Sub(task.ID.Timestamp())/time.Second)))
+	if task.Description != "" {
+		cal.Text("DESCRIPTION", task.Description)
+	}
+	if task.Status != "" {
+		cal.Text("CATEGORIES", task.Status)
+	}
+}
+
+// writeDue writes a due date at midnight UTC as a whole day and any other
+// due date as a point in time.
+func writeDue(cal *infrastructure.ICalendarWriter, name string, due time.Time) {
+	due = due.UTC()
+	if due.Equal(due.Truncate(24 * time.Hour)) {
+		cal.Date(name, due)
+		return
+	}
+	cal.DateTime(name, due)
+}
+
+// taskLastModified returns when the task last changed, falling back to its
+// creation time for tasks written before updates were tracked.
+func taskLastModified(task domain.Task) time.Time {
+	if task.UpdatedAt != 0 {
+		return task.UpdatedAt.Time()
+	}
+	return task.ID.Timestamp()
+}
+
+// icalDuration formats d as an RFC 5545 DURATION in whole seconds.
+func icalDuration(d time.Duration) string {
+	return fmt.Sprintf("PT%dS", int64(d/time.Second))
+}
+
+// requestScheme returns the scheme the client used, honouring a TLS proxy.
+func requestScheme(c *gin.Context) string {
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto == "https" || proto == "http" {
+		return proto
+	}
+	if c.Request.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
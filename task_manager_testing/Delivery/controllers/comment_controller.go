@@ -0,0 +1,159 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	infrastructure "task_manager_testing/Infrastructure"
+	"task_manager_testing/domain"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// CommentController handles the discussion on tasks.
+type CommentController struct {
+	CommentUsecase domain.CommentUsecase
+}
+
+// NewCommentController initializes a new CommentController.
+func NewCommentController(commentUsecase domain.CommentUsecase) *CommentController {
+	return &CommentController{CommentUsecase: commentUsecase}
+}
+
+// commentRequest is the body of a new or edited comment. ParentID is only
+// read for new comments.
+type commentRequest struct {
+	Body     string `json:"body" binding:"required"`
+	ParentID string `json:"parent_id"`
+}
+
+// AddComment posts a comment on a task, or a reply when parent_id is given.
+func (cc *CommentController) AddComment(c *gin.Context) {
+	actor, taskId, ok := cc.commentParams(c)
+	if !ok {
+		return
+	}
+
+	var req commentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid input. Please provide the comment body."))
+		return
+	}
+	var parentId *primitive.ObjectID
+	if req.ParentID != "" {
+		id, err := primitive.ObjectIDFromHex(req.ParentID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid parent comment ID format."))
+			return
+		}
+		parentId = &id
+	}
+
+	comment, err := cc.CommentUsecase.AddComment(c.Request.Context(), actor, taskId, parentId, req.Body)
+	if cc.writeError(c, err, "Failed to add the comment. Please try again later.") {
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Comment added successfully.", "comment": comment})
+}
+
+// GetComments returns the discussion of a task as threads of replies.
+func (cc *CommentController) GetComments(c *gin.Context) {
+	actor, taskId, ok := cc.commentParams(c)
+	if !ok {
+		return
+	}
+
+	comments, err := cc.CommentUsecase.GetComments(c.Request.Context(), actor, taskId)
+	if cc.writeError(c, err, "Failed to retrieve comments. Please try again later.") {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Comments retrieved successfully.", "comments": comments})
+}
+
+// UpdateComment edits the body of one of the logged-in user's comments.
+func (cc *CommentController) UpdateComment(c *gin.Context) {
+	actor, taskId, ok := cc.commentParams(c)
+	if !ok {
+		return
+	}
+	id, err := primitive.ObjectIDFromHex(c.Param("commentId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid comment ID format."))
+		return
+	}
+
+	var req commentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid input. Please provide the comment body."))
+		return
+	}
+
+	comment, err := cc.CommentUsecase.UpdateComment(c.Request.Context(), actor, taskId, id, req.Body)
+	if cc.writeError(c, err, "Failed to update the comment. Please try again later.") {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Comment updated successfully.", "comment": comment})
+}
+
+// DeleteComment deletes a comment. Authors may delete their own comments,
+// admins those of users and root users those of users and admins.
+func (cc *CommentController) DeleteComment(c *gin.Context) {
+	actor, taskId, ok := cc.commentParams(c)
+	if !ok {
+		return
+	}
+	id, err := primitive.ObjectIDFromHex(c.Param("commentId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid comment ID format."))
+		return
+	}
+
+	err = cc.CommentUsecase.DeleteComment(c.Request.Context(), actor, taskId, id)
+	if cc.writeError(c, err, "Failed to delete the comment. Please try again later.") {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Comment deleted successfully."})
+}
+
+// commentParams returns the caller and the task ID of the request, or writes
+// the error response and returns false.
+func (cc *CommentController) commentParams(c *gin.Context) (domain.User, primitive.ObjectID, bool) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, infrastructure.ErrorResponse(c, "Unauthorized. Please log in to discuss tasks."))
+		return domain.User{}, primitive.NilObjectID, false
+	}
+
+	taskId, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid ID format. Please provide a valid task ID."))
+		return domain.User{}, primitive.NilObjectID, false
+	}
+
+	claims, _ := c.Get("user")
+	userClaims := claims.(*domain.Claims)
+	return domain.User{ID: userId, Username: userClaims.Username, Role: userClaims.Role}, taskId, true
+}
+
+// writeError writes the response for a failed comment operation and reports
+// whether err was set.
+func (cc *CommentController) writeError(c *gin.Context, err error, message string) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, domain.ErrInvalidComment):
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, err.Error()))
+	case errors.Is(err, domain.ErrForbidden):
+		c.JSON(http.StatusForbidden, infrastructure.ErrorResponse(c, err.Error()))
+	case errors.Is(err, mongo.ErrNoDocuments):
+		c.JSON(http.StatusNotFound, infrastructure.ErrorResponse(c, "Task or comment not found."))
+	default:
+		c.JSON(http.StatusInternalServerError, infrastructure.ErrorResponse(c, message))
+	}
+	return true
+}
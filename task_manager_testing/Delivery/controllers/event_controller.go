@@ -0,0 +1,188 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	infrastructure "task_manager_testing/Infrastructure"
+	"task_manager_testing/domain"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+)
+
+// streamReset tells a client that asked to resume from an event no longer
+// remembered that it missed events and should reload its tasks.
+const streamReset = "reset"
+
+// EventController streams task changes to the logged-in user as they happen.
+type EventController struct {
+	Stream domain.TaskEventStream
+	// Heartbeat is how often an idle stream is sent a keep-alive
+	Heartbeat time.Duration
+}
+
+// NewEventController initializes a new EventController.
+func NewEventController(stream domain.TaskEventStream, heartbeat time.Duration) *EventController {
+	return &EventController{Stream: stream, Heartbeat: heartbeat}
+}
+
+// StreamEvents sends task events as Server-Sent Events. Clients resume after
+// a reconnect with the Last-Event-ID header or the last_event_id parameter.
+func (ec *EventController) StreamEvents(c *gin.Context) {
+	visible, ok := ec.visibleEvents(c)
+	if !ok {
+		return
+	}
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+
+	missed, resumed, events, cancel := ec.Stream.Subscribe(lastEventID)
+	defer cancel()
+
+	// The stream outlives the server's write timeout
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	if !resumed {
+		fmt.Fprintf(c.Writer, "event: %s\ndata: {}\n\n", streamReset)
+	}
+	for _, event := range missed {
+		if visible(event) {
+			writeServerSentEvent(c, event)
+		}
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(ec.Heartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(c.Writer, ": heartbeat\n\n")
+		case event, open := <-events:
+			if !open {
+				// Fell behind; the client reconnects and resumes
+				return
+			}
+			if !visible(event) {
+				continue
+			}
+			writeServerSentEvent(c, event)
+		}
+		c.Writer.Flush()
+	}
+}
+
+// StreamEventsWebSocket sends task events as JSON messages over a WebSocket.
+// Clients resume after a reconnect with the last_event_id parameter.
+func (ec *EventController) StreamEventsWebSocket(c *gin.Context) {
+	visible, ok := ec.visibleEvents(c)
+	if !ok {
+		return
+	}
+
+	// Subscribe before the handshake so no event is lost once it completes
+	missed, resumed, events, cancel := ec.Stream.Subscribe(c.Query("last_event_id"))
+	defer cancel()
+
+	server := websocket.Server{
+		// Clients authenticate with a bearer token rather than cookies, so
+		// connections from other origins cannot act on a user's behalf
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler: func(conn *websocket.Conn) {
+			ec.serveWebSocket(conn, missed, resumed, events, visible)
+		},
+	}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+func (ec *EventController) serveWebSocket(conn *websocket.Conn, missed []domain.TaskEvent, resumed bool, events <-chan domain.TaskEvent, visible func(domain.TaskEvent) bool) {
+	defer conn.Close()
+	// The connection outlives the server's read and write timeouts
+	_ = conn.SetDeadline(time.Time{})
+
+	// Messages from the client are not expected; reading detects the close
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		var discard string
+		for websocket.Message.Receive(conn, &discard) == nil {
+		}
+	}()
+
+	if !resumed && websocket.JSON.Send(conn, gin.H{"type": streamReset}) != nil {
+		return
+	}
+	for _, event := range missed {
+		if visible(event) && websocket.JSON.Send(conn, event) != nil {
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(ec.Heartbeat)
+	defer heartbeat.Stop()
+	for {
+		var err error
+		select {
+		case <-closed:
+			return
+		case <-heartbeat.C:
+			err = websocket.JSON.Send(conn, gin.H{"type": "heartbeat"})
+		case event, open := <-events:
+			if !open {
+				return
+			}
+			if visible(event) {
+				err = websocket.JSON.Send(conn, event)
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// visibleEvents returns the filter of the events the logged-in user may see:
+// those of their own tasks, those of their organization for admins, or every
+// event for root users.
+func (ec *EventController) visibleEvents(c *gin.Context) (func(domain.TaskEvent) bool, bool) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, infrastructure.ErrorResponse(c, "Unauthorized. Please log in to follow task changes."))
+		return nil, false
+	}
+
+	claims, _ := c.Get("user")
+	switch claims.(*domain.Claims).Role {
+	case "user":
+		return func(event domain.TaskEvent) bool { return event.Task.CreatedBy == userId }, true
+	case "root":
+		return func(domain.TaskEvent) bool { return true }, true
+	}
+	orgId, _ := domain.OrgFromContext(c.Request.Context())
+	return func(event domain.TaskEvent) bool {
+		if event.Task.OrgID == nil {
+			return orgId.IsZero()
+		}
+		return *event.Task.OrgID == orgId
+	}, true
+}
+
+// writeServerSentEvent writes event in the text/event-stream format.
+func writeServerSentEvent(c *gin.Context, event domain.TaskEvent) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(c.Writer, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+}
@@ -0,0 +1,44 @@
+package controllers
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"task_manager_testing/domain"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// readinessTimeout bounds how long the readiness probe waits for the database.
+const readinessTimeout = 2 * time.Second
+
+// HealthController serves the liveness and readiness probes.
+type HealthController struct {
+	Database domain.Pinger
+}
+
+// NewHealthController creates a new HealthController checking the given database.
+func NewHealthController(database domain.Pinger) *HealthController {
+	return &HealthController{Database: database}
+}
+
+// Healthz reports that the process is up and able to serve requests.
+func (hc *HealthController) Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Readyz reports whether the service can handle traffic, which requires the
+// database to answer a ping.
+func (hc *HealthController) Readyz(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), readinessTimeout)
+	defer cancel()
+
+	if err := hc.Database.Ping(ctx); err != nil {
+		slog.WarnContext(ctx, "readiness check failed", slog.String("error", err.Error()))
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "unavailable", "checks": gin.H{"database": "unreachable"}})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "ok", "checks": gin.H{"database": "ok"}})
+}
@@ -0,0 +1,69 @@
+package controllers_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"task_manager_testing/Delivery/controllers"
+	"task_manager_testing/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type HealthControllerSuite struct {
+	suite.Suite
+	pinger        *mocks.Pinger
+	testingServer *httptest.Server
+}
+
+func (suite *HealthControllerSuite) SetupTest() {
+	suite.pinger = &mocks.Pinger{}
+	handler := controllers.NewHealthController(suite.pinger)
+
+	router := gin.Default()
+	router.GET("/healthz", handler.Healthz)
+	router.GET("/readyz", handler.Readyz)
+
+	suite.testingServer = httptest.NewServer(router)
+}
+
+func (suite *HealthControllerSuite) TearDownTest() {
+	suite.testingServer.Close()
+	suite.pinger.AssertExpectations(suite.T())
+}
+
+func (suite *HealthControllerSuite) TestHealthz() {
+	response, err := http.Get(fmt.Sprintf("%s/healthz", suite.testingServer.URL))
+	suite.NoError(err, "no error when calling the endpoint")
+	defer response.Body.Close()
+
+	suite.Equal(http.StatusOK, response.StatusCode)
+}
+
+func (suite *HealthControllerSuite) TestReadyz() {
+	suite.pinger.On("Ping", mock.Anything).Return(nil).Once()
+
+	response, err := http.Get(fmt.Sprintf("%s/readyz", suite.testingServer.URL))
+	suite.NoError(err, "no error when calling the endpoint")
+	defer response.Body.Close()
+
+	suite.Equal(http.StatusOK, response.StatusCode)
+}
+
+func (suite *HealthControllerSuite) TestReadyzDatabaseDown() {
+	suite.pinger.On("Ping", mock.Anything).Return(fmt.Errorf("server selection timeout")).Once()
+
+	response, err := http.Get(fmt.Sprintf("%s/readyz", suite.testingServer.URL))
+	suite.NoError(err, "no error when calling the endpoint")
+	defer response.Body.Close()
+
+	suite.Equal(http.StatusServiceUnavailable, response.StatusCode)
+}
+
+func TestHealthControllerSuite(t *testing.T) {
+	suite.Run(t, new(HealthControllerSuite))
+}
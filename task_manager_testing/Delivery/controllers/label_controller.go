@@ -0,0 +1,256 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	infrastructure "task_manager_testing/Infrastructure"
+	"task_manager_testing/domain"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// LabelController handles labels and the labels of tasks.
+type LabelController struct {
+	LabelUsecase domain.LabelUsecase
+}
+
+// NewLabelController initializes a new LabelController.
+func NewLabelController(labelUsecase domain.LabelUsecase) *LabelController {
+	return &LabelController{LabelUsecase: labelUsecase}
+}
+
+// labelRequest is the body of a new label. Scope defaults to personal.
+type labelRequest struct {
+	Name  string `json:"name" binding:"required"`
+	Color string `json:"color"`
+	Scope string `json:"scope"`
+}
+
+// taskLabelsRequest is the body replacing the labels of a task.
+type taskLabelsRequest struct {
+	Labels []string `json:"labels"`
+}
+
+// CreateLabel creates a personal label, or a global one for admins and root
+// users.
+func (lc *LabelController) CreateLabel(c *gin.Context) {
+	actor, ok := lc.actor(c)
+	if !ok {
+		return
+	}
+
+	var req labelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid input. Please provide the label name."))
+		return
+	}
+
+	label, err := lc.LabelUsecase.CreateLabel(c.Request.Context(), actor, req.Name, req.Color, req.Scope)
+	if lc.writeError(c, err, "Failed to create the label. Please try again later.") {
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Label created successfully.", "label": label})
+}
+
+// GetLabels lists the global labels and the caller's personal labels.
+func (lc *LabelController) GetLabels(c *gin.Context) {
+	actor, ok := lc.actor(c)
+	if !ok {
+		return
+	}
+
+	labels, err := lc.LabelUsecase.GetLabels(c.Request.Context(), actor)
+	if lc.writeError(c, err, "Failed to retrieve labels. Please try again later.") {
+		return
+	}
+	if labels == nil {
+		labels = []domain.Label{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Labels retrieved successfully.", "labels": labels})
+}
+
+// UpdateLabel renames or recolors a label.
+func (lc *LabelController) UpdateLabel(c *gin.Context) {
+	actor, id, ok := lc.labelParams(c)
+	if !ok {
+		return
+	}
+
+	var update domain.LabelUpdate
+	if err := c.ShouldBindJSON(&update); err != nil {
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid input. Please provide the label name or color."))
+		return
+	}
+
+	label, err := lc.LabelUsecase.UpdateLabel(c.Request.Context(), actor, id, update)
+	if lc.writeError(c, err, "Failed to update the label. Please try again later.") {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Label updated successfully.", "label": label})
+}
+
+// DeleteLabel deletes a label and takes it off every task.
+func (lc *LabelController) DeleteLabel(c *gin.Context) {
+	actor, id, ok := lc.labelParams(c)
+	if !ok {
+		return
+	}
+
+	err := lc.LabelUsecase.DeleteLabel(c.Request.Context(), actor, id)
+	if lc.writeError(c, err, "Failed to delete the label. Please try again later.") {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Label deleted successfully."})
+}
+
+// SetTaskLabels replaces the labels of a task.
+func (lc *LabelController) SetTaskLabels(c *gin.Context) {
+	actor, ok := lc.actor(c)
+	if !ok {
+		return
+	}
+	taskId, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid ID format. Please provide a valid task ID."))
+		return
+	}
+
+	var req taskLabelsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid input. Please provide the list of label IDs."))
+		return
+	}
+	labelIds, err := parseObjectIDs(req.Labels)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid label ID format."))
+		return
+	}
+
+	task, err := lc.LabelUsecase.SetTaskLabels(c.Request.Context(), actor, taskId, labelIds)
+	if lc.writeError(c, err, "Failed to set the task labels. Please try again later.") {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Task labels updated successfully.", "task": task})
+}
+
+// AddTaskLabel puts one label on a task.
+func (lc *LabelController) AddTaskLabel(c *gin.Context) {
+	actor, taskId, labelId, ok := lc.taskLabelParams(c)
+	if !ok {
+		return
+	}
+
+	task, err := lc.LabelUsecase.AddTaskLabel(c.Request.Context(), actor, taskId, labelId)
+	if lc.writeError(c, err, "Failed to add the label. Please try again later.") {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Label added to the task.", "task": task})
+}
+
+// RemoveTaskLabel takes one label off a task.
+func (lc *LabelController) RemoveTaskLabel(c *gin.Context) {
+	actor, taskId, labelId, ok := lc.taskLabelParams(c)
+	if !ok {
+		return
+	}
+
+	task, err := lc.LabelUsecase.RemoveTaskLabel(c.Request.Context(), actor, taskId, labelId)
+	if lc.writeError(c, err, "Failed to remove the label. Please try again later.") {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Label removed from the task.", "task": task})
+}
+
+// actor returns the caller of the request, or writes the error response and
+// returns false.
+func (lc *LabelController) actor(c *gin.Context) (domain.User, bool) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, infrastructure.ErrorResponse(c, "Unauthorized. Please log in to manage labels."))
+		return domain.User{}, false
+	}
+
+	claims, _ := c.Get("user")
+	userClaims := claims.(*domain.Claims)
+	return domain.User{ID: userId, Username: userClaims.Username, Role: userClaims.Role}, true
+}
+
+// labelParams returns the caller and the label ID of the request, or writes
+// the error response and returns false.
+func (lc *LabelController) labelParams(c *gin.Context) (domain.User, primitive.ObjectID, bool) {
+	actor, ok := lc.actor(c)
+	if !ok {
+		return domain.User{}, primitive.NilObjectID, false
+	}
+
+	id, err := primitive.ObjectIDFromHex(c.Param("labelId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid label ID format."))
+		return domain.User{}, primitive.NilObjectID, false
+	}
+	return actor, id, true
+}
+
+// taskLabelParams is labelParams that also reads the task ID.
+func (lc *LabelController) taskLabelParams(c *gin.Context) (domain.User, primitive.ObjectID, primitive.ObjectID, bool) {
+	actor, labelId, ok := lc.labelParams(c)
+	if !ok {
+		return domain.User{}, primitive.NilObjectID, primitive.NilObjectID, false
+	}
+
+	taskId, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid ID format. Please provide a valid task ID."))
+		return domain.User{}, primitive.NilObjectID, primitive.NilObjectID, false
+	}
+	return actor, taskId, labelId, true
+}
+
+// writeError writes the response for a failed label operation and reports
+// whether err was set.
+func (lc *LabelController) writeError(c *gin.Context, err error, message string) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, domain.ErrInvalidLabel):
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, err.Error()))
+	case errors.Is(err, domain.ErrLabelExists):
+		c.JSON(http.StatusConflict, infrastructure.ErrorResponse(c, "A label with this name already exists."))
+	case errors.Is(err, domain.ErrForbidden):
+		c.JSON(http.StatusForbidden, infrastructure.ErrorResponse(c, err.Error()))
+	case errors.Is(err, mongo.ErrNoDocuments):
+		c.JSON(http.StatusNotFound, infrastructure.ErrorResponse(c, "Task or label not found."))
+	default:
+		c.JSON(http.StatusInternalServerError, infrastructure.ErrorResponse(c, message))
+	}
+	return true
+}
+
+// parseObjectIDs parses hex IDs, which may also be given as comma-separated
+// lists. Blank entries are skipped.
+func parseObjectIDs(values []string) ([]primitive.ObjectID, error) {
+	var ids []primitive.ObjectID
+	for _, value := range values {
+		for _, hex := range strings.Split(value, ",") {
+			if hex = strings.TrimSpace(hex); hex == "" {
+				continue
+			}
+			id, err := primitive.ObjectIDFromHex(hex)
+			if err != nil {
+				return nil, err
+			}
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
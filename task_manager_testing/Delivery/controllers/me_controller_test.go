@@ -0,0 +1,130 @@
+package controllers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"task_manager_testing/Delivery/controllers"
+	"task_manager_testing/domain"
+	"task_manager_testing/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MeControllerSuite tests the endpoints that act on the logged-in user
+type MeControllerSuite struct {
+	suite.Suite
+	userUsecase   *mocks.UserUsecase
+	testingServer *httptest.Server
+	userID        primitive.ObjectID
+}
+
+func (suite *MeControllerSuite) SetupTest() {
+	suite.userUsecase = &mocks.UserUsecase{}
+	suite.userID = primitive.NewObjectID()
+	handler := controllers.NewUserController(suite.userUsecase)
+
+	router := gin.Default()
+	// Stand in for the auth middleware
+	router.Use(func(c *gin.Context) {
+		c.Set("user", &domain.Claims{UserID: suite.userID.Hex(), Username: "tester1", Role: "user"})
+		c.Next()
+	})
+	router.GET("/me", handler.GetMe)
+	router.PATCH("/me", handler.UpdateMe)
+	router.POST("/me/password", handler.ChangePassword)
+
+	suite.testingServer = httptest.NewServer(router)
+}
+
+func (suite *MeControllerSuite) TearDownTest() {
+	suite.testingServer.Close()
+	suite.userUsecase.AssertExpectations(suite.T())
+}
+
+func (suite *MeControllerSuite) TestGetMe() {
+	user := domain.User{ID: suite.userID, Username: "tester1", Password: "hashed", Role: "user"}
+	suite.userUsecase.On("GetUserById", mock.Anything, suite.userID).Return(user, nil)
+
+	response, err := http.Get(fmt.Sprintf("%s/me", suite.testingServer.URL))
+	suite.NoError(err, "no error when calling the endpoint")
+	defer response.Body.Close()
+
+	var responseBody struct {
+		Data domain.User `json:"data"`
+	}
+	json.NewDecoder(response.Body).Decode(&responseBody)
+
+	suite.Equal(http.StatusOK, response.StatusCode)
+	suite.Equal("tester1", responseBody.Data.Username)
+	suite.Empty(responseBody.Data.Password)
+}
+
+func (suite *MeControllerSuite) TestUpdateMe() {
+	updated := domain.User{ID: suite.userID, Username: "tester2", Password: "hashed", Role: "user"}
+	suite.userUsecase.On("UpdateUser", mock.Anything, suite.userID, domain.User{Username: "tester2"}).Return(nil)
+	suite.userUsecase.On("GetUserById", mock.Anything, suite.userID).Return(updated, nil)
+
+	requestBody, err := json.Marshal(map[string]string{"username": "tester2", "role": "root"})
+	suite.NoError(err, "can not marshal struct to json")
+
+	req, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("%s/me", suite.testingServer.URL), bytes.NewBuffer(requestBody))
+	suite.NoError(err, "can not create PATCH request")
+	req.Header.Set("Content-Type", "application/json")
+
+	response, err := http.DefaultClient.Do(req)
+	suite.NoError(err, "no error when calling the endpoint")
+	defer response.Body.Close()
+
+	suite.Equal(http.StatusOK, response.StatusCode)
+}
+
+func (suite *MeControllerSuite) TestChangePassword() {
+	testCases := []struct {
+		name           string
+		mockError      error
+		expectedStatus int
+	}{
+		{name: "Valid change", mockError: nil, expectedStatus: http.StatusOK},
+		{name: "Wrong old password", mockError: domain.ErrInvalidPassword, expectedStatus: http.StatusForbidden},
+		{name: "Repository failure", mockError: fmt.Errorf("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			suite.userUsecase.ExpectedCalls = nil
+			suite.userUsecase.On("ChangePassword", mock.Anything, suite.userID, "oldPassword", "newPassword").Return(tc.mockError).Once()
+
+			requestBody, err := json.Marshal(map[string]string{"old_password": "oldPassword", "new_password": "newPassword"})
+			suite.NoError(err, "can not marshal struct to json")
+
+			response, err := http.Post(fmt.Sprintf("%s/me/password", suite.testingServer.URL), "application/json", bytes.NewBuffer(requestBody))
+			suite.NoError(err, "no error when calling the endpoint")
+			defer response.Body.Close()
+
+			suite.Equal(tc.expectedStatus, response.StatusCode)
+		})
+	}
+}
+
+func (suite *MeControllerSuite) TestChangePasswordInvalidRequest() {
+	requestBody, err := json.Marshal(map[string]string{"new_password": "newPassword"})
+	suite.NoError(err, "can not marshal struct to json")
+
+	response, err := http.Post(fmt.Sprintf("%s/me/password", suite.testingServer.URL), "application/json", bytes.NewBuffer(requestBody))
+	suite.NoError(err, "no error when calling the endpoint")
+	defer response.Body.Close()
+
+	suite.Equal(http.StatusBadRequest, response.StatusCode)
+}
+
+func TestMeControllerSuite(t *testing.T) {
+	suite.Run(t, new(MeControllerSuite))
+}
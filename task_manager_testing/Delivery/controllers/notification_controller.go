@@ -0,0 +1,98 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	infrastructure "task_manager_testing/Infrastructure"
+	"task_manager_testing/domain"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// NotificationController handles the logged-in user's notifications.
+type NotificationController struct {
+	NotificationUsecase domain.NotificationUsecase
+}
+
+// NewNotificationController initializes a new NotificationController.
+func NewNotificationController(notificationUsecase domain.NotificationUsecase) *NotificationController {
+	return &NotificationController{NotificationUsecase: notificationUsecase}
+}
+
+// GetNotifications lists the logged-in user's notifications, newest first.
+// unread=true leaves out the ones already read.
+func (nc *NotificationController) GetNotifications(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, infrastructure.ErrorResponse(c, "Unauthorized. Please log in to view your notifications."))
+		return
+	}
+
+	limit := domain.MaxNotificationsPage
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > domain.MaxNotificationsPage {
+			c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid limit. Use a number between 1 and "+strconv.Itoa(domain.MaxNotificationsPage)+"."))
+			return
+		}
+		limit = parsed
+	}
+
+	notifications, unread, err := nc.NotificationUsecase.GetNotifications(c.Request.Context(), userId, c.Query("unread") == "true", limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, infrastructure.ErrorResponse(c, "Failed to retrieve notifications. Please try again later."))
+		return
+	}
+	if notifications == nil {
+		notifications = []domain.Notification{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Notifications retrieved successfully.", "notifications": notifications, "unread": unread})
+}
+
+// MarkRead marks one of the logged-in user's notifications as read.
+func (nc *NotificationController) MarkRead(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, infrastructure.ErrorResponse(c, "Unauthorized. Please log in to update your notifications."))
+		return
+	}
+
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid notification ID format."))
+		return
+	}
+
+	err = nc.NotificationUsecase.MarkRead(c.Request.Context(), userId, id)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.JSON(http.StatusNotFound, infrastructure.ErrorResponse(c, "Notification not found."))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, infrastructure.ErrorResponse(c, "Failed to update the notification. Please try again later."))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Notification marked as read."})
+}
+
+// MarkAllRead marks every notification of the logged-in user as read.
+func (nc *NotificationController) MarkAllRead(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, infrastructure.ErrorResponse(c, "Unauthorized. Please log in to update your notifications."))
+		return
+	}
+
+	updated, err := nc.NotificationUsecase.MarkAllRead(c.Request.Context(), userId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, infrastructure.ErrorResponse(c, "Failed to update notifications. Please try again later."))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Notifications marked as read.", "updated": updated})
+}
@@ -0,0 +1,122 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	infrastructure "task_manager_testing/Infrastructure"
+	"task_manager_testing/domain"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// OrganizationController handles the organizations users are grouped in.
+type OrganizationController struct {
+	OrganizationUsecase domain.OrganizationUsecase
+}
+
+// NewOrganizationController initializes a new OrganizationController.
+func NewOrganizationController(organizationUsecase domain.OrganizationUsecase) *OrganizationController {
+	return &OrganizationController{OrganizationUsecase: organizationUsecase}
+}
+
+// CreateOrganization creates an organization users can then register in.
+func (oc *OrganizationController) CreateOrganization(c *gin.Context) {
+	actor, ok := oc.actor(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Name string `json:"name" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid request. Please provide the organization name."))
+		return
+	}
+
+	org, err := oc.OrganizationUsecase.CreateOrganization(c.Request.Context(), actor, req.Name)
+	if oc.writeError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Organization created successfully.", "organization": org})
+}
+
+// GetAllOrganizations lists every organization.
+func (oc *OrganizationController) GetAllOrganizations(c *gin.Context) {
+	actor, ok := oc.actor(c)
+	if !ok {
+		return
+	}
+
+	orgs, err := oc.OrganizationUsecase.GetAllOrganizations(c.Request.Context(), actor)
+	if oc.writeError(c, err) {
+		return
+	}
+	if orgs == nil {
+		orgs = []domain.Organization{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Organizations retrieved successfully.", "organizations": orgs})
+}
+
+// GetOrganization retrieves an organization by its ID.
+func (oc *OrganizationController) GetOrganization(c *gin.Context) {
+	actor, ok := oc.actor(c)
+	if !ok {
+		return
+	}
+
+	orgId, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid organization ID format."))
+		return
+	}
+
+	org, err := oc.OrganizationUsecase.GetOrganizationById(c.Request.Context(), actor, orgId)
+	if oc.writeError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Organization retrieved successfully.", "organization": org})
+}
+
+// actor returns the caller of the request with their organization, or writes
+// the error response and returns false.
+func (oc *OrganizationController) actor(c *gin.Context) (domain.User, bool) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, infrastructure.ErrorResponse(c, "Unauthorized. Please log in to manage organizations."))
+		return domain.User{}, false
+	}
+
+	claims, _ := c.Get("user")
+	userClaims := claims.(*domain.Claims)
+	actor := domain.User{ID: userId, Username: userClaims.Username, Role: userClaims.Role}
+	if orgId, err := primitive.ObjectIDFromHex(userClaims.OrgID); err == nil {
+		actor.OrgID = &orgId
+	}
+	return actor, true
+}
+
+// writeError writes the response for a failed organization request and
+// reports whether err was set.
+func (oc *OrganizationController) writeError(c *gin.Context, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, domain.ErrInvalidOrganization):
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, err.Error()))
+	case errors.Is(err, domain.ErrForbidden):
+		c.JSON(http.StatusForbidden, infrastructure.ErrorResponse(c, err.Error()))
+	case errors.Is(err, domain.ErrOrganizationExists):
+		c.JSON(http.StatusConflict, infrastructure.ErrorResponse(c, "An organization with this name already exists."))
+	case errors.Is(err, mongo.ErrNoDocuments):
+		c.JSON(http.StatusNotFound, infrastructure.ErrorResponse(c, "Organization not found."))
+	default:
+		c.JSON(http.StatusInternalServerError, infrastructure.ErrorResponse(c, "Failed to process the organization. Please try again later."))
+	}
+	return true
+}
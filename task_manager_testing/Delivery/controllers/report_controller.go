@@ -0,0 +1,148 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	infrastructure "task_manager_testing/Infrastructure"
+	"task_manager_testing/domain"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ReportController serves the task reports.
+type ReportController struct {
+	ReportUsecase domain.ReportUsecase
+}
+
+// NewReportController initializes a new ReportController.
+func NewReportController(reportUsecase domain.ReportUsecase) *ReportController {
+	return &ReportController{ReportUsecase: reportUsecase}
+}
+
+// Throughput reports the tasks created and completed per period. Every report
+// is filtered by the user_id, from and to query parameters; this one also
+// reads the period, day, week or month.
+func (rc *ReportController) Throughput(c *gin.Context) {
+	actor, query, ok := rc.queryParams(c)
+	if !ok {
+		return
+	}
+
+	periods, err := rc.ReportUsecase.Throughput(c.Request.Context(), actor, query)
+	if rc.writeError(c, err) {
+		return
+	}
+	if periods == nil {
+		periods = []domain.PeriodCount{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Report retrieved successfully.", "periods": periods})
+}
+
+// CycleTime reports the time tasks took from "In Progress" to "Completed".
+func (rc *ReportController) CycleTime(c *gin.Context) {
+	actor, query, ok := rc.queryParams(c)
+	if !ok {
+		return
+	}
+
+	report, err := rc.ReportUsecase.CycleTime(c.Request.Context(), actor, query)
+	if rc.writeError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Report retrieved successfully.", "cycle_time": report})
+}
+
+// Overdue reports the number of overdue tasks per user.
+func (rc *ReportController) Overdue(c *gin.Context) {
+	actor, query, ok := rc.queryParams(c)
+	if !ok {
+		return
+	}
+
+	users, err := rc.ReportUsecase.Overdue(c.Request.Context(), actor, query)
+	if rc.writeError(c, err) {
+		return
+	}
+	if users == nil {
+		users = []domain.UserOverdue{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Report retrieved successfully.", "users": users})
+}
+
+// Burndown reports the open tasks at the end of every day.
+func (rc *ReportController) Burndown(c *gin.Context) {
+	actor, query, ok := rc.queryParams(c)
+	if !ok {
+		return
+	}
+
+	points, err := rc.ReportUsecase.Burndown(c.Request.Context(), actor, query)
+	if rc.writeError(c, err) {
+		return
+	}
+	if points == nil {
+		points = []domain.BurndownPoint{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Report retrieved successfully.", "points": points})
+}
+
+// queryParams returns the caller and the report query of the request, or
+// writes the error response and returns false.
+func (rc *ReportController) queryParams(c *gin.Context) (domain.User, domain.ReportQuery, bool) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, infrastructure.ErrorResponse(c, "Unauthorized. Please log in to view reports."))
+		return domain.User{}, domain.ReportQuery{}, false
+	}
+	claims, _ := c.Get("user")
+	userClaims := claims.(*domain.Claims)
+	actor := domain.User{ID: userId, Username: userClaims.Username, Role: userClaims.Role}
+
+	query := domain.ReportQuery{Period: c.Query("period")}
+	if value := c.Query("user_id"); value != "" {
+		createdBy, err := primitive.ObjectIDFromHex(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid user ID format."))
+			return domain.User{}, domain.ReportQuery{}, false
+		}
+		query.CreatedBy = createdBy
+	}
+	for _, param := range []struct {
+		name  string
+		value *time.Time
+	}{{"from", &query.From}, {"to", &query.To}} {
+		value := c.Query(param.name)
+		if value == "" {
+			continue
+		}
+		at, err := parseDueDate(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid "+param.name+" date, expected YYYY-MM-DD or RFC 3339."))
+			return domain.User{}, domain.ReportQuery{}, false
+		}
+		*param.value = at
+	}
+	return actor, query, true
+}
+
+// writeError writes the response for a failed report and reports whether
+// err was set.
+func (rc *ReportController) writeError(c *gin.Context, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, domain.ErrInvalidReport):
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, err.Error()))
+	case errors.Is(err, domain.ErrForbidden):
+		c.JSON(http.StatusForbidden, infrastructure.ErrorResponse(c, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, infrastructure.ErrorResponse(c, "Failed to build the report. Please try again later."))
+	}
+	return true
+}
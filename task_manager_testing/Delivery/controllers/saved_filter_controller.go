@@ -0,0 +1,161 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	infrastructure "task_manager_testing/Infrastructure"
+	"task_manager_testing/domain"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// SavedFilterController handles the saved task filters of users.
+type SavedFilterController struct {
+	SavedFilterUsecase domain.SavedFilterUsecase
+}
+
+// NewSavedFilterController initializes a new SavedFilterController.
+func NewSavedFilterController(savedFilterUsecase domain.SavedFilterUsecase) *SavedFilterController {
+	return &SavedFilterController{SavedFilterUsecase: savedFilterUsecase}
+}
+
+// savedFilterRequest is the body of a new saved filter. It takes the same
+// criteria as the query of GET /tasks.
+type savedFilterRequest struct {
+	Name   string   `json:"name" binding:"required"`
+	Status string   `json:"status"`
+	Labels []string `json:"labels"`
+	Match  string   `json:"match"`
+}
+
+// SaveFilter saves a named filter for the caller.
+func (fc *SavedFilterController) SaveFilter(c *gin.Context) {
+	actor, ok := fc.actor(c)
+	if !ok {
+		return
+	}
+
+	var req savedFilterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid input. Please provide the filter name."))
+		return
+	}
+	labels, err := parseObjectIDs(req.Labels)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid label ID format."))
+		return
+	}
+
+	filter, err := fc.SavedFilterUsecase.SaveFilter(c.Request.Context(), actor, domain.SavedFilter{
+		Name:       req.Name,
+		Status:     req.Status,
+		Labels:     labels,
+		LabelMatch: req.Match,
+	})
+	if fc.writeError(c, err, "Failed to save the filter. Please try again later.") {
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Filter saved successfully.", "filter": filter})
+}
+
+// GetFilters lists the caller's saved filters.
+func (fc *SavedFilterController) GetFilters(c *gin.Context) {
+	actor, ok := fc.actor(c)
+	if !ok {
+		return
+	}
+
+	filters, err := fc.SavedFilterUsecase.GetFilters(c.Request.Context(), actor)
+	if fc.writeError(c, err, "Failed to retrieve filters. Please try again later.") {
+		return
+	}
+	if filters == nil {
+		filters = []domain.SavedFilter{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Filters retrieved successfully.", "filters": filters})
+}
+
+// DeleteFilter deletes one of the caller's saved filters.
+func (fc *SavedFilterController) DeleteFilter(c *gin.Context) {
+	actor, id, ok := fc.filterParams(c)
+	if !ok {
+		return
+	}
+
+	err := fc.SavedFilterUsecase.DeleteFilter(c.Request.Context(), actor, id)
+	if fc.writeError(c, err, "Failed to delete the filter. Please try again later.") {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Filter deleted successfully."})
+}
+
+// RunFilter returns the caller's tasks currently matching a saved filter.
+func (fc *SavedFilterController) RunFilter(c *gin.Context) {
+	actor, id, ok := fc.filterParams(c)
+	if !ok {
+		return
+	}
+
+	filter, tasks, err := fc.SavedFilterUsecase.RunFilter(c.Request.Context(), actor, id)
+	if fc.writeError(c, err, "Failed to run the filter. Please try again later.") {
+		return
+	}
+	if tasks == nil {
+		tasks = []domain.Task{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Filter applied successfully.", "filter": filter, "tasks": tasks})
+}
+
+// actor returns the caller of the request, or writes the error response and
+// returns false.
+func (fc *SavedFilterController) actor(c *gin.Context) (domain.User, bool) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, infrastructure.ErrorResponse(c, "Unauthorized. Please log in to manage your filters."))
+		return domain.User{}, false
+	}
+
+	claims, _ := c.Get("user")
+	userClaims := claims.(*domain.Claims)
+	return domain.User{ID: userId, Username: userClaims.Username, Role: userClaims.Role}, true
+}
+
+// filterParams returns the caller and the filter ID of the request, or writes
+// the error response and returns false.
+func (fc *SavedFilterController) filterParams(c *gin.Context) (domain.User, primitive.ObjectID, bool) {
+	actor, ok := fc.actor(c)
+	if !ok {
+		return domain.User{}, primitive.NilObjectID, false
+	}
+
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid filter ID format."))
+		return domain.User{}, primitive.NilObjectID, false
+	}
+	return actor, id, true
+}
+
+// writeError writes the response for a failed filter operation and reports
+// whether err was set.
+func (fc *SavedFilterController) writeError(c *gin.Context, err error, message string) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, domain.ErrInvalidFilter), errors.Is(err, domain.ErrInvalidTask):
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, err.Error()))
+	case errors.Is(err, domain.ErrFilterExists):
+		c.JSON(http.StatusConflict, infrastructure.ErrorResponse(c, "A filter with this name already exists."))
+	case errors.Is(err, mongo.ErrNoDocuments):
+		c.JSON(http.StatusNotFound, infrastructure.ErrorResponse(c, "Filter not found."))
+	default:
+		c.JSON(http.StatusInternalServerError, infrastructure.ErrorResponse(c, message))
+	}
+	return true
+}
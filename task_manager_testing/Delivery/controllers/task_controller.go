@@ -1,12 +1,17 @@
 package controllers
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	infrastructure "task_manager_testing/Infrastructure"
 	"task_manager_testing/domain"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 // TaskController handles HTTP requests for task operations.
@@ -31,17 +36,18 @@ func NewTaskController(taskUsecase domain.TaskUsecase, userUsecase domain.UserUs
 func (tc *TaskController) AddTask(c *gin.Context) {
 	var task domain.Task
 	if err := c.BindJSON(&task); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to parse request. Ensure the task data is correct: " + err.Error()})
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Failed to parse request. Ensure the task data is correct: "+err.Error()))
 		return
 	}
 
-	// Set the task ID to a new ObjectID
+	// Set the task ID to a new ObjectID; labels are added through the task label routes
 	task.ID = primitive.NewObjectID()
+	task.Labels = nil
 
 	// Retrieve user claims from the context (set by middleware)
 	claims, exists := c.Get("user")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized. Please log in to add a task."})
+		c.JSON(http.StatusUnauthorized, infrastructure.ErrorResponse(c, "Unauthorized. Please log in to add a task."))
 		return
 	}
 
@@ -49,14 +55,14 @@ func (tc *TaskController) AddTask(c *gin.Context) {
 	userClaims := claims.(*domain.Claims)
 	createdByID, err := primitive.ObjectIDFromHex(userClaims.UserID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID. Please try again."})
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid user ID. Please try again."))
 		return
 	}
 	task.CreatedBy = createdByID
 
 	// Delegate task creation to the TaskUsecase
-	if err := tc.TaskUsecase.AddTask(task); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to add task. Please ensure all required fields are filled: " + err.Error()})
+	if err := tc.TaskUsecase.AddTask(c.Request.Context(), task); err != nil {
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Failed to add task. Please ensure all required fields are filled: "+err.Error()))
 		return
 	}
 
@@ -66,27 +72,54 @@ func (tc *TaskController) AddTask(c *gin.Context) {
 
 // GetMyTasks retrieves tasks created by the logged-in user.
 // It validates the user and fetches their tasks via the TaskUsecase.
+// The tasks can be filtered with the status, labels and match query
+// parameters: labels is a comma-separated list of label IDs, all of which a
+// task must carry unless match is "any".
 func (tc *TaskController) GetMyTasks(c *gin.Context) {
 	// Retrieve user claims from the context
 	claims, exists := c.Get("user")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized. Please log in to view your tasks."})
+		c.JSON(http.StatusUnauthorized, infrastructure.ErrorResponse(c, "Unauthorized. Please log in to view your tasks."))
 		return
 	}
 
 	// Extract user ID from claims
 	userClaims, ok := claims.(*domain.Claims)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user claims. Please try again."})
+		c.JSON(http.StatusUnauthorized, infrastructure.ErrorResponse(c, "Invalid user claims. Please try again."))
 		return
 	}
 
 	userId, _ := primitive.ObjectIDFromHex(userClaims.UserID)
 
+	// Filter the tasks when any filter is given
+	status, match := c.Query("status"), c.Query("match")
+	labels, err := parseObjectIDs(c.QueryArray("labels"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid label ID format."))
+		return
+	}
+	if status != "" || match != "" || len(labels) > 0 {
+		tasks, err := tc.TaskUsecase.FindTasks(c.Request.Context(), domain.TaskFilter{CreatedBy: userId, Status: status, Labels: labels, LabelMatch: match})
+		if errors.Is(err, domain.ErrInvalidTask) {
+			c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, err.Error()))
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, infrastructure.ErrorResponse(c, "Failed to retrieve your tasks. Please try again later."))
+			return
+		}
+		if tasks == nil {
+			tasks = []domain.Task{}
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Your tasks retrieved successfully!", "tasks": tasks})
+		return
+	}
+
 	// Fetch tasks created by the user from the TaskUsecase
-	tasks, err := tc.TaskUsecase.GetMyTasks(userId)
+	tasks, err := tc.TaskUsecase.GetMyTasks(c.Request.Context(), userId)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Failed to retrieve your tasks. Please try again later: " + err.Error()})
+		c.JSON(http.StatusNotFound, infrastructure.ErrorResponse(c, "Failed to retrieve your tasks. Please try again later: "+err.Error()))
 		return
 	}
 
@@ -98,9 +131,9 @@ func (tc *TaskController) GetMyTasks(c *gin.Context) {
 // It delegates the task retrieval to the TaskUsecase.
 func (tc *TaskController) GetAllTasks(c *gin.Context) {
 	// Fetch all tasks from the TaskUsecase
-	tasks, err := tc.TaskUsecase.GetAllTasks()
+	tasks, err := tc.TaskUsecase.GetAllTasks(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Failed to retrieve tasks. Please try again later: " + err.Error()})
+		c.JSON(http.StatusNotFound, infrastructure.ErrorResponse(c, "Failed to retrieve tasks. Please try again later: "+err.Error()))
 		return
 	}
 
@@ -116,14 +149,14 @@ func (tc *TaskController) GetTaskById(c *gin.Context) {
 	// Convert the task ID from string to ObjectID
 	id, err := primitive.ObjectIDFromHex(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format. Please provide a valid task ID."})
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid ID format. Please provide a valid task ID."))
 		return
 	}
 
 	// Fetch the task by ID from the TaskUsecase
-	task, err := tc.TaskUsecase.GetTaskById(id)
+	task, err := tc.TaskUsecase.GetTaskById(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found. Please ensure the task ID is correct: " + err.Error()})
+		c.JSON(http.StatusNotFound, infrastructure.ErrorResponse(c, "Task not found. Please ensure the task ID is correct: "+err.Error()))
 		return
 	}
 
@@ -139,7 +172,7 @@ func (tc *TaskController) UpdateFullTask(c *gin.Context) {
 	// Convert the task ID from string to ObjectID
 	id, err := primitive.ObjectIDFromHex(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format. Please provide a valid task ID."})
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid ID format. Please provide a valid task ID."))
 		return
 	}
 
@@ -151,32 +184,29 @@ func (tc *TaskController) UpdateFullTask(c *gin.Context) {
 	var task domain.Task
 	task.ID = id
 	if err := c.BindJSON(&task); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to parse request. Ensure the task data is correct: " + err.Error()})
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Failed to parse request. Ensure the task data is correct: "+err.Error()))
 		return
 	}
 
-	// Fetch the user who created the task
-	otherUser, _ := tc.UserUsecase.GetUserById(task.CreatedBy)
-
-	// Authorization checks based on user roles
-	if userClaims.Role == "user" && userClaims.UserID != task.CreatedBy.Hex() {
-		c.JSON(http.StatusForbidden, gin.H{"error": "You can only edit your own tasks."})
+	// Fetch the stored task, since the creator in the request cannot be trusted
+	stored, err := tc.TaskUsecase.GetTaskById(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, infrastructure.ErrorResponse(c, "Task not found. Please ensure the task ID is correct: "+err.Error()))
 		return
 	}
 
-	if userClaims.Role == "admin" && (otherUser.Role == "admin" || otherUser.Role == "root") {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Admins cannot edit tasks of other admins or root users."})
-		return
-	}
+	// Fetch the user who created the task
+	otherUser, _ := tc.UserUsecase.GetUserById(c.Request.Context(), stored.CreatedBy)
 
-	if userClaims.Role == "root" && otherUser.Role == "root" && userClaims.UserID != task.CreatedBy.Hex() {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Root users cannot edit tasks of other root users."})
+	// Authorization checks based on user roles
+	if message := taskAccessError(userClaims, stored.CreatedBy, otherUser, "edit"); message != "" {
+		c.JSON(http.StatusForbidden, infrastructure.ErrorResponse(c, message))
 		return
 	}
 
 	// Delegate the full task update to the TaskUsecase
-	if err := tc.TaskUsecase.UpdateFullTask(id, task); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to update task. Please ensure all required fields are filled: " + err.Error()})
+	if err := tc.TaskUsecase.UpdateFullTask(c.Request.Context(), id, task); err != nil {
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Failed to update task. Please ensure all required fields are filled: "+err.Error()))
 		return
 	}
 
@@ -187,13 +217,26 @@ func (tc *TaskController) UpdateFullTask(c *gin.Context) {
 // UpdateSomeTask handles partial updates to a task by its ID.
 // It validates the task ID, performs authorization checks,
 // and delegates the partial update to the TaskUsecase.
+// The scope query parameter of a recurring task is "this" to change this
+// occurrence only, the default, or "future" to change the future occurrences too.
 func (tc *TaskController) UpdateSomeTask(c *gin.Context) {
 	idStr := c.Param("id")
 
 	// Convert the task ID from string to ObjectID
 	id, err := primitive.ObjectIDFromHex(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format. Please provide a valid task ID."})
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid ID format. Please provide a valid task ID."))
+		return
+	}
+
+	// Pick how far the change reaches in a series of recurring tasks
+	update := tc.TaskUsecase.UpdateSomeTask
+	switch c.DefaultQuery("scope", domain.EditThisOccurrence) {
+	case domain.EditThisOccurrence:
+	case domain.EditFutureOccurrences:
+		update = tc.TaskUsecase.UpdateFutureOccurrences
+	default:
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, fmt.Sprintf("Invalid scope. Use %q or %q.", domain.EditThisOccurrence, domain.EditFutureOccurrences)))
 		return
 	}
 
@@ -202,41 +245,31 @@ func (tc *TaskController) UpdateSomeTask(c *gin.Context) {
 	userClaims := claims.(*domain.Claims)
 
 	// Fetch the task by ID from the TaskUsecase
-	task, err := tc.TaskUsecase.GetTaskById(id)
+	task, err := tc.TaskUsecase.GetTaskById(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found. Please ensure the task ID is correct: " + err.Error()})
+		c.JSON(http.StatusNotFound, infrastructure.ErrorResponse(c, "Task not found. Please ensure the task ID is correct: "+err.Error()))
 		return
 	}
 
 	// Fetch the user who created the task
-	otherUser, _ := tc.UserUsecase.GetUserById(task.CreatedBy)
+	otherUser, _ := tc.UserUsecase.GetUserById(c.Request.Context(), task.CreatedBy)
 
 	// Authorization checks based on user roles
-	if userClaims.Role == "user" && userClaims.UserID != task.CreatedBy.Hex() {
-		c.JSON(http.StatusForbidden, gin.H{"error": "You can only edit your own tasks."})
-		return
-	}
-
-	if userClaims.Role == "admin" && (otherUser.Role == "admin" || otherUser.Role == "root") {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Admins cannot edit tasks of other admins or root users."})
-		return
-	}
-
-	if userClaims.Role == "root" && otherUser.Role == "root" && userClaims.UserID != task.CreatedBy.Hex() {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Root users cannot edit tasks of other root users."})
+	if message := taskAccessError(userClaims, task.CreatedBy, otherUser, "edit"); message != "" {
+		c.JSON(http.StatusForbidden, infrastructure.ErrorResponse(c, message))
 		return
 	}
 
 	// Bind the incoming JSON to a map for partial update
 	var updatedFields bson.M
 	if err := c.BindJSON(&updatedFields); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to parse request. Ensure the task data is correct: " + err.Error()})
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Failed to parse request. Ensure the task data is correct: "+err.Error()))
 		return
 	}
 
 	// Delegate the partial update to the TaskUsecase
-	if err := tc.TaskUsecase.UpdateSomeTask(id, updatedFields); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to update task. Please ensure all required fields are filled: " + err.Error()})
+	if err := update(c.Request.Context(), id, updatedFields); err != nil {
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Failed to update task. Please ensure all required fields are filled: "+err.Error()))
 		return
 	}
 
@@ -253,7 +286,7 @@ func (tc *TaskController) DeleteTask(c *gin.Context) {
 	// Convert the task ID from string to ObjectID
 	id, err := primitive.ObjectIDFromHex(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format. Please provide a valid task ID."})
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid ID format. Please provide a valid task ID."))
 		return
 	}
 
@@ -262,37 +295,142 @@ func (tc *TaskController) DeleteTask(c *gin.Context) {
 	userClaims := claims.(*domain.Claims)
 
 	// Fetch the task by ID from the TaskUsecase
-	task, err := tc.TaskUsecase.GetTaskById(id)
+	task, err := tc.TaskUsecase.GetTaskById(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found. Please ensure the task ID is correct: " + err.Error()})
+		c.JSON(http.StatusNotFound, infrastructure.ErrorResponse(c, "Task not found. Please ensure the task ID is correct: "+err.Error()))
 		return
 	}
 
 	// Fetch the user who created the task
-	otherUser, _ := tc.UserUsecase.GetUserById(task.CreatedBy)
+	otherUser, _ := tc.UserUsecase.GetUserById(c.Request.Context(), task.CreatedBy)
 
 	// Authorization checks based on user roles
-	if userClaims.Role == "user" && userClaims.UserID != task.CreatedBy.Hex() {
-		c.JSON(http.StatusForbidden, gin.H{"error": "You can only delete your own tasks."})
+	if message := taskAccessError(userClaims, task.CreatedBy, otherUser, "delete"); message != "" {
+		c.JSON(http.StatusForbidden, infrastructure.ErrorResponse(c, message))
 		return
 	}
 
-	if userClaims.Role == "admin" && (otherUser.Role == "admin" || otherUser.Role == "root") {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Admins cannot delete tasks of other admins or root users."})
+	// Delegate the task deletion to the TaskUsecase
+	if err := tc.TaskUsecase.DeleteTask(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Failed to delete task. Please try again: "+err.Error()))
+		return
+	}
+
+	// Respond with success message
+	c.JSON(http.StatusOK, gin.H{"message": "Task deleted successfully!"})
+}
+
+// bulkTasksRequest is the body of a bulk request. With Atomic set, either
+// every operation succeeds or none is applied.
+type bulkTasksRequest struct {
+	Atomic     bool                       `json:"atomic"`
+	Operations []domain.BulkTaskOperation `json:"operations" binding:"required"`
+}
+
+// BulkTasks applies a list of create, update, delete and status operations.
+// Each operation is authorized with the same role rules as the single task
+// endpoints and gets its own result in the response.
+func (tc *TaskController) BulkTasks(c *gin.Context) {
+	var request bulkTasksRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Failed to parse request. Ensure the operations are correct: "+err.Error()))
 		return
 	}
 
-	if userClaims.Role == "root" && otherUser.Role == "root" && userClaims.UserID != task.CreatedBy.Hex() {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Root users cannot delete tasks of other root users."})
+	// Retrieve user claims from the context
+	claims, _ := c.Get("user")
+	userClaims := claims.(*domain.Claims)
+	userId, err := primitive.ObjectIDFromHex(userClaims.UserID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid user ID. Please try again."))
 		return
 	}
 
-	// Delegate the task deletion to the TaskUsecase
-	if err := tc.TaskUsecase.DeleteTask(id); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to delete task. Please try again: " + err.Error()})
+	// New tasks always belong to the caller
+	for _, op := range request.Operations {
+		if op.Op == domain.BulkCreate && op.Task != nil {
+			op.Task.ID = primitive.NewObjectID()
+			op.Task.CreatedBy = userId
+		}
+	}
+
+	authorize := func(ctx context.Context, task domain.Task) error {
+		otherUser, _ := tc.UserUsecase.GetUserById(ctx, task.CreatedBy)
+		if message := taskAccessError(userClaims, task.CreatedBy, otherUser, "change"); message != "" {
+			return fmt.Errorf("%w: %s", domain.ErrForbidden, message)
+		}
+		return nil
+	}
+
+	results, err := tc.TaskUsecase.BulkTasks(c.Request.Context(), request.Operations, request.Atomic, authorize)
+	if errors.Is(err, domain.ErrInvalidTask) {
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, infrastructure.ErrorResponse(c, "Failed to apply bulk operations. Please try again: "+err.Error()))
 		return
 	}
 
-	// Respond with success message
-	c.JSON(http.StatusOK, gin.H{"message": "Task deleted successfully!"})
+	failed := 0
+	for i := range results {
+		results[i].Status = bulkResultStatus(results[i])
+		if results[i].Status == http.StatusNotFound {
+			results[i].Error = "Task not found."
+		}
+		if results[i].Err != nil {
+			failed++
+		}
+	}
+
+	// 207 tells clients to look at the individual results
+	status := http.StatusOK
+	if failed > 0 {
+		status = http.StatusMultiStatus
+	}
+	c.JSON(status, gin.H{
+		"message":   "Bulk operations processed.",
+		"atomic":    request.Atomic,
+		"succeeded": len(results) - failed,
+		"failed":    failed,
+		"results":   results,
+	})
+}
+
+// taskAccessError returns why the caller described by claims may not perform
+// action on a task created by owner, or an empty string when it is allowed.
+func taskAccessError(claims *domain.Claims, createdBy primitive.ObjectID, owner domain.User, action string) string {
+	if claims.Role == "user" && claims.UserID != createdBy.Hex() {
+		return "You can only " + action + " your own tasks."
+	}
+
+	if claims.Role == "admin" && (owner.Role == "admin" || owner.Role == "root") {
+		return "Admins cannot " + action + " tasks of other admins or root users."
+	}
+
+	if claims.Role == "root" && owner.Role == "root" && claims.UserID != createdBy.Hex() {
+		return "Root users cannot " + action + " tasks of other root users."
+	}
+	return ""
+}
+
+// bulkResultStatus maps the outcome of a bulk operation to the status the
+// equivalent single request would have returned.
+func bulkResultStatus(result domain.BulkTaskResult) int {
+	switch {
+	case result.Err == nil && result.Op == domain.BulkCreate:
+		return http.StatusCreated
+	case result.Err == nil:
+		return http.StatusOK
+	case errors.Is(result.Err, domain.ErrInvalidTask):
+		return http.StatusBadRequest
+	case errors.Is(result.Err, domain.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(result.Err, mongo.ErrNoDocuments):
+		return http.StatusNotFound
+	case errors.Is(result.Err, domain.ErrRolledBack):
+		return http.StatusFailedDependency
+	default:
+		return http.StatusInternalServerError
+	}
 }
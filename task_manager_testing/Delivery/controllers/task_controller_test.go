@@ -13,16 +13,17 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type TaskControllerSuite struct {
 	suite.Suite
-	taskUsecase   mocks.TaskUsecase
+	taskUsecase   *mocks.TaskUsecase
 	taskCtrl      controllers.TaskController
 	testingServer *httptest.Server
-	userUsecase   mocks.UserUsecase
+	userUsecase   *mocks.UserUsecase
 	userCtrl      controllers.UserController
 }
 
@@ -41,7 +42,7 @@ func (suite *TaskControllerSuite) SetupTest() {
 
 	testingServer := httptest.NewServer(router)
 	suite.testingServer = testingServer
-	suite.taskUsecase = *taskUsecase
+	suite.taskUsecase = taskUsecase
 	suite.taskCtrl = *handler
 }
 
@@ -59,7 +60,7 @@ func (suite *TaskControllerSuite) TestAddTask() {
 		CreatedBy:   primitive.NewObjectID(),
 	}
 
-	suite.taskUsecase.On("AddTask", task).Return(nil)
+	suite.taskUsecase.On("AddTask", mock.Anything, task).Return(nil)
 
 	requestBody, err := json.Marshal(&task)
 	suite.NoError(err, "can not marshal struct to json")
@@ -83,7 +84,7 @@ func (suite *TaskControllerSuite) TestGetMyTasks() {
 		CreatedBy:   primitive.NewObjectID(),
 	}
 
-	suite.taskUsecase.On("GetMyTasks", task.CreatedBy).Return([]domain.Task{task}, nil)
+	suite.taskUsecase.On("GetMyTasks", mock.Anything, task.CreatedBy).Return([]domain.Task{task}, nil)
 
 	response, err := http.Get(fmt.Sprintf("%s/task/%s", suite.testingServer.URL, task.CreatedBy.Hex()))
 	suite.NoError(err, "no error when calling the endpoint")
@@ -103,7 +104,7 @@ func (suite *TaskControllerSuite) TestGetAllTasks() {
 		CreatedBy:   primitive.NewObjectID(),
 	}
 
-	suite.taskUsecase.On("GetAllTasks").Return([]domain.Task{task}, nil)
+	suite.taskUsecase.On("GetAllTasks", mock.Anything, mock.Anything).Return([]domain.Task{task}, nil)
 
 	response, err := http.Get(fmt.Sprintf("%s/tasks", suite.testingServer.URL))
 	suite.NoError(err, "no error when calling the endpoint")
@@ -123,7 +124,7 @@ func (suite *TaskControllerSuite) TestUpdateFullTask() {
 		CreatedBy:   primitive.NewObjectID(),
 	}
 
-	suite.taskUsecase.On("UpdateFullTask", task.ID, task).Return(nil)
+	suite.taskUsecase.On("UpdateFullTask", mock.Anything, task.ID, task).Return(nil)
 
 	requestBody, err := json.Marshal(&task)
 	suite.NoError(err, "can not marshal struct to json")
@@ -158,7 +159,7 @@ func (suite *TaskControllerSuite) TestUpdateSomeTask() {
 		"status": "In Progress",
 	}
 
-	suite.taskUsecase.On("UpdateSomeTask", task.ID, update).Return(nil)
+	suite.taskUsecase.On("UpdateSomeTask", mock.Anything, task.ID, update).Return(nil)
 
 	requestBody, err := json.Marshal(&update)
 	suite.NoError(err, "can not marshal struct to json")
@@ -189,7 +190,7 @@ func (suite *TaskControllerSuite) TestDeleteTask() {
 		CreatedBy:   primitive.NewObjectID(),
 	}
 
-	suite.taskUsecase.On("DeleteTask", task.ID).Return(nil)
+	suite.taskUsecase.On("DeleteTask", mock.Anything, task.ID).Return(nil)
 
 	req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/task/%s", suite.testingServer.URL, task.ID.Hex()), nil)
 	suite.NoError(err, "can not create DELETE request")
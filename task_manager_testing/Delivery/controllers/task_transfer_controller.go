@@ -0,0 +1,451 @@
+package controllers
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strings"
+	infrastructure "task_manager_testing/Infrastructure"
+	"task_manager_testing/domain"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Import and export formats.
+const (
+	formatCSV    = "csv"
+	formatJSON   = "json"
+	formatNDJSON = "ndjson"
+)
+
+// exportFlushEvery is how many tasks are written between flushes.
+const exportFlushEvery = 100
+
+// exportColumns are the CSV columns of an export, in order.
+var exportColumns = []string{"id", "title", "description", "due_date", "status", "created_by"}
+
+// importFields are the task fields an import can set.
+var importFields = map[string]bool{"title": true, "description": true, "due_date": true, "status": true}
+
+// ExportTasks streams the tasks visible to the caller as CSV, a JSON array or
+// newline-delimited JSON, chosen with the format query parameter. Users see
+// their own tasks and admins and root users see every task.
+func (tc *TaskController) ExportTasks(c *gin.Context) {
+	format := c.DefaultQuery("format", formatJSON)
+	if format != formatCSV && format != formatJSON && format != formatNDJSON {
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid format. Use csv, json or ndjson."))
+		return
+	}
+
+	// Retrieve user claims from the context
+	claims, _ := c.Get("user")
+	userClaims := claims.(*domain.Claims)
+	owner := primitive.NilObjectID
+	if userClaims.Role == "user" {
+		userId, err := primitive.ObjectIDFromHex(userClaims.UserID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid user ID. Please try again."))
+			return
+		}
+		owner = userId
+	}
+
+	contentTypes := map[string]string{
+		formatCSV:    "text/csv; charset=utf-8",
+		formatJSON:   "application/json; charset=utf-8",
+		formatNDJSON: "application/x-ndjson",
+	}
+	c.Header("Content-Type", contentTypes[format])
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="tasks.%s"`, format))
+	c.Status(http.StatusOK)
+
+	writer := newTaskWriter(format, c.Writer)
+	count := 0
+	err := tc.TaskUsecase.ExportTasks(c.Request.Context(), owner, func(task domain.Task) error {
+		if err := writer.write(task); err != nil {
+			return err
+		}
+		if count++; count%exportFlushEvery == 0 {
+			return writer.flush(c.Writer)
+		}
+		return nil
+	})
+	if err == nil {
+		err = writer.close()
+	}
+	if err != nil {
+		// The status line is already sent, so the client sees a truncated body
+		slog.ErrorContext(c.Request.Context(), "task export failed", slog.String("error", err.Error()))
+		c.Error(err)
+		return
+	}
+	c.Writer.Flush()
+}
+
+// taskWriter encodes exported tasks in one of the export formats.
+type taskWriter struct {
+	format  string
+	w       io.Writer
+	csv     *csv.Writer
+	written int
+}
+
+func newTaskWriter(format string, w io.Writer) *taskWriter {
+	tw := &taskWriter{format: format, w: w}
+	if format == formatCSV {
+		tw.csv = csv.NewWriter(w)
+	}
+	return tw
+}
+
+func (tw *taskWriter) write(task domain.Task) error {
+	defer func() { tw.written++ }()
+
+	switch tw.format {
+	case formatCSV:
+		if tw.written == 0 {
+			if err := tw.csv.Write(exportColumns); err != nil {
+				return err
+			}
+		}
+		dueDate := ""
+		if task.DueDate != 0 {
+			dueDate = task.DueDate.Time().UTC().Format(time.RFC3339)
+		}
+		return tw.csv.Write([]string{task.ID.Hex(), csvCell(task.Title), csvCell(task.Description), dueDate, task.Status, task.CreatedBy.Hex()})
+	case formatNDJSON:
+		return json.NewEncoder(tw.w).Encode(task)
+	default:
+		prefix := ","
+		if tw.written == 0 {
+			prefix = "["
+		}
+		body, err := json.Marshal(task)
+		if err != nil {
+			return err
+		}
+		_, err = tw.w.Write(append([]byte(prefix), body...))
+		return err
+	}
+}
+
+// csvFormulaStarts are the characters spreadsheets start a formula with.
+const csvFormulaStarts = "=+-@\t\r"
+
+// csvCell keeps spreadsheets from running value as a formula by prefixing
+// values that start like one with a single quote.
+func csvCell(value string) string {
+	if value != "" && strings.ContainsRune(csvFormulaStarts, rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// csvValue removes the quote csvCell adds, so exported tasks import unchanged.
+// A value that really starts with a quote before a formula character loses it.
+func csvValue(cell string) string {
+	if len(cell) > 1 && cell[0] == '\'' && strings.ContainsRune(csvFormulaStarts, rune(cell[1])) {
+		return cell[1:]
+	}
+	return cell
+}
+
+func (tw *taskWriter) flush(f http.Flusher) error {
+	if tw.csv != nil {
+		tw.csv.Flush()
+		if err := tw.csv.Error(); err != nil {
+			return err
+		}
+	}
+	f.Flush()
+	return nil
+}
+
+// close finishes the document, writing the header or brackets of an empty export.
+func (tw *taskWriter) close() error {
+	switch tw.format {
+	case formatCSV:
+		if tw.written == 0 {
+			tw.csv.Write(exportColumns)
+		}
+		tw.csv.Flush()
+		return tw.csv.Error()
+	case formatJSON:
+		closing := "]"
+		if tw.written == 0 {
+			closing = "[]"
+		}
+		_, err := io.WriteString(tw.w, closing)
+		return err
+	}
+	return nil
+}
+
+// ImportTasks creates tasks from a CSV file, a JSON array of objects or
+// newline-delimited JSON. The format comes from the format query parameter or
+// the Content-Type. Source columns are matched to the title, description,
+// due_date and status fields by name, or with a columns mapping such as
+// columns=Name:title,Due:due_date. With dry_run=true the rows are only
+// validated. Every row is reported with its own result.
+func (tc *TaskController) ImportTasks(c *gin.Context) {
+	format := c.Query("format")
+	if format == "" {
+		format = importFormat(c.ContentType())
+	}
+	if format != formatCSV && format != formatJSON && format != formatNDJSON {
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid format. Use csv, json or ndjson."))
+		return
+	}
+
+	mapping, err := parseColumnMapping(c.Query("columns"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, err.Error()))
+		return
+	}
+	dryRun := c.Query("dry_run") == "true"
+
+	// Retrieve user claims from the context
+	claims, _ := c.Get("user")
+	userClaims := claims.(*domain.Claims)
+	userId, err := primitive.ObjectIDFromHex(userClaims.UserID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid user ID. Please try again."))
+		return
+	}
+
+	rows, err := parseImportRows(format, c.Request.Body, mapping)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Failed to parse import: "+err.Error()))
+		return
+	}
+
+	// Imported tasks always belong to the caller
+	for i := range rows {
+		rows[i].Task.ID = primitive.NewObjectID()
+		rows[i].Task.CreatedBy = userId
+	}
+
+	results, err := tc.TaskUsecase.ImportTasks(c.Request.Context(), rows, dryRun)
+	if errors.Is(err, domain.ErrInvalidTask) {
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, infrastructure.ErrorResponse(c, "Failed to import tasks. Please try again: "+err.Error()))
+		return
+	}
+
+	counts := map[string]int{}
+	for _, result := range results {
+		counts[result.Status]++
+	}
+
+	// 207 tells clients to look at the individual rows
+	status := http.StatusOK
+	if counts[domain.ImportInvalid]+counts[domain.ImportFailed] > 0 {
+		status = http.StatusMultiStatus
+	}
+	c.JSON(status, gin.H{
+		"message": "Import processed.",
+		"dry_run": dryRun,
+		"created": counts[domain.ImportCreated],
+		"valid":   counts[domain.ImportCreated] + counts[domain.ImportValid],
+		"invalid": counts[domain.ImportInvalid],
+		"failed":  counts[domain.ImportFailed],
+		"results": results,
+	})
+}
+
+// importFormat picks the import format from the request's media type.
+func importFormat(contentType string) string {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "text/csv":
+		return formatCSV
+	case "application/x-ndjson", "application/ndjson":
+		return formatNDJSON
+	default:
+		return formatJSON
+	}
+}
+
+// parseColumnMapping parses "Source:field,Other:field" into a map from source
+// column to task field.
+func parseColumnMapping(raw string) (map[string]string, error) {
+	mapping := map[string]string{}
+	if raw == "" {
+		return mapping, nil
+	}
+	for _, pair := range strings.Split(raw, ",") {
+		source, field, ok := strings.Cut(pair, ":")
+		source, field = strings.TrimSpace(source), strings.TrimSpace(field)
+		if !ok || source == "" {
+			return nil, fmt.Errorf("invalid column mapping %q, expected Source:field", pair)
+		}
+		if !importFields[field] {
+			return nil, fmt.Errorf("cannot map column %q to unknown field %q", source, field)
+		}
+		mapping[source] = field
+	}
+	return mapping, nil
+}
+
+// parseImportRows reads the rows of body. Problems with a single row are
+// reported on that row; an error is only returned when body cannot be read.
+// Reading stops one row past domain.MaxImportRows.
+func parseImportRows(format string, body io.Reader, mapping map[string]string) ([]domain.TaskImportRow, error) {
+	switch format {
+	case formatCSV:
+		return parseCSVRows(body, mapping)
+	case formatNDJSON:
+		return parseNDJSONRows(body, mapping)
+	default:
+		return parseJSONRows(body, mapping)
+	}
+}
+
+func parseCSVRows(body io.Reader, mapping map[string]string) ([]domain.TaskImportRow, error) {
+	reader := csv.NewReader(body)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\uFEFF")
+	}
+
+	var rows []domain.TaskImportRow
+	for len(rows) <= domain.MaxImportRows {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		row := len(rows) + 1
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			rows = append(rows, domain.TaskImportRow{Row: row, Errors: []string{parseErr.Err.Error()}})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		values := make(map[string]string, len(header))
+		for i, column := range header {
+			values[column] = csvValue(record[i])
+		}
+		rows = append(rows, buildImportRow(row, values, mapping))
+	}
+	return rows, nil
+}
+
+func parseJSONRows(body io.Reader, mapping map[string]string) ([]domain.TaskImportRow, error) {
+	decoder := json.NewDecoder(body)
+	if token, err := decoder.Token(); err != nil || token != json.Delim('[') {
+		return nil, fmt.Errorf("expected a JSON array of tasks")
+	}
+
+	var rows []domain.TaskImportRow
+	for decoder.More() && len(rows) <= domain.MaxImportRows {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return nil, err
+		}
+		rows = append(rows, parseJSONRow(len(rows)+1, raw, mapping))
+	}
+	return rows, nil
+}
+
+func parseNDJSONRows(body io.Reader, mapping map[string]string) ([]domain.TaskImportRow, error) {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var rows []domain.TaskImportRow
+	for scanner.Scan() && len(rows) <= domain.MaxImportRows {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		rows = append(rows, parseJSONRow(len(rows)+1, line, mapping))
+	}
+	return rows, scanner.Err()
+}
+
+// parseJSONRow reads one JSON object as a row.
+func parseJSONRow(row int, raw []byte, mapping map[string]string) domain.TaskImportRow {
+	var object map[string]interface{}
+	if err := json.Unmarshal(raw, &object); err != nil || object == nil {
+		return domain.TaskImportRow{Row: row, Errors: []string{"row must be a JSON object"}}
+	}
+
+	values := make(map[string]string, len(object))
+	for key, value := range object {
+		switch v := value.(type) {
+		case nil:
+		case string:
+			values[key] = v
+		default:
+			values[key] = fmt.Sprint(v)
+		}
+	}
+	return buildImportRow(row, values, mapping)
+}
+
+// buildImportRow maps the columns of a row to task fields. Columns that are
+// neither mapped nor named after a field are ignored.
+func buildImportRow(row int, values map[string]string, mapping map[string]string) domain.TaskImportRow {
+	result := domain.TaskImportRow{Row: row}
+	for column, value := range values {
+		field, mapped := mapping[column]
+		if !mapped {
+			field = strings.ToLower(strings.TrimSpace(column))
+		}
+		value = strings.TrimSpace(value)
+
+		switch field {
+		case "title":
+			result.Task.Title = value
+		case "description":
+			result.Task.Description = value
+		case "status":
+			result.Task.Status = value
+		case "due_date":
+			if value == "" {
+				continue
+			}
+			dueDate, err := parseDueDate(value)
+			if err != nil {
+				result.Errors = append(result.Errors, err.Error())
+				continue
+			}
+			result.Task.DueDate = primitive.NewDateTimeFromTime(dueDate)
+		}
+	}
+	return result
+}
+
+// parseDueDate accepts RFC 3339 timestamps and plain dates.
+func parseDueDate(value string) (time.Time, error) {
+	if dueDate, err := time.Parse(time.RFC3339, value); err == nil {
+		return dueDate, nil
+	}
+	if dueDate, err := time.Parse(time.DateOnly, value); err == nil {
+		return dueDate, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid due date %q, expected YYYY-MM-DD or RFC 3339", value)
+}
@@ -0,0 +1,190 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	infrastructure "task_manager_testing/Infrastructure"
+	"task_manager_testing/domain"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// TimeEntryController handles time tracking on tasks and the time reports.
+type TimeEntryController struct {
+	TimeEntryUsecase domain.TimeEntryUsecase
+}
+
+// NewTimeEntryController initializes a new TimeEntryController.
+func NewTimeEntryController(timeEntryUsecase domain.TimeEntryUsecase) *TimeEntryController {
+	return &TimeEntryController{TimeEntryUsecase: timeEntryUsecase}
+}
+
+// StartTimer starts the caller's timer on a task.
+func (tc *TimeEntryController) StartTimer(c *gin.Context) {
+	actor, taskId, ok := tc.taskParams(c)
+	if !ok {
+		return
+	}
+
+	entry, err := tc.TimeEntryUsecase.StartTimer(c.Request.Context(), actor, taskId)
+	if tc.writeError(c, err, "Failed to start the timer. Please try again later.") {
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Timer started.", "entry": entry})
+}
+
+// StopTimer stops the caller's timer on a task.
+func (tc *TimeEntryController) StopTimer(c *gin.Context) {
+	actor, taskId, ok := tc.taskParams(c)
+	if !ok {
+		return
+	}
+
+	entry, err := tc.TimeEntryUsecase.StopTimer(c.Request.Context(), actor, taskId)
+	if tc.writeError(c, err, "Failed to stop the timer. Please try again later.") {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Timer stopped.", "entry": entry})
+}
+
+// GetTaskTime lists the time entries of a task with the time per user.
+func (tc *TimeEntryController) GetTaskTime(c *gin.Context) {
+	actor, taskId, ok := tc.taskParams(c)
+	if !ok {
+		return
+	}
+
+	taskTime, err := tc.TimeEntryUsecase.GetTaskTime(c.Request.Context(), actor, taskId)
+	if tc.writeError(c, err, "Failed to retrieve the tracked time. Please try again later.") {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Tracked time retrieved successfully.", "time": taskTime})
+}
+
+// TimeByUser reports the time tracked per user, filtered by the user_id,
+// from and to query parameters.
+func (tc *TimeEntryController) TimeByUser(c *gin.Context) {
+	actor, query, ok := tc.queryParams(c)
+	if !ok {
+		return
+	}
+
+	users, err := tc.TimeEntryUsecase.TimeByUser(c.Request.Context(), actor, query)
+	if tc.writeError(c, err, "Failed to retrieve the tracked time. Please try again later.") {
+		return
+	}
+	if users == nil {
+		users = []domain.UserTime{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Tracked time retrieved successfully.", "users": users})
+}
+
+// TimeByWeek reports the time tracked per ISO week, filtered like TimeByUser.
+func (tc *TimeEntryController) TimeByWeek(c *gin.Context) {
+	actor, query, ok := tc.queryParams(c)
+	if !ok {
+		return
+	}
+
+	weeks, err := tc.TimeEntryUsecase.TimeByWeek(c.Request.Context(), actor, query)
+	if tc.writeError(c, err, "Failed to retrieve the tracked time. Please try again later.") {
+		return
+	}
+	if weeks == nil {
+		weeks = []domain.WeekTime{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Tracked time retrieved successfully.", "weeks": weeks})
+}
+
+// actor returns the caller of the request, or writes the error response and
+// returns false.
+func (tc *TimeEntryController) actor(c *gin.Context) (domain.User, bool) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, infrastructure.ErrorResponse(c, "Unauthorized. Please log in to track time."))
+		return domain.User{}, false
+	}
+
+	claims, _ := c.Get("user")
+	userClaims := claims.(*domain.Claims)
+	return domain.User{ID: userId, Username: userClaims.Username, Role: userClaims.Role}, true
+}
+
+// taskParams returns the caller and the task ID of the request, or writes
+// the error response and returns false.
+func (tc *TimeEntryController) taskParams(c *gin.Context) (domain.User, primitive.ObjectID, bool) {
+	actor, ok := tc.actor(c)
+	if !ok {
+		return domain.User{}, primitive.NilObjectID, false
+	}
+
+	taskId, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid ID format. Please provide a valid task ID."))
+		return domain.User{}, primitive.NilObjectID, false
+	}
+	return actor, taskId, true
+}
+
+// queryParams returns the caller and the time query of the request, or
+// writes the error response and returns false.
+func (tc *TimeEntryController) queryParams(c *gin.Context) (domain.User, domain.TimeQuery, bool) {
+	actor, ok := tc.actor(c)
+	if !ok {
+		return domain.User{}, domain.TimeQuery{}, false
+	}
+
+	var query domain.TimeQuery
+	if value := c.Query("user_id"); value != "" {
+		userId, err := primitive.ObjectIDFromHex(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid user ID format."))
+			return domain.User{}, domain.TimeQuery{}, false
+		}
+		query.UserID = userId
+	}
+	for _, param := range []struct {
+		name  string
+		value *time.Time
+	}{{"from", &query.From}, {"to", &query.To}} {
+		value := c.Query(param.name)
+		if value == "" {
+			continue
+		}
+		at, err := parseDueDate(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid "+param.name+" date, expected YYYY-MM-DD or RFC 3339."))
+			return domain.User{}, domain.TimeQuery{}, false
+		}
+		*param.value = at
+	}
+	return actor, query, true
+}
+
+// writeError writes the response for a failed time tracking operation and
+// reports whether err was set.
+func (tc *TimeEntryController) writeError(c *gin.Context, err error, message string) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, domain.ErrInvalidTimeQuery):
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, err.Error()))
+	case errors.Is(err, domain.ErrTimerRunning), errors.Is(err, domain.ErrTimerNotRunning):
+		c.JSON(http.StatusConflict, infrastructure.ErrorResponse(c, err.Error()))
+	case errors.Is(err, domain.ErrForbidden):
+		c.JSON(http.StatusForbidden, infrastructure.ErrorResponse(c, err.Error()))
+	case errors.Is(err, mongo.ErrNoDocuments):
+		c.JSON(http.StatusNotFound, infrastructure.ErrorResponse(c, "Task not found."))
+	default:
+		c.JSON(http.StatusInternalServerError, infrastructure.ErrorResponse(c, message))
+	}
+	return true
+}
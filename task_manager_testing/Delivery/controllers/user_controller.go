@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
 	infrastructure "task_manager_testing/Infrastructure"
 	"task_manager_testing/domain"
@@ -19,29 +20,109 @@ func NewUserController(userusecase domain.UserUsecase) *UserController {
 	return &UserController{UserUsecase: userusecase}
 }
 
-// RegisterUser handles user registration requests.
+// RegisterUser handles user registration requests. Anyone can register, so
+// the new user is always a regular user without an organization; admins and
+// organization members are created with CreateUser.
 func (uc *UserController) RegisterUser(c *gin.Context) {
 	var req struct {
 		Username string `json:"username" binding:"required"`
 		Password string `json:"password" binding:"required"`
-		Role     string `json:"role" binding:"required"`
 	}
 
 	// Validate incoming JSON request
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request. Please provide a valid username, password, and role."})
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid request. Please provide a valid username and password."))
 		return
 	}
 
 	// Attempt to register the new user
-	if err := uc.UserUsecase.RegisterUser(req.Username, req.Password, req.Role); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Registration failed. Please try again later."})
+	err := uc.UserUsecase.RegisterUser(c.Request.Context(), req.Username, req.Password, "user")
+	if errors.Is(err, domain.ErrUserExists) {
+		c.JSON(http.StatusConflict, infrastructure.ErrorResponse(c, "The username is already taken."))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, infrastructure.ErrorResponse(c, "Registration failed. Please try again later."))
 		return
 	}
 
 	c.JSON(http.StatusCreated, gin.H{"message": "User successfully registered."})
 }
 
+// CreateUser creates a user on behalf of an admin or root user. Admins create
+// users and admins of their own organization; root users create any role in
+// the organization given as org_id.
+func (uc *UserController) CreateUser(c *gin.Context) {
+	claims, _ := c.Get("user")
+	userClaims := claims.(*domain.Claims)
+	if userClaims.Role != "admin" && userClaims.Role != "root" {
+		c.JSON(http.StatusForbidden, infrastructure.ErrorResponse(c, "Only admins can create users."))
+		return
+	}
+
+	var req struct {
+		Username string `json:"username" binding:"required"`
+		Password string `json:"password" binding:"required"`
+		Role     string `json:"role"`
+		OrgID    string `json:"org_id"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid request. Please provide a valid username and password."))
+		return
+	}
+	if req.Role == "" {
+		req.Role = "user"
+	}
+
+	switch req.Role {
+	case "user", "admin":
+	case "root":
+		if userClaims.Role != "root" {
+			c.JSON(http.StatusForbidden, infrastructure.ErrorResponse(c, "Only root users can grant the root role."))
+			return
+		}
+		if req.OrgID != "" {
+			c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Root users cannot belong to an organization."))
+			return
+		}
+	default:
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid role. Use user, admin or root."))
+		return
+	}
+
+	// Admins are scoped to their organization by the auth middleware, so only
+	// root users choose the organization of the new user
+	ctx := c.Request.Context()
+	if req.OrgID != "" {
+		if userClaims.Role != "root" {
+			c.JSON(http.StatusForbidden, infrastructure.ErrorResponse(c, "Only root users can choose the organization of a user."))
+			return
+		}
+		orgId, err := primitive.ObjectIDFromHex(req.OrgID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid organization ID format."))
+			return
+		}
+		ctx = domain.WithOrg(ctx, orgId)
+	}
+
+	err := uc.UserUsecase.RegisterUser(ctx, req.Username, req.Password, req.Role)
+	if errors.Is(err, domain.ErrUserExists) {
+		c.JSON(http.StatusConflict, infrastructure.ErrorResponse(c, "The username is already taken."))
+		return
+	}
+	if errors.Is(err, domain.ErrInvalidOrganization) {
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "The organization does not exist."))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, infrastructure.ErrorResponse(c, "Failed to create the user. Please try again later."))
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "User successfully created."})
+}
+
 // Login handles user authentication and token generation.
 func (uc *UserController) Login(c *gin.Context) {
 	var req struct {
@@ -51,21 +132,25 @@ func (uc *UserController) Login(c *gin.Context) {
 
 	// Validate incoming JSON request
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request. Please provide a valid username and password."})
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid request. Please provide a valid username and password."))
 		return
 	}
 
 	// Attempt to authenticate the user
-	user, err := uc.UserUsecase.Login(req.Username, req.Password)
+	user, err := uc.UserUsecase.Login(c.Request.Context(), req.Username, req.Password)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials. Please check your username and password."})
+		c.JSON(http.StatusUnauthorized, infrastructure.ErrorResponse(c, "Invalid credentials. Please check your username and password."))
 		return
 	}
 
 	// Generate JWT token
-	token, err := infrastructure.GenerateJWT(user.ID.Hex(), user.Username, user.Role)
+	orgId := ""
+	if user.OrgID != nil {
+		orgId = user.OrgID.Hex()
+	}
+	token, err := infrastructure.GenerateJWT(user.ID.Hex(), user.Username, user.Role, orgId)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token. Please try again later."})
+		c.JSON(http.StatusInternalServerError, infrastructure.ErrorResponse(c, "Failed to generate token. Please try again later."))
 		return
 	}
 
@@ -75,9 +160,9 @@ func (uc *UserController) Login(c *gin.Context) {
 // GetAllUsers retrieves all registered users.
 func (uc *UserController) GetAllUsers(c *gin.Context) {
 	// Retrieve users from the use case layer
-	users, err := uc.UserUsecase.GetAllUsers()
+	users, err := uc.UserUsecase.GetAllUsers(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve users. Please try again later."})
+		c.JSON(http.StatusInternalServerError, infrastructure.ErrorResponse(c, "Failed to retrieve users. Please try again later."))
 		return
 	}
 
@@ -89,14 +174,14 @@ func (uc *UserController) GetUserById(c *gin.Context) {
 	paramId := c.Param("id")
 	newParamId, err := primitive.ObjectIDFromHex(paramId)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID format."})
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid user ID format."))
 		return
 	}
 
 	// Retrieve the user by ID from the use case layer
-	user, err := uc.UserUsecase.GetUserById(newParamId)
+	user, err := uc.UserUsecase.GetUserById(c.Request.Context(), newParamId)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User with the given ID not found."})
+		c.JSON(http.StatusNotFound, infrastructure.ErrorResponse(c, "User with the given ID not found."))
 		return
 	}
 
@@ -112,20 +197,20 @@ func (uc *UserController) UpdateUser(c *gin.Context) {
 
 	// Validate incoming JSON request
 	if err := c.ShouldBindJSON(&user); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request. Please provide valid user data."})
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid request. Please provide valid user data."))
 		return
 	}
 
 	newParamId, err := primitive.ObjectIDFromHex(paramId)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID format."})
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid user ID format."))
 		return
 	}
 
 	// Retrieve the user to be updated
-	otherUser, err := uc.UserUsecase.GetUserById(newParamId)
+	otherUser, err := uc.UserUsecase.GetUserById(c.Request.Context(), newParamId)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User with the given ID not found."})
+		c.JSON(http.StatusNotFound, infrastructure.ErrorResponse(c, "User with the given ID not found."))
 		return
 	}
 
@@ -133,15 +218,20 @@ func (uc *UserController) UpdateUser(c *gin.Context) {
 
 	// Role-based access control checks
 	if userClaims.Role == "user" && userClaims.UserID != paramId {
-		c.JSON(http.StatusForbidden, gin.H{"error": "You can only edit your own profile."})
+		c.JSON(http.StatusForbidden, infrastructure.ErrorResponse(c, "You can only edit your own profile."))
 		return
 	}
 	if userClaims.Role == "admin" && (otherUser.Role == "admin" || otherUser.Role == "root") {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Admins cannot edit other admins or root users."})
+		c.JSON(http.StatusForbidden, infrastructure.ErrorResponse(c, "Admins cannot edit other admins or root users."))
 		return
 	}
 	if userClaims.Role == "root" && otherUser.Role == "root" && userClaims.UserID != paramId {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Root users cannot edit other root users."})
+		c.JSON(http.StatusForbidden, infrastructure.ErrorResponse(c, "Root users cannot edit other root users."))
+		return
+	}
+	// Root users are global, so only they can make a user root
+	if user.Role == "root" && userClaims.Role != "root" {
+		c.JSON(http.StatusForbidden, infrastructure.ErrorResponse(c, "Only root users can grant the root role."))
 		return
 	}
 
@@ -151,8 +241,13 @@ func (uc *UserController) UpdateUser(c *gin.Context) {
 	}
 
 	// Attempt to update the user's profile
-	if err := uc.UserUsecase.UpdateUser(newParamId, user); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user. Please try again later."})
+	err = uc.UserUsecase.UpdateUser(c.Request.Context(), newParamId, user)
+	if errors.Is(err, domain.ErrUserExists) {
+		c.JSON(http.StatusConflict, infrastructure.ErrorResponse(c, "The username is already taken."))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, infrastructure.ErrorResponse(c, "Failed to update user. Please try again later."))
 		return
 	}
 
@@ -166,36 +261,151 @@ func (uc *UserController) DeleteUser(c *gin.Context) {
 	userClaims := claims.(*domain.Claims)
 	newParamId, err := primitive.ObjectIDFromHex(paramId)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID format."})
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid user ID format."))
 		return
 	}
 
 	// Retrieve the user to be deleted
-	otherUser, err := uc.UserUsecase.GetUserById(newParamId)
+	otherUser, err := uc.UserUsecase.GetUserById(c.Request.Context(), newParamId)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User with the given ID not found."})
+		c.JSON(http.StatusNotFound, infrastructure.ErrorResponse(c, "User with the given ID not found."))
 		return
 	}
 
 	// Role-based access control checks
 	if userClaims.Role == "user" && userClaims.UserID != paramId {
-		c.JSON(http.StatusForbidden, gin.H{"error": "You can only delete your own profile."})
+		c.JSON(http.StatusForbidden, infrastructure.ErrorResponse(c, "You can only delete your own profile."))
 		return
 	}
 	if userClaims.Role == "admin" && (otherUser.Role == "admin" || otherUser.Role == "root") {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Admins cannot delete other admins or root users."})
+		c.JSON(http.StatusForbidden, infrastructure.ErrorResponse(c, "Admins cannot delete other admins or root users."))
 		return
 	}
 	if userClaims.Role == "root" && otherUser.Role == "root" && userClaims.UserID != paramId {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Root users cannot delete other root users."})
+		c.JSON(http.StatusForbidden, infrastructure.ErrorResponse(c, "Root users cannot delete other root users."))
 		return
 	}
 
 	// Attempt to delete the user
-	if err := uc.UserUsecase.DeleteUser(newParamId); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete user. Please try again later."})
+	if err := uc.UserUsecase.DeleteUser(c.Request.Context(), newParamId); err != nil {
+		c.JSON(http.StatusInternalServerError, infrastructure.ErrorResponse(c, "Failed to delete user. Please try again later."))
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "User with ID " + paramId + " has been successfully deleted."})
 }
+
+// GetMe retrieves the profile of the logged-in user.
+func (uc *UserController) GetMe(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, infrastructure.ErrorResponse(c, "Unauthorized. Please log in to view your profile."))
+		return
+	}
+
+	// Retrieve the user from the use case layer
+	user, err := uc.UserUsecase.GetUserById(c.Request.Context(), userId)
+	if err != nil {
+		c.JSON(http.StatusNotFound, infrastructure.ErrorResponse(c, "Your user profile could not be found."))
+		return
+	}
+
+	user.Password = ""
+	c.JSON(http.StatusOK, gin.H{"message": "Profile retrieved successfully.", "data": user})
+}
+
+// UpdateMe applies a partial update to the profile of the logged-in user.
+// Only the username can be changed here; passwords go through ChangePassword.
+func (uc *UserController) UpdateMe(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, infrastructure.ErrorResponse(c, "Unauthorized. Please log in to update your profile."))
+		return
+	}
+
+	var req struct {
+		Username string `json:"username" binding:"required"`
+	}
+
+	// Validate incoming JSON request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid request. Please provide a valid username."))
+		return
+	}
+
+	// Attempt to update the user's profile
+	err := uc.UserUsecase.UpdateUser(c.Request.Context(), userId, domain.User{Username: req.Username})
+	if errors.Is(err, domain.ErrUserExists) {
+		c.JSON(http.StatusConflict, infrastructure.ErrorResponse(c, "The username is already taken."))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, infrastructure.ErrorResponse(c, "Failed to update profile. Please try again later."))
+		return
+	}
+
+	user, err := uc.UserUsecase.GetUserById(c.Request.Context(), userId)
+	if err != nil {
+		c.JSON(http.StatusNotFound, infrastructure.ErrorResponse(c, "Your user profile could not be found."))
+		return
+	}
+
+	user.Password = ""
+	c.JSON(http.StatusOK, gin.H{"message": "Profile updated successfully.", "data": user})
+}
+
+// ChangePassword changes the password of the logged-in user after checking the old one.
+func (uc *UserController) ChangePassword(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, infrastructure.ErrorResponse(c, "Unauthorized. Please log in to change your password."))
+		return
+	}
+
+	var req struct {
+		OldPassword string `json:"old_password" binding:"required"`
+		NewPassword string `json:"new_password" binding:"required"`
+	}
+
+	// Validate incoming JSON request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid request. Please provide your old and new password."))
+		return
+	}
+
+	// Attempt to change the password
+	err := uc.UserUsecase.ChangePassword(c.Request.Context(), userId, req.OldPassword, req.NewPassword)
+	if errors.Is(err, domain.ErrInvalidPassword) {
+		c.JSON(http.StatusForbidden, infrastructure.ErrorResponse(c, "The old password is incorrect."))
+		return
+	}
+	if errors.Is(err, domain.ErrEmptyPassword) {
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "The new password cannot be empty."))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, infrastructure.ErrorResponse(c, "Failed to change password. Please try again later."))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password changed successfully."})
+}
+
+// currentUserId extracts the logged-in user's ID from the claims set by the auth middleware.
+func currentUserId(c *gin.Context) (primitive.ObjectID, bool) {
+	claims, exists := c.Get("user")
+	if !exists {
+		return primitive.NilObjectID, false
+	}
+
+	userClaims, ok := claims.(*domain.Claims)
+	if !ok {
+		return primitive.NilObjectID, false
+	}
+
+	userId, err := primitive.ObjectIDFromHex(userClaims.UserID)
+	if err != nil {
+		return primitive.NilObjectID, false
+	}
+	return userId, true
+}
@@ -12,13 +12,14 @@ import (
 	"task_manager_testing/mocks"
 
 	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type UserControllerSuite struct {
 	suite.Suite
-	userUsecase   mocks.UserUsecase
+	userUsecase   *mocks.UserUsecase
 	userCtrl      controllers.UserController
 	testingServer *httptest.Server
 }
@@ -33,7 +34,7 @@ func (suite *UserControllerSuite) SetupTest() {
 
 	testingServer := httptest.NewServer(router)
 	suite.testingServer = testingServer
-	suite.userUsecase = *userUsecase
+	suite.userUsecase = userUsecase
 	suite.userCtrl = *handler // Assigning UserController correctly
 }
 
@@ -47,7 +48,7 @@ func (suite *UserControllerSuite) TestRegisterUser() {
 		Password: "password",
 		Role:     "user",
 	}
-	suite.userUsecase.On("RegisterUser", user).Return(nil)
+	suite.userUsecase.On("RegisterUser", mock.Anything, user).Return(nil)
 
 	requestBody, err := json.Marshal(&user)
 	suite.NoError(err, "can not marshal struct to json")
@@ -69,7 +70,7 @@ func (suite *UserControllerSuite) TestLogin() {
 		Username: "tester1",
 		Password: "password",
 	}
-	suite.userUsecase.On("Login", user.Username, user.Password).Return(user, nil)
+	suite.userUsecase.On("Login", mock.Anything, user.Username, user.Password).Return(user, nil)
 
 	requestBody, err := json.Marshal(&user)
 	suite.NoError(err, "can not marshal struct to json")
@@ -125,7 +126,7 @@ func (suite *UserControllerSuite) TestLoginInvalidCredentials() {
 		Password: "password",
 	}
 
-	suite.userUsecase.On("Login", user.Username, user.Password).Return(domain.User{}, fmt.Errorf("Invalid credentials"))
+	suite.userUsecase.On("Login", mock.Anything, user.Username, user.Password).Return(domain.User{}, fmt.Errorf("Invalid credentials"))
 
 	requestBody, err := json.Marshal(&user)
 	suite.NoError(err, "can not marshal struct to json")
@@ -152,7 +153,7 @@ func (suite *UserControllerSuite) TestGetAllTasks() {
 			Role:     "user",
 		},
 	}
-	suite.userUsecase.On("GetAllUsers").Return(user, nil)
+	suite.userUsecase.On("GetAllUsers", mock.Anything, mock.Anything).Return(user, nil)
 
 	response, err := http.Get(fmt.Sprintf("%s/users", suite.testingServer.URL))
 	suite.NoError(err, "no error when calling the endpoint")
@@ -173,7 +174,7 @@ func (suite *UserControllerSuite) UpdateUser() {
 		Password: "password",
 		Role:     "user",
 	}
-	suite.userUsecase.On("UpdateUser", user).Return(nil)
+	suite.userUsecase.On("UpdateUser", mock.Anything, user).Return(nil)
 
 	requestBody, err := json.Marshal(&user)
 	suite.NoError(err, "can not marshal struct to json")
@@ -197,7 +198,7 @@ func (suite *UserControllerSuite) DeleteUser() {
 		Password: "password",
 		Role:     "user",
 	}
-	suite.userUsecase.On("DeleteUser", user).Return(nil)
+	suite.userUsecase.On("DeleteUser", mock.Anything, user).Return(nil)
 
 	requestBody, err := json.Marshal(&user)
 	suite.NoError(err, "can not marshal struct to json")
@@ -221,7 +222,7 @@ func (suite *UserControllerSuite) TestGetUserByID() {
 		Password: "password",
 		Role:     "user",
 	}
-	suite.userUsecase.On("GetUserByID", user.ID).Return(user, nil)
+	suite.userUsecase.On("GetUserByID", mock.Anything, user.ID).Return(user, nil)
 
 	response, err := http.Get(fmt.Sprintf("%s/users/%s", suite.testingServer.URL, user.ID))
 	suite.NoError(err, "no error when calling the endpoint")
@@ -250,7 +251,7 @@ func (suite *UserControllerSuite) TestGetUserByIDNotFound() {
 		Password : "password",
 		Role : "user",
 	}
-	suite.userUsecase.On("GetUserByID", user.ID).Return(domain.User{}, fmt.Errorf("User not found"))
+	suite.userUsecase.On("GetUserByID", mock.Anything, user.ID).Return(domain.User{}, fmt.Errorf("User not found"))
 
 	response, err := http.Get(fmt.Sprintf("%s/users/%s", suite.testingServer.URL, user.ID))
 	suite.NoError(err, "no error when calling the endpoint")
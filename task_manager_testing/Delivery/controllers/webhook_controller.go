@@ -0,0 +1,229 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	infrastructure "task_manager_testing/Infrastructure"
+	"task_manager_testing/domain"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// WebhookController manages the logged-in user's webhooks and their deliveries.
+type WebhookController struct {
+	WebhookUsecase domain.WebhookUsecase
+}
+
+// NewWebhookController initializes a new WebhookController.
+func NewWebhookController(webhookUsecase domain.WebhookUsecase) *WebhookController {
+	return &WebhookController{WebhookUsecase: webhookUsecase}
+}
+
+// CreateWebhook subscribes a URL to task events. The signing secret is only
+// returned here. Admin and root users receive the events of every task.
+func (wc *WebhookController) CreateWebhook(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, infrastructure.ErrorResponse(c, "Unauthorized. Please log in to manage webhooks."))
+		return
+	}
+
+	var req struct {
+		URL    string   `json:"url" binding:"required"`
+		Events []string `json:"events" binding:"required"`
+		Secret string   `json:"secret"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid input. Please provide a url and the events to receive."))
+		return
+	}
+
+	claims, _ := c.Get("user")
+	webhook, err := wc.WebhookUsecase.CreateWebhook(c.Request.Context(), domain.Webhook{
+		OwnerID:  userId,
+		URL:      req.URL,
+		Events:   req.Events,
+		Secret:   req.Secret,
+		AllTasks: claims.(*domain.Claims).Role != "user",
+		AllOrgs:  claims.(*domain.Claims).Role == "root",
+		Active:   true,
+	})
+	if errors.Is(err, domain.ErrInvalidWebhook) {
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, infrastructure.ErrorResponse(c, "Failed to create the webhook. Please try again later."))
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Webhook created successfully.", "webhook": webhook, "secret": webhook.Secret})
+}
+
+// GetWebhooks lists the logged-in user's webhooks.
+func (wc *WebhookController) GetWebhooks(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, infrastructure.ErrorResponse(c, "Unauthorized. Please log in to manage webhooks."))
+		return
+	}
+
+	webhooks, err := wc.WebhookUsecase.GetWebhooks(c.Request.Context(), userId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, infrastructure.ErrorResponse(c, "Failed to retrieve webhooks. Please try again later."))
+		return
+	}
+	if webhooks == nil {
+		webhooks = []domain.Webhook{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Webhooks retrieved successfully.", "webhooks": webhooks})
+}
+
+// GetWebhook returns one of the logged-in user's webhooks.
+func (wc *WebhookController) GetWebhook(c *gin.Context) {
+	userId, id, ok := wc.webhookParams(c)
+	if !ok {
+		return
+	}
+
+	webhook, err := wc.WebhookUsecase.GetWebhook(c.Request.Context(), userId, id)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.JSON(http.StatusNotFound, infrastructure.ErrorResponse(c, "Webhook not found."))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, infrastructure.ErrorResponse(c, "Failed to retrieve the webhook. Please try again later."))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook retrieved successfully.", "webhook": webhook})
+}
+
+// UpdateWebhook changes the URL, the events or whether a webhook is active.
+func (wc *WebhookController) UpdateWebhook(c *gin.Context) {
+	userId, id, ok := wc.webhookParams(c)
+	if !ok {
+		return
+	}
+
+	var update domain.WebhookUpdate
+	if err := c.ShouldBindJSON(&update); err != nil {
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid input. Please check the webhook fields."))
+		return
+	}
+
+	webhook, err := wc.WebhookUsecase.UpdateWebhook(c.Request.Context(), userId, id, update)
+	if errors.Is(err, domain.ErrInvalidWebhook) {
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, err.Error()))
+		return
+	}
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.JSON(http.StatusNotFound, infrastructure.ErrorResponse(c, "Webhook not found."))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, infrastructure.ErrorResponse(c, "Failed to update the webhook. Please try again later."))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook updated successfully.", "webhook": webhook})
+}
+
+// DeleteWebhook removes a webhook together with its delivery log.
+func (wc *WebhookController) DeleteWebhook(c *gin.Context) {
+	userId, id, ok := wc.webhookParams(c)
+	if !ok {
+		return
+	}
+
+	err := wc.WebhookUsecase.DeleteWebhook(c.Request.Context(), userId, id)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.JSON(http.StatusNotFound, infrastructure.ErrorResponse(c, "Webhook not found."))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, infrastructure.ErrorResponse(c, "Failed to delete the webhook. Please try again later."))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook deleted successfully."})
+}
+
+// GetDeliveries lists the latest deliveries of a webhook with every attempt.
+func (wc *WebhookController) GetDeliveries(c *gin.Context) {
+	userId, id, ok := wc.webhookParams(c)
+	if !ok {
+		return
+	}
+
+	limit := domain.MaxWebhookDeliveriesPage
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > domain.MaxWebhookDeliveriesPage {
+			c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid limit. Use a number between 1 and "+strconv.Itoa(domain.MaxWebhookDeliveriesPage)+"."))
+			return
+		}
+		limit = parsed
+	}
+
+	deliveries, err := wc.WebhookUsecase.GetDeliveries(c.Request.Context(), userId, id, limit)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.JSON(http.StatusNotFound, infrastructure.ErrorResponse(c, "Webhook not found."))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, infrastructure.ErrorResponse(c, "Failed to retrieve deliveries. Please try again later."))
+		return
+	}
+	if deliveries == nil {
+		deliveries = []domain.WebhookDelivery{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Deliveries retrieved successfully.", "deliveries": deliveries})
+}
+
+// Redeliver queues the payload of an earlier delivery to be sent again.
+func (wc *WebhookController) Redeliver(c *gin.Context) {
+	userId, id, ok := wc.webhookParams(c)
+	if !ok {
+		return
+	}
+	deliveryId, err := primitive.ObjectIDFromHex(c.Param("deliveryId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid delivery ID format."))
+		return
+	}
+
+	delivery, err := wc.WebhookUsecase.Redeliver(c.Request.Context(), userId, id, deliveryId)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.JSON(http.StatusNotFound, infrastructure.ErrorResponse(c, "Delivery not found."))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, infrastructure.ErrorResponse(c, "Failed to queue the redelivery. Please try again later."))
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Redelivery queued.", "delivery": delivery})
+}
+
+// webhookParams returns the caller and the webhook ID of the request, or
+// writes the error response and returns false.
+func (wc *WebhookController) webhookParams(c *gin.Context) (primitive.ObjectID, primitive.ObjectID, bool) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, infrastructure.ErrorResponse(c, "Unauthorized. Please log in to manage webhooks."))
+		return primitive.NilObjectID, primitive.NilObjectID, false
+	}
+
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "Invalid webhook ID format."))
+		return primitive.NilObjectID, primitive.NilObjectID, false
+	}
+	return userId, id, true
+}
@@ -0,0 +1,14 @@
+package routers
+
+import (
+	"task_manager_testing/Delivery/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func NewProtectedAttachmentRouter(attachmentController *controllers.AttachmentController, group *gin.RouterGroup) {
+	// Routes to list, download and delete the files attached to a task
+	group.GET("/tasks/:id/attachments", attachmentController.GetAttachments)
+	group.GET("/tasks/:id/attachments/:attachmentId", attachmentController.DownloadAttachment)
+	group.DELETE("/tasks/:id/attachments/:attachmentId", attachmentController.DeleteAttachment)
+}
@@ -0,0 +1,13 @@
+package routers
+
+import (
+	"task_manager_testing/Delivery/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func NewProtectedCalendarRouter(calendarController *controllers.CalendarController, group *gin.RouterGroup) {
+	// Routes to create, replace and disable the logged-in user's calendar feed
+	group.POST("/me/calendar-token", calendarController.RotateToken)
+	group.DELETE("/me/calendar-token", calendarController.RevokeToken)
+}
@@ -0,0 +1,16 @@
+package routers
+
+import (
+	"task_manager_testing/Delivery/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func NewProtectedCommentRouter(commentController *controllers.CommentController, group *gin.RouterGroup) {
+	// Routes to read and join the discussion of a task
+	group.GET("/tasks/:id/comments", commentController.GetComments)
+	group.POST("/tasks/:id/comments", commentController.AddComment)
+	// Routes to edit or delete a comment
+	group.PATCH("/tasks/:id/comments/:commentId", commentController.UpdateComment)
+	group.DELETE("/tasks/:id/comments/:commentId", commentController.DeleteComment)
+}
@@ -0,0 +1,14 @@
+package routers
+
+import (
+	"task_manager_testing/Delivery/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func NewProtectedEventRouter(eventController *controllers.EventController, group *gin.RouterGroup) {
+	// Route to follow task changes as Server-Sent Events
+	group.GET("/events", eventController.StreamEvents)
+	// Route to follow task changes over a WebSocket
+	group.GET("/events/ws", eventController.StreamEventsWebSocket)
+}
@@ -0,0 +1,19 @@
+package routers
+
+import (
+	"task_manager_testing/Delivery/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func NewProtectedLabelRouter(labelController *controllers.LabelController, group *gin.RouterGroup) {
+	// Routes to manage global and personal labels
+	group.GET("/labels", labelController.GetLabels)
+	group.POST("/labels", labelController.CreateLabel)
+	group.PATCH("/labels/:labelId", labelController.UpdateLabel)
+	group.DELETE("/labels/:labelId", labelController.DeleteLabel)
+	// Routes to label tasks
+	group.PUT("/tasks/:id/labels", labelController.SetTaskLabels)
+	group.POST("/tasks/:id/labels/:labelId", labelController.AddTaskLabel)
+	group.DELETE("/tasks/:id/labels/:labelId", labelController.RemoveTaskLabel)
+}
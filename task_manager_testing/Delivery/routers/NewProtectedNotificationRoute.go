@@ -0,0 +1,16 @@
+package routers
+
+import (
+	"task_manager_testing/Delivery/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func NewProtectedNotificationRouter(notificationController *controllers.NotificationController, group *gin.RouterGroup) {
+	// Route to list the logged-in user's notifications
+	group.GET("/notifications", notificationController.GetNotifications)
+	// Route to mark every notification as read
+	group.POST("/notifications/read", notificationController.MarkAllRead)
+	// Route to mark one notification as read
+	group.POST("/notifications/:id/read", notificationController.MarkRead)
+}
@@ -0,0 +1,14 @@
+package routers
+
+import (
+	"task_manager_testing/Delivery/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func NewProtectedOrganizationRouter(organizationController *controllers.OrganizationController, group *gin.RouterGroup) {
+	// Routes to manage organizations (root users) and see one's own
+	group.POST("/organizations", organizationController.CreateOrganization)
+	group.GET("/organizations", organizationController.GetAllOrganizations)
+	group.GET("/organizations/:id", organizationController.GetOrganization)
+}
@@ -0,0 +1,15 @@
+package routers
+
+import (
+	"task_manager_testing/Delivery/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func NewProtectedReportRouter(reportController *controllers.ReportController, group *gin.RouterGroup) {
+	// Routes to report on tasks
+	group.GET("/reports/throughput", reportController.Throughput)
+	group.GET("/reports/cycle-time", reportController.CycleTime)
+	group.GET("/reports/overdue", reportController.Overdue)
+	group.GET("/reports/burndown", reportController.Burndown)
+}
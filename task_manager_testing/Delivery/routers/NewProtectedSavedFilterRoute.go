@@ -0,0 +1,16 @@
+package routers
+
+import (
+	"task_manager_testing/Delivery/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func NewProtectedSavedFilterRouter(savedFilterController *controllers.SavedFilterController, group *gin.RouterGroup) {
+	// Routes to manage the caller's saved task filters
+	group.GET("/filters", savedFilterController.GetFilters)
+	group.POST("/filters", savedFilterController.SaveFilter)
+	group.DELETE("/filters/:id", savedFilterController.DeleteFilter)
+	// Route to run a saved filter again
+	group.GET("/filters/:id/tasks", savedFilterController.RunFilter)
+}
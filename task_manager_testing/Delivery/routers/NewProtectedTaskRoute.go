@@ -2,24 +2,17 @@ package routers
 
 import (
 	"task_manager_testing/Delivery/controllers"
-	repository "task_manager_testing/Repository"
-	usecase "task_manager_testing/Usecase"
 
 	"github.com/gin-gonic/gin"
-	"go.mongodb.org/mongo-driver/mongo"
 )
 
 
 
-func NewProtectedTaskRouter(client *mongo.Client , db string, group *gin.RouterGroup) {
+func NewProtectedTaskRouter(taskController *controllers.TaskController, group *gin.RouterGroup) {
 	
-	taskRepository := repository.NewTaskRepository(client, db, "tasks")
-	taskUsecase := usecase.NewTaskUsecase(taskRepository)
-	userRepository := repository.NewUserRepository(client, db, "users")
-	userUsecase := usecase.NewUserUsecase(userRepository)
-	taskController := controllers.NewTaskController(taskUsecase, userUsecase)
-
 	group.POST("/tasks", taskController.AddTask)
+	// Route to get every task of the caller's organization
+	group.GET("/alltasks", taskController.GetAllTasks)
 	// Route to get tasks created by the logged-in user
 	group.GET("/tasks", taskController.GetMyTasks)
 	// Route to get a specific task by ID (requires authentication)
@@ -30,5 +23,15 @@ func NewProtectedTaskRouter(client *mongo.Client , db string, group *gin.RouterG
 	group.PUT("/tasks/:id", taskController.UpdateSomeTask)
 	// Route to delete a task by ID (requires authentication)
 	group.DELETE("/tasks/:id", taskController.DeleteTask)
+	// Route to apply many task operations at once (requires authentication)
+	group.POST("/tasks/bulk", taskController.BulkTasks)
+	// Route to download the tasks visible to the caller (requires authentication)
+	group.GET("/tasks/export", taskController.ExportTasks)
+	// Route to create tasks from a CSV or JSON file (requires authentication)
+	group.POST("/tasks/import", taskController.ImportTasks)
+	// Route to get the caller's tasks as a kanban board (requires authentication)
+	group.GET("/board", taskController.GetBoard)
+	// Route to move a task on the board (requires authentication)
+	group.POST("/tasks/:id/move", taskController.MoveTask)
 	
-}
\ No newline at end of file
+}
@@ -0,0 +1,17 @@
+package routers
+
+import (
+	"task_manager_testing/Delivery/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func NewProtectedTimeRouter(timeEntryController *controllers.TimeEntryController, group *gin.RouterGroup) {
+	// Routes to track time on a task
+	group.POST("/tasks/:id/time/start", timeEntryController.StartTimer)
+	group.POST("/tasks/:id/time/stop", timeEntryController.StopTimer)
+	group.GET("/tasks/:id/time", timeEntryController.GetTaskTime)
+	// Routes to report the tracked time
+	group.GET("/time/users", timeEntryController.TimeByUser)
+	group.GET("/time/weeks", timeEntryController.TimeByWeek)
+}
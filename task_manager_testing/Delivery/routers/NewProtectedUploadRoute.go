@@ -0,0 +1,12 @@
+package routers
+
+import (
+	"task_manager_testing/Delivery/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func NewProtectedUploadRouter(attachmentController *controllers.AttachmentController, group *gin.RouterGroup) {
+	// Route to attach a file to a task; it needs a larger body limit than the other routes
+	group.POST("/tasks/:id/attachments", attachmentController.UploadAttachment)
+}
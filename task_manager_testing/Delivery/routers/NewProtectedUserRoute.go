@@ -2,29 +2,25 @@ package routers
 
 import (
 	"task_manager_testing/Delivery/controllers"
-	repository "task_manager_testing/Repository"
-	usecase "task_manager_testing/Usecase"
 
 	"github.com/gin-gonic/gin"
-	"go.mongodb.org/mongo-driver/mongo"
 )
 
 
 
-func NewProtectedUserRouter(client *mongo.Client , db string, group *gin.RouterGroup) {
+func NewProtectedUserRouter(userController *controllers.UserController, group *gin.RouterGroup) {
 	
-	userRepository := repository.NewUserRepository(client, db, "users")
-	userUsecase := usecase.NewUserUsecase(userRepository)
-	userController := controllers.NewUserController(userUsecase)
-
-
+	// Route to list the users of the caller's organization
+	group.GET("/users", userController.GetAllUsers)
+	// Route to create a user or an admin (requires admin role)
+	group.POST("/users", userController.CreateUser)
 	// Route to update a user's details (requires admin role)
 	group.PATCH("/users/:id", userController.UpdateUser)
 	// Route to delete a user (requires admin role)
 	group.DELETE("/users/:id", userController.DeleteUser)
 
-
-	
-	
-	
-}
\ No newline at end of file
+	// Routes for the logged-in user's own profile
+	group.GET("/me", userController.GetMe)
+	group.PATCH("/me", userController.UpdateMe)
+	group.POST("/me/password", userController.ChangePassword)
+}
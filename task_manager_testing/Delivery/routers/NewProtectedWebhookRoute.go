@@ -0,0 +1,20 @@
+package routers
+
+import (
+	"task_manager_testing/Delivery/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func NewProtectedWebhookRouter(webhookController *controllers.WebhookController, group *gin.RouterGroup) {
+	// Routes to manage the logged-in user's webhooks
+	group.POST("/webhooks", webhookController.CreateWebhook)
+	group.GET("/webhooks", webhookController.GetWebhooks)
+	group.GET("/webhooks/:id", webhookController.GetWebhook)
+	group.PATCH("/webhooks/:id", webhookController.UpdateWebhook)
+	group.DELETE("/webhooks/:id", webhookController.DeleteWebhook)
+	// Route to inspect the delivery log of a webhook
+	group.GET("/webhooks/:id/deliveries", webhookController.GetDeliveries)
+	// Route to send an earlier delivery again
+	group.POST("/webhooks/:id/deliveries/:deliveryId/redeliver", webhookController.Redeliver)
+}
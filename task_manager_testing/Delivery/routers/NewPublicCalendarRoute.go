@@ -0,0 +1,12 @@
+package routers
+
+import (
+	"task_manager_testing/Delivery/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func NewPublicCalendarRouter(calendarController *controllers.CalendarController, group *gin.RouterGroup) {
+	// Route to subscribe to a calendar feed, authenticated by the secret token in the URL
+	group.GET("/calendar/:token", calendarController.Feed)
+}
@@ -1,32 +1,160 @@
 package routers
 
 import (
+	"log/slog"
+	"net/http"
+	"task_manager_testing/Delivery/controllers"
 	infrastructure "task_manager_testing/Infrastructure"
+	"task_manager_testing/config"
+	"task_manager_testing/domain"
 
 	"github.com/gin-gonic/gin"
-	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
-func SetupRouter(client *mongo.Client) *gin.Engine {
-	r := gin.Default()
+// serviceName is reported on the spans created for each request.
+const serviceName = "task-manager"
+
+// Dependencies are the services the HTTP layer is built on. They are created
+// once by the composition root and shared by every route.
+type Dependencies struct {
+	TaskUsecase domain.TaskUsecase
+	UserUsecase domain.UserUsecase
+	Database    domain.Pinger
+	Logger      *slog.Logger
+	Metrics     *infrastructure.Metrics
+	// Idempotency stores the responses replayed for retried write requests
+	Idempotency domain.IdempotencyRepository
+	// RateLimitStore holds the token buckets when rate limiting is enabled
+	RateLimitStore infrastructure.RateLimitStore
+	// NotificationUsecase serves the due-date reminders
+	NotificationUsecase domain.NotificationUsecase
+	// WebhookUsecase manages webhooks and their deliveries
+	WebhookUsecase domain.WebhookUsecase
+	// TaskEvents streams task changes to connected clients
+	TaskEvents domain.TaskEventStream
+	// CommentUsecase manages the discussion on tasks
+	CommentUsecase domain.CommentUsecase
+	// AttachmentUsecase stores the files attached to tasks
+	AttachmentUsecase domain.AttachmentUsecase
+	// LabelUsecase manages labels and the labels of tasks
+	LabelUsecase domain.LabelUsecase
+	// SavedFilterUsecase manages the saved task filters of users
+	SavedFilterUsecase domain.SavedFilterUsecase
+	// TimeEntryUsecase tracks time on tasks
+	TimeEntryUsecase domain.TimeEntryUsecase
+	// ReportUsecase builds the task reports
+	ReportUsecase domain.ReportUsecase
+	// OrganizationUsecase manages the organizations users are grouped in
+	OrganizationUsecase domain.OrganizationUsecase
+}
+
+func SetupRouter(cfg *config.Config, deps Dependencies) *gin.Engine {
+	r := gin.New()
+	// Only the configured proxies may set the client address through
+	// X-Forwarded-For; the addresses were checked when the config was loaded
+	if err := r.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		panic(err)
+	}
+	r.Use(gin.Recovery())
+	r.Use(infrastructure.RequestIDMiddleware())
+	// Calendar feed tokens are credentials and must not reach logs or traces
+	r.Use(infrastructure.RedactPathMiddleware("token"))
+	r.Use(otelgin.Middleware(serviceName, otelgin.WithFilter(func(req *http.Request) bool {
+		return req.URL.Path != "/metrics" && req.URL.Path != "/healthz" && req.URL.Path != "/readyz"
+	})))
+	r.Use(infrastructure.LoggingMiddleware(deps.Logger))
+	r.Use(infrastructure.MetricsMiddleware(deps.Metrics))
+	r.Use(infrastructure.SecurityHeadersMiddleware(infrastructure.SecurityHeadersOptions{
+		HSTSMaxAge:            cfg.Security.HSTSMaxAge.Duration,
+		ContentSecurityPolicy: cfg.Security.ContentSecurityPolicy,
+	}))
+	r.Use(infrastructure.CORSMiddleware(infrastructure.CORSOptions{
+		AllowedOrigins:   cfg.CORS.AllowedOrigins,
+		AllowCredentials: cfg.CORS.AllowCredentials,
+		MaxAge:           cfg.CORS.MaxAge.Duration,
+	}))
+
+	// Create every controller once
+	taskController := controllers.NewTaskController(deps.TaskUsecase, deps.UserUsecase)
+	userController := controllers.NewUserController(deps.UserUsecase)
+	healthController := controllers.NewHealthController(deps.Database)
+	calendarController := controllers.NewCalendarController(deps.TaskUsecase, deps.UserUsecase)
+	notificationController := controllers.NewNotificationController(deps.NotificationUsecase)
+	webhookController := controllers.NewWebhookController(deps.WebhookUsecase)
+	eventController := controllers.NewEventController(deps.TaskEvents, cfg.Events.Heartbeat.Duration)
+	commentController := controllers.NewCommentController(deps.CommentUsecase)
+	attachmentController := controllers.NewAttachmentController(deps.AttachmentUsecase)
+	labelController := controllers.NewLabelController(deps.LabelUsecase)
+	savedFilterController := controllers.NewSavedFilterController(deps.SavedFilterUsecase)
+	timeEntryController := controllers.NewTimeEntryController(deps.TimeEntryUsecase)
+	reportController := controllers.NewReportController(deps.ReportUsecase)
+	organizationController := controllers.NewOrganizationController(deps.OrganizationUsecase)
+
+	// Expose Prometheus metrics
+	r.GET("/metrics", gin.WrapH(deps.Metrics.Handler()))
+
+	// Liveness and readiness probes
+	r.GET("/healthz", healthController.Healthz)
+	r.GET("/readyz", healthController.Readyz)
+
+	// Request bodies are limited per group, because uploads need a higher limit
+	bodyLimit := infrastructure.MaxBodySizeMiddleware(cfg.Server.MaxBodyBytes)
 
 	publicRouter := r.Group("/")
+	publicRouter.Use(bodyLimit)
+	if cfg.RateLimit.Enabled {
+		rule := cfg.RateLimit.Public
+		publicRouter.Use(infrastructure.RateLimitMiddleware(deps.RateLimitStore, "public", infrastructure.PerMinute(rule.RequestsPerMinute, rule.Burst), infrastructure.ClientIPIdentity))
+	}
 
-	NewPublicTaskRouter(client,"taskdb",publicRouter)
-	NewPublicUserRouter(client,"taskdb",publicRouter)
+	NewPublicUserRouter(userController, publicRouter)
+	NewPublicCalendarRouter(calendarController, publicRouter)
 	
 	protectedRoute := r.Group("/")
-	protectedRoute.Use(infrastructure.AuthMiddleware())
+	protectedRoute.Use(bodyLimit, infrastructure.AuthMiddleware())
+	if cfg.RateLimit.Enabled {
+		rule := cfg.RateLimit.Protected
+		protectedRoute.Use(infrastructure.RateLimitMiddleware(deps.RateLimitStore, "protected", infrastructure.PerMinute(rule.RequestsPerMinute, rule.Burst), infrastructure.UserIdentity))
+	}
+	protectedRoute.Use(infrastructure.IdempotencyMiddleware(deps.Idempotency, cfg.Idempotency.TTL.Duration))
 
+	NewProtectedTaskRouter(taskController, protectedRoute)
+	NewProtectedUserRouter(userController, protectedRoute)
+	NewProtectedCalendarRouter(calendarController, protectedRoute)
+	NewProtectedNotificationRouter(notificationController, protectedRoute)
+	NewProtectedWebhookRouter(webhookController, protectedRoute)
+	NewProtectedCommentRouter(commentController, protectedRoute)
+	NewProtectedAttachmentRouter(attachmentController, protectedRoute)
+	NewProtectedLabelRouter(labelController, protectedRoute)
+	NewProtectedSavedFilterRouter(savedFilterController, protectedRoute)
+	NewProtectedTimeRouter(timeEntryController, protectedRoute)
+	NewProtectedReportRouter(reportController, protectedRoute)
+	NewProtectedOrganizationRouter(organizationController, protectedRoute)
 
-	NewProtectedTaskRouter(client,"taskdb",protectedRoute)
-	NewProtectedUserRouter(client,"taskdb",protectedRoute)
-	
+	// Uploads may be as large as an attachment plus the room the other requests
+	// get for the multipart framing
+	uploadRoute := r.Group("/")
+	uploadRoute.Use(infrastructure.MaxBodySizeMiddleware(cfg.Attachments.MaxFileBytes+cfg.Server.MaxBodyBytes), infrastructure.AuthMiddleware())
+	if cfg.RateLimit.Enabled {
+		rule := cfg.RateLimit.Protected
+		uploadRoute.Use(infrastructure.RateLimitMiddleware(deps.RateLimitStore, "protected", infrastructure.PerMinute(rule.RequestsPerMinute, rule.Burst), infrastructure.UserIdentity))
+	}
+	uploadRoute.Use(infrastructure.IdempotencyMiddleware(deps.Idempotency, cfg.Idempotency.TTL.Duration))
 
-	return r
-}
+	NewProtectedUploadRouter(attachmentController, uploadRoute)
 
+	// Event streams also accept the token as a query parameter, because
+	// browsers cannot set headers on EventSource and WebSocket connections
+	streamRoute := r.Group("/")
+	streamRoute.Use(bodyLimit, infrastructure.QueryTokenMiddleware(), infrastructure.AuthMiddleware())
+	if cfg.RateLimit.Enabled {
+		rule := cfg.RateLimit.Protected
+		streamRoute.Use(infrastructure.RateLimitMiddleware(deps.RateLimitStore, "protected", infrastructure.PerMinute(rule.RequestsPerMinute, rule.Burst), infrastructure.UserIdentity))
+	}
 
+	NewProtectedEventRouter(eventController, streamRoute)
 	
 
-
+	return r
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 func AuthMiddleware() gin.HandlerFunc {
@@ -14,7 +15,7 @@ func AuthMiddleware() gin.HandlerFunc {
 		// Retrieve the Authorization header
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "authorization header required"})
+			c.JSON(http.StatusUnauthorized, ErrorResponse(c, "authorization header required"))
 			c.Abort()
 			return
 		}
@@ -22,7 +23,7 @@ func AuthMiddleware() gin.HandlerFunc {
 		// Split the header to get the token part
 		parts := strings.Split(authHeader, " ")
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid authorization header format"})
+			c.JSON(http.StatusUnauthorized, ErrorResponse(c, "invalid authorization header format"))
 			c.Abort()
 			return
 		}
@@ -34,21 +35,58 @@ func AuthMiddleware() gin.HandlerFunc {
 			return jwtKey, nil
 		})
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+			c.JSON(http.StatusUnauthorized, ErrorResponse(c, "invalid token"))
 			c.Abort()
 			return
 		}
 
 		claims, ok := token.Claims.(*domain.Claims)
-		if !ok || !token.Valid {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+		if !ok || !token.Valid || (jwtIssuer != "" && !claims.VerifyIssuer(jwtIssuer, true)) {
+			c.JSON(http.StatusUnauthorized, ErrorResponse(c, "invalid token"))
 			c.Abort()
 			return
 		}
 
+		// Root users are global; everyone else only reaches the data of their
+		// organization
+		ctx := WithUserID(c.Request.Context(), claims.UserID)
+		if claims.Role != "root" {
+			orgID := primitive.NilObjectID
+			if claims.OrgID != "" {
+				if orgID, err = primitive.ObjectIDFromHex(claims.OrgID); err != nil {
+					c.JSON(http.StatusUnauthorized, ErrorResponse(c, "invalid token"))
+					c.Abort()
+					return
+				}
+			}
+			ctx = domain.WithOrg(ctx, orgID)
+		}
+
 		// Set user claims and role in the context
 		c.Set("user", claims)
 		c.Set("role", claims.Role)
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// AccessTokenQueryParam carries the access token of clients that cannot set
+// request headers, such as browser EventSource and WebSocket connections.
+const AccessTokenQueryParam = "access_token"
+
+// QueryTokenMiddleware moves a token given in the access_token query parameter
+// into the Authorization header for AuthMiddleware. A header already present wins.
+func QueryTokenMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query := c.Request.URL.Query()
+		if token := query.Get(AccessTokenQueryParam); token != "" {
+			if c.GetHeader("Authorization") == "" {
+				c.Request.Header.Set("Authorization", "Bearer "+token)
+			}
+			// Keep the token out of anything that records the URL later on
+			query.Del(AccessTokenQueryParam)
+			c.Request.URL.RawQuery = query.Encode()
+		}
 		c.Next()
 	}
 }
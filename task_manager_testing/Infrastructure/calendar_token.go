@@ -0,0 +1,30 @@
+package infrastructure
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// calendarTokenBytes is the amount of randomness in a calendar feed token.
+const calendarTokenBytes = 32
+
+// NewCalendarToken returns a random URL-safe token for a calendar feed and
+// the hash under which it is stored.
+func NewCalendarToken() (token, hash string, err error) {
+	secret := make([]byte, calendarTokenBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", err
+	}
+	token = base64.RawURLEncoding.EncodeToString(secret)
+	return token, HashCalendarToken(token), nil
+}
+
+// HashCalendarToken returns the stored form of a calendar feed token. Tokens
+// are random, so a plain SHA-256 is enough to keep a database leak from
+// exposing working feed URLs.
+func HashCalendarToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,94 @@
+package infrastructure
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CORSOptions configures which browser origins may call the API.
+type CORSOptions struct {
+	// AllowedOrigins lists the allowed origins; "*" allows any origin
+	AllowedOrigins []string
+	// AllowCredentials lets browsers send cookies and Authorization headers to
+	// the listed origins. It never applies to "*".
+	AllowCredentials bool
+	// MaxAge is how long browsers may cache a preflight response
+	MaxAge time.Duration
+}
+
+var (
+	corsAllowedMethods = strings.Join([]string{
+		http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodOptions,
+	}, ", ")
+	corsAllowedHeaders = strings.Join([]string{
+		"Authorization", "Content-Type", RequestIDHeader, IdempotencyKeyHeader,
+	}, ", ")
+	corsExposedHeaders = strings.Join([]string{
+		RequestIDHeader, IdempotentReplayedHeader, "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "Retry-After",
+	}, ", ")
+)
+
+// CORSMiddleware adds the CORS headers for requests from an allowed origin and
+// answers preflight requests itself. Requests from other origins are served
+// without CORS headers, so browsers refuse to expose the response, and their
+// preflight requests are rejected with 403.
+func CORSMiddleware(opts CORSOptions) gin.HandlerFunc {
+	allowed := make(map[string]bool, len(opts.AllowedOrigins))
+	allowAny := false
+	for _, origin := range opts.AllowedOrigins {
+		if origin == "*" {
+			allowAny = true
+		}
+		allowed[strings.TrimSuffix(origin, "/")] = true
+	}
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if origin == "" {
+			c.Next()
+			return
+		}
+
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+		c.Writer.Header().Add("Vary", "Origin")
+
+		if !allowAny && !allowed[origin] {
+			if preflight {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			c.Next()
+			return
+		}
+
+		// Browsers never send credentials to the wildcard, and echoing the origin
+		// instead would let every site make credentialed requests
+		if allowAny {
+			c.Header("Access-Control-Allow-Origin", "*")
+		} else {
+			c.Header("Access-Control-Allow-Origin", origin)
+			if opts.AllowCredentials {
+				c.Header("Access-Control-Allow-Credentials", "true")
+			}
+		}
+
+		if !preflight {
+			c.Header("Access-Control-Expose-Headers", corsExposedHeaders)
+			c.Next()
+			return
+		}
+
+		c.Writer.Header().Add("Vary", "Access-Control-Request-Method")
+		c.Writer.Header().Add("Vary", "Access-Control-Request-Headers")
+		c.Header("Access-Control-Allow-Methods", corsAllowedMethods)
+		c.Header("Access-Control-Allow-Headers", corsAllowedHeaders)
+		if opts.MaxAge > 0 {
+			c.Header("Access-Control-Max-Age", strconv.Itoa(int(opts.MaxAge.Seconds())))
+		}
+		c.AbortWithStatus(http.StatusNoContent)
+	}
+}
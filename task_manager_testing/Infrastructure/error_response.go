@@ -0,0 +1,44 @@
+package infrastructure
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorResponse builds the JSON body of an error response. It carries the
+// request's trace ID, when tracing is enabled, so a failure reported by a
+// client can be looked up in the tracing backend.
+func ErrorResponse(c *gin.Context, message string) gin.H {
+	body := gin.H{"error": message}
+	if traceID := TraceIDFromContext(c.Request.Context()); traceID != "" {
+		body["trace_id"] = traceID
+	}
+	return body
+}
+
+// ProblemContentType is the media type of RFC 9457 problem details.
+const ProblemContentType = "application/problem+json"
+
+// Problem is an RFC 9457 problem details body.
+type Problem struct {
+	Type    string `json:"type"`
+	Title   string `json:"title"`
+	Status  int    `json:"status"`
+	Detail  string `json:"detail,omitempty"`
+	TraceID string `json:"trace_id,omitempty"`
+}
+
+// AbortWithProblem stops the request and responds with a problem details body.
+func AbortWithProblem(c *gin.Context, status int, detail string) {
+	body, _ := json.Marshal(Problem{
+		Type:    "about:blank",
+		Title:   http.StatusText(status),
+		Status:  status,
+		Detail:  detail,
+		TraceID: TraceIDFromContext(c.Request.Context()),
+	})
+	c.Data(status, ProblemContentType, body)
+	c.Abort()
+}
@@ -0,0 +1,92 @@
+package infrastructure
+
+import (
+	"context"
+	"sync"
+	"task_manager_testing/domain"
+)
+
+// subscriberBuffer is how many events a subscriber may lag behind before it
+// is dropped; it then has to reconnect and resume from its last event.
+const subscriberBuffer = 64
+
+// TaskEventBus is an in-process publish/subscribe hub for task events. It
+// remembers the latest events so subscribers can resume after a reconnect.
+type TaskEventBus struct {
+	mu          sync.Mutex
+	history     []domain.TaskEvent
+	historySize int
+	subscribers map[chan domain.TaskEvent]struct{}
+	// closed is set by Close; later subscribers get a closed channel
+	closed bool
+}
+
+func NewTaskEventBus(historySize int) *TaskEventBus {
+	return &TaskEventBus{
+		historySize: historySize,
+		subscribers: map[chan domain.TaskEvent]struct{}{},
+	}
+}
+
+// PublishTaskEvent records event and sends it to every subscriber.
+func (b *TaskEventBus) PublishTaskEvent(ctx context.Context, event domain.TaskEvent) error {
+	b.mu.Lock()
+	b.history = append(b.history, event)
+	if len(b.history) > b.historySize {
+		b.history = b.history[len(b.history)-b.historySize:]
+	}
+	for subscriber := range b.subscribers {
+		select {
+		case subscriber <- event:
+		default:
+			// Never block the publisher on a slow reader
+			delete(b.subscribers, subscriber)
+			close(subscriber)
+		}
+	}
+	b.mu.Unlock()
+	return nil
+}
+
+func (b *TaskEventBus) Subscribe(lastEventID string) ([]domain.TaskEvent, bool, <-chan domain.TaskEvent, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var missed []domain.TaskEvent
+	resumed := lastEventID == ""
+	for i := len(b.history) - 1; i >= 0 && !resumed; i-- {
+		if b.history[i].ID == lastEventID {
+			missed = append(missed, b.history[i+1:]...)
+			resumed = true
+		}
+	}
+
+	subscriber := make(chan domain.TaskEvent, subscriberBuffer)
+	if b.closed {
+		close(subscriber)
+		return missed, resumed, subscriber, func() {}
+	}
+	b.subscribers[subscriber] = struct{}{}
+	cancel := func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subscribers[subscriber]; ok {
+			delete(b.subscribers, subscriber)
+			close(subscriber)
+		}
+	}
+	return missed, resumed, subscriber, cancel
+}
+
+// Close ends every subscription, now and later, so that the open streams
+// return. The HTTP server does not cancel them when it shuts down.
+func (b *TaskEventBus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for subscriber := range b.subscribers {
+		delete(b.subscribers, subscriber)
+		close(subscriber)
+	}
+}
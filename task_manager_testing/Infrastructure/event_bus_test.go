@@ -0,0 +1,87 @@
+package infrastructure_test
+
+import (
+	"context"
+	"testing"
+
+	infrastructure "task_manager_testing/Infrastructure"
+	"task_manager_testing/domain"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// EventBusSuite tests the in-process task event bus
+type EventBusSuite struct {
+	suite.Suite
+}
+
+func event(id string) domain.TaskEvent {
+	return domain.TaskEvent{ID: id, Type: domain.EventTaskUpdated}
+}
+
+func (suite *EventBusSuite) TestPublishAndResume() {
+	bus := infrastructure.NewTaskEventBus(3)
+
+	missed, resumed, live, cancel := bus.Subscribe("")
+	suite.Empty(missed)
+	suite.True(resumed)
+
+	for _, id := range []string{"1", "2", "3", "4"} {
+		suite.Require().NoError(bus.PublishTaskEvent(context.Background(), event(id)))
+	}
+	suite.Equal("1", (<-live).ID)
+	cancel()
+	cancel()
+	// Cancelling closes the channel after the events already buffered
+	remaining := 0
+	for range live {
+		remaining++
+	}
+	suite.Equal(3, remaining)
+
+	// Only the latest three events are remembered
+	missed, resumed, _, cancel = bus.Subscribe("2")
+	defer cancel()
+	suite.True(resumed)
+	suite.Equal([]domain.TaskEvent{event("3"), event("4")}, missed)
+
+	missed, resumed, _, cancel = bus.Subscribe("1")
+	defer cancel()
+	suite.False(resumed)
+	suite.Empty(missed)
+}
+
+func (suite *EventBusSuite) TestSlowSubscriberIsDropped() {
+	bus := infrastructure.NewTaskEventBus(10)
+	_, _, live, cancel := bus.Subscribe("")
+	defer cancel()
+
+	for i := 0; i < 100; i++ {
+		suite.Require().NoError(bus.PublishTaskEvent(context.Background(), event("e")))
+	}
+	received := 0
+	for range live {
+		received++
+	}
+	suite.Less(received, 100)
+}
+
+func (suite *EventBusSuite) TestCloseEndsSubscriptions() {
+	bus := infrastructure.NewTaskEventBus(10)
+	_, _, live, cancel := bus.Subscribe("")
+	defer cancel()
+
+	bus.Close()
+	_, open := <-live
+	suite.False(open)
+
+	// Streams opened while shutting down end at once
+	_, _, late, cancel := bus.Subscribe("")
+	defer cancel()
+	_, open = <-late
+	suite.False(open)
+}
+
+func TestEventBusSuite(t *testing.T) {
+	suite.Run(t, new(EventBusSuite))
+}
@@ -0,0 +1,110 @@
+package infrastructure
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ICalendarContentType is the media type of an iCalendar document.
+const ICalendarContentType = "text/calendar; charset=utf-8"
+
+// iCalendar date and date-time formats, always written in UTC.
+const (
+	icalDateTime = "20060102T150405Z"
+	icalDate     = "20060102"
+)
+
+// icalLineOctets is the longest content line allowed before folding.
+const icalLineOctets = 75
+
+// ICalendarWriter writes RFC 5545 content lines, escaping text values and
+// folding long lines. The first write error is kept and reported by Flush.
+type ICalendarWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func NewICalendarWriter(w io.Writer) *ICalendarWriter {
+	return &ICalendarWriter{w: bufio.NewWriter(w)}
+}
+
+// Begin opens a component such as VCALENDAR or VEVENT.
+func (iw *ICalendarWriter) Begin(component string) {
+	iw.Raw("BEGIN", component)
+}
+
+// End closes a component.
+func (iw *ICalendarWriter) End(component string) {
+	iw.Raw("END", component)
+}
+
+// Text writes a property with a TEXT value, escaping it as RFC 5545 requires.
+func (iw *ICalendarWriter) Text(name, value string) {
+	iw.Raw(name, EscapeICalendarText(value))
+}
+
+// DateTime writes a property with a UTC DATE-TIME value.
+func (iw *ICalendarWriter) DateTime(name string, t time.Time) {
+	iw.Raw(name, t.UTC().Format(icalDateTime))
+}
+
+// Date writes a property with a DATE value.
+func (iw *ICalendarWriter) Date(name string, t time.Time) {
+	iw.Raw(name+";VALUE=DATE", t.UTC().Format(icalDate))
+}
+
+// Raw writes a property whose value is already encoded.
+func (iw *ICalendarWriter) Raw(name, value string) {
+	if iw.err != nil {
+		return
+	}
+	_, iw.err = iw.w.WriteString(foldICalendarLine(name+":"+value) + "\r\n")
+}
+
+// Flush writes any buffered lines and returns the first error seen.
+func (iw *ICalendarWriter) Flush() error {
+	if iw.err != nil {
+		return iw.err
+	}
+	return iw.w.Flush()
+}
+
+// EscapeICalendarText escapes backslashes, semicolons, commas and newlines in
+// a TEXT value.
+func EscapeICalendarText(value string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	).Replace(value)
+}
+
+// foldICalendarLine splits line into chunks of at most 75 octets joined by a
+// line break and a space, without splitting multi-byte characters.
+func foldICalendarLine(line string) string {
+	if len(line) <= icalLineOctets {
+		return line
+	}
+
+	var folded strings.Builder
+	limit := icalLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		folded.WriteString(line[:cut])
+		folded.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space, which counts toward the limit
+		limit = icalLineOctets - 1
+	}
+	folded.WriteString(line)
+	return folded.String()
+}
@@ -0,0 +1,44 @@
+package infrastructure_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	infrastructure "task_manager_testing/Infrastructure"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// ICalendarSuite tests the RFC 5545 encoding of calendar feeds
+type ICalendarSuite struct {
+	suite.Suite
+}
+
+func (suite *ICalendarSuite) TestEscapeText() {
+	suite.Equal(`a\, b\; c\\d\ne`, infrastructure.EscapeICalendarText("a, b; c\\d\r\ne"))
+}
+
+func (suite *ICalendarSuite) TestWriterFoldsLongLines() {
+	var buf bytes.Buffer
+	cal := infrastructure.NewICalendarWriter(&buf)
+	cal.Text("SUMMARY", strings.Repeat("é", 60))
+	cal.DateTime("DUE", time.Date(2030, 1, 15, 9, 30, 0, 0, time.FixedZone("CET", 3600)))
+	cal.Date("DTSTART", time.Date(2030, 1, 15, 0, 0, 0, 0, time.UTC))
+	suite.Require().NoError(cal.Flush())
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n")
+	suite.Require().Len(lines, 4)
+	unfolded := lines[0] + strings.TrimPrefix(lines[1], " ")
+	suite.Equal("SUMMARY:"+strings.Repeat("é", 60), unfolded)
+	for _, line := range lines {
+		suite.LessOrEqual(len(line), 75)
+	}
+	suite.Equal("DUE:20300115T083000Z", lines[2])
+	suite.Equal("DTSTART;VALUE=DATE:20300115", lines[3])
+}
+
+func TestICalendarSuite(t *testing.T) {
+	suite.Run(t, new(ICalendarSuite))
+}
@@ -0,0 +1,206 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"task_manager_testing/domain"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// IdempotencyKeyHeader carries the client-chosen key of a write request.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on responses replayed from the store.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+	// idempotencyLockTimeout bounds how long a request that never finished,
+	// for example because the server crashed, blocks retries with its key.
+	idempotencyLockTimeout = time.Minute
+	// idempotencyMemoryLimit is the largest body kept in memory while it is
+	// hashed; larger ones, such as attachment uploads, go to a temporary file.
+	idempotencyMemoryLimit = 64 << 10
+)
+
+// IdempotencyMiddleware makes write requests that carry an Idempotency-Key
+// header safe to retry. The first response with a given key is stored for ttl
+// and replayed for every retry. Reusing a key for a different request is
+// rejected with 422, and retrying while the first request is still running
+// with 409. Server errors are not stored so the request can be retried.
+//
+// Keys are scoped to the authenticated user, so it must run after AuthMiddleware.
+func IdempotencyMiddleware(store domain.IdempotencyRepository, ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" || !isWriteMethod(c.Request.Method) {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse(c, "Idempotency-Key must be at most 255 characters"))
+			return
+		}
+
+		digest := requestHash(c.Request)
+		body, cleanup, err := spoolBody(c.Request.Body, digest)
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				abortBodyTooLarge(c, maxBytesErr.Limit)
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse(c, "failed to read request body"))
+			return
+		}
+		defer cleanup()
+		c.Request.Body = body
+
+		ctx := c.Request.Context()
+		userID := UserIDFromContext(ctx)
+		if userID == "" {
+			userID = "anonymous"
+		}
+		now := time.Now()
+		record := domain.IdempotencyRecord{
+			Key:         userID + ":" + key,
+			RequestHash: hex.EncodeToString(digest.Sum(nil)),
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(idempotencyLockTimeout),
+		}
+
+		existing, reserved, err := store.Reserve(ctx, record)
+		if err != nil {
+			slog.ErrorContext(ctx, "idempotency store failed", slog.String("error", err.Error()))
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, ErrorResponse(c, "failed to process Idempotency-Key"))
+			return
+		}
+		if !reserved {
+			replay(c, existing, record.RequestHash)
+			return
+		}
+
+		// Keep the record up to date even if the client goes away
+		storeCtx := context.WithoutCancel(ctx)
+		recorder := &recordingWriter{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		completed := false
+		defer func() {
+			// Let the request be retried when the handler failed or panicked
+			if !completed {
+				if err := store.Release(storeCtx, record.Key); err != nil {
+					slog.ErrorContext(ctx, "failed to release Idempotency-Key", slog.String("error", err.Error()))
+				}
+			}
+		}()
+
+		c.Next()
+
+		if recorder.Status() >= http.StatusInternalServerError {
+			return
+		}
+		record.Completed = true
+		record.StatusCode = recorder.Status()
+		record.ContentType = recorder.Header().Get("Content-Type")
+		record.Body = recorder.body.Bytes()
+		record.ExpiresAt = time.Now().Add(ttl)
+		if err := store.Complete(storeCtx, record); err != nil {
+			slog.ErrorContext(ctx, "failed to store idempotent response", slog.String("error", err.Error()))
+			return
+		}
+		completed = true
+	}
+}
+
+// replay answers a retried request from the stored record.
+func replay(c *gin.Context, existing domain.IdempotencyRecord, requestHash string) {
+	switch {
+	case existing.RequestHash != requestHash:
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, ErrorResponse(c, "Idempotency-Key was already used for a different request"))
+	case !existing.Completed:
+		c.AbortWithStatusJSON(http.StatusConflict, ErrorResponse(c, "a request with this Idempotency-Key is still being processed"))
+	default:
+		c.Header(IdempotentReplayedHeader, "true")
+		c.Data(existing.StatusCode, existing.ContentType, existing.Body)
+		c.Abort()
+	}
+}
+
+// requestHash starts the hash identifying a request by its method, path,
+// query and body; spoolBody adds the body.
+func requestHash(req *http.Request) hash.Hash {
+	digest := sha256.New()
+	io.WriteString(digest, req.Method+" "+req.URL.Path+"?"+req.URL.RawQuery+"\n")
+	return digest
+}
+
+// spoolBody reads body while writing it to digest and returns a reader over the
+// same bytes for the handler. Bodies up to idempotencyMemoryLimit stay in
+// memory, larger ones are streamed to a temporary file that cleanup removes.
+func spoolBody(body io.Reader, digest io.Writer) (io.ReadCloser, func(), error) {
+	var head bytes.Buffer
+	_, err := io.CopyN(io.MultiWriter(&head, digest), body, idempotencyMemoryLimit+1)
+	if errors.Is(err, io.EOF) {
+		return io.NopCloser(&head), func() {}, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	file, err := os.CreateTemp("", "idempotent-body-*")
+	if err != nil {
+		return nil, nil, err
+	}
+	cleanup := func() {
+		file.Close()
+		os.Remove(file.Name())
+	}
+	if _, err := io.Copy(file, &head); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	if _, err := io.Copy(io.MultiWriter(file, digest), body); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	// The handler may close the body; the file stays open until cleanup
+	return io.NopCloser(file), cleanup, nil
+}
+
+func isWriteMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// recordingWriter keeps a copy of the response body.
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
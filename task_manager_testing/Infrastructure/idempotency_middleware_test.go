@@ -0,0 +1,161 @@
+package infrastructure_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	infrastructure "task_manager_testing/Infrastructure"
+	repository "task_manager_testing/Repository"
+	"task_manager_testing/domain"
+	"task_manager_testing/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// IdempotencySuite tests that retried write requests are replayed
+type IdempotencySuite struct {
+	suite.Suite
+	store   domain.IdempotencyRepository
+	router  *gin.Engine
+	created int
+	status  int
+}
+
+func (suite *IdempotencySuite) SetupTest() {
+	suite.store = repository.NewInMemoryIdempotencyRepository()
+	suite.created = 0
+	suite.status = http.StatusCreated
+	suite.router = suite.newRouter(suite.store)
+}
+
+func (suite *IdempotencySuite) newRouter(store domain.IdempotencyRepository) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Request = c.Request.WithContext(infrastructure.WithUserID(c.Request.Context(), c.GetHeader("X-Test-User")))
+	})
+	router.Use(infrastructure.IdempotencyMiddleware(store, time.Hour))
+	router.POST("/tasks", func(c *gin.Context) {
+		suite.created++
+		body, _ := io.ReadAll(c.Request.Body)
+		c.JSON(suite.status, gin.H{"created": suite.created, "size": len(body)})
+	})
+	return router
+}
+
+func (suite *IdempotencySuite) post(key, userID, body string) *httptest.ResponseRecorder {
+	return suite.postTo("/tasks", key, userID, body)
+}
+
+func (suite *IdempotencySuite) postTo(target, key, userID, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Test-User", userID)
+	if key != "" {
+		req.Header.Set(infrastructure.IdempotencyKeyHeader, key)
+	}
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	return w
+}
+
+func (suite *IdempotencySuite) TestReplaysFirstResponse() {
+	first := suite.post("key-1", "user-1", `{"title":"a"}`)
+	suite.Equal(http.StatusCreated, first.Code)
+	suite.Empty(first.Header().Get(infrastructure.IdempotentReplayedHeader))
+
+	retry := suite.post("key-1", "user-1", `{"title":"a"}`)
+	suite.Equal(http.StatusCreated, retry.Code)
+	suite.Equal("true", retry.Header().Get(infrastructure.IdempotentReplayedHeader))
+	suite.Equal(first.Body.String(), retry.Body.String())
+	suite.Equal("application/json; charset=utf-8", retry.Header().Get("Content-Type"))
+	suite.Equal(1, suite.created)
+}
+
+func (suite *IdempotencySuite) TestWithoutKey() {
+	suite.post("", "user-1", `{"title":"a"}`)
+	suite.post("", "user-1", `{"title":"a"}`)
+	suite.Equal(2, suite.created)
+}
+
+func (suite *IdempotencySuite) TestKeysAreScopedToUsers() {
+	suite.post("key-1", "user-1", `{"title":"a"}`)
+	w := suite.post("key-1", "user-2", `{"title":"a"}`)
+	suite.Empty(w.Header().Get(infrastructure.IdempotentReplayedHeader))
+	suite.Equal(2, suite.created)
+}
+
+func (suite *IdempotencySuite) TestDifferentPayload() {
+	suite.post("key-1", "user-1", `{"title":"a"}`)
+	w := suite.post("key-1", "user-1", `{"title":"b"}`)
+	suite.Equal(http.StatusUnprocessableEntity, w.Code)
+	suite.Equal(1, suite.created)
+}
+
+func (suite *IdempotencySuite) TestDifferentQuery() {
+	suite.postTo("/tasks?dry_run=true", "key-1", "user-1", `{"title":"a"}`)
+	w := suite.postTo("/tasks?dry_run=false", "key-1", "user-1", `{"title":"a"}`)
+	suite.Equal(http.StatusUnprocessableEntity, w.Code)
+	suite.Equal(1, suite.created)
+}
+
+// TestLargeBody tests that bodies too large to keep in memory reach the
+// handler whole and are still told apart
+func (suite *IdempotencySuite) TestLargeBody() {
+	body := strings.Repeat("a", 1<<20)
+	first := suite.post("key-1", "user-1", body)
+	suite.Equal(http.StatusCreated, first.Code)
+	suite.JSONEq(`{"created":1,"size":1048576}`, first.Body.String())
+
+	retry := suite.post("key-1", "user-1", body)
+	suite.Equal("true", retry.Header().Get(infrastructure.IdempotentReplayedHeader))
+	changed := suite.post("key-1", "user-1", body[1:]+"b")
+	suite.Equal(http.StatusUnprocessableEntity, changed.Code)
+	suite.Equal(1, suite.created)
+}
+
+func (suite *IdempotencySuite) TestServerErrorsAreNotStored() {
+	suite.status = http.StatusInternalServerError
+	suite.Equal(http.StatusInternalServerError, suite.post("key-1", "user-1", `{"title":"a"}`).Code)
+
+	suite.status = http.StatusCreated
+	w := suite.post("key-1", "user-1", `{"title":"a"}`)
+	suite.Equal(http.StatusCreated, w.Code)
+	suite.Empty(w.Header().Get(infrastructure.IdempotentReplayedHeader))
+	suite.Equal(2, suite.created)
+}
+
+func (suite *IdempotencySuite) TestRequestInProgress() {
+	// The first request with the key has not finished yet
+	store := &mocks.IdempotencyRepository{}
+	store.On("Reserve", mock.Anything, mock.Anything).Return(func(ctx context.Context, record domain.IdempotencyRecord) (domain.IdempotencyRecord, bool, error) {
+		return domain.IdempotencyRecord{Key: record.Key, RequestHash: record.RequestHash}, false, nil
+	})
+	suite.router = suite.newRouter(store)
+
+	w := suite.post("key-1", "user-1", `{"title":"a"}`)
+	suite.Equal(http.StatusConflict, w.Code)
+	suite.Equal(0, suite.created)
+}
+
+func (suite *IdempotencySuite) TestStoreFailure() {
+	store := &mocks.IdempotencyRepository{}
+	store.On("Reserve", mock.Anything, mock.Anything).Return(domain.IdempotencyRecord{}, false, errors.New("connection refused"))
+	suite.router = suite.newRouter(store)
+
+	w := suite.post("key-1", "user-1", `{"title":"a"}`)
+	suite.Equal(http.StatusServiceUnavailable, w.Code)
+	suite.Equal(0, suite.created)
+}
+
+func TestIdempotencySuite(t *testing.T) {
+	suite.Run(t, new(IdempotencySuite))
+}
@@ -8,19 +8,34 @@ import (
 )
 
 
-var jwtKey = []byte("1234")
+var (
+	jwtKey         = []byte("1234")
+	jwtIssuer      = ""
+	accessTokenTTL = 24 * time.Hour
+)
+
+// ConfigureJWT sets the signing secret, the issuer and the lifetime of the
+// tokens generated by GenerateJWT and accepted by AuthMiddleware.
+func ConfigureJWT(secret, issuer string, ttl time.Duration) {
+	jwtKey = []byte(secret)
+	jwtIssuer = issuer
+	accessTokenTTL = ttl
+}
 
-// GenerateJWT generates a JWT token for the given user ID, username, and role.
-// The token expires in 24 hours.
-func GenerateJWT(userID string, username string, role string) (string, error) {
+// GenerateJWT generates a JWT token for the given user ID, username, role and
+// organization ID, empty for users without an organization. The token expires
+// after the configured access token TTL (24 hours by default).
+func GenerateJWT(userID string, username string, role string, orgID string) (string, error) {
 	claims := &domain.Claims{
 		UserID:   userID,
 		Username: username,
 		Role:     role,
+		OrgID:    orgID,
 		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: time.Now().Add(time.Hour * 24).Unix(),
+			ExpiresAt: time.Now().Add(accessTokenTTL).Unix(),
+			Issuer:    jwtIssuer,
 		},
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString(jwtKey)
-}
\ No newline at end of file
+}
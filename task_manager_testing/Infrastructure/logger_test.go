@@ -0,0 +1,73 @@
+package infrastructure_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	infrastructure "task_manager_testing/Infrastructure"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+)
+
+// LoggerSuite tests structured logging and request ID propagation
+type LoggerSuite struct {
+	suite.Suite
+}
+
+func (suite *LoggerSuite) TestParseLogLevel() {
+	suite.Equal(slog.LevelDebug, infrastructure.ParseLogLevel("DEBUG"))
+	suite.Equal(slog.LevelWarn, infrastructure.ParseLogLevel("warn"))
+	suite.Equal(slog.LevelError, infrastructure.ParseLogLevel("error"))
+	suite.Equal(slog.LevelInfo, infrastructure.ParseLogLevel(""))
+	suite.Equal(slog.LevelInfo, infrastructure.ParseLogLevel("verbose"))
+}
+
+func (suite *LoggerSuite) TestLoggerIncludesContextIDs() {
+	var buf bytes.Buffer
+	logger := infrastructure.NewLogger(&buf, "info")
+
+	ctx := infrastructure.WithRequestID(context.Background(), "req-1")
+	ctx = infrastructure.WithUserID(ctx, "user-1")
+	logger.InfoContext(ctx, "hello")
+	logger.DebugContext(ctx, "filtered out")
+
+	var record map[string]interface{}
+	suite.Require().NoError(json.Unmarshal(buf.Bytes(), &record))
+	suite.Equal("hello", record["msg"])
+	suite.Equal("req-1", record["request_id"])
+	suite.Equal("user-1", record["user_id"])
+}
+
+func (suite *LoggerSuite) TestRequestIDMiddleware() {
+	var seen string
+	router := gin.New()
+	router.Use(infrastructure.RequestIDMiddleware())
+	router.GET("/ping", func(c *gin.Context) {
+		seen = infrastructure.RequestIDFromContext(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+
+	// A client supplied ID is reused and echoed
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	req.Header.Set(infrastructure.RequestIDHeader, "client-id")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	suite.Equal("client-id", w.Header().Get(infrastructure.RequestIDHeader))
+	suite.Equal("client-id", seen)
+
+	// Otherwise a new ID is generated
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ping", nil))
+	suite.NotEmpty(w.Header().Get(infrastructure.RequestIDHeader))
+	suite.Equal(w.Header().Get(infrastructure.RequestIDHeader), seen)
+}
+
+func TestLoggerSuite(t *testing.T) {
+	suite.Run(t, new(LoggerSuite))
+}
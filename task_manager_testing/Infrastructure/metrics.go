@@ -0,0 +1,126 @@
+package infrastructure
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Metrics holds the Prometheus collectors exposed on /metrics.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	HTTPRequestDuration         *prometheus.HistogramVec
+	RepositoryOperationDuration *prometheus.HistogramVec
+	RepositoryErrors            *prometheus.CounterVec
+	LoginAttempts               *prometheus.CounterVec
+}
+
+// NewMetrics creates the application's collectors in a dedicated registry.
+func NewMetrics() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		HTTPRequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "task_manager",
+			Name:      "http_request_duration_seconds",
+			Help:      "Duration of HTTP requests by method, route and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		RepositoryOperationDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "task_manager",
+			Name:      "repository_operation_duration_seconds",
+			Help:      "Duration of repository operations against MongoDB.",
+			Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"repository", "operation"}),
+		RepositoryErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "task_manager",
+			Name:      "repository_errors_total",
+			Help:      "Number of failed repository operations.",
+		}, []string{"repository", "operation"}),
+		LoginAttempts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "task_manager",
+			Name:      "login_attempts_total",
+			Help:      "Number of login attempts by result.",
+		}, []string{"result"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.HTTPRequestDuration,
+		m.RepositoryOperationDuration,
+		m.RepositoryErrors,
+		m.LoginAttempts,
+	)
+	return m
+}
+
+// Handler returns the HTTP handler serving the registry in the Prometheus text format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// Registry returns the underlying registry, mainly for tests.
+func (m *Metrics) Registry() *prometheus.Registry {
+	return m.registry
+}
+
+// ObserveRepositoryOperation records the latency and outcome of a repository call.
+func (m *Metrics) ObserveRepositoryOperation(repository, operation string, start time.Time, failed bool) {
+	m.RepositoryOperationDuration.WithLabelValues(repository, operation).Observe(time.Since(start).Seconds())
+	if failed {
+		m.RepositoryErrors.WithLabelValues(repository, operation).Inc()
+	}
+}
+
+// RecordLogin counts a login attempt as a success or a failure.
+func (m *Metrics) RecordLogin(success bool) {
+	result := "failure"
+	if success {
+		result = "success"
+	}
+	m.LoginAttempts.WithLabelValues(result).Inc()
+}
+
+// RegisterTaskCounter exposes the number of tasks per status, computed by
+// countByStatus each time the metrics are scraped.
+func (m *Metrics) RegisterTaskCounter(countByStatus func(ctx context.Context) (map[string]int64, error)) {
+	m.registry.MustRegister(&taskCollector{
+		countByStatus: countByStatus,
+		desc: prometheus.NewDesc(
+			"task_manager_tasks",
+			"Number of tasks by status.",
+			[]string{"status"}, nil,
+		),
+	})
+}
+
+// taskCollector queries the current task counts on every scrape.
+type taskCollector struct {
+	countByStatus func(ctx context.Context) (map[string]int64, error)
+	desc          *prometheus.Desc
+}
+
+func (tc *taskCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- tc.desc
+}
+
+func (tc *taskCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	counts, err := tc.countByStatus(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to count tasks for metrics", slog.String("error", err.Error()))
+		ch <- prometheus.NewInvalidMetric(tc.desc, err)
+		return
+	}
+	for status, count := range counts {
+		ch <- prometheus.MustNewConstMetric(tc.desc, prometheus.GaugeValue, float64(count), status)
+	}
+}
@@ -1,6 +1,10 @@
 package infrastructure
 
-import "golang.org/x/crypto/bcrypt"
+import (
+	"context"
+
+	"golang.org/x/crypto/bcrypt"
+)
 
 func HashPassword(password string) (string, error) {
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
@@ -12,4 +16,21 @@ func HashPassword(password string) (string, error) {
 
 func ComparePasswords(hashedPassword, password string) error {
 	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
-}
\ No newline at end of file
+}
+
+// HashPasswordContext hashes password inside its own span so that bcrypt's
+// cost shows up separately in request traces.
+func HashPasswordContext(ctx context.Context, password string) (hashed string, err error) {
+	_, span := StartSpan(ctx, "bcrypt.GenerateFromPassword")
+	defer EndSpan(span, &err)
+
+	return HashPassword(password)
+}
+
+// ComparePasswordsContext compares a password with its hash inside its own span.
+func ComparePasswordsContext(ctx context.Context, hashedPassword, password string) (err error) {
+	_, span := StartSpan(ctx, "bcrypt.CompareHashAndPassword")
+	defer EndSpan(span, &err)
+
+	return ComparePasswords(hashedPassword, password)
+}
@@ -0,0 +1,69 @@
+package infrastructure
+
+import (
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IdentifyFunc returns the identity a request is rate limited by.
+type IdentifyFunc func(c *gin.Context) string
+
+// ClientIPIdentity limits requests by the client's IP address.
+func ClientIPIdentity(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// UserIdentity limits requests by the authenticated user, and falls back to
+// the client's IP address when there is none. It must run after AuthMiddleware.
+func UserIdentity(c *gin.Context) string {
+	if userID := UserIDFromContext(c.Request.Context()); userID != "" {
+		return "user:" + userID
+	}
+	return ClientIPIdentity(c)
+}
+
+// RateLimitMiddleware allows each identity limit.Burst requests at once and
+// limit.Rate requests per second after that. Buckets are kept per group, so
+// the same client has separate budgets for separate route groups. Every
+// response carries the RateLimit-Limit, RateLimit-Remaining and
+// RateLimit-Reset headers, and rejected requests get 429 with Retry-After.
+//
+// If the store fails, the request is let through rather than taking the API
+// down with it.
+func RateLimitMiddleware(store RateLimitStore, group string, limit RateLimit, identify IdentifyFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := "ratelimit:" + group + ":" + identify(c)
+
+		result, err := store.Take(c.Request.Context(), key, limit)
+		if err != nil {
+			slog.ErrorContext(c.Request.Context(), "rate limit store failed",
+				slog.String("group", group),
+				slog.String("error", err.Error()),
+			)
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.ResetAfter)))
+
+		if !result.Allowed {
+			slog.WarnContext(c.Request.Context(), "rate limit exceeded", slog.String("group", group))
+			c.Header("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, ErrorResponse(c, "rate limit exceeded"))
+			return
+		}
+		c.Next()
+	}
+}
+
+// ceilSeconds rounds d up to whole seconds, as the headers require.
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,153 @@
+package infrastructure_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	infrastructure "task_manager_testing/Infrastructure"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/suite"
+)
+
+// RateLimitSuite runs the same token bucket checks against every store
+type RateLimitSuite struct {
+	suite.Suite
+	now    time.Time
+	stores map[string]infrastructure.RateLimitStore
+}
+
+func (suite *RateLimitSuite) SetupTest() {
+	suite.now = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return suite.now }
+
+	// miniredis is a local Redis fake that runs the token bucket Lua script
+	server := miniredis.RunT(suite.T())
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	suite.T().Cleanup(func() { client.Close() })
+
+	suite.stores = map[string]infrastructure.RateLimitStore{
+		"memory": infrastructure.NewMemoryRateLimitStoreWithClock(clock),
+		"redis":  infrastructure.NewRedisRateLimitStoreWithClock(client, clock),
+	}
+}
+
+func (suite *RateLimitSuite) TestTokenBucket() {
+	limit := infrastructure.PerMinute(60, 3)
+
+	for name, store := range suite.stores {
+		suite.Run(name, func() {
+			ctx := context.Background()
+
+			// The burst is available at once
+			for i := 2; i >= 0; i-- {
+				result, err := store.Take(ctx, "ratelimit:test:"+name, limit)
+				suite.Require().NoError(err)
+				suite.True(result.Allowed)
+				suite.Equal(3, result.Limit)
+				suite.Equal(i, result.Remaining)
+			}
+
+			result, err := store.Take(ctx, "ratelimit:test:"+name, limit)
+			suite.Require().NoError(err)
+			suite.False(result.Allowed)
+			suite.Equal(time.Second, result.RetryAfter)
+			suite.Equal(3*time.Second, result.ResetAfter)
+
+			// Other identities have their own bucket
+			result, err = store.Take(ctx, "ratelimit:other:"+name, limit)
+			suite.Require().NoError(err)
+			suite.True(result.Allowed)
+
+			// One token is added every second
+			suite.now = suite.now.Add(time.Second)
+			result, err = store.Take(ctx, "ratelimit:test:"+name, limit)
+			suite.Require().NoError(err)
+			suite.True(result.Allowed)
+			suite.Equal(0, result.Remaining)
+
+			suite.now = suite.now.Add(time.Minute)
+			result, err = store.Take(ctx, "ratelimit:test:"+name, limit)
+			suite.Require().NoError(err)
+			suite.True(result.Allowed)
+			suite.Equal(2, result.Remaining)
+		})
+	}
+}
+
+func (suite *RateLimitSuite) newRouter(store infrastructure.RateLimitStore, identify infrastructure.IdentifyFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if userID := c.GetHeader("X-Test-User"); userID != "" {
+			c.Request = c.Request.WithContext(infrastructure.WithUserID(c.Request.Context(), userID))
+		}
+	})
+	router.Use(infrastructure.RateLimitMiddleware(store, "test", infrastructure.PerMinute(30, 2), identify))
+	router.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return router
+}
+
+func (suite *RateLimitSuite) get(router *gin.Engine, userID string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if userID != "" {
+		req.Header.Set("X-Test-User", userID)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func (suite *RateLimitSuite) TestMiddlewareHeaders() {
+	router := suite.newRouter(suite.stores["memory"], infrastructure.ClientIPIdentity)
+
+	w := suite.get(router, "")
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Equal("2", w.Header().Get("RateLimit-Limit"))
+	suite.Equal("1", w.Header().Get("RateLimit-Remaining"))
+	suite.Equal("2", w.Header().Get("RateLimit-Reset"))
+
+	suite.Equal(http.StatusOK, suite.get(router, "").Code)
+
+	w = suite.get(router, "")
+	suite.Equal(http.StatusTooManyRequests, w.Code)
+	suite.Equal("0", w.Header().Get("RateLimit-Remaining"))
+	suite.Equal("2", w.Header().Get("Retry-After"))
+	suite.JSONEq(`{"error":"rate limit exceeded"}`, w.Body.String())
+}
+
+func (suite *RateLimitSuite) TestMiddlewareLimitsByUser() {
+	router := suite.newRouter(suite.stores["redis"], infrastructure.UserIdentity)
+
+	suite.Equal(http.StatusOK, suite.get(router, "user-1").Code)
+	suite.Equal(http.StatusOK, suite.get(router, "user-1").Code)
+	suite.Equal(http.StatusTooManyRequests, suite.get(router, "user-1").Code)
+
+	// Users behind the same IP are limited separately
+	suite.Equal(http.StatusOK, suite.get(router, "user-2").Code)
+}
+
+// failingStore is a rate limit store whose backend is unavailable
+type failingStore struct{}
+
+func (failingStore) Take(ctx context.Context, key string, limit infrastructure.RateLimit) (infrastructure.RateLimitResult, error) {
+	return infrastructure.RateLimitResult{}, errors.New("connection refused")
+}
+
+func (suite *RateLimitSuite) TestMiddlewareFailsOpen() {
+	router := suite.newRouter(failingStore{}, infrastructure.ClientIPIdentity)
+
+	w := suite.get(router, "")
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Empty(w.Header().Get("RateLimit-Limit"))
+}
+
+func TestRateLimitSuite(t *testing.T) {
+	suite.Run(t, new(RateLimitSuite))
+}
@@ -0,0 +1,48 @@
+package infrastructure
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RedactedPathSegment replaces the value of a secret route parameter in the
+// request path.
+const RedactedPathSegment = "REDACTED"
+
+// RedactPathMiddleware masks the values of the route parameters named params
+// in the request path, so the tracing, logging and recovery middleware after
+// it never record them. Handlers still read the values with c.Param.
+func RedactPathMiddleware(params ...string) gin.HandlerFunc {
+	secret := make(map[string]bool, len(params))
+	for _, param := range params {
+		secret[param] = true
+	}
+
+	return func(c *gin.Context) {
+		routeSegments := strings.Split(c.FullPath(), "/")
+		pathSegments := strings.Split(c.Request.URL.Path, "/")
+		if len(routeSegments) != len(pathSegments) {
+			c.Next()
+			return
+		}
+
+		redacted := false
+		for i, segment := range routeSegments {
+			if strings.HasPrefix(segment, ":") && secret[segment[1:]] {
+				pathSegments[i] = RedactedPathSegment
+				redacted = true
+			}
+		}
+		if redacted {
+			req := c.Request.WithContext(c.Request.Context())
+			u := *req.URL
+			u.Path = strings.Join(pathSegments, "/")
+			u.RawPath = ""
+			req.URL = &u
+			req.RequestURI = u.RequestURI()
+			c.Request = req
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,88 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// tokenBucketScript refills and takes a token from the bucket stored at
+// KEYS[1] atomically. ARGV holds the rate in tokens per millisecond, the burst
+// and the current time in milliseconds. It returns whether the request is
+// allowed, the tokens left and the milliseconds until a token is available
+// and until the bucket is full again.
+var tokenBucketScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+	tokens = burst
+	ts = now
+end
+
+if now > ts then
+	tokens = math.min(burst, tokens + (now - ts) * rate)
+	ts = now
+end
+
+local allowed = 0
+local retry = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	retry = math.ceil((1 - tokens) / rate)
+end
+local reset = math.ceil((burst - tokens) / rate)
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', tostring(ts))
+redis.call('PEXPIRE', KEYS[1], math.max(reset, 1))
+return {allowed, math.floor(tokens), retry, reset}
+`)
+
+// RedisRateLimitStore keeps token buckets in Redis so every instance of the
+// service shares the same limits. Any client that can run Lua scripts works,
+// including go-redis clients, rings and clusters.
+type RedisRateLimitStore struct {
+	client redis.Scripter
+	now    func() time.Time
+}
+
+func NewRedisRateLimitStore(client redis.Scripter) *RedisRateLimitStore {
+	return NewRedisRateLimitStoreWithClock(client, time.Now)
+}
+
+// NewRedisRateLimitStoreWithClock creates a store that reads the time from now.
+func NewRedisRateLimitStoreWithClock(client redis.Scripter, now func() time.Time) *RedisRateLimitStore {
+	return &RedisRateLimitStore{client: client, now: now}
+}
+
+func (s *RedisRateLimitStore) Take(ctx context.Context, key string, limit RateLimit) (RateLimitResult, error) {
+	args := []interface{}{
+		strconv.FormatFloat(limit.Rate/1000, 'g', -1, 64),
+		limit.Burst,
+		s.now().UnixMilli(),
+	}
+	values, err := tokenBucketScript.Run(ctx, s.client, []string{key}, args...).Int64Slice()
+	if err != nil {
+		return RateLimitResult{}, err
+	}
+	if len(values) != 4 {
+		return RateLimitResult{}, fmt.Errorf("unexpected rate limit script reply %v", values)
+	}
+
+	return RateLimitResult{
+		Allowed:    values[0] == 1,
+		Limit:      limit.Burst,
+		Remaining:  int(values[1]),
+		RetryAfter: time.Duration(values[2]) * time.Millisecond,
+		ResetAfter: time.Duration(values[3]) * time.Millisecond,
+	}, nil
+}
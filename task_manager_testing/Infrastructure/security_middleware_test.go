@@ -0,0 +1,157 @@
+package infrastructure_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	infrastructure "task_manager_testing/Infrastructure"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+)
+
+// SecurityMiddlewareSuite tests CORS, security headers and body size limits
+type SecurityMiddlewareSuite struct {
+	suite.Suite
+}
+
+func (suite *SecurityMiddlewareSuite) newRouter(middleware ...gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware...)
+	router.POST("/echo", func(c *gin.Context) {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.String(http.StatusOK, string(body))
+	})
+	return router
+}
+
+func (suite *SecurityMiddlewareSuite) serve(router *gin.Engine, req *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func (suite *SecurityMiddlewareSuite) TestCORSAllowedOrigin() {
+	router := suite.newRouter(infrastructure.CORSMiddleware(infrastructure.CORSOptions{
+		AllowedOrigins:   []string{"https://app.example.com"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader("{}"))
+	req.Header.Set("Origin", "https://app.example.com")
+	w := suite.serve(router, req)
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Equal("https://app.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	suite.Equal("true", w.Header().Get("Access-Control-Allow-Credentials"))
+	suite.Contains(w.Header().Get("Access-Control-Expose-Headers"), "X-Request-ID")
+	suite.Equal("Origin", w.Header().Get("Vary"))
+
+	// Preflight requests are answered without reaching a route
+	req = httptest.NewRequest(http.MethodOptions, "/echo", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	w = suite.serve(router, req)
+	suite.Equal(http.StatusNoContent, w.Code)
+	suite.Contains(w.Header().Get("Access-Control-Allow-Methods"), http.MethodPost)
+	suite.Contains(w.Header().Get("Access-Control-Allow-Headers"), "Authorization")
+	suite.Equal("600", w.Header().Get("Access-Control-Max-Age"))
+}
+
+func (suite *SecurityMiddlewareSuite) TestCORSDisallowedOrigin() {
+	router := suite.newRouter(infrastructure.CORSMiddleware(infrastructure.CORSOptions{
+		AllowedOrigins: []string{"https://app.example.com"},
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader("{}"))
+	req.Header.Set("Origin", "https://evil.example.com")
+	w := suite.serve(router, req)
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Empty(w.Header().Get("Access-Control-Allow-Origin"))
+
+	req = httptest.NewRequest(http.MethodOptions, "/echo", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	w = suite.serve(router, req)
+	suite.Equal(http.StatusForbidden, w.Code)
+	suite.Empty(w.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func (suite *SecurityMiddlewareSuite) TestCORSWildcard() {
+	// Any origin gets the wildcard rather than its own origin, even with credentials
+	for _, allowCredentials := range []bool{false, true} {
+		router := suite.newRouter(infrastructure.CORSMiddleware(infrastructure.CORSOptions{
+			AllowedOrigins:   []string{"*"},
+			AllowCredentials: allowCredentials,
+		}))
+
+		req := httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader("{}"))
+		req.Header.Set("Origin", "https://anywhere.example.com")
+		w := suite.serve(router, req)
+		suite.Equal("*", w.Header().Get("Access-Control-Allow-Origin"))
+		suite.Empty(w.Header().Get("Access-Control-Allow-Credentials"))
+	}
+}
+
+func (suite *SecurityMiddlewareSuite) TestSecurityHeaders() {
+	router := suite.newRouter(infrastructure.SecurityHeadersMiddleware(infrastructure.SecurityHeadersOptions{
+		HSTSMaxAge: 24 * time.Hour,
+	}))
+
+	w := suite.serve(router, httptest.NewRequest(http.MethodPost, "/echo", nil))
+	suite.Equal("nosniff", w.Header().Get("X-Content-Type-Options"))
+	suite.Equal("DENY", w.Header().Get("X-Frame-Options"))
+	suite.Equal(infrastructure.DefaultContentSecurityPolicy, w.Header().Get("Content-Security-Policy"))
+	suite.Equal("max-age=86400; includeSubDomains", w.Header().Get("Strict-Transport-Security"))
+
+	router = suite.newRouter(infrastructure.SecurityHeadersMiddleware(infrastructure.SecurityHeadersOptions{
+		ContentSecurityPolicy: "default-src 'self'",
+	}))
+	w = suite.serve(router, httptest.NewRequest(http.MethodPost, "/echo", nil))
+	suite.Equal("default-src 'self'", w.Header().Get("Content-Security-Policy"))
+	suite.Empty(w.Header().Get("Strict-Transport-Security"))
+}
+
+func (suite *SecurityMiddlewareSuite) TestMaxBodySize() {
+	router := suite.newRouter(infrastructure.MaxBodySizeMiddleware(8))
+
+	w := suite.serve(router, httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader("12345678")))
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Equal("12345678", w.Body.String())
+
+	w = suite.serve(router, httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader("123456789")))
+	suite.Equal(http.StatusRequestEntityTooLarge, w.Code)
+	suite.Equal(infrastructure.ProblemContentType, w.Header().Get("Content-Type"))
+
+	var problem infrastructure.Problem
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &problem))
+	suite.Equal(http.StatusRequestEntityTooLarge, problem.Status)
+	suite.Equal("Request Entity Too Large", problem.Title)
+	suite.Equal("request body must not exceed 8 bytes", problem.Detail)
+
+	// Bodies of unknown length are checked as they are read
+	req := httptest.NewRequest(http.MethodPost, "/echo", io.MultiReader(strings.NewReader("12345"), strings.NewReader("6789")))
+	req.ContentLength = -1
+	w = suite.serve(router, req)
+	suite.Equal(http.StatusRequestEntityTooLarge, w.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/echo", io.MultiReader(strings.NewReader("1234"), strings.NewReader("5678")))
+	req.ContentLength = -1
+	w = suite.serve(router, req)
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Equal("12345678", w.Body.String())
+}
+
+func TestSecurityMiddlewareSuite(t *testing.T) {
+	suite.Run(t, new(SecurityMiddlewareSuite))
+}
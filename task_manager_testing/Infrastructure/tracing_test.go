@@ -0,0 +1,117 @@
+package infrastructure_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	infrastructure "task_manager_testing/Infrastructure"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// TracingSuite tests the span helpers against an in-memory recorder
+type TracingSuite struct {
+	suite.Suite
+	recorder *tracetest.SpanRecorder
+}
+
+func (suite *TracingSuite) SetupTest() {
+	suite.recorder = tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(suite.recorder)))
+}
+
+func (suite *TracingSuite) TestEndSpanRecordsError() {
+	work := func(ctx context.Context) (err error) {
+		_, span := infrastructure.StartSpan(ctx, "work")
+		defer infrastructure.EndSpan(span, &err)
+		return errors.New("boom")
+	}
+
+	suite.Error(work(context.Background()))
+
+	spans := suite.recorder.Ended()
+	suite.Require().Len(spans, 1)
+	suite.Equal("work", spans[0].Name())
+	suite.Equal(codes.Error, spans[0].Status().Code)
+}
+
+func (suite *TracingSuite) TestPasswordHashingIsTraced() {
+	ctx, span := infrastructure.StartSpan(context.Background(), "parent")
+	hashed, err := infrastructure.HashPasswordContext(ctx, "password")
+	suite.Require().NoError(err)
+	suite.NoError(infrastructure.ComparePasswordsContext(ctx, hashed, "password"))
+	span.End()
+
+	spans := suite.recorder.Ended()
+	suite.Require().Len(spans, 3)
+	suite.Equal("bcrypt.GenerateFromPassword", spans[0].Name())
+	suite.Equal("bcrypt.CompareHashAndPassword", spans[1].Name())
+	suite.Equal(spans[2].SpanContext().SpanID(), spans[0].Parent().SpanID())
+}
+
+func (suite *TracingSuite) TestErrorResponseIncludesTraceID() {
+	router := gin.New()
+	router.GET("/fail", func(c *gin.Context) {
+		ctx, span := infrastructure.StartSpan(c.Request.Context(), "request")
+		defer span.End()
+		c.Request = c.Request.WithContext(ctx)
+		c.JSON(http.StatusBadRequest, infrastructure.ErrorResponse(c, "bad request"))
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/fail", nil))
+
+	var body map[string]string
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &body))
+	suite.Equal("bad request", body["error"])
+	suite.Len(body["trace_id"], 32)
+}
+
+func (suite *TracingSuite) TestSecretPathParamsAreRedacted() {
+	var logs bytes.Buffer
+	var token string
+	router := gin.New()
+	router.Use(infrastructure.RedactPathMiddleware("token"), otelgin.Middleware("test"),
+		infrastructure.LoggingMiddleware(slog.New(slog.NewJSONHandler(&logs, nil))))
+	router.GET("/calendar/:token", func(c *gin.Context) {
+		token = c.Param("token")
+		c.Status(http.StatusOK)
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/calendar/s3cret.ics", nil))
+
+	// The handler gets the token, the span and the log only the masked path
+	suite.Equal("s3cret.ics", token)
+	spans := suite.recorder.Ended()
+	suite.Require().Len(spans, 1)
+	for _, attr := range spans[0].Attributes() {
+		suite.NotContains(attr.Value.Emit(), "s3cret", string(attr.Key))
+	}
+	suite.NotContains(logs.String(), "s3cret")
+	suite.Contains(logs.String(), `"path":"/calendar/`+infrastructure.RedactedPathSegment+`"`)
+}
+
+func (suite *TracingSuite) TestInitTracerRejectsUnknownExporter() {
+	_, err := infrastructure.InitTracer(context.Background(), "test", "zipkin")
+	suite.Error(err)
+
+	shutdown, err := infrastructure.InitTracer(context.Background(), "test", "none")
+	suite.NoError(err)
+	suite.NoError(shutdown(context.Background()))
+}
+
+func TestTracingSuite(t *testing.T) {
+	suite.Run(t, new(TracingSuite))
+}
@@ -0,0 +1,140 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"syscall"
+	"task_manager_testing/domain"
+	"time"
+)
+
+// ErrWebhookAddressBlocked is returned when a webhook URL resolves to an
+// address of the server's own networks.
+var ErrWebhookAddressBlocked = errors.New("webhook address is not allowed")
+
+// sharedAddressSpace is the carrier-grade NAT range, private like RFC 1918.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// Headers sent with every webhook delivery.
+const (
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookSignatureHeader = "X-Webhook-Signature"
+)
+
+// webhookUserAgent identifies the task manager to webhook receivers.
+const webhookUserAgent = "task-manager-webhooks/1.0"
+
+// WebhookClient sends signed webhook deliveries over HTTP.
+type WebhookClient struct {
+	client *http.Client
+}
+
+// NewWebhookClient returns a client that gives up on a receiver after timeout.
+// Redirects are not followed, so a receiver must answer at its own URL.
+//
+// Users choose the URLs, so the client refuses to connect to loopback,
+// link-local, private and other non-public addresses unless they are in
+// allowed. The check runs on the address actually dialed, after DNS
+// resolution, so a name cannot be rebound to an internal address.
+func NewWebhookClient(timeout time.Duration, allowed []netip.Prefix) *WebhookClient {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+			if !webhookAddressAllowed(addrPort.Addr(), allowed) {
+				return fmt.Errorf("%w: %s", ErrWebhookAddressBlocked, addrPort.Addr())
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &WebhookClient{
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: transport,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// webhookAddressAllowed reports whether deliveries may be sent to addr: public
+// unicast addresses, and the addresses in allowed.
+func webhookAddressAllowed(addr netip.Addr, allowed []netip.Prefix) bool {
+	addr = addr.Unmap()
+	for _, prefix := range allowed {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return addr.IsGlobalUnicast() && !addr.IsPrivate() && !sharedAddressSpace.Contains(addr)
+}
+
+// SendWebhook posts the payload with its signature and returns the status code.
+func (wc *WebhookClient) SendWebhook(ctx context.Context, request domain.WebhookRequest) (status int, err error) {
+	ctx, span := StartSpan(ctx, "WebhookClient.SendWebhook")
+	defer EndSpan(span, &err)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, request.URL, bytes.NewReader(request.Payload))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", webhookUserAgent)
+	req.Header.Set(WebhookEventHeader, request.Event)
+	req.Header.Set(WebhookDeliveryHeader, request.DeliveryID)
+	req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(WebhookSignatureHeader, SignWebhook(request.Secret, timestamp, request.Payload))
+
+	response, err := wc.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	// Drain a little of the body so the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(response.Body, 64<<10))
+	return response.StatusCode, nil
+}
+
+// SignWebhook returns the signature header value for a payload sent at
+// timestamp: "sha256=" and the hex HMAC-SHA256 of "<timestamp>.<payload>"
+// keyed with the secret. Receivers recompute it and compare in constant time,
+// and reject old timestamps to stop replays.
+func SignWebhook(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// NewWebhookSecret returns a random secret for signing webhook deliveries.
+func NewWebhookSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return "whsec_" + base64.RawURLEncoding.EncodeToString(secret), nil
+}
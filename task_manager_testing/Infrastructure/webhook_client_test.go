@@ -0,0 +1,63 @@
+package infrastructure_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	infrastructure "task_manager_testing/Infrastructure"
+	"task_manager_testing/domain"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// WebhookClientSuite tests which addresses webhook deliveries may reach
+type WebhookClientSuite struct {
+	suite.Suite
+	receiver *httptest.Server
+}
+
+func (suite *WebhookClientSuite) SetupTest() {
+	suite.receiver = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+}
+
+func (suite *WebhookClientSuite) TearDownTest() {
+	suite.receiver.Close()
+}
+
+func (suite *WebhookClientSuite) send(client *infrastructure.WebhookClient, url string) (int, error) {
+	return client.SendWebhook(context.Background(), domain.WebhookRequest{URL: url, Event: domain.EventTaskCreated, Secret: "secret", Payload: []byte("{}")})
+}
+
+// TestInternalAddressesAreBlocked tests that loopback, link-local and private
+// receivers are refused, including names that resolve to them
+func (suite *WebhookClientSuite) TestInternalAddressesAreBlocked() {
+	client := infrastructure.NewWebhookClient(time.Second, nil)
+	for _, url := range []string{
+		suite.receiver.URL,
+		"http://localhost:1/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://10.0.0.1/hook",
+		"http://[::1]:1/hook",
+	} {
+		_, err := suite.send(client, url)
+		suite.ErrorIs(err, infrastructure.ErrWebhookAddressBlocked, url)
+	}
+}
+
+// TestAllowedNetworks tests that configured networks can be reached
+func (suite *WebhookClientSuite) TestAllowedNetworks() {
+	client := infrastructure.NewWebhookClient(time.Second, []netip.Prefix{netip.MustParsePrefix("127.0.0.0/8")})
+	status, err := suite.send(client, suite.receiver.URL)
+	suite.Require().NoError(err)
+	suite.Equal(http.StatusNoContent, status)
+}
+
+func TestWebhookClientSuite(t *testing.T) {
+	suite.Run(t, new(WebhookClientSuite))
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"task_manager_testing/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// AttachmentRepository stores the metadata of attachments; their content is
+// kept in a BlobStore.
+type AttachmentRepository struct {
+	collection *mongo.Collection
+}
+
+func NewAttachmentRepository(client *mongo.Client, dbName, collectionName string) *AttachmentRepository {
+	return &AttachmentRepository{collection: client.Database(dbName).Collection(collectionName)}
+}
+
+// EnsureIndexes indexes the attachments of each task and the uploads of each
+// user, which are summed for the quota.
+func (ar *AttachmentRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := ar.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "task_id", Value: 1}, {Key: "created_at", Value: 1}}},
+		{Keys: bson.D{{Key: "owner_id", Value: 1}, {Key: "size", Value: 1}}},
+	})
+	logResult(ctx, "attachments.create_index", err)
+	return err
+}
+
+// CreateAttachment inserts the attachment first and sums the usage of its
+// owner afterwards, removing it again when the sum is over quota. Every
+// upload that stays has seen itself and all earlier ones in its sum, so
+// concurrent uploads cannot exceed the quota together.
+func (ar *AttachmentRepository) CreateAttachment(ctx context.Context, attachment domain.Attachment, quota int64) error {
+	_, err := ar.collection.InsertOne(ctx, &attachment)
+	logResult(ctx, "attachments.insert", err, slog.String("attachment_id", attachment.ID.Hex()), slog.String("task_id", attachment.TaskID.Hex()))
+	if err != nil {
+		return err
+	}
+
+	usage, err := ar.GetUsage(ctx, attachment.OwnerID)
+	if err == nil && usage <= quota {
+		return nil
+	}
+	if deleteErr := ar.DeleteAttachment(ctx, attachment.ID); deleteErr != nil {
+		return deleteErr
+	}
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("%w: only %d bytes of the quota are left", domain.ErrQuotaExceeded, max(quota-(usage-attachment.Size), 0))
+}
+
+func (ar *AttachmentRepository) GetAttachment(ctx context.Context, id primitive.ObjectID) (domain.Attachment, error) {
+	var attachment domain.Attachment
+	err := ar.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&attachment)
+	logResult(ctx, "attachments.find_one", err, slog.String("attachment_id", id.Hex()))
+	return attachment, err
+}
+
+func (ar *AttachmentRepository) GetAttachments(ctx context.Context, taskID primitive.ObjectID) ([]domain.Attachment, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := ar.collection.Find(ctx, bson.M{"task_id": taskID}, opts)
+	logResult(ctx, "attachments.find", err, slog.String("task_id", taskID.Hex()))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var attachments []domain.Attachment
+	if err := cursor.All(ctx, &attachments); err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+func (ar *AttachmentRepository) DeleteAttachment(ctx context.Context, id primitive.ObjectID) error {
+	_, err := ar.collection.DeleteOne(ctx, bson.M{"_id": id})
+	logResult(ctx, "attachments.delete", err, slog.String("attachment_id", id.Hex()))
+	return err
+}
+
+func (ar *AttachmentRepository) DeleteTaskAttachments(ctx context.Context, taskID primitive.ObjectID) ([]domain.Attachment, error) {
+	attachments, err := ar.GetAttachments(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	_, err = ar.collection.DeleteMany(ctx, bson.M{"task_id": taskID})
+	logResult(ctx, "attachments.delete_many", err, slog.String("task_id", taskID.Hex()), slog.Int("count", len(attachments)))
+	if err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+func (ar *AttachmentRepository) GetUsage(ctx context.Context, ownerID primitive.ObjectID) (int64, error) {
+	cursor, err := ar.collection.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"owner_id": ownerID}}},
+		{{Key: "$group", Value: bson.M{"_id": nil, "total": bson.M{"$sum": "$size"}}}},
+	})
+	logResult(ctx, "attachments.usage", err, slog.String("target_user_id", ownerID.Hex()))
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var usage []struct {
+		Total int64 `bson:"total"`
+	}
+	if err := cursor.All(ctx, &usage); err != nil || len(usage) == 0 {
+		return 0, err
+	}
+	return usage[0].Total, nil
+}
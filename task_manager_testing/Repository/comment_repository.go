@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+	"task_manager_testing/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type CommentRepository struct {
+	collection *mongo.Collection
+}
+
+func NewCommentRepository(client *mongo.Client, dbName, collectionName string) *CommentRepository {
+	return &CommentRepository{collection: client.Database(dbName).Collection(collectionName)}
+}
+
+// EnsureIndexes indexes the discussion of each task and the replies to each
+// comment.
+func (cr *CommentRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := cr.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "task_id", Value: 1}, {Key: "created_at", Value: 1}}},
+		{Keys: bson.D{{Key: "parent_id", Value: 1}}, Options: options.Index().SetSparse(true)},
+	})
+	logResult(ctx, "comments.create_index", err)
+	return err
+}
+
+func (cr *CommentRepository) CreateComment(ctx context.Context, comment domain.Comment) error {
+	_, err := cr.collection.InsertOne(ctx, &comment)
+	logResult(ctx, "comments.insert", err, slog.String("comment_id", comment.ID.Hex()), slog.String("task_id", comment.TaskID.Hex()))
+	return err
+}
+
+func (cr *CommentRepository) GetComment(ctx context.Context, id primitive.ObjectID) (domain.Comment, error) {
+	var comment domain.Comment
+	err := cr.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&comment)
+	logResult(ctx, "comments.find_one", err, slog.String("comment_id", id.Hex()))
+	return comment, err
+}
+
+func (cr *CommentRepository) GetComments(ctx context.Context, taskID primitive.ObjectID) ([]domain.Comment, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := cr.collection.Find(ctx, bson.M{"task_id": taskID}, opts)
+	logResult(ctx, "comments.find", err, slog.String("task_id", taskID.Hex()))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var comments []domain.Comment
+	if err := cursor.All(ctx, &comments); err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+func (cr *CommentRepository) UpdateComment(ctx context.Context, comment domain.Comment) error {
+	_, err := cr.collection.ReplaceOne(ctx, bson.M{"_id": comment.ID}, &comment)
+	logResult(ctx, "comments.replace", err, slog.String("comment_id", comment.ID.Hex()))
+	return err
+}
+
+func (cr *CommentRepository) DeleteComment(ctx context.Context, id primitive.ObjectID) error {
+	_, err := cr.collection.DeleteOne(ctx, bson.M{"_id": id})
+	logResult(ctx, "comments.delete", err, slog.String("comment_id", id.Hex()))
+	return err
+}
+
+func (cr *CommentRepository) DeleteTaskComments(ctx context.Context, taskID primitive.ObjectID) error {
+	result, err := cr.collection.DeleteMany(ctx, bson.M{"task_id": taskID})
+	var deleted int64
+	if err == nil {
+		deleted = result.DeletedCount
+	}
+	logResult(ctx, "comments.delete_many", err, slog.String("task_id", taskID.Hex()), slog.Int64("count", deleted))
+	return err
+}
+
+func (cr *CommentRepository) CountReplies(ctx context.Context, id primitive.ObjectID) (int64, error) {
+	count, err := cr.collection.CountDocuments(ctx, bson.M{"parent_id": id})
+	logResult(ctx, "comments.count_replies", err, slog.String("comment_id", id.Hex()))
+	return count, err
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"task_manager_testing/domain"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GridFSBlobStore is a BlobStore keeping blobs in a MongoDB GridFS bucket,
+// using the key as the file ID.
+type GridFSBlobStore struct {
+	bucket *gridfs.Bucket
+}
+
+func NewGridFSBlobStore(client *mongo.Client, dbName, bucketName string) (*GridFSBlobStore, error) {
+	bucket, err := gridfs.NewBucket(client.Database(dbName), options.GridFSBucket().SetName(bucketName))
+	if err != nil {
+		return nil, err
+	}
+	return &GridFSBlobStore{bucket: bucket}, nil
+}
+
+func (gs *GridFSBlobStore) Put(ctx context.Context, key string, content io.Reader) (err error) {
+	defer func() { logResult(ctx, "blobs.gridfs_put", err, slog.String("blob_key", key)) }()
+
+	stream, err := gs.bucket.OpenUploadStreamWithID(key, key)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := stream.SetWriteDeadline(deadline); err != nil {
+			stream.Abort()
+			return err
+		}
+	}
+
+	if _, err := io.Copy(stream, content); err != nil {
+		// Abort removes the chunks written so far
+		stream.Abort()
+		return err
+	}
+	return stream.Close()
+}
+
+func (gs *GridFSBlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	stream, err := gs.bucket.OpenDownloadStream(key)
+	if errors.Is(err, gridfs.ErrFileNotFound) {
+		err = domain.ErrBlobNotFound
+	}
+	logResult(ctx, "blobs.gridfs_get", err, slog.String("blob_key", key))
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := stream.SetReadDeadline(deadline); err != nil {
+			stream.Close()
+			return nil, err
+		}
+	}
+	return stream, nil
+}
+
+func (gs *GridFSBlobStore) Delete(ctx context.Context, key string) error {
+	err := gs.bucket.DeleteContext(ctx, key)
+	if errors.Is(err, gridfs.ErrFileNotFound) {
+		err = nil
+	}
+	logResult(ctx, "blobs.gridfs_delete", err, slog.String("blob_key", key))
+	return err
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"task_manager_testing/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type IdempotencyRepository struct {
+	collection *mongo.Collection
+}
+
+func NewIdempotencyRepository(client *mongo.Client, dbName, collectionName string) *IdempotencyRepository {
+	collection := client.Database(dbName).Collection(collectionName)
+	return &IdempotencyRepository{collection: collection}
+}
+
+// EnsureIndexes lets MongoDB delete records once they expire.
+func (ir *IdempotencyRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := ir.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+	logResult(ctx, "idempotency.create_index", err)
+	return err
+}
+
+// Reserve inserts record, or returns the unexpired record stored under its key.
+func (ir *IdempotencyRepository) Reserve(ctx context.Context, record domain.IdempotencyRecord) (domain.IdempotencyRecord, bool, error) {
+	// MongoDB removes expired documents about once a minute, so remove a
+	// leftover expired record with this key before inserting
+	_, err := ir.collection.DeleteOne(ctx, bson.M{"_id": record.Key, "expires_at": bson.M{"$lte": time.Now()}})
+	logResult(ctx, "idempotency.delete_expired", err)
+	if err != nil {
+		return domain.IdempotencyRecord{}, false, err
+	}
+
+	_, err = ir.collection.InsertOne(ctx, &record)
+	logResult(ctx, "idempotency.insert", err)
+	if err == nil {
+		return domain.IdempotencyRecord{}, true, nil
+	}
+	if !mongo.IsDuplicateKeyError(err) {
+		return domain.IdempotencyRecord{}, false, err
+	}
+
+	var existing domain.IdempotencyRecord
+	err = ir.collection.FindOne(ctx, bson.M{"_id": record.Key}).Decode(&existing)
+	logResult(ctx, "idempotency.find_one", err)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		// The record expired in between, so try again
+		return ir.Reserve(ctx, record)
+	}
+	if err != nil {
+		return domain.IdempotencyRecord{}, false, err
+	}
+	return existing, false, nil
+}
+
+// Complete stores the final response of the request.
+func (ir *IdempotencyRepository) Complete(ctx context.Context, record domain.IdempotencyRecord) error {
+	_, err := ir.collection.ReplaceOne(ctx, bson.M{"_id": record.Key}, &record)
+	logResult(ctx, "idempotency.replace", err, slog.Int("status_code", record.StatusCode))
+	return err
+}
+
+// Release deletes a pending record.
+func (ir *IdempotencyRepository) Release(ctx context.Context, key string) error {
+	_, err := ir.collection.DeleteOne(ctx, bson.M{"_id": key, "completed": false})
+	logResult(ctx, "idempotency.delete", err)
+	return err
+}
@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	infrastructure "task_manager_testing/Infrastructure"
+	"task_manager_testing/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// InstrumentedTaskRepository decorates a TaskRepository with Prometheus metrics
+// and an OpenTelemetry span per call.
+type InstrumentedTaskRepository struct {
+	next    domain.TaskRepository
+	metrics *infrastructure.Metrics
+}
+
+func NewInstrumentedTaskRepository(next domain.TaskRepository, metrics *infrastructure.Metrics) *InstrumentedTaskRepository {
+	return &InstrumentedTaskRepository{next: next, metrics: metrics}
+}
+
+func (ir *InstrumentedTaskRepository) begin(ctx context.Context, operation string) (context.Context, func(error)) {
+	return beginOperation(ctx, ir.metrics, "tasks", "TaskRepository", operation)
+}
+
+func (ir *InstrumentedTaskRepository) AddTask(ctx context.Context, task domain.Task) error {
+	ctx, done := ir.begin(ctx, "AddTask")
+	err := ir.next.AddTask(ctx, task)
+	done(err)
+	return err
+}
+
+func (ir *InstrumentedTaskRepository) GetTaskById(ctx context.Context, id primitive.ObjectID) (domain.Task, error) {
+	ctx, done := ir.begin(ctx, "GetTaskById")
+	task, err := ir.next.GetTaskById(ctx, id)
+	done(err)
+	return task, err
+}
+
+func (ir *InstrumentedTaskRepository) GetAllTasks(ctx context.Context) ([]domain.Task, error) {
+	ctx, done := ir.begin(ctx, "GetAllTasks")
+	tasks, err := ir.next.GetAllTasks(ctx)
+	done(err)
+	return tasks, err
+}
+
+func (ir *InstrumentedTaskRepository) GetMyTasks(ctx context.Context, userId primitive.ObjectID) ([]domain.Task, error) {
+	ctx, done := ir.begin(ctx, "GetMyTasks")
+	tasks, err := ir.next.GetMyTasks(ctx, userId)
+	done(err)
+	return tasks, err
+}
+
+func (ir *InstrumentedTaskRepository) UpdateFullTask(ctx context.Context, id primitive.ObjectID, task domain.Task) error {
+	ctx, done := ir.begin(ctx, "UpdateFullTask")
+	err := ir.next.UpdateFullTask(ctx, id, task)
+	done(err)
+	return err
+}
+
+func (ir *InstrumentedTaskRepository) UpdateSomeTask(ctx context.Context, id primitive.ObjectID, task map[string]interface{}) error {
+	ctx, done := ir.begin(ctx, "UpdateSomeTask")
+	err := ir.next.UpdateSomeTask(ctx, id, task)
+	done(err)
+	return err
+}
+
+func (ir *InstrumentedTaskRepository) DeleteTask(ctx context.Context, id primitive.ObjectID) error {
+	ctx, done := ir.begin(ctx, "DeleteTask")
+	err := ir.next.DeleteTask(ctx, id)
+	done(err)
+	return err
+}
+
+func (ir *InstrumentedTaskRepository) CountTasksByStatus(ctx context.Context) (map[string]int64, error) {
+	ctx, done := ir.begin(ctx, "CountTasksByStatus")
+	counts, err := ir.next.CountTasksByStatus(ctx)
+	done(err)
+	return counts, err
+}
+
+func (ir *InstrumentedTaskRepository) StreamTasks(ctx context.Context, createdBy primitive.ObjectID, fn func(domain.Task) error) error {
+	ctx, done := ir.begin(ctx, "StreamTasks")
+	err := ir.next.StreamTasks(ctx, createdBy, fn)
+	done(err)
+	return err
+}
+
+func (ir *InstrumentedTaskRepository) GetTasksDueBefore(ctx context.Context, before time.Time) ([]domain.Task, error) {
+	ctx, done := ir.begin(ctx, "GetTasksDueBefore")
+	tasks, err := ir.next.GetTasksDueBefore(ctx, before)
+	done(err)
+	return tasks, err
+}
+
+func (ir *InstrumentedTaskRepository) FindTasks(ctx context.Context, filter domain.TaskFilter) ([]domain.Task, error) {
+	ctx, done := ir.begin(ctx, "FindTasks")
+	tasks, err := ir.next.FindTasks(ctx, filter)
+	done(err)
+	return tasks, err
+}
+
+func (ir *InstrumentedTaskRepository) RemoveLabel(ctx context.Context, labelID primitive.ObjectID) error {
+	ctx, done := ir.begin(ctx, "RemoveLabel")
+	err := ir.next.RemoveLabel(ctx, labelID)
+	done(err)
+	return err
+}
+
+// InstrumentedUserRepository decorates a UserRepository with Prometheus metrics
+// and an OpenTelemetry span per call, including the login success and failure counters.
+type InstrumentedUserRepository struct {
+	next    domain.UserRepository
+	metrics *infrastructure.Metrics
+}
+
+func NewInstrumentedUserRepository(next domain.UserRepository, metrics *infrastructure.Metrics) *InstrumentedUserRepository {
+	return &InstrumentedUserRepository{next: next, metrics: metrics}
+}
+
+func (ir *InstrumentedUserRepository) begin(ctx context.Context, operation string) (context.Context, func(error)) {
+	return beginOperation(ctx, ir.metrics, "users", "UserRepository", operation)
+}
+
+func (ir *InstrumentedUserRepository) RegisterUser(ctx context.Context, username, password, role string) error {
+	ctx, done := ir.begin(ctx, "RegisterUser")
+	err := ir.next.RegisterUser(ctx, username, password, role)
+	done(err)
+	return err
+}
+
+func (ir *InstrumentedUserRepository) Login(ctx context.Context, username, password string) (domain.User, error) {
+	ctx, done := ir.begin(ctx, "Login")
+	user, err := ir.next.Login(ctx, username, password)
+	done(err)
+	ir.metrics.RecordLogin(err == nil)
+	return user, err
+}
+
+func (ir *InstrumentedUserRepository) UpdateUser(ctx context.Context, id primitive.ObjectID, user domain.User) error {
+	ctx, done := ir.begin(ctx, "UpdateUser")
+	err := ir.next.UpdateUser(ctx, id, user)
+	done(err)
+	return err
+}
+
+func (ir *InstrumentedUserRepository) GetUserById(ctx context.Context, id primitive.ObjectID) (domain.User, error) {
+	ctx, done := ir.begin(ctx, "GetUserById")
+	user, err := ir.next.GetUserById(ctx, id)
+	done(err)
+	return user, err
+}
+
+func (ir *InstrumentedUserRepository) DeleteUser(ctx context.Context, id primitive.ObjectID) error {
+	ctx, done := ir.begin(ctx, "DeleteUser")
+	err := ir.next.DeleteUser(ctx, id)
+	done(err)
+	return err
+}
+
+func (ir *InstrumentedUserRepository) GetAllUsers(ctx context.Context) ([]domain.User, error) {
+	ctx, done := ir.begin(ctx, "GetAllUsers")
+	users, err := ir.next.GetAllUsers(ctx)
+	done(err)
+	return users, err
+}
+
+func (ir *InstrumentedUserRepository) GetUserByCalendarToken(ctx context.Context, tokenHash string) (domain.User, error) {
+	ctx, done := ir.begin(ctx, "GetUserByCalendarToken")
+	user, err := ir.next.GetUserByCalendarToken(ctx, tokenHash)
+	done(err)
+	return user, err
+}
+
+func (ir *InstrumentedUserRepository) GetUserByUsername(ctx context.Context, username string) (domain.User, error) {
+	ctx, done := ir.begin(ctx, "GetUserByUsername")
+	user, err := ir.next.GetUserByUsername(ctx, username)
+	done(err)
+	return user, err
+}
+
+// beginOperation starts a span for a repository call and returns the function
+// that ends it and records the call's latency and outcome.
+func beginOperation(ctx context.Context, metrics *infrastructure.Metrics, repository, spanPrefix, operation string) (context.Context, func(error)) {
+	start := time.Now()
+	ctx, span := infrastructure.StartSpan(ctx, spanPrefix+"."+operation)
+	span.SetAttributes(attribute.String("db.system", "mongodb"), attribute.String("db.mongodb.collection", repository))
+
+	return ctx, func(err error) {
+		failed := isFailure(err)
+		if failed {
+			infrastructure.EndSpan(span, &err)
+		} else {
+			span.End()
+		}
+		metrics.ObserveRepositoryOperation(repository, operation, start, failed)
+	}
+}
+
+// isFailure reports whether err is a genuine repository error. Missing documents
+// and wrong passwords are normal outcomes and are not counted as errors.
+func isFailure(err error) bool {
+	return err != nil &&
+		!errors.Is(err, mongo.ErrNoDocuments) &&
+		!errors.Is(err, bcrypt.ErrMismatchedHashAndPassword)
+}
@@ -0,0 +1,82 @@
+package repository_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	infrastructure "task_manager_testing/Infrastructure"
+	repository "task_manager_testing/Repository"
+	"task_manager_testing/domain"
+	"task_manager_testing/mocks"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// InstrumentedRepositorySuite tests the metrics decorators around the repositories
+type InstrumentedRepositorySuite struct {
+	suite.Suite
+	metrics  *infrastructure.Metrics
+	taskRepo *mocks.TaskRepository
+	userRepo *mocks.UserRepository
+}
+
+func (suite *InstrumentedRepositorySuite) SetupTest() {
+	suite.metrics = infrastructure.NewMetrics()
+	suite.taskRepo = &mocks.TaskRepository{}
+	suite.userRepo = &mocks.UserRepository{}
+}
+
+func (suite *InstrumentedRepositorySuite) TestTaskOperationsAreCounted() {
+	id := primitive.NewObjectID()
+	suite.taskRepo.On("GetTaskById", mock.Anything, id).Return(domain.Task{}, mongo.ErrNoDocuments)
+	suite.taskRepo.On("DeleteTask", mock.Anything, id).Return(fmt.Errorf("connection reset"))
+
+	repo := repository.NewInstrumentedTaskRepository(suite.taskRepo, suite.metrics)
+	_, err := repo.GetTaskById(context.Background(), id)
+	suite.ErrorIs(err, mongo.ErrNoDocuments)
+	err = repo.DeleteTask(context.Background(), id)
+	suite.Error(err)
+
+	suite.Equal(2, testutil.CollectAndCount(suite.metrics.RepositoryOperationDuration))
+	// A missing document is not a repository failure
+	suite.Equal(float64(0), testutil.ToFloat64(suite.metrics.RepositoryErrors.WithLabelValues("tasks", "GetTaskById")))
+	suite.Equal(float64(1), testutil.ToFloat64(suite.metrics.RepositoryErrors.WithLabelValues("tasks", "DeleteTask")))
+	suite.taskRepo.AssertExpectations(suite.T())
+}
+
+func (suite *InstrumentedRepositorySuite) TestLoginAttemptsAreCounted() {
+	suite.userRepo.On("Login", mock.Anything, "tester1", "password").Return(domain.User{Username: "tester1"}, nil)
+	suite.userRepo.On("Login", mock.Anything, "tester1", "wrong").Return(domain.User{}, bcrypt.ErrMismatchedHashAndPassword)
+
+	repo := repository.NewInstrumentedUserRepository(suite.userRepo, suite.metrics)
+	_, err := repo.Login(context.Background(), "tester1", "password")
+	suite.NoError(err)
+	_, err = repo.Login(context.Background(), "tester1", "wrong")
+	suite.Error(err)
+	_, err = repo.Login(context.Background(), "tester1", "wrong")
+	suite.Error(err)
+
+	suite.Equal(float64(1), testutil.ToFloat64(suite.metrics.LoginAttempts.WithLabelValues("success")))
+	suite.Equal(float64(2), testutil.ToFloat64(suite.metrics.LoginAttempts.WithLabelValues("failure")))
+	suite.Equal(float64(0), testutil.ToFloat64(suite.metrics.RepositoryErrors.WithLabelValues("users", "Login")))
+	suite.userRepo.AssertExpectations(suite.T())
+}
+
+func (suite *InstrumentedRepositorySuite) TestTaskCounter() {
+	suite.taskRepo.On("CountTasksByStatus", mock.Anything).Return(map[string]int64{"Completed": 3, "In Progress": 1}, nil)
+	suite.metrics.RegisterTaskCounter(suite.taskRepo.CountTasksByStatus)
+
+	count, err := testutil.GatherAndCount(suite.metrics.Registry(), "task_manager_tasks")
+	suite.NoError(err)
+	suite.Equal(2, count)
+}
+
+func TestInstrumentedRepositorySuite(t *testing.T) {
+	suite.Run(t, new(InstrumentedRepositorySuite))
+}
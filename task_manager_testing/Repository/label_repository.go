@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"task_manager_testing/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type LabelRepository struct {
+	collection *mongo.Collection
+}
+
+func NewLabelRepository(client *mongo.Client, dbName, collectionName string) *LabelRepository {
+	return &LabelRepository{collection: client.Database(dbName).Collection(collectionName)}
+}
+
+// EnsureIndexes keeps label names unique per organization and owner, global
+// labels having a null owner, which also indexes the per-user listing. The
+// index that kept global label names unique across organizations is dropped.
+func (lr *LabelRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := lr.collection.Indexes().DropOne(ctx, "owner_id_1_name_key_1")
+	if err != nil && !isIndexNotFound(err) {
+		logResult(ctx, "labels.drop_index", err)
+		return err
+	}
+	_, err = lr.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "org_id", Value: 1}, {Key: "owner_id", Value: 1}, {Key: "name_key", Value: 1}}, Options: options.Index().SetUnique(true)},
+	})
+	logResult(ctx, "labels.create_index", err)
+	return err
+}
+
+func (lr *LabelRepository) CreateLabel(ctx context.Context, label domain.Label) error {
+	label.OrgID = domain.ScopedOrg(ctx, label.OrgID)
+	_, err := lr.collection.InsertOne(ctx, &label)
+	if mongo.IsDuplicateKeyError(err) {
+		logResult(ctx, "labels.insert", nil, slog.Bool("duplicate", true))
+		return domain.ErrLabelExists
+	}
+	logResult(ctx, "labels.insert", err, slog.String("label_id", label.ID.Hex()))
+	return err
+}
+
+func (lr *LabelRepository) GetLabel(ctx context.Context, id primitive.ObjectID) (domain.Label, error) {
+	var label domain.Label
+	err := lr.collection.FindOne(ctx, orgScoped(ctx, bson.M{"_id": id})).Decode(&label)
+	logResult(ctx, "labels.find_one", err, slog.String("label_id", id.Hex()))
+	return label, err
+}
+
+func (lr *LabelRepository) GetLabels(ctx context.Context, ownerID primitive.ObjectID) ([]domain.Label, error) {
+	filter := orgScoped(ctx, bson.M{"$or": bson.A{bson.M{"owner_id": nil}, bson.M{"owner_id": ownerID}}})
+	opts := options.Find().SetSort(bson.D{{Key: "name_key", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := lr.collection.Find(ctx, filter, opts)
+	logResult(ctx, "labels.find", err, slog.String("owner_id", ownerID.Hex()))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var labels []domain.Label
+	if err := cursor.All(ctx, &labels); err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
+
+func (lr *LabelRepository) UpdateLabel(ctx context.Context, label domain.Label) error {
+	label.OrgID = domain.ScopedOrg(ctx, label.OrgID)
+	_, err := lr.collection.ReplaceOne(ctx, orgScoped(ctx, bson.M{"_id": label.ID}), &label)
+	if mongo.IsDuplicateKeyError(err) {
+		logResult(ctx, "labels.replace", nil, slog.Bool("duplicate", true))
+		return domain.ErrLabelExists
+	}
+	logResult(ctx, "labels.replace", err, slog.String("label_id", label.ID.Hex()))
+	return err
+}
+
+func (lr *LabelRepository) DeleteLabel(ctx context.Context, id primitive.ObjectID) error {
+	_, err := lr.collection.DeleteOne(ctx, orgScoped(ctx, bson.M{"_id": id}))
+	logResult(ctx, "labels.delete", err, slog.String("label_id", id.Hex()))
+	return err
+}
+
+// isIndexNotFound reports whether err is the error of dropping an index, or
+// an index of a collection, that does not exist.
+func isIndexNotFound(err error) bool {
+	var cmdErr mongo.CommandError
+	return errors.As(err, &cmdErr) && (cmdErr.Code == 27 || cmdErr.Code == 26)
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"task_manager_testing/domain"
+)
+
+// LocalBlobStore is a BlobStore keeping each blob in a file of one directory.
+type LocalBlobStore struct {
+	dir string
+}
+
+// NewLocalBlobStore creates dir if needed and stores blobs in it.
+func NewLocalBlobStore(dir string) (*LocalBlobStore, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, err
+	}
+	return &LocalBlobStore{dir: dir}, nil
+}
+
+// Put writes content to a temporary file first, so that a failed upload never
+// leaves a partial blob under key.
+func (ls *LocalBlobStore) Put(ctx context.Context, key string, content io.Reader) (err error) {
+	path, err := ls.path(key)
+	if err != nil {
+		return err
+	}
+	defer func() { logResult(ctx, "blobs.local_put", err, slog.String("blob_key", key)) }()
+
+	file, err := os.CreateTemp(ls.dir, ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := io.Copy(file, content); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
+
+func (ls *LocalBlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := ls.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		err = domain.ErrBlobNotFound
+	}
+	logResult(ctx, "blobs.local_get", err, slog.String("blob_key", key))
+	if err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+func (ls *LocalBlobStore) Delete(ctx context.Context, key string) error {
+	path, err := ls.path(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		err = nil
+	}
+	logResult(ctx, "blobs.local_delete", err, slog.String("blob_key", key))
+	return err
+}
+
+// path returns the file of key, refusing keys that could leave the directory.
+func (ls *LocalBlobStore) path(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, ".") || strings.ContainsAny(key, `/\`) {
+		return "", fmt.Errorf("invalid blob key %q", key)
+	}
+	return filepath.Join(ls.dir, key), nil
+}
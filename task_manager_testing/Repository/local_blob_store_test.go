@@ -0,0 +1,76 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	repository "task_manager_testing/Repository"
+	"task_manager_testing/domain"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// LocalBlobStoreSuite tests the filesystem blob store in a temporary directory
+type LocalBlobStoreSuite struct {
+	suite.Suite
+	dir   string
+	store *repository.LocalBlobStore
+}
+
+func (suite *LocalBlobStoreSuite) SetupTest() {
+	suite.dir = filepath.Join(suite.T().TempDir(), "blobs")
+	store, err := repository.NewLocalBlobStore(suite.dir)
+	suite.Require().NoError(err)
+	suite.store = store
+}
+
+func (suite *LocalBlobStoreSuite) TestPutGetDelete() {
+	ctx := context.Background()
+	suite.Require().NoError(suite.store.Put(ctx, "abc", strings.NewReader("hello")))
+
+	blob, err := suite.store.Get(ctx, "abc")
+	suite.Require().NoError(err)
+	content, err := io.ReadAll(blob)
+	blob.Close()
+	suite.Require().NoError(err)
+	suite.Equal("hello", string(content))
+
+	suite.Require().NoError(suite.store.Delete(ctx, "abc"))
+	_, err = suite.store.Get(ctx, "abc")
+	suite.ErrorIs(err, domain.ErrBlobNotFound)
+	// Deleting a missing blob is not an error
+	suite.NoError(suite.store.Delete(ctx, "abc"))
+}
+
+func (suite *LocalBlobStoreSuite) TestFailedPutLeavesNothing() {
+	err := suite.store.Put(context.Background(), "abc", io.MultiReader(strings.NewReader("partial"), failingReader{}))
+	suite.Error(err)
+
+	_, err = suite.store.Get(context.Background(), "abc")
+	suite.ErrorIs(err, domain.ErrBlobNotFound)
+	entries, err := os.ReadDir(suite.dir)
+	suite.Require().NoError(err)
+	suite.Empty(entries)
+}
+
+func (suite *LocalBlobStoreSuite) TestKeysCannotLeaveTheDirectory() {
+	for _, key := range []string{"", "../escape", "a/b", `a\b`, ".hidden"} {
+		suite.Error(suite.store.Put(context.Background(), key, strings.NewReader("x")), key)
+	}
+}
+
+// failingReader fails every read
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
+func TestLocalBlobStoreSuite(t *testing.T) {
+	suite.Run(t, new(LocalBlobStoreSuite))
+}
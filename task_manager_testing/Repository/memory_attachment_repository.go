@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"task_manager_testing/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// InMemoryAttachmentRepository is an AttachmentRepository kept in memory, used
+// to run the application without MongoDB in tests.
+type InMemoryAttachmentRepository struct {
+	mu          sync.Mutex
+	attachments []domain.Attachment
+}
+
+func NewInMemoryAttachmentRepository() *InMemoryAttachmentRepository {
+	return &InMemoryAttachmentRepository{}
+}
+
+func (mr *InMemoryAttachmentRepository) CreateAttachment(ctx context.Context, attachment domain.Attachment, quota int64) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	usage := mr.usage(attachment.OwnerID)
+	if usage+attachment.Size > quota {
+		return fmt.Errorf("%w: only %d bytes of the quota are left", domain.ErrQuotaExceeded, max(quota-usage, 0))
+	}
+	mr.attachments = append(mr.attachments, attachment)
+	return nil
+}
+
+func (mr *InMemoryAttachmentRepository) GetAttachment(ctx context.Context, id primitive.ObjectID) (domain.Attachment, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	for _, attachment := range mr.attachments {
+		if attachment.ID == id {
+			return attachment, nil
+		}
+	}
+	return domain.Attachment{}, mongo.ErrNoDocuments
+}
+
+func (mr *InMemoryAttachmentRepository) GetAttachments(ctx context.Context, taskID primitive.ObjectID) ([]domain.Attachment, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	var attachments []domain.Attachment
+	for _, attachment := range mr.attachments {
+		if attachment.TaskID == taskID {
+			attachments = append(attachments, attachment)
+		}
+	}
+	return attachments, nil
+}
+
+func (mr *InMemoryAttachmentRepository) DeleteAttachment(ctx context.Context, id primitive.ObjectID) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	attachments := mr.attachments[:0]
+	for _, attachment := range mr.attachments {
+		if attachment.ID != id {
+			attachments = append(attachments, attachment)
+		}
+	}
+	mr.attachments = attachments
+	return nil
+}
+
+func (mr *InMemoryAttachmentRepository) DeleteTaskAttachments(ctx context.Context, taskID primitive.ObjectID) ([]domain.Attachment, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	var deleted []domain.Attachment
+	attachments := mr.attachments[:0]
+	for _, attachment := range mr.attachments {
+		if attachment.TaskID == taskID {
+			deleted = append(deleted, attachment)
+		} else {
+			attachments = append(attachments, attachment)
+		}
+	}
+	mr.attachments = attachments
+	return deleted, nil
+}
+
+func (mr *InMemoryAttachmentRepository) GetUsage(ctx context.Context, ownerID primitive.ObjectID) (int64, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	return mr.usage(ownerID), nil
+}
+
+// usage sums the sizes of the attachments of ownerID; mu must be held.
+func (mr *InMemoryAttachmentRepository) usage(ownerID primitive.ObjectID) int64 {
+	var usage int64
+	for _, attachment := range mr.attachments {
+		if attachment.OwnerID == ownerID {
+			usage += attachment.Size
+		}
+	}
+	return usage
+}
+
+// Snapshot saves the stored attachments and returns a function restoring them.
+func (mr *InMemoryAttachmentRepository) Snapshot() func() {
+	mr.mu.Lock()
+	attachments := append([]domain.Attachment(nil), mr.attachments...)
+	mr.mu.Unlock()
+
+	return func() {
+		mr.mu.Lock()
+		defer mr.mu.Unlock()
+		mr.attachments = attachments
+	}
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+	"task_manager_testing/domain"
+)
+
+// InMemoryBlobStore is a BlobStore kept in memory, used to run the
+// application without MongoDB in tests.
+type InMemoryBlobStore struct {
+	mu    sync.Mutex
+	blobs map[string][]byte
+}
+
+func NewInMemoryBlobStore() *InMemoryBlobStore {
+	return &InMemoryBlobStore{blobs: make(map[string][]byte)}
+}
+
+func (ms *InMemoryBlobStore) Put(ctx context.Context, key string, content io.Reader) error {
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return err
+	}
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	ms.blobs[key] = data
+	return nil
+}
+
+func (ms *InMemoryBlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	data, exists := ms.blobs[key]
+	if !exists {
+		return nil, domain.ErrBlobNotFound
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (ms *InMemoryBlobStore) Delete(ctx context.Context, key string) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	delete(ms.blobs, key)
+	return nil
+}
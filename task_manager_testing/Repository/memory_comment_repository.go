@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"task_manager_testing/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// InMemoryCommentRepository is a CommentRepository kept in memory, used to run
+// the application without MongoDB in tests.
+type InMemoryCommentRepository struct {
+	mu       sync.Mutex
+	comments []domain.Comment
+}
+
+func NewInMemoryCommentRepository() *InMemoryCommentRepository {
+	return &InMemoryCommentRepository{}
+}
+
+func (mr *InMemoryCommentRepository) CreateComment(ctx context.Context, comment domain.Comment) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	mr.comments = append(mr.comments, comment)
+	return nil
+}
+
+func (mr *InMemoryCommentRepository) GetComment(ctx context.Context, id primitive.ObjectID) (domain.Comment, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	for _, comment := range mr.comments {
+		if comment.ID == id {
+			return comment, nil
+		}
+	}
+	return domain.Comment{}, mongo.ErrNoDocuments
+}
+
+func (mr *InMemoryCommentRepository) GetComments(ctx context.Context, taskID primitive.ObjectID) ([]domain.Comment, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	// Comments are appended as they are created, so they are oldest first
+	var comments []domain.Comment
+	for _, comment := range mr.comments {
+		if comment.TaskID == taskID {
+			comments = append(comments, comment)
+		}
+	}
+	return comments, nil
+}
+
+func (mr *InMemoryCommentRepository) UpdateComment(ctx context.Context, comment domain.Comment) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	for i, existing := range mr.comments {
+		if existing.ID == comment.ID {
+			mr.comments[i] = comment
+		}
+	}
+	return nil
+}
+
+func (mr *InMemoryCommentRepository) DeleteComment(ctx context.Context, id primitive.ObjectID) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	comments := mr.comments[:0]
+	for _, comment := range mr.comments {
+		if comment.ID != id {
+			comments = append(comments, comment)
+		}
+	}
+	mr.comments = comments
+	return nil
+}
+
+func (mr *InMemoryCommentRepository) DeleteTaskComments(ctx context.Context, taskID primitive.ObjectID) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	comments := mr.comments[:0]
+	for _, comment := range mr.comments {
+		if comment.TaskID != taskID {
+			comments = append(comments, comment)
+		}
+	}
+	mr.comments = comments
+	return nil
+}
+
+func (mr *InMemoryCommentRepository) CountReplies(ctx context.Context, id primitive.ObjectID) (int64, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	var count int64
+	for _, comment := range mr.comments {
+		if comment.ParentID != nil && *comment.ParentID == id {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Snapshot saves the stored comments and returns a function restoring them.
+func (mr *InMemoryCommentRepository) Snapshot() func() {
+	mr.mu.Lock()
+	comments := append([]domain.Comment(nil), mr.comments...)
+	mr.mu.Unlock()
+
+	return func() {
+		mr.mu.Lock()
+		defer mr.mu.Unlock()
+		mr.comments = comments
+	}
+}
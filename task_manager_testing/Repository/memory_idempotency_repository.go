@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"task_manager_testing/domain"
+	"time"
+)
+
+// InMemoryIdempotencyRepository is an IdempotencyRepository kept in memory.
+type InMemoryIdempotencyRepository struct {
+	mu      sync.Mutex
+	records map[string]domain.IdempotencyRecord
+}
+
+func NewInMemoryIdempotencyRepository() *InMemoryIdempotencyRepository {
+	return &InMemoryIdempotencyRepository{records: make(map[string]domain.IdempotencyRecord)}
+}
+
+func (mr *InMemoryIdempotencyRepository) Reserve(ctx context.Context, record domain.IdempotencyRecord) (domain.IdempotencyRecord, bool, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	now := time.Now()
+	for key, existing := range mr.records {
+		if !existing.ExpiresAt.After(now) {
+			delete(mr.records, key)
+		}
+	}
+
+	if existing, exists := mr.records[record.Key]; exists {
+		return existing, false, nil
+	}
+	mr.records[record.Key] = record
+	return domain.IdempotencyRecord{}, true, nil
+}
+
+func (mr *InMemoryIdempotencyRepository) Complete(ctx context.Context, record domain.IdempotencyRecord) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	mr.records[record.Key] = record
+	return nil
+}
+
+func (mr *InMemoryIdempotencyRepository) Release(ctx context.Context, key string) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	if existing, exists := mr.records[key]; exists && !existing.Completed {
+		delete(mr.records, key)
+	}
+	return nil
+}
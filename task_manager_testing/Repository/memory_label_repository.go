@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"task_manager_testing/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// InMemoryLabelRepository is a LabelRepository kept in memory, used to run the
+// application without MongoDB in tests.
+type InMemoryLabelRepository struct {
+	mu     sync.Mutex
+	labels []domain.Label
+}
+
+func NewInMemoryLabelRepository() *InMemoryLabelRepository {
+	return &InMemoryLabelRepository{}
+}
+
+func (mr *InMemoryLabelRepository) CreateLabel(ctx context.Context, label domain.Label) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	label.OrgID = domain.ScopedOrg(ctx, label.OrgID)
+	if mr.nameTaken(label) {
+		return domain.ErrLabelExists
+	}
+	mr.labels = append(mr.labels, label)
+	return nil
+}
+
+func (mr *InMemoryLabelRepository) GetLabel(ctx context.Context, id primitive.ObjectID) (domain.Label, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	for _, label := range mr.labels {
+		if label.ID == id && inOrgScope(ctx, label.OrgID) {
+			return label, nil
+		}
+	}
+	return domain.Label{}, mongo.ErrNoDocuments
+}
+
+func (mr *InMemoryLabelRepository) GetLabels(ctx context.Context, ownerID primitive.ObjectID) ([]domain.Label, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	var labels []domain.Label
+	for _, label := range mr.labels {
+		if (label.OwnerID == nil || *label.OwnerID == ownerID) && inOrgScope(ctx, label.OrgID) {
+			labels = append(labels, label)
+		}
+	}
+	sort.SliceStable(labels, func(i, j int) bool {
+		return labels[i].NameKey < labels[j].NameKey
+	})
+	return labels, nil
+}
+
+func (mr *InMemoryLabelRepository) UpdateLabel(ctx context.Context, label domain.Label) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	label.OrgID = domain.ScopedOrg(ctx, label.OrgID)
+	if mr.nameTaken(label) {
+		return domain.ErrLabelExists
+	}
+	for i, existing := range mr.labels {
+		if existing.ID == label.ID && inOrgScope(ctx, existing.OrgID) {
+			mr.labels[i] = label
+		}
+	}
+	return nil
+}
+
+func (mr *InMemoryLabelRepository) DeleteLabel(ctx context.Context, id primitive.ObjectID) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	labels := mr.labels[:0]
+	for _, label := range mr.labels {
+		if label.ID != id || !inOrgScope(ctx, label.OrgID) {
+			labels = append(labels, label)
+		}
+	}
+	mr.labels = labels
+	return nil
+}
+
+// nameTaken reports whether another label of the same organization and owner
+// has the name of label, like the unique index of the MongoDB repository.
+func (mr *InMemoryLabelRepository) nameTaken(label domain.Label) bool {
+	for _, existing := range mr.labels {
+		if existing.ID != label.ID && existing.NameKey == label.NameKey && sameOwner(existing.OwnerID, label.OwnerID) &&
+			sameOwner(existing.OrgID, label.OrgID) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameOwner reports whether two optional owners or organizations are equal.
+func sameOwner(a, b *primitive.ObjectID) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"task_manager_testing/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// InMemoryNotificationRepository is a NotificationRepository kept in memory,
+// used to run the application without MongoDB in tests.
+type InMemoryNotificationRepository struct {
+	mu            sync.Mutex
+	notifications []domain.Notification
+	keys          map[string]bool
+}
+
+func NewInMemoryNotificationRepository() *InMemoryNotificationRepository {
+	return &InMemoryNotificationRepository{keys: make(map[string]bool)}
+}
+
+func (mr *InMemoryNotificationRepository) CreateNotification(ctx context.Context, notification domain.Notification) (bool, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	if mr.keys[notification.Key] {
+		return false, nil
+	}
+	mr.keys[notification.Key] = true
+	mr.notifications = append(mr.notifications, notification)
+	return true, nil
+}
+
+func (mr *InMemoryNotificationRepository) GetNotifications(ctx context.Context, userID primitive.ObjectID, unreadOnly bool, limit int) ([]domain.Notification, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	// Newest first, like the MongoDB repository
+	var notifications []domain.Notification
+	for i := len(mr.notifications) - 1; i >= 0; i-- {
+		if notification := mr.notifications[i]; notification.UserID == userID && (!unreadOnly || notification.ReadAt == 0) {
+			notifications = append(notifications, notification)
+		}
+	}
+	sort.SliceStable(notifications, func(i, j int) bool {
+		return notifications[i].CreatedAt > notifications[j].CreatedAt
+	})
+	if len(notifications) > limit {
+		notifications = notifications[:limit]
+	}
+	return notifications, nil
+}
+
+func (mr *InMemoryNotificationRepository) CountUnread(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	var count int64
+	for _, notification := range mr.notifications {
+		if notification.UserID == userID && notification.ReadAt == 0 {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (mr *InMemoryNotificationRepository) MarkRead(ctx context.Context, userID, id primitive.ObjectID, readAt time.Time) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	for i, notification := range mr.notifications {
+		if notification.ID == id && notification.UserID == userID {
+			if notification.ReadAt == 0 {
+				mr.notifications[i].ReadAt = primitive.NewDateTimeFromTime(readAt)
+			}
+			return nil
+		}
+	}
+	return mongo.ErrNoDocuments
+}
+
+func (mr *InMemoryNotificationRepository) MarkAllRead(ctx context.Context, userID primitive.ObjectID, readAt time.Time) (int64, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	var updated int64
+	for i, notification := range mr.notifications {
+		if notification.UserID == userID && notification.ReadAt == 0 {
+			mr.notifications[i].ReadAt = primitive.NewDateTimeFromTime(readAt)
+			updated++
+		}
+	}
+	return updated, nil
+}
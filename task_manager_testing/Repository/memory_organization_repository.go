@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"task_manager_testing/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// InMemoryOrganizationRepository is an OrganizationRepository kept in memory,
+// used to run the application without MongoDB in tests.
+type InMemoryOrganizationRepository struct {
+	mu   sync.Mutex
+	orgs []domain.Organization
+}
+
+func NewInMemoryOrganizationRepository() *InMemoryOrganizationRepository {
+	return &InMemoryOrganizationRepository{}
+}
+
+func (mr *InMemoryOrganizationRepository) AddOrganization(ctx context.Context, org domain.Organization) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	for _, existing := range mr.orgs {
+		if existing.NameKey == org.NameKey {
+			return domain.ErrOrganizationExists
+		}
+	}
+	mr.orgs = append(mr.orgs, org)
+	return nil
+}
+
+func (mr *InMemoryOrganizationRepository) GetOrganizationById(ctx context.Context, id primitive.ObjectID) (domain.Organization, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	for _, org := range mr.orgs {
+		if org.ID == id {
+			return org, nil
+		}
+	}
+	return domain.Organization{}, mongo.ErrNoDocuments
+}
+
+func (mr *InMemoryOrganizationRepository) GetAllOrganizations(ctx context.Context) ([]domain.Organization, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	orgs := append([]domain.Organization(nil), mr.orgs...)
+	sort.SliceStable(orgs, func(i, j int) bool { return orgs[i].NameKey < orgs[j].NameKey })
+	return orgs, nil
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"task_manager_testing/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// InMemoryOutboxRepository is an OutboxRepository kept in memory, used to run
+// the application without MongoDB in tests. Pass it to NewInMemoryTransactor
+// so its events are rolled back together with the tasks.
+type InMemoryOutboxRepository struct {
+	mu       sync.Mutex
+	messages []domain.OutboxMessage
+}
+
+func NewInMemoryOutboxRepository() *InMemoryOutboxRepository {
+	return &InMemoryOutboxRepository{}
+}
+
+func (mr *InMemoryOutboxRepository) AddEvents(ctx context.Context, events []domain.TaskEvent) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	now := primitive.NewDateTimeFromTime(time.Now())
+	for _, event := range events {
+		mr.messages = append(mr.messages, domain.OutboxMessage{
+			ID:            primitive.NewObjectID(),
+			Event:         event,
+			DeliveredTo:   []string{},
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		})
+	}
+	return nil
+}
+
+func (mr *InMemoryOutboxRepository) ClaimMessage(ctx context.Context, now time.Time, lease time.Duration) (domain.OutboxMessage, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	// Messages are kept in insertion order, so the first due one is the oldest
+	due := -1
+	for i, message := range mr.messages {
+		if message.DeadLetteredAt != nil || message.NextAttemptAt.Time().After(now) {
+			continue
+		}
+		if due < 0 || message.NextAttemptAt < mr.messages[due].NextAttemptAt {
+			due = i
+		}
+	}
+	if due < 0 {
+		return domain.OutboxMessage{}, mongo.ErrNoDocuments
+	}
+
+	claimed := mr.messages[due]
+	claimed.DeliveredTo = append([]string{}, claimed.DeliveredTo...)
+	mr.messages[due].NextAttemptAt = primitive.NewDateTimeFromTime(now.Add(lease))
+	return claimed, nil
+}
+
+func (mr *InMemoryOutboxRepository) RetryMessage(ctx context.Context, message domain.OutboxMessage) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	for i, existing := range mr.messages {
+		if existing.ID == message.ID {
+			mr.messages[i] = message
+		}
+	}
+	return nil
+}
+
+func (mr *InMemoryOutboxRepository) DeadLetterMessage(ctx context.Context, message domain.OutboxMessage) error {
+	return mr.RetryMessage(ctx, message)
+}
+
+func (mr *InMemoryOutboxRepository) DeleteMessage(ctx context.Context, id primitive.ObjectID) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	messages := mr.messages[:0]
+	for _, message := range mr.messages {
+		if message.ID != id {
+			messages = append(messages, message)
+		}
+	}
+	mr.messages = messages
+	return nil
+}
+
+// Snapshot saves the stored messages and returns a function restoring them.
+func (mr *InMemoryOutboxRepository) Snapshot() func() {
+	mr.mu.Lock()
+	messages := append([]domain.OutboxMessage(nil), mr.messages...)
+	mr.mu.Unlock()
+
+	return func() {
+		mr.mu.Lock()
+		defer mr.mu.Unlock()
+		mr.messages = messages
+	}
+}
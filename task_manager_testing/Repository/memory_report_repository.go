@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"task_manager_testing/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// InMemoryReportRepository is a ReportRepository kept in memory, used to run
+// the application without MongoDB in tests. It builds the same reports as
+// ReportRepository, reading the tasks from an InMemoryTaskRepository.
+type InMemoryReportRepository struct {
+	mu      sync.Mutex
+	tasks   *InMemoryTaskRepository
+	changes []domain.StatusChange
+}
+
+func NewInMemoryReportRepository(tasks *InMemoryTaskRepository) *InMemoryReportRepository {
+	return &InMemoryReportRepository{tasks: tasks}
+}
+
+func (mr *InMemoryReportRepository) AddStatusChange(ctx context.Context, change domain.StatusChange) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	change.OrgID = domain.ScopedOrg(ctx, change.OrgID)
+	mr.changes = append(mr.changes, change)
+	return nil
+}
+
+func (mr *InMemoryReportRepository) Throughput(ctx context.Context, query domain.ReportQuery) ([]domain.PeriodCount, error) {
+	from, to := primitive.NewDateTimeFromTime(query.From), primitive.NewDateTimeFromTime(query.To)
+	buckets := make(map[time.Time]*domain.PeriodCount)
+	for _, change := range mr.matching(ctx, query.CreatedBy) {
+		if change.At < from || change.At >= to {
+			continue
+		}
+		start := domain.PeriodStart(change.At.Time(), query.Period)
+		count, ok := buckets[start]
+		if !ok {
+			count = &domain.PeriodCount{Start: start}
+			buckets[start] = count
+		}
+		if change.From == "" {
+			count.Created++
+		}
+		if change.To == domain.TaskCompleted {
+			count.Completed++
+		}
+	}
+
+	var counts []domain.PeriodCount
+	for _, count := range buckets {
+		counts = append(counts, *count)
+	}
+	sort.Slice(counts, func(i, j int) bool { return counts[i].Start.Before(counts[j].Start) })
+	return counts, nil
+}
+
+func (mr *InMemoryReportRepository) CycleTimes(ctx context.Context, query domain.ReportQuery) ([]domain.TaskCycleTime, error) {
+	cycles := make(map[primitive.ObjectID]*domain.TaskCycleTime)
+	for _, change := range mr.matching(ctx, query.CreatedBy) {
+		if change.To != domain.TaskInProgress && change.To != domain.TaskCompleted {
+			continue
+		}
+		cycle, ok := cycles[change.TaskID]
+		if !ok {
+			cycle = &domain.TaskCycleTime{TaskID: change.TaskID}
+			cycles[change.TaskID] = cycle
+		}
+		at := change.At.Time().UTC()
+		if change.To == domain.TaskInProgress && (cycle.StartedAt.IsZero() || at.Before(cycle.StartedAt)) {
+			cycle.StartedAt = at
+		}
+		if change.To == domain.TaskCompleted && at.After(cycle.CompletedAt) {
+			cycle.CompletedAt = at
+		}
+	}
+
+	var cycleTimes []domain.TaskCycleTime
+	for _, cycle := range cycles {
+		if cycle.StartedAt.IsZero() || cycle.CompletedAt.Before(query.From) || !cycle.CompletedAt.Before(query.To) || cycle.CompletedAt.Before(cycle.StartedAt) {
+			continue
+		}
+		cycle.Hours = float64(cycle.CompletedAt.Sub(cycle.StartedAt)/time.Millisecond) / float64(time.Hour/time.Millisecond)
+		cycleTimes = append(cycleTimes, *cycle)
+	}
+	sort.Slice(cycleTimes, func(i, j int) bool {
+		if !cycleTimes[i].CompletedAt.Equal(cycleTimes[j].CompletedAt) {
+			return cycleTimes[i].CompletedAt.Before(cycleTimes[j].CompletedAt)
+		}
+		return cycleTimes[i].TaskID.Hex() < cycleTimes[j].TaskID.Hex()
+	})
+	return cycleTimes, nil
+}
+
+func (mr *InMemoryReportRepository) OverdueByUser(ctx context.Context, createdBy primitive.ObjectID, now time.Time) ([]domain.UserOverdue, error) {
+	tasks, err := mr.tasks.GetTasksDueBefore(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[primitive.ObjectID]int)
+	for _, task := range tasks {
+		if createdBy.IsZero() || task.CreatedBy == createdBy {
+			counts[task.CreatedBy]++
+		}
+	}
+
+	var overdue []domain.UserOverdue
+	for userID, count := range counts {
+		overdue = append(overdue, domain.UserOverdue{UserID: userID, Overdue: count})
+	}
+	sort.Slice(overdue, func(i, j int) bool {
+		if overdue[i].Overdue != overdue[j].Overdue {
+			return overdue[i].Overdue > overdue[j].Overdue
+		}
+		return overdue[i].UserID.Hex() < overdue[j].UserID.Hex()
+	})
+	return overdue, nil
+}
+
+func (mr *InMemoryReportRepository) OpenTasks(ctx context.Context, createdBy primitive.ObjectID, ends []time.Time) ([]int, error) {
+	// The changes are kept in the order they were made
+	byTask := make(map[primitive.ObjectID][]domain.StatusChange)
+	for _, change := range mr.matching(ctx, createdBy) {
+		byTask[change.TaskID] = append(byTask[change.TaskID], change)
+	}
+
+	open := make([]int, len(ends))
+	for _, changes := range byTask {
+		for i, end := range ends {
+			status := ""
+			for _, change := range changes {
+				if !change.At.Time().Before(end) {
+					break
+				}
+				status = change.To
+			}
+			if status != "" && status != domain.TaskCompleted {
+				open[i]++
+			}
+		}
+	}
+	return open, nil
+}
+
+// Snapshot saves the stored status changes and returns a function restoring them.
+func (mr *InMemoryReportRepository) Snapshot() func() {
+	mr.mu.Lock()
+	changes := append([]domain.StatusChange(nil), mr.changes...)
+	mr.mu.Unlock()
+
+	return func() {
+		mr.mu.Lock()
+		defer mr.mu.Unlock()
+		mr.changes = changes
+	}
+}
+
+// matching returns a copy of the status changes of the tasks of createdBy, or
+// of every task of the organization of ctx when it is primitive.NilObjectID.
+func (mr *InMemoryReportRepository) matching(ctx context.Context, createdBy primitive.ObjectID) []domain.StatusChange {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	var changes []domain.StatusChange
+	for _, change := range mr.changes {
+		if inOrgScope(ctx, change.OrgID) && (createdBy.IsZero() || change.CreatedBy == createdBy) {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
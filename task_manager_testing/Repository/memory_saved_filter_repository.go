@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"task_manager_testing/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// InMemorySavedFilterRepository is a SavedFilterRepository kept in memory,
+// used to run the application without MongoDB in tests.
+type InMemorySavedFilterRepository struct {
+	mu      sync.Mutex
+	filters []domain.SavedFilter
+}
+
+func NewInMemorySavedFilterRepository() *InMemorySavedFilterRepository {
+	return &InMemorySavedFilterRepository{}
+}
+
+func (mr *InMemorySavedFilterRepository) CreateFilter(ctx context.Context, filter domain.SavedFilter) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	filter.OrgID = domain.ScopedOrg(ctx, filter.OrgID)
+	for _, existing := range mr.filters {
+		if existing.UserID == filter.UserID && existing.NameKey == filter.NameKey {
+			return domain.ErrFilterExists
+		}
+	}
+	mr.filters = append(mr.filters, filter)
+	return nil
+}
+
+func (mr *InMemorySavedFilterRepository) GetFilter(ctx context.Context, id primitive.ObjectID) (domain.SavedFilter, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	for _, filter := range mr.filters {
+		if filter.ID == id && inOrgScope(ctx, filter.OrgID) {
+			return filter, nil
+		}
+	}
+	return domain.SavedFilter{}, mongo.ErrNoDocuments
+}
+
+func (mr *InMemorySavedFilterRepository) GetFilters(ctx context.Context, userID primitive.ObjectID) ([]domain.SavedFilter, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	var filters []domain.SavedFilter
+	for _, filter := range mr.filters {
+		if filter.UserID == userID && inOrgScope(ctx, filter.OrgID) {
+			filters = append(filters, filter)
+		}
+	}
+	sort.SliceStable(filters, func(i, j int) bool {
+		return filters[i].NameKey < filters[j].NameKey
+	})
+	return filters, nil
+}
+
+func (mr *InMemorySavedFilterRepository) DeleteFilter(ctx context.Context, id primitive.ObjectID) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	filters := mr.filters[:0]
+	for _, filter := range mr.filters {
+		if filter.ID != id || !inOrgScope(ctx, filter.OrgID) {
+			filters = append(filters, filter)
+		}
+	}
+	mr.filters = filters
+	return nil
+}
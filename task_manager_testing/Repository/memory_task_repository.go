@@ -0,0 +1,275 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"task_manager_testing/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// InMemoryTaskRepository is a TaskRepository kept in memory, used to run the
+// application without MongoDB in tests.
+type InMemoryTaskRepository struct {
+	mu    sync.RWMutex
+	tasks map[primitive.ObjectID]domain.Task
+	order []primitive.ObjectID
+}
+
+func NewInMemoryTaskRepository() *InMemoryTaskRepository {
+	return &InMemoryTaskRepository{tasks: make(map[primitive.ObjectID]domain.Task)}
+}
+
+func (mr *InMemoryTaskRepository) AddTask(ctx context.Context, task domain.Task) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	if _, exists := mr.tasks[task.ID]; exists {
+		return mongo.WriteException{WriteErrors: mongo.WriteErrors{{Code: 11000, Message: "duplicate key"}}}
+	}
+	task.OrgID = domain.ScopedOrg(ctx, task.OrgID)
+	if task.Rank == "" {
+		last := ""
+		for _, existing := range mr.tasks {
+			if existing.CreatedBy == task.CreatedBy && existing.Rank > last {
+				last = existing.Rank
+			}
+		}
+		task.Rank = domain.RankBetween(last, "")
+	}
+	if mr.rankTaken(task) {
+		return domain.ErrRankTaken
+	}
+	task.UpdatedAt = primitive.NewDateTimeFromTime(time.Now())
+	mr.tasks[task.ID] = task
+	mr.order = append(mr.order, task.ID)
+	return nil
+}
+
+func (mr *InMemoryTaskRepository) GetTaskById(ctx context.Context, id primitive.ObjectID) (domain.Task, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	task, exists := mr.tasks[id]
+	if !exists || !inOrgScope(ctx, task.OrgID) {
+		return domain.Task{}, mongo.ErrNoDocuments
+	}
+	return task, nil
+}
+
+func (mr *InMemoryTaskRepository) GetAllTasks(ctx context.Context) ([]domain.Task, error) {
+	return mr.filter(ctx, func(domain.Task) bool { return true }), nil
+}
+
+func (mr *InMemoryTaskRepository) GetMyTasks(ctx context.Context, userId primitive.ObjectID) ([]domain.Task, error) {
+	return mr.filter(ctx, func(task domain.Task) bool { return task.CreatedBy == userId }), nil
+}
+
+func (mr *InMemoryTaskRepository) UpdateFullTask(ctx context.Context, id primitive.ObjectID, task domain.Task) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	if existing, exists := mr.tasks[id]; !exists || !inOrgScope(ctx, existing.OrgID) {
+		return nil
+	}
+	task.ID = id
+	task.OrgID = domain.ScopedOrg(ctx, task.OrgID)
+	if mr.rankTaken(task) {
+		return domain.ErrRankTaken
+	}
+	task.UpdatedAt = primitive.NewDateTimeFromTime(time.Now())
+	mr.tasks[id] = task
+	return nil
+}
+
+// UpdateSomeTask applies the fields of update the same way a Mongo $set would,
+// by round-tripping the task through BSON.
+func (mr *InMemoryTaskRepository) UpdateSomeTask(ctx context.Context, id primitive.ObjectID, update map[string]interface{}) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	task, exists := mr.tasks[id]
+	if !exists || !inOrgScope(ctx, task.OrgID) {
+		return nil
+	}
+
+	raw, err := bson.Marshal(task)
+	if err != nil {
+		return err
+	}
+	var doc bson.M
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		return err
+	}
+	for field, value := range update {
+		doc[field] = value
+	}
+	doc["updated_at"] = primitive.NewDateTimeFromTime(time.Now())
+	raw, err = bson.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	var updated domain.Task
+	if err := bson.Unmarshal(raw, &updated); err != nil {
+		return err
+	}
+	if mr.rankTaken(updated) {
+		return domain.ErrRankTaken
+	}
+	mr.tasks[id] = updated
+	return nil
+}
+
+// rankTaken reports whether another task of the creator of task has its rank,
+// which the MongoDB index rejects. mr.mu must be held.
+func (mr *InMemoryTaskRepository) rankTaken(task domain.Task) bool {
+	if task.Rank == "" {
+		return false
+	}
+	for _, existing := range mr.tasks {
+		if existing.ID != task.ID && existing.CreatedBy == task.CreatedBy && existing.Rank == task.Rank {
+			return true
+		}
+	}
+	return false
+}
+
+func (mr *InMemoryTaskRepository) DeleteTask(ctx context.Context, id primitive.ObjectID) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	if task, exists := mr.tasks[id]; !exists || !inOrgScope(ctx, task.OrgID) {
+		return nil
+	}
+	delete(mr.tasks, id)
+	for i, existing := range mr.order {
+		if existing == id {
+			mr.order = append(mr.order[:i], mr.order[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (mr *InMemoryTaskRepository) CountTasksByStatus(ctx context.Context) (map[string]int64, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	counts := make(map[string]int64)
+	for _, task := range mr.tasks {
+		if inOrgScope(ctx, task.OrgID) {
+			counts[task.Status]++
+		}
+	}
+	return counts, nil
+}
+
+func (mr *InMemoryTaskRepository) StreamTasks(ctx context.Context, createdBy primitive.ObjectID, fn func(domain.Task) error) error {
+	tasks := mr.filter(ctx, func(task domain.Task) bool { return createdBy.IsZero() || task.CreatedBy == createdBy })
+	for _, task := range tasks {
+		if err := fn(task); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (mr *InMemoryTaskRepository) GetTasksDueBefore(ctx context.Context, before time.Time) ([]domain.Task, error) {
+	return mr.filter(ctx, func(task domain.Task) bool {
+		return task.DueDate != 0 && task.Status != domain.TaskCompleted && task.DueDate.Time().Before(before)
+	}), nil
+}
+
+func (mr *InMemoryTaskRepository) FindTasks(ctx context.Context, filter domain.TaskFilter) ([]domain.Task, error) {
+	return mr.filter(ctx, func(task domain.Task) bool {
+		if !filter.CreatedBy.IsZero() && task.CreatedBy != filter.CreatedBy {
+			return false
+		}
+		if filter.Status != "" && task.Status != filter.Status {
+			return false
+		}
+		if !filter.SeriesID.IsZero() && (task.SeriesID == nil || *task.SeriesID != filter.SeriesID) {
+			return false
+		}
+		if len(filter.Labels) == 0 {
+			return true
+		}
+		matched := 0
+		for _, label := range filter.Labels {
+			if hasLabel(task, label) {
+				matched++
+			}
+		}
+		if filter.LabelMatch == domain.LabelMatchAny {
+			return matched > 0
+		}
+		return matched == len(filter.Labels)
+	}), nil
+}
+
+func (mr *InMemoryTaskRepository) RemoveLabel(ctx context.Context, labelID primitive.ObjectID) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	for id, task := range mr.tasks {
+		if !hasLabel(task, labelID) || !inOrgScope(ctx, task.OrgID) {
+			continue
+		}
+		labels := make([]primitive.ObjectID, 0, len(task.Labels)-1)
+		for _, label := range task.Labels {
+			if label != labelID {
+				labels = append(labels, label)
+			}
+		}
+		task.Labels = labels
+		task.UpdatedAt = primitive.NewDateTimeFromTime(time.Now())
+		mr.tasks[id] = task
+	}
+	return nil
+}
+
+// hasLabel reports whether task carries label.
+func hasLabel(task domain.Task, label primitive.ObjectID) bool {
+	for _, existing := range task.Labels {
+		if existing == label {
+			return true
+		}
+	}
+	return false
+}
+
+// filter returns the tasks visible in ctx matching keep in insertion order.
+func (mr *InMemoryTaskRepository) filter(ctx context.Context, keep func(domain.Task) bool) []domain.Task {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	var tasks []domain.Task
+	for _, id := range mr.order {
+		if task := mr.tasks[id]; inOrgScope(ctx, task.OrgID) && keep(task) {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks
+}
+
+// Snapshot saves the stored tasks and returns a function restoring them.
+func (mr *InMemoryTaskRepository) Snapshot() func() {
+	mr.mu.RLock()
+	tasks := make(map[primitive.ObjectID]domain.Task, len(mr.tasks))
+	for id, task := range mr.tasks {
+		tasks[id] = task
+	}
+	order := append([]primitive.ObjectID(nil), mr.order...)
+	mr.mu.RUnlock()
+
+	return func() {
+		mr.mu.Lock()
+		defer mr.mu.Unlock()
+		mr.tasks = tasks
+		mr.order = order
+	}
+}
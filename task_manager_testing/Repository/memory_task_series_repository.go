@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"task_manager_testing/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// InMemoryTaskSeriesRepository is a TaskSeriesRepository kept in memory, used
+// to run the application without MongoDB in tests.
+type InMemoryTaskSeriesRepository struct {
+	mu     sync.Mutex
+	series []domain.TaskSeries
+}
+
+func NewInMemoryTaskSeriesRepository() *InMemoryTaskSeriesRepository {
+	return &InMemoryTaskSeriesRepository{}
+}
+
+func (mr *InMemoryTaskSeriesRepository) CreateSeries(ctx context.Context, series domain.TaskSeries) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	if mr.find(series.ID) >= 0 {
+		return mongo.WriteException{WriteErrors: mongo.WriteErrors{{Code: 11000, Message: "duplicate key"}}}
+	}
+	series.OrgID = domain.ScopedOrg(ctx, series.OrgID)
+	mr.series = append(mr.series, series)
+	return nil
+}
+
+func (mr *InMemoryTaskSeriesRepository) GetSeries(ctx context.Context, id primitive.ObjectID) (domain.TaskSeries, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	i := mr.find(id)
+	if i < 0 {
+		return domain.TaskSeries{}, mongo.ErrNoDocuments
+	}
+	return mr.series[i], nil
+}
+
+// UpdateSeries keeps the stored occurrence count, like the Mongo repository.
+func (mr *InMemoryTaskSeriesRepository) UpdateSeries(ctx context.Context, series domain.TaskSeries) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	if i := mr.find(series.ID); i >= 0 {
+		series.CreatedBy = mr.series[i].CreatedBy
+		series.Occurrences = mr.series[i].Occurrences
+		series.OrgID = mr.series[i].OrgID
+		mr.series[i] = series
+	}
+	return nil
+}
+
+func (mr *InMemoryTaskSeriesRepository) AdvanceSeries(ctx context.Context, id primitive.ObjectID, from int, latestDue primitive.DateTime) (bool, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	i := mr.find(id)
+	if i < 0 || mr.series[i].Occurrences != from || mr.series[i].Ended {
+		return false, nil
+	}
+	mr.series[i].Occurrences = from + 1
+	mr.series[i].LatestDue = latestDue
+	return true, nil
+}
+
+func (mr *InMemoryTaskSeriesRepository) EndSeries(ctx context.Context, id primitive.ObjectID) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	if i := mr.find(id); i >= 0 {
+		mr.series[i].Ended = true
+	}
+	return nil
+}
+
+func (mr *InMemoryTaskSeriesRepository) GetDueSeries(ctx context.Context, before time.Time) ([]domain.TaskSeries, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	var due []domain.TaskSeries
+	for _, series := range mr.series {
+		if !series.Ended && series.LatestDue.Time().Before(before) {
+			due = append(due, series)
+		}
+	}
+	return due, nil
+}
+
+// find returns the index of the series id, or -1.
+func (mr *InMemoryTaskSeriesRepository) find(id primitive.ObjectID) int {
+	for i, series := range mr.series {
+		if series.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// Snapshot saves the stored series and returns a function restoring them.
+func (mr *InMemoryTaskSeriesRepository) Snapshot() func() {
+	mr.mu.Lock()
+	series := append([]domain.TaskSeries(nil), mr.series...)
+	mr.mu.Unlock()
+
+	return func() {
+		mr.mu.Lock()
+		defer mr.mu.Unlock()
+		mr.series = series
+	}
+}
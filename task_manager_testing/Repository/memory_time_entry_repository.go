@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"task_manager_testing/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// InMemoryTimeEntryRepository is a TimeEntryRepository kept in memory, used
+// to run the application without MongoDB in tests.
+type InMemoryTimeEntryRepository struct {
+	mu      sync.Mutex
+	entries []domain.TimeEntry
+}
+
+func NewInMemoryTimeEntryRepository() *InMemoryTimeEntryRepository {
+	return &InMemoryTimeEntryRepository{}
+}
+
+func (mr *InMemoryTimeEntryRepository) StartEntry(ctx context.Context, entry domain.TimeEntry) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	for _, existing := range mr.entries {
+		if existing.UserID == entry.UserID && existing.Running {
+			return domain.ErrTimerRunning
+		}
+	}
+	entry.OrgID = domain.ScopedOrg(ctx, entry.OrgID)
+	mr.entries = append(mr.entries, entry)
+	return nil
+}
+
+func (mr *InMemoryTimeEntryRepository) StopEntry(ctx context.Context, userID, taskID primitive.ObjectID, stoppedAt time.Time) (domain.TimeEntry, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	for i, entry := range mr.entries {
+		if entry.UserID == userID && entry.TaskID == taskID && entry.Running {
+			stopTimeEntry(&mr.entries[i], stoppedAt)
+			return mr.entries[i], nil
+		}
+	}
+	return domain.TimeEntry{}, domain.ErrTimerNotRunning
+}
+
+func (mr *InMemoryTimeEntryRepository) GetEntries(ctx context.Context, taskID primitive.ObjectID) ([]domain.TimeEntry, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	var entries []domain.TimeEntry
+	for _, entry := range mr.entries {
+		if entry.TaskID == taskID {
+			entries = append(entries, entry)
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].StartedAt < entries[j].StartedAt })
+	return entries, nil
+}
+
+func (mr *InMemoryTimeEntryRepository) TimeByUser(ctx context.Context, query domain.TimeQuery) ([]domain.UserTime, error) {
+	seconds := make(map[primitive.ObjectID]int64)
+	for _, entry := range mr.matching(ctx, query) {
+		seconds[entry.UserID] += entry.Seconds
+	}
+
+	totals := make([]domain.UserTime, 0, len(seconds))
+	for userID, total := range seconds {
+		totals = append(totals, domain.UserTime{UserID: userID, Seconds: total})
+	}
+	sort.Slice(totals, func(i, j int) bool {
+		if totals[i].Seconds != totals[j].Seconds {
+			return totals[i].Seconds > totals[j].Seconds
+		}
+		return totals[i].UserID.Hex() < totals[j].UserID.Hex()
+	})
+	return totals, nil
+}
+
+func (mr *InMemoryTimeEntryRepository) TimeByWeek(ctx context.Context, query domain.TimeQuery) ([]domain.WeekTime, error) {
+	var weeks []domain.WeekTime
+	index := make(map[[2]int]int)
+	for _, entry := range mr.matching(ctx, query) {
+		year, week := entry.StartedAt.Time().UTC().ISOWeek()
+		i, ok := index[[2]int{year, week}]
+		if !ok {
+			i = len(weeks)
+			index[[2]int{year, week}] = i
+			weeks = append(weeks, domain.WeekTime{Year: year, Week: week})
+		}
+		weeks[i].Seconds += entry.Seconds
+	}
+	sort.Slice(weeks, func(i, j int) bool {
+		if weeks[i].Year != weeks[j].Year {
+			return weeks[i].Year < weeks[j].Year
+		}
+		return weeks[i].Week < weeks[j].Week
+	})
+	return weeks, nil
+}
+
+// matching returns the stopped entries matching query in the organization of
+// ctx.
+func (mr *InMemoryTimeEntryRepository) matching(ctx context.Context, query domain.TimeQuery) []domain.TimeEntry {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	var entries []domain.TimeEntry
+	for _, entry := range mr.entries {
+		started := entry.StartedAt.Time()
+		switch {
+		case entry.Running, !inOrgScope(ctx, entry.OrgID):
+		case !query.UserID.IsZero() && entry.UserID != query.UserID:
+		case !query.From.IsZero() && started.Before(query.From):
+		case !query.To.IsZero() && !started.Before(query.To):
+		default:
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"sync"
+)
+
+// Snapshotter is an in-memory repository whose state can be saved and restored.
+type Snapshotter interface {
+	// Snapshot saves the current state and returns a function restoring it.
+	Snapshot() (restore func())
+}
+
+// InMemoryTransactor gives in-memory repositories transaction semantics by
+// restoring a snapshot when the function fails. Transactions run one at a
+// time, but writes made outside of them are not isolated and are lost when a
+// transaction rolls back.
+type InMemoryTransactor struct {
+	mu           sync.Mutex
+	repositories []Snapshotter
+}
+
+func NewInMemoryTransactor(repositories ...Snapshotter) *InMemoryTransactor {
+	return &InMemoryTransactor{repositories: repositories}
+}
+
+func (mt *InMemoryTransactor) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	mt.mu.Lock()
+	defer mt.mu.Unlock()
+
+	restores := make([]func(), len(mt.repositories))
+	for i, repository := range mt.repositories {
+		restores[i] = repository.Snapshot()
+	}
+
+	if err := fn(ctx); err != nil {
+		for _, restore := range restores {
+			restore()
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	infrastructure "task_manager_testing/Infrastructure"
+	"task_manager_testing/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// InMemoryUserRepository is a UserRepository kept in memory, used to run the
+// application without MongoDB in tests.
+type InMemoryUserRepository struct {
+	mu    sync.RWMutex
+	users map[primitive.ObjectID]domain.User
+	order []primitive.ObjectID
+}
+
+func NewInMemoryUserRepository() *InMemoryUserRepository {
+	return &InMemoryUserRepository{users: make(map[primitive.ObjectID]domain.User)}
+}
+
+func (mr *InMemoryUserRepository) RegisterUser(ctx context.Context, username, password, role string) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	if mr.usernameTaken(primitive.NilObjectID, username) {
+		return domain.ErrUserExists
+	}
+	id := primitive.NewObjectID()
+	mr.users[id] = domain.User{ID: id, Username: username, Password: password, Role: role, OrgID: domain.ScopedOrg(ctx, nil)}
+	mr.order = append(mr.order, id)
+	return nil
+}
+
+func (mr *InMemoryUserRepository) Login(ctx context.Context, username, password string) (domain.User, error) {
+	user, found := mr.findByUsername(ctx, username)
+	if !found {
+		return domain.User{}, mongo.ErrNoDocuments
+	}
+
+	if err := infrastructure.ComparePasswordsContext(ctx, user.Password, password); err != nil {
+		return domain.User{}, err
+	}
+	return user, nil
+}
+
+func (mr *InMemoryUserRepository) UpdateUser(ctx context.Context, id primitive.ObjectID, user domain.User) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	if existing, exists := mr.users[id]; !exists || !inOrgScope(ctx, existing.OrgID) {
+		return nil
+	}
+	if mr.usernameTaken(id, user.Username) {
+		return domain.ErrUserExists
+	}
+	user.OrgID = domain.ScopedOrg(ctx, user.OrgID)
+	mr.users[id] = user
+	return nil
+}
+
+// usernameTaken reports whether a user other than id has username in any
+// organization, like the unique index of the MongoDB repository.
+func (mr *InMemoryUserRepository) usernameTaken(id primitive.ObjectID, username string) bool {
+	for _, user := range mr.users {
+		if user.ID != id && user.Username == username {
+			return true
+		}
+	}
+	return false
+}
+
+func (mr *InMemoryUserRepository) GetUserById(ctx context.Context, id primitive.ObjectID) (domain.User, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	user, exists := mr.users[id]
+	if !exists || !inOrgScope(ctx, user.OrgID) {
+		return domain.User{}, mongo.ErrNoDocuments
+	}
+	return user, nil
+}
+
+func (mr *InMemoryUserRepository) DeleteUser(ctx context.Context, id primitive.ObjectID) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	if user, exists := mr.users[id]; !exists || !inOrgScope(ctx, user.OrgID) {
+		return nil
+	}
+	delete(mr.users, id)
+	for i, existing := range mr.order {
+		if existing == id {
+			mr.order = append(mr.order[:i], mr.order[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (mr *InMemoryUserRepository) GetAllUsers(ctx context.Context) ([]domain.User, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	var users []domain.User
+	for _, id := range mr.order {
+		if user := mr.users[id]; inOrgScope(ctx, user.OrgID) {
+			users = append(users, user)
+		}
+	}
+	return users, nil
+}
+
+func (mr *InMemoryUserRepository) GetUserByCalendarToken(ctx context.Context, tokenHash string) (domain.User, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	for _, id := range mr.order {
+		if user := mr.users[id]; tokenHash != "" && user.CalendarToken == tokenHash && inOrgScope(ctx, user.OrgID) {
+			return user, nil
+		}
+	}
+	return domain.User{}, mongo.ErrNoDocuments
+}
+
+func (mr *InMemoryUserRepository) GetUserByUsername(ctx context.Context, username string) (domain.User, error) {
+	user, found := mr.findByUsername(ctx, username)
+	if !found {
+		return domain.User{}, mongo.ErrNoDocuments
+	}
+	return user, nil
+}
+
+// findByUsername returns the first user visible in ctx registered with
+// username.
+func (mr *InMemoryUserRepository) findByUsername(ctx context.Context, username string) (domain.User, bool) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	for _, id := range mr.order {
+		if user := mr.users[id]; user.Username == username && inOrgScope(ctx, user.OrgID) {
+			return user, true
+		}
+	}
+	return domain.User{}, false
+}
@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"task_manager_testing/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// InMemoryWebhookRepository is a WebhookRepository kept in memory, used to run
+// the application without MongoDB in tests.
+type InMemoryWebhookRepository struct {
+	mu         sync.Mutex
+	webhooks   []domain.Webhook
+	deliveries []domain.WebhookDelivery
+}
+
+func NewInMemoryWebhookRepository() *InMemoryWebhookRepository {
+	return &InMemoryWebhookRepository{}
+}
+
+func (mr *InMemoryWebhookRepository) CreateWebhook(ctx context.Context, webhook domain.Webhook) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	mr.webhooks = append(mr.webhooks, webhook)
+	return nil
+}
+
+func (mr *InMemoryWebhookRepository) GetWebhook(ctx context.Context, id primitive.ObjectID) (domain.Webhook, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	for _, webhook := range mr.webhooks {
+		if webhook.ID == id {
+			return webhook, nil
+		}
+	}
+	return domain.Webhook{}, mongo.ErrNoDocuments
+}
+
+func (mr *InMemoryWebhookRepository) GetWebhooks(ctx context.Context, ownerID primitive.ObjectID) ([]domain.Webhook, error) {
+	return mr.filterWebhooks(func(webhook domain.Webhook) bool { return webhook.OwnerID == ownerID }), nil
+}
+
+func (mr *InMemoryWebhookRepository) GetWebhooksForEvent(ctx context.Context, eventType string) ([]domain.Webhook, error) {
+	return mr.filterWebhooks(func(webhook domain.Webhook) bool {
+		if !webhook.Active {
+			return false
+		}
+		for _, subscribed := range webhook.Events {
+			if subscribed == eventType {
+				return true
+			}
+		}
+		return false
+	}), nil
+}
+
+func (mr *InMemoryWebhookRepository) UpdateWebhook(ctx context.Context, webhook domain.Webhook) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	for i, existing := range mr.webhooks {
+		if existing.ID == webhook.ID {
+			mr.webhooks[i] = webhook
+		}
+	}
+	return nil
+}
+
+func (mr *InMemoryWebhookRepository) DeleteWebhook(ctx context.Context, id primitive.ObjectID) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	webhooks := mr.webhooks[:0]
+	for _, webhook := range mr.webhooks {
+		if webhook.ID != id {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+	mr.webhooks = webhooks
+
+	deliveries := mr.deliveries[:0]
+	for _, delivery := range mr.deliveries {
+		if delivery.WebhookID != id {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	mr.deliveries = deliveries
+	return nil
+}
+
+func (mr *InMemoryWebhookRepository) CreateDelivery(ctx context.Context, delivery domain.WebhookDelivery) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	mr.deliveries = append(mr.deliveries, delivery)
+	return nil
+}
+
+func (mr *InMemoryWebhookRepository) GetDelivery(ctx context.Context, id primitive.ObjectID) (domain.WebhookDelivery, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	for _, delivery := range mr.deliveries {
+		if delivery.ID == id {
+			return delivery, nil
+		}
+	}
+	return domain.WebhookDelivery{}, mongo.ErrNoDocuments
+}
+
+func (mr *InMemoryWebhookRepository) GetDeliveries(ctx context.Context, webhookID primitive.ObjectID, limit int) ([]domain.WebhookDelivery, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	// Newest first, like the MongoDB repository
+	var deliveries []domain.WebhookDelivery
+	for i := len(mr.deliveries) - 1; i >= 0 && len(deliveries) < limit; i-- {
+		if mr.deliveries[i].WebhookID == webhookID {
+			deliveries = append(deliveries, mr.deliveries[i])
+		}
+	}
+	return deliveries, nil
+}
+
+func (mr *InMemoryWebhookRepository) ClaimDueDelivery(ctx context.Context, now time.Time, lease time.Duration) (domain.WebhookDelivery, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	due := -1
+	for i, delivery := range mr.deliveries {
+		if delivery.Status != domain.DeliveryPending || delivery.NextAttemptAt.Time().After(now) {
+			continue
+		}
+		if due < 0 || delivery.NextAttemptAt < mr.deliveries[due].NextAttemptAt {
+			due = i
+		}
+	}
+	if due < 0 {
+		return domain.WebhookDelivery{}, mongo.ErrNoDocuments
+	}
+
+	claimed := mr.deliveries[due]
+	mr.deliveries[due].NextAttemptAt = primitive.NewDateTimeFromTime(now.Add(lease))
+	return claimed, nil
+}
+
+func (mr *InMemoryWebhookRepository) UpdateDelivery(ctx context.Context, delivery domain.WebhookDelivery) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	for i, existing := range mr.deliveries {
+		if existing.ID == delivery.ID {
+			mr.deliveries[i] = delivery
+		}
+	}
+	return nil
+}
+
+// filterWebhooks returns the webhooks matching keep in creation order.
+func (mr *InMemoryWebhookRepository) filterWebhooks(keep func(domain.Webhook) bool) []domain.Webhook {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	var webhooks []domain.Webhook
+	for _, webhook := range mr.webhooks {
+		if keep(webhook) {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+	return webhooks
+}
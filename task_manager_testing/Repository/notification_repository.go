@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+	"task_manager_testing/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type NotificationRepository struct {
+	collection *mongo.Collection
+}
+
+func NewNotificationRepository(client *mongo.Client, dbName, collectionName string) *NotificationRepository {
+	collection := client.Database(dbName).Collection(collectionName)
+	return &NotificationRepository{collection: collection}
+}
+
+// EnsureIndexes makes notification keys unique, which de-duplicates reminders
+// across instances, and indexes the per-user listing.
+func (nr *NotificationRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := nr.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "key", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	})
+	logResult(ctx, "notifications.create_index", err)
+	return err
+}
+
+func (nr *NotificationRepository) CreateNotification(ctx context.Context, notification domain.Notification) (bool, error) {
+	_, err := nr.collection.InsertOne(ctx, &notification)
+	if mongo.IsDuplicateKeyError(err) {
+		logResult(ctx, "notifications.insert", nil, slog.Bool("duplicate", true))
+		return false, nil
+	}
+	logResult(ctx, "notifications.insert", err, slog.String("notification_id", notification.ID.Hex()))
+	return err == nil, err
+}
+
+func (nr *NotificationRepository) GetNotifications(ctx context.Context, userID primitive.ObjectID, unreadOnly bool, limit int) ([]domain.Notification, error) {
+	filter := bson.M{"user_id": userID}
+	if unreadOnly {
+		filter["read_at"] = nil
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).SetLimit(int64(limit))
+
+	cursor, err := nr.collection.Find(ctx, filter, opts)
+	logResult(ctx, "notifications.find", err)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var notifications []domain.Notification
+	if err := cursor.All(ctx, &notifications); err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+func (nr *NotificationRepository) CountUnread(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	count, err := nr.collection.CountDocuments(ctx, bson.M{"user_id": userID, "read_at": nil})
+	logResult(ctx, "notifications.count_unread", err)
+	return count, err
+}
+
+// MarkRead keeps the time a notification was first read.
+func (nr *NotificationRepository) MarkRead(ctx context.Context, userID, id primitive.ObjectID, readAt time.Time) error {
+	update := mongo.Pipeline{{{Key: "$set", Value: bson.M{
+		"read_at": bson.M{"$ifNull": bson.A{"$read_at", primitive.NewDateTimeFromTime(readAt)}},
+	}}}}
+	result, err := nr.collection.UpdateOne(ctx, bson.M{"_id": id, "user_id": userID}, update)
+	logResult(ctx, "notifications.mark_read", err, slog.String("notification_id", id.Hex()))
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (nr *NotificationRepository) MarkAllRead(ctx context.Context, userID primitive.ObjectID, readAt time.Time) (int64, error) {
+	result, err := nr.collection.UpdateMany(ctx,
+		bson.M{"user_id": userID, "read_at": nil},
+		bson.M{"$set": bson.M{"read_at": primitive.NewDateTimeFromTime(readAt)}},
+	)
+	logResult(ctx, "notifications.mark_all_read", err)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
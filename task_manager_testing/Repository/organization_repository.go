@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+	"task_manager_testing/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type OrganizationRepository struct {
+	collection *mongo.Collection
+}
+
+func NewOrganizationRepository(client *mongo.Client, dbName, collectionName string) *OrganizationRepository {
+	return &OrganizationRepository{collection: client.Database(dbName).Collection(collectionName)}
+}
+
+// EnsureIndexes keeps organization names unique.
+func (or *OrganizationRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := or.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "name_key", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	logResult(ctx, "organizations.create_index", err)
+	return err
+}
+
+func (or *OrganizationRepository) AddOrganization(ctx context.Context, org domain.Organization) error {
+	_, err := or.collection.InsertOne(ctx, &org)
+	if mongo.IsDuplicateKeyError(err) {
+		logResult(ctx, "organizations.insert", nil, slog.Bool("duplicate", true))
+		return domain.ErrOrganizationExists
+	}
+	logResult(ctx, "organizations.insert", err, slog.String("org_id", org.ID.Hex()))
+	return err
+}
+
+func (or *OrganizationRepository) GetOrganizationById(ctx context.Context, id primitive.ObjectID) (domain.Organization, error) {
+	var org domain.Organization
+	err := or.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&org)
+	logResult(ctx, "organizations.find_one", err, slog.String("org_id", id.Hex()))
+	return org, err
+}
+
+func (or *OrganizationRepository) GetAllOrganizations(ctx context.Context) ([]domain.Organization, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "name_key", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := or.collection.Find(ctx, bson.M{}, opts)
+	logResult(ctx, "organizations.find_all", err)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var orgs []domain.Organization
+	if err := cursor.All(ctx, &orgs); err != nil {
+		return nil, err
+	}
+	return orgs, nil
+}
+
+// orgScoped adds the organization ctx is scoped to to filter. Documents
+// without an organization have no org_id, which a null filter matches.
+func orgScoped(ctx context.Context, filter bson.M) bson.M {
+	orgID, ok := domain.OrgFromContext(ctx)
+	switch {
+	case !ok:
+	case orgID.IsZero():
+		filter["org_id"] = nil
+	default:
+		filter["org_id"] = orgID
+	}
+	return filter
+}
+
+// inOrgScope reports whether a document of the organization orgID is visible
+// in ctx.
+func inOrgScope(ctx context.Context, orgID *primitive.ObjectID) bool {
+	scope, ok := domain.OrgFromContext(ctx)
+	if !ok {
+		return true
+	}
+	if orgID == nil {
+		return scope.IsZero()
+	}
+	return *orgID == scope
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+	"task_manager_testing/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// OutboxRepository keeps the task events still to be relayed in a MongoDB
+// collection. Writes join the transaction of the session in their context.
+type OutboxRepository struct {
+	collection *mongo.Collection
+}
+
+func NewOutboxRepository(client *mongo.Client, dbName, collectionName string) *OutboxRepository {
+	return &OutboxRepository{collection: client.Database(dbName).Collection(collectionName)}
+}
+
+// EnsureIndexes indexes the queue of due messages.
+func (or *OutboxRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := or.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "next_attempt_at", Value: 1}, {Key: "_id", Value: 1}},
+	})
+	logResult(ctx, "outbox.create_index", err)
+	return err
+}
+
+func (or *OutboxRepository) AddEvents(ctx context.Context, events []domain.TaskEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	now := primitive.NewDateTimeFromTime(time.Now())
+	messages := make([]interface{}, len(events))
+	for i, event := range events {
+		messages[i] = domain.OutboxMessage{
+			ID:            primitive.NewObjectID(),
+			Event:         event,
+			DeliveredTo:   []string{},
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		}
+	}
+	_, err := or.collection.InsertMany(ctx, messages)
+	logResult(ctx, "outbox.insert", err, slog.Int("count", len(events)))
+	return err
+}
+
+func (or *OutboxRepository) ClaimMessage(ctx context.Context, now time.Time, lease time.Duration) (domain.OutboxMessage, error) {
+	var message domain.OutboxMessage
+	err := or.collection.FindOneAndUpdate(ctx,
+		bson.M{"next_attempt_at": bson.M{"$lte": primitive.NewDateTimeFromTime(now)}, "dead_lettered_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"next_attempt_at": primitive.NewDateTimeFromTime(now.Add(lease))}},
+		options.FindOneAndUpdate().SetSort(bson.D{{Key: "next_attempt_at", Value: 1}, {Key: "_id", Value: 1}}).SetReturnDocument(options.Before),
+	).Decode(&message)
+	logResult(ctx, "outbox.claim", err)
+	return message, err
+}
+
+func (or *OutboxRepository) RetryMessage(ctx context.Context, message domain.OutboxMessage) error {
+	_, err := or.collection.UpdateOne(ctx, bson.M{"_id": message.ID}, bson.M{"$set": bson.M{
+		"delivered_to":    message.DeliveredTo,
+		"attempts":        message.Attempts,
+		"last_error":      message.LastError,
+		"next_attempt_at": message.NextAttemptAt,
+	}})
+	logResult(ctx, "outbox.retry", err, slog.String("message_id", message.ID.Hex()))
+	return err
+}
+
+func (or *OutboxRepository) DeadLetterMessage(ctx context.Context, message domain.OutboxMessage) error {
+	_, err := or.collection.UpdateOne(ctx, bson.M{"_id": message.ID}, bson.M{"$set": bson.M{
+		"delivered_to":     message.DeliveredTo,
+		"attempts":         message.Attempts,
+		"last_error":       message.LastError,
+		"dead_lettered_at": message.DeadLetteredAt,
+	}})
+	logResult(ctx, "outbox.dead_letter", err, slog.String("message_id", message.ID.Hex()))
+	return err
+}
+
+func (or *OutboxRepository) DeleteMessage(ctx context.Context, id primitive.ObjectID) error {
+	_, err := or.collection.DeleteOne(ctx, bson.M{"_id": id})
+	logResult(ctx, "outbox.delete", err, slog.String("message_id", id.Hex()))
+	return err
+}
@@ -0,0 +1,243 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+	"task_manager_testing/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ReportRepository records the status changes of tasks and builds the
+// reports with aggregation pipelines over them and over the tasks.
+type ReportRepository struct {
+	tasks   *mongo.Collection
+	changes *mongo.Collection
+}
+
+func NewReportRepository(client *mongo.Client, dbName, tasksCollection, changesCollection string) *ReportRepository {
+	db := client.Database(dbName)
+	return &ReportRepository{tasks: db.Collection(tasksCollection), changes: db.Collection(changesCollection)}
+}
+
+// EnsureIndexes indexes the status changes by time, by organization, by
+// creator and by task.
+func (rr *ReportRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := rr.changes.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "at", Value: 1}}},
+		{Keys: bson.D{{Key: "org_id", Value: 1}, {Key: "at", Value: 1}}},
+		{Keys: bson.D{{Key: "created_by", Value: 1}, {Key: "at", Value: 1}}},
+		{Keys: bson.D{{Key: "task_id", Value: 1}, {Key: "at", Value: 1}}},
+	})
+	logResult(ctx, "status_changes.create_index", err)
+	return err
+}
+
+func (rr *ReportRepository) AddStatusChange(ctx context.Context, change domain.StatusChange) error {
+	change.OrgID = domain.ScopedOrg(ctx, change.OrgID)
+	_, err := rr.changes.InsertOne(ctx, &change)
+	logResult(ctx, "status_changes.insert", err, slog.String("task_id", change.TaskID.Hex()), slog.String("to", change.To))
+	return err
+}
+
+// Throughput buckets the creations and completions of query with $dateTrunc.
+// Weeks start on Monday, in UTC.
+func (rr *ReportRepository) Throughput(ctx context.Context, query domain.ReportQuery) ([]domain.PeriodCount, error) {
+	match := changesFilter(ctx, query.CreatedBy)
+	match["at"] = bson.M{"$gte": primitive.NewDateTimeFromTime(query.From), "$lt": primitive.NewDateTimeFromTime(query.To)}
+	match["$or"] = bson.A{bson.M{"from": ""}, bson.M{"to": domain.TaskCompleted}}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: bson.D{{Key: "$dateTrunc", Value: bson.D{
+				{Key: "date", Value: "$at"},
+				{Key: "unit", Value: query.Period},
+				{Key: "startOfWeek", Value: "monday"},
+			}}}},
+			{Key: "created", Value: countWhen(bson.D{{Key: "$eq", Value: bson.A{"$from", ""}}})},
+			{Key: "completed", Value: countWhen(bson.D{{Key: "$eq", Value: bson.A{"$to", domain.TaskCompleted}}})},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+	}
+	cursor, err := rr.changes.Aggregate(ctx, pipeline)
+	logResult(ctx, "status_changes.throughput", err, slog.String("period", query.Period))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var counts []domain.PeriodCount
+	for cursor.Next(ctx) {
+		var row struct {
+			Start     time.Time `bson:"_id"`
+			Created   int       `bson:"created"`
+			Completed int       `bson:"completed"`
+		}
+		if err := cursor.Decode(&row); err != nil {
+			return nil, err
+		}
+		counts = append(counts, domain.PeriodCount{Start: row.Start.UTC(), Created: row.Created, Completed: row.Completed})
+	}
+	return counts, cursor.Err()
+}
+
+// CycleTimes groups the changes of every task into its first start and its
+// last completion.
+func (rr *ReportRepository) CycleTimes(ctx context.Context, query domain.ReportQuery) ([]domain.TaskCycleTime, error) {
+	match := changesFilter(ctx, query.CreatedBy)
+	match["to"] = bson.M{"$in": bson.A{domain.TaskInProgress, domain.TaskCompleted}}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$task_id"},
+			{Key: "started_at", Value: bson.D{{Key: "$min", Value: atWhen(domain.TaskInProgress)}}},
+			{Key: "completed_at", Value: bson.D{{Key: "$max", Value: atWhen(domain.TaskCompleted)}}},
+		}}},
+		{{Key: "$match", Value: bson.M{
+			"started_at":   bson.M{"$ne": nil},
+			"completed_at": bson.M{"$gte": primitive.NewDateTimeFromTime(query.From), "$lt": primitive.NewDateTimeFromTime(query.To)},
+			"$expr":        bson.M{"$gte": bson.A{"$completed_at", "$started_at"}},
+		}}},
+		{{Key: "$set", Value: bson.D{{Key: "hours", Value: bson.D{{Key: "$divide", Value: bson.A{
+			bson.D{{Key: "$subtract", Value: bson.A{"$completed_at", "$started_at"}}},
+			float64(time.Hour / time.Millisecond),
+		}}}}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "completed_at", Value: 1}, {Key: "_id", Value: 1}}}},
+	}
+	cursor, err := rr.changes.Aggregate(ctx, pipeline)
+	logResult(ctx, "status_changes.cycle_times", err)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var cycleTimes []domain.TaskCycleTime
+	if err := cursor.All(ctx, &cycleTimes); err != nil {
+		return nil, err
+	}
+	for i := range cycleTimes {
+		cycleTimes[i].StartedAt, cycleTimes[i].CompletedAt = cycleTimes[i].StartedAt.UTC(), cycleTimes[i].CompletedAt.UTC()
+	}
+	return cycleTimes, nil
+}
+
+// OverdueByUser groups the overdue tasks by creator.
+func (rr *ReportRepository) OverdueByUser(ctx context.Context, createdBy primitive.ObjectID, now time.Time) ([]domain.UserOverdue, error) {
+	match := orgScoped(ctx, bson.M{
+		"due_date": bson.M{"$gt": primitive.DateTime(0), "$lt": primitive.NewDateTimeFromTime(now)},
+		"status":   bson.M{"$ne": domain.TaskCompleted},
+	})
+	if !createdBy.IsZero() {
+		match["created_by"] = createdBy
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.D{{Key: "_id", Value: "$created_by"}, {Key: "overdue", Value: bson.D{{Key: "$sum", Value: 1}}}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "overdue", Value: -1}, {Key: "_id", Value: 1}}}},
+	}
+	cursor, err := rr.tasks.Aggregate(ctx, pipeline)
+	logResult(ctx, "tasks.overdue_by_user", err)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var overdue []domain.UserOverdue
+	if err := cursor.All(ctx, &overdue); err != nil {
+		return nil, err
+	}
+	return overdue, nil
+}
+
+// OpenTasks replays the changes of every task up to each of ends: a task is
+// open at an end when its last change before it left it in a status other
+// than completed or deleted.
+func (rr *ReportRepository) OpenTasks(ctx context.Context, createdBy primitive.ObjectID, ends []time.Time) ([]int, error) {
+	open := make([]int, len(ends))
+	if len(ends) == 0 {
+		return open, nil
+	}
+	dates := make(bson.A, len(ends))
+	for i, end := range ends {
+		dates[i] = primitive.NewDateTimeFromTime(end)
+	}
+
+	match := changesFilter(ctx, createdBy)
+	match["at"] = bson.M{"$lt": dates[len(dates)-1]}
+	openAtEnd := bson.D{{Key: "$let", Value: bson.D{
+		{Key: "vars", Value: bson.D{{Key: "before", Value: bson.D{{Key: "$filter", Value: bson.D{
+			{Key: "input", Value: "$changes"},
+			{Key: "as", Value: "change"},
+			{Key: "cond", Value: bson.D{{Key: "$lt", Value: bson.A{"$$change.at", "$$end"}}}},
+		}}}}}},
+		{Key: "in", Value: bson.D{{Key: "$cond", Value: bson.A{
+			bson.D{{Key: "$and", Value: bson.A{
+				bson.D{{Key: "$gt", Value: bson.A{bson.D{{Key: "$size", Value: "$$before"}}, 0}}},
+				bson.D{{Key: "$not", Value: bson.A{bson.D{{Key: "$in", Value: bson.A{
+					bson.D{{Key: "$last", Value: "$$before.to"}},
+					bson.A{domain.TaskCompleted, ""},
+				}}}}}},
+			}}},
+			1,
+			0,
+		}}}},
+	}}}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$sort", Value: bson.D{{Key: "at", Value: 1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$task_id"},
+			{Key: "changes", Value: bson.D{{Key: "$push", Value: bson.D{{Key: "to", Value: "$to"}, {Key: "at", Value: "$at"}}}}},
+		}}},
+		{{Key: "$project", Value: bson.D{{Key: "open", Value: bson.D{{Key: "$map", Value: bson.D{
+			{Key: "input", Value: dates},
+			{Key: "as", Value: "end"},
+			{Key: "in", Value: openAtEnd},
+		}}}}}}},
+		{{Key: "$unwind", Value: bson.D{{Key: "path", Value: "$open"}, {Key: "includeArrayIndex", Value: "end"}}}},
+		{{Key: "$group", Value: bson.D{{Key: "_id", Value: "$end"}, {Key: "open", Value: bson.D{{Key: "$sum", Value: "$open"}}}}}},
+	}
+	cursor, err := rr.changes.Aggregate(ctx, pipeline)
+	logResult(ctx, "status_changes.open_tasks", err, slog.Int("points", len(ends)))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var row struct {
+			End  int `bson:"_id"`
+			Open int `bson:"open"`
+		}
+		if err := cursor.Decode(&row); err != nil {
+			return nil, err
+		}
+		if row.End >= 0 && row.End < len(open) {
+			open[row.End] = row.Open
+		}
+	}
+	return open, cursor.Err()
+}
+
+// changesFilter selects the status changes of the tasks of createdBy, or of
+// every task of the organization of ctx when it is primitive.NilObjectID.
+func changesFilter(ctx context.Context, createdBy primitive.ObjectID) bson.M {
+	filter := orgScoped(ctx, bson.M{})
+	if !createdBy.IsZero() {
+		filter["created_by"] = createdBy
+	}
+	return filter
+}
+
+// countWhen sums 1 for every grouped document matching condition.
+func countWhen(condition bson.D) bson.D {
+	return bson.D{{Key: "$sum", Value: bson.D{{Key: "$cond", Value: bson.A{condition, 1, 0}}}}}
+}
+
+// atWhen is the time of a change into status, or null for other changes.
+func atWhen(status string) bson.D {
+	return bson.D{{Key: "$cond", Value: bson.A{bson.D{{Key: "$eq", Value: bson.A{"$to", status}}}, "$at", nil}}}
+}
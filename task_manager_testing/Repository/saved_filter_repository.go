@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+	"task_manager_testing/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type SavedFilterRepository struct {
+	collection *mongo.Collection
+}
+
+func NewSavedFilterRepository(client *mongo.Client, dbName, collectionName string) *SavedFilterRepository {
+	return &SavedFilterRepository{collection: client.Database(dbName).Collection(collectionName)}
+}
+
+// EnsureIndexes keeps filter names unique per user, which also indexes the
+// per-user listing.
+func (fr *SavedFilterRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := fr.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "name_key", Value: 1}}, Options: options.Index().SetUnique(true)},
+	})
+	logResult(ctx, "saved_filters.create_index", err)
+	return err
+}
+
+func (fr *SavedFilterRepository) CreateFilter(ctx context.Context, filter domain.SavedFilter) error {
+	filter.OrgID = domain.ScopedOrg(ctx, filter.OrgID)
+	_, err := fr.collection.InsertOne(ctx, &filter)
+	if mongo.IsDuplicateKeyError(err) {
+		logResult(ctx, "saved_filters.insert", nil, slog.Bool("duplicate", true))
+		return domain.ErrFilterExists
+	}
+	logResult(ctx, "saved_filters.insert", err, slog.String("filter_id", filter.ID.Hex()))
+	return err
+}
+
+func (fr *SavedFilterRepository) GetFilter(ctx context.Context, id primitive.ObjectID) (domain.SavedFilter, error) {
+	var filter domain.SavedFilter
+	err := fr.collection.FindOne(ctx, orgScoped(ctx, bson.M{"_id": id})).Decode(&filter)
+	logResult(ctx, "saved_filters.find_one", err, slog.String("filter_id", id.Hex()))
+	return filter, err
+}
+
+func (fr *SavedFilterRepository) GetFilters(ctx context.Context, userID primitive.ObjectID) ([]domain.SavedFilter, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "name_key", Value: 1}})
+	cursor, err := fr.collection.Find(ctx, orgScoped(ctx, bson.M{"user_id": userID}), opts)
+	logResult(ctx, "saved_filters.find", err, slog.String("user_id", userID.Hex()))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var filters []domain.SavedFilter
+	if err := cursor.All(ctx, &filters); err != nil {
+		return nil, err
+	}
+	return filters, nil
+}
+
+func (fr *SavedFilterRepository) DeleteFilter(ctx context.Context, id primitive.ObjectID) error {
+	_, err := fr.collection.DeleteOne(ctx, orgScoped(ctx, bson.M{"_id": id}))
+	logResult(ctx, "saved_filters.delete", err, slog.String("filter_id", id.Hex()))
+	return err
+}
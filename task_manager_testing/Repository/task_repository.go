@@ -2,11 +2,17 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
 	"task_manager_testing/domain"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type TaskRepository struct {
@@ -18,62 +24,303 @@ func NewTaskRepository(client *mongo.Client, dbName, collectionName string) *Tas
 	return &TaskRepository{collection: collection}
 }
 
-func (tr *TaskRepository) AddTask(task domain.Task) error {
-	_, err := tr.collection.InsertOne(context.TODO(), task)
+// maxRankAttempts bounds how often AddTask ranks a new task again after a
+// concurrent insert took the same rank.
+const maxRankAttempts = 5
+
+// rankIndex is the index keeping the ranks of each creator unique.
+const rankIndex = "created_by_1_rank_1_unique"
+
+// EnsureIndexes indexes the lookups of tasks by organization, by creator, by
+// label and by recurring series, and keeps the ranks of each creator unique.
+// Tasks sharing a rank under the earlier index, which allowed duplicates, are
+// ranked again first, and that index is dropped once the unique one exists so
+// rank lookups are never left without an index.
+func (tr *TaskRepository) EnsureIndexes(ctx context.Context) error {
+	if err := tr.rerankDuplicates(ctx); err != nil {
+		return err
+	}
+	_, err := tr.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "org_id", Value: 1}, {Key: "created_by", Value: 1}}},
+		{Keys: bson.D{{Key: "created_by", Value: 1}}},
+		{Keys: bson.D{{Key: "labels", Value: 1}}},
+		{Keys: bson.D{{Key: "created_by", Value: 1}, {Key: "rank", Value: 1}}, Options: options.Index().
+			SetName(rankIndex).SetUnique(true).SetPartialFilterExpression(bson.M{"rank": bson.M{"$exists": true}})},
+		{Keys: bson.D{{Key: "series_id", Value: 1}, {Key: "occurrence", Value: 1}}, Options: options.Index().SetSparse(true)},
+	})
+	logResult(ctx, "tasks.create_index", err)
+	if err != nil {
+		return err
+	}
+	_, err = tr.collection.Indexes().DropOne(ctx, "created_by_1_rank_1")
+	if err != nil && !isIndexNotFound(err) {
+		logResult(ctx, "tasks.drop_index", err)
+		return err
+	}
+	return nil
+}
+
+// rerankDuplicates gives every task but the oldest of each group sharing a
+// rank with the same creator a new rank between that rank and the next one of
+// the creator, keeping the order of the group.
+func (tr *TaskRepository) rerankDuplicates(ctx context.Context) error {
+	cursor, err := tr.collection.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"rank": bson.M{"$exists": true}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: bson.D{{Key: "created_by", Value: "$created_by"}, {Key: "rank", Value: "$rank"}}},
+			{Key: "ids", Value: bson.D{{Key: "$push", Value: "$_id"}}},
+		}}},
+		{{Key: "$match", Value: bson.M{"ids.1": bson.M{"$exists": true}}}},
+	})
+	logResult(ctx, "tasks.find_duplicate_ranks", err)
+	if err != nil {
+		return err
+	}
+	var groups []struct {
+		Key struct {
+			CreatedBy primitive.ObjectID `bson:"created_by"`
+			Rank      string             `bson:"rank"`
+		} `bson:"_id"`
+		IDs []primitive.ObjectID `bson:"ids"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		return err
+	}
+
+	for _, group := range groups {
+		var next domain.Task
+		err := tr.collection.FindOne(ctx,
+			bson.M{"created_by": group.Key.CreatedBy, "rank": bson.M{"$gt": group.Key.Rank}},
+			options.FindOne().SetSort(bson.D{{Key: "rank", Value: 1}}).SetProjection(bson.M{"rank": 1}),
+		).Decode(&next)
+		if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+			logResult(ctx, "tasks.find_next_rank", err)
+			return err
+		}
+		rank := group.Key.Rank
+		for _, id := range group.IDs[1:] {
+			rank = domain.RankBetween(rank, next.Rank)
+			_, err := tr.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"rank": rank}})
+			logResult(ctx, "tasks.rerank", err, slog.String("task_id", id.Hex()))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// AddTask stores task in the organization of ctx, ranked after the other
+// tasks of its creator when it has no rank. Two tasks created at once may be
+// given the same rank; outside a transaction the one inserted second is
+// ranked again. In a transaction the conflict aborts the transaction, so
+// AddTask returns domain.ErrRankTaken and the caller runs it again.
+func (tr *TaskRepository) AddTask(ctx context.Context, task domain.Task) error {
+	task.OrgID = domain.ScopedOrg(ctx, task.OrgID)
+	ranked := task.Rank == ""
+	retry := ranked && mongo.SessionFromContext(ctx) == nil
+	for attempt := 1; ; attempt++ {
+		if ranked {
+			var last domain.Task
+			err := tr.collection.FindOne(ctx,
+				bson.M{"created_by": task.CreatedBy, "rank": bson.M{"$exists": true}},
+				options.FindOne().SetSort(bson.D{{Key: "rank", Value: -1}}).SetProjection(bson.M{"rank": 1}),
+			).Decode(&last)
+			if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+				logResult(ctx, "tasks.find_last_rank", err)
+				return err
+			}
+			task.Rank = domain.RankBetween(last.Rank, "")
+		}
+		task.UpdatedAt = primitive.NewDateTimeFromTime(time.Now())
+		_, err := tr.collection.InsertOne(ctx, task)
+		logResult(ctx, "tasks.insert", err, slog.String("task_id", task.ID.Hex()), slog.Int("attempt", attempt))
+		err = rankError(err)
+		if !retry || !errors.Is(err, domain.ErrRankTaken) || attempt == maxRankAttempts {
+			return err
+		}
+	}
+}
+
+func (tr *TaskRepository) GetMyTasks(ctx context.Context, userID primitive.ObjectID) ([]domain.Task, error) {
+	tasks, err := tr.findTasks(ctx, orgScoped(ctx, bson.M{"created_by": userID}))
+	logResult(ctx, "tasks.find_mine", err, slog.Int("count", len(tasks)))
+	return tasks, err
+}
+
+func (tr *TaskRepository) GetAllTasks(ctx context.Context) ([]domain.Task, error) {
+	tasks, err := tr.findTasks(ctx, orgScoped(ctx, bson.M{}))
+	logResult(ctx, "tasks.find_all", err, slog.Int("count", len(tasks)))
+	return tasks, err
+}
+
+func (tr *TaskRepository) GetTaskById(ctx context.Context, id primitive.ObjectID) (domain.Task, error) {
+	var task domain.Task
+	err := tr.collection.FindOne(ctx, orgScoped(ctx, bson.M{"_id": id})).Decode(&task)
+	logResult(ctx, "tasks.find_one", err, slog.String("task_id", id.Hex()))
+	return task, err
+}
+
+func (tr *TaskRepository) UpdateFullTask(ctx context.Context, id primitive.ObjectID, task domain.Task) error {
+	task.OrgID = domain.ScopedOrg(ctx, task.OrgID)
+	task.UpdatedAt = primitive.NewDateTimeFromTime(time.Now())
+	_, err := tr.collection.ReplaceOne(ctx, orgScoped(ctx, bson.M{"_id": id}), &task)
+	logResult(ctx, "tasks.replace", err, slog.String("task_id", id.Hex()))
+	return rankError(err)
+}
+
+func (tr *TaskRepository) UpdateSomeTask(ctx context.Context, id primitive.ObjectID, update map[string]interface{}) error {
+	set := bson.M{"updated_at": primitive.NewDateTimeFromTime(time.Now())}
+	for field, value := range update {
+		set[field] = value
+	}
+	_, err := tr.collection.UpdateOne(ctx, orgScoped(ctx, bson.M{"_id": id}), bson.M{"$set": set})
+	logResult(ctx, "tasks.update", err, slog.String("task_id", id.Hex()))
+	return rankError(err)
+}
+
+// rankError turns a duplicate key error of the rank index into
+// domain.ErrRankTaken, keeping the driver error in the message.
+func rankError(err error) error {
+	if mongo.IsDuplicateKeyError(err) && strings.Contains(err.Error(), rankIndex) {
+		return fmt.Errorf("%w: %v", domain.ErrRankTaken, err)
+	}
 	return err
 }
 
-func (tr *TaskRepository) GetMyTasks(userID primitive.ObjectID) ([]domain.Task, error) {
-	var tasks []domain.Task
-	cursor, err := tr.collection.Find(context.TODO(), bson.M{"created_by": userID})
+func (tr *TaskRepository) DeleteTask(ctx context.Context, id primitive.ObjectID) error {
+	_, err := tr.collection.DeleteOne(ctx, orgScoped(ctx, bson.M{"_id": id}))
+	logResult(ctx, "tasks.delete", err, slog.String("task_id", id.Hex()))
+	return err
+}
+
+// CountTasksByStatus returns the number of tasks for each status.
+func (tr *TaskRepository) CountTasksByStatus(ctx context.Context) (map[string]int64, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: orgScoped(ctx, bson.M{})}},
+		{{Key: "$group", Value: bson.D{{Key: "_id", Value: "$status"}, {Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}}}}},
+	}
+	cursor, err := tr.collection.Aggregate(ctx, pipeline)
+	logResult(ctx, "tasks.count_by_status", err)
 	if err != nil {
 		return nil, err
 	}
-	defer cursor.Close(context.TODO())
-	for cursor.Next(context.TODO()) {
-		var task domain.Task
-		if err := cursor.Decode(&task); err != nil {
+	defer cursor.Close(ctx)
+
+	counts := make(map[string]int64)
+	for cursor.Next(ctx) {
+		var row struct {
+			Status string `bson:"_id"`
+			Count  int64  `bson:"count"`
+		}
+		if err := cursor.Decode(&row); err != nil {
 			return nil, err
 		}
-		tasks = append(tasks, task)
+		counts[row.Status] = row.Count
 	}
-	return tasks, nil
+	return counts, cursor.Err()
 }
 
-func (tr *TaskRepository) GetAllTasks() ([]domain.Task, error) {
-	var tasks []domain.Task
-	cursor, err := tr.collection.Find(context.TODO(), bson.M{})
+// StreamTasks decodes the matching tasks one at a time from the cursor.
+func (tr *TaskRepository) StreamTasks(ctx context.Context, createdBy primitive.ObjectID, fn func(domain.Task) error) error {
+	filter := orgScoped(ctx, bson.M{})
+	if !createdBy.IsZero() {
+		filter["created_by"] = createdBy
+	}
+	cursor, err := tr.collection.Find(ctx, filter)
+	logResult(ctx, "tasks.stream", err)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	defer cursor.Close(context.TODO())
-	for cursor.Next(context.TODO()) {
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
 		var task domain.Task
 		if err := cursor.Decode(&task); err != nil {
-			return nil, err
+			return err
+		}
+		if err := fn(task); err != nil {
+			return err
 		}
-		tasks = append(tasks, task)
 	}
-	return tasks, nil
+	return cursor.Err()
 }
 
-func (tr *TaskRepository) GetTaskById(id primitive.ObjectID) (domain.Task, error) {
-	var task domain.Task
-	err := tr.collection.FindOne(context.TODO(), bson.M{"_id": id}).Decode(&task)
-	return task, err
+// GetTasksDueBefore returns the tasks that are not completed and are due before before.
+func (tr *TaskRepository) GetTasksDueBefore(ctx context.Context, before time.Time) ([]domain.Task, error) {
+	tasks, err := tr.findTasks(ctx, orgScoped(ctx, bson.M{
+		"due_date": bson.M{"$gt": primitive.DateTime(0), "$lt": primitive.NewDateTimeFromTime(before)},
+		"status":   bson.M{"$ne": domain.TaskCompleted},
+	}))
+	logResult(ctx, "tasks.find_due", err, slog.Int("count", len(tasks)))
+	return tasks, err
 }
 
-func (tr *TaskRepository) UpdateFullTask(id primitive.ObjectID, task domain.Task) error {
-	_, err := tr.collection.ReplaceOne(context.TODO(), bson.M{"_id": id}, &task)
-	return err
+// FindTasks returns the tasks matching filter, oldest first.
+func (tr *TaskRepository) FindTasks(ctx context.Context, filter domain.TaskFilter) ([]domain.Task, error) {
+	query := orgScoped(ctx, bson.M{})
+	if !filter.CreatedBy.IsZero() {
+		query["created_by"] = filter.CreatedBy
+	}
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+	if len(filter.Labels) > 0 {
+		operator := "$all"
+		if filter.LabelMatch == domain.LabelMatchAny {
+			operator = "$in"
+		}
+		query["labels"] = bson.M{operator: filter.Labels}
+	}
+	if !filter.SeriesID.IsZero() {
+		query["series_id"] = filter.SeriesID
+	}
+	tasks, err := tr.findTasks(ctx, query, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	logResult(ctx, "tasks.find_filtered", err, slog.Int("count", len(tasks)))
+	return tasks, err
 }
 
-func (tr *TaskRepository) UpdateSomeTask(id primitive.ObjectID, update map[string]interface{}) error {
-	_, err := tr.collection.UpdateOne(context.TODO(), bson.M{"_id": id}, bson.M{"$set": update})
+// RemoveLabel pulls the label from every task carrying it.
+func (tr *TaskRepository) RemoveLabel(ctx context.Context, labelID primitive.ObjectID) error {
+	result, err := tr.collection.UpdateMany(ctx, orgScoped(ctx, bson.M{"labels": labelID}), bson.M{
+		"$pull": bson.M{"labels": labelID},
+		"$set":  bson.M{"updated_at": primitive.NewDateTimeFromTime(time.Now())},
+	})
+	var modified int64
+	if result != nil {
+		modified = result.ModifiedCount
+	}
+	logResult(ctx, "tasks.remove_label", err, slog.String("label_id", labelID.Hex()), slog.Int64("modified", modified))
 	return err
 }
 
-func (tr *TaskRepository) DeleteTask(id primitive.ObjectID) error {
-	_, err := tr.collection.DeleteOne(context.TODO(), bson.M{"_id": id})
-	return err
+// findTasks decodes every task matching filter.
+func (tr *TaskRepository) findTasks(ctx context.Context, filter bson.M, opts ...*options.FindOptions) ([]domain.Task, error) {
+	var tasks []domain.Task
+	cursor, err := tr.collection.Find(ctx, filter, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	for cursor.Next(ctx) {
+		var task domain.Task
+		if err := cursor.Decode(&task); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+// logResult records the outcome of a repository operation. Missing documents are
+// expected during normal use and are logged at debug level like successes.
+func logResult(ctx context.Context, operation string, err error, attrs ...slog.Attr) {
+	attrs = append(attrs, slog.String("operation", operation))
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		attrs = append(attrs, slog.String("error", err.Error()))
+		slog.LogAttrs(ctx, slog.LevelError, "repository operation failed", attrs...)
+		return
+	}
+	slog.LogAttrs(ctx, slog.LevelDebug, "repository operation completed", attrs...)
 }
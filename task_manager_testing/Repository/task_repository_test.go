@@ -1,6 +1,7 @@
 package repository_test
 
 import (
+	"context"
 	"task_manager_testing/config/database"
 	repository "task_manager_testing/Repository"
 	"task_manager_testing/domain"
@@ -38,12 +39,72 @@ func (suite *TaskRepositorySuite) TestAddTask() {
 		CreatedBy:   primitive.NewObjectID(),
 	}
 
-	err := suite.repository.AddTask(task)
+	err := suite.repository.AddTask(context.TODO(), task)
 	suite.NoError(err)
 }
 
+// TestConcurrentAddTaskRanks tests that tasks created at once by the same
+// user get distinct ranks
+func (suite *TaskRepositorySuite) TestConcurrentAddTaskRanks() {
+	ctx := context.TODO()
+	suite.Require().NoError(suite.repository.EnsureIndexes(ctx))
+
+	// Each round of conflicts stores at least one task, so every task is
+	// stored within the repository's five attempts
+	creator := primitive.NewObjectID()
+	errs := make(chan error, 5)
+	for i := 0; i < cap(errs); i++ {
+		go func() {
+			errs <- suite.repository.AddTask(ctx, domain.Task{ID: primitive.NewObjectID(), Title: "Concurrent", Status: "Pending", CreatedBy: creator})
+		}()
+	}
+	for i := 0; i < cap(errs); i++ {
+		suite.NoError(<-errs)
+	}
+
+	tasks, err := suite.repository.GetMyTasks(ctx, creator)
+	suite.Require().NoError(err)
+	ranks := map[string]bool{}
+	for _, task := range tasks {
+		ranks[task.Rank] = true
+	}
+	suite.Len(ranks, cap(errs))
+}
+
+// TestEnsureIndexesRanksDuplicates tests that tasks sharing a rank from
+// before ranks were unique are ranked apart, in creation order
+func (suite *TaskRepositorySuite) TestEnsureIndexesRanksDuplicates() {
+	ctx := context.TODO()
+	name := "taskstest_" + primitive.NewObjectID().Hex()
+	defer suite.client.Database("taskdb").Collection(name).Drop(ctx)
+	tasks := repository.NewTaskRepository(suite.client, "taskdb", name)
+
+	creator := primitive.NewObjectID()
+	first, second, next := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	for _, task := range []domain.Task{
+		{ID: first, Title: "First", Status: "Pending", CreatedBy: creator, Rank: "1"},
+		{ID: second, Title: "Second", Status: "Pending", CreatedBy: creator, Rank: "1"},
+		{ID: next, Title: "Next", Status: "Pending", CreatedBy: creator, Rank: "2"},
+	} {
+		suite.Require().NoError(tasks.AddTask(ctx, task))
+	}
+	suite.Require().NoError(tasks.EnsureIndexes(ctx))
+
+	ranks := map[primitive.ObjectID]string{}
+	stored, err := tasks.GetMyTasks(ctx, creator)
+	suite.Require().NoError(err)
+	for _, task := range stored {
+		ranks[task.ID] = task.Rank
+	}
+	suite.Equal("1", ranks[first])
+	suite.Equal("2", ranks[next])
+	suite.Greater(ranks[second], ranks[first])
+	suite.Less(ranks[second], ranks[next])
+	suite.ErrorIs(tasks.UpdateSomeTask(ctx, second, map[string]interface{}{"rank": "1"}), domain.ErrRankTaken)
+}
+
 func (suite *TaskRepositorySuite) TestGetAllTasks() {
-	tasks, err := suite.repository.GetAllTasks()
+	tasks, err := suite.repository.GetAllTasks(context.TODO())
 	suite.NoError(err)
 	suite.Empty(tasks)
 }
@@ -58,16 +119,16 @@ func (suite *TaskRepositorySuite) TestGetTaskById() {
 		CreatedBy:   primitive.NewObjectID(),
 	}
 
-	err := suite.repository.AddTask(task)
+	err := suite.repository.AddTask(context.TODO(), task)
 	suite.NoError(err)
 
-	task, err = suite.repository.GetTaskById(task.ID)
+	task, err = suite.repository.GetTaskById(context.TODO(), task.ID)
 	suite.NoError(err)
 	suite.NotEmpty(task)
 }
 
 func (suite *TaskRepositorySuite) TestGetMyTasks() {
-	tasks, err := suite.repository.GetMyTasks(primitive.NewObjectID())
+	tasks, err := suite.repository.GetMyTasks(context.TODO(), primitive.NewObjectID())
 	suite.NoError(err)
 	suite.Empty(tasks)
 }
@@ -82,11 +143,11 @@ func (suite *TaskRepositorySuite) TestUpdateFullTask() {
 		CreatedBy:   primitive.NewObjectID(),
 	}
 
-	err := suite.repository.AddTask(task)
+	err := suite.repository.AddTask(context.TODO(), task)
 	suite.NoError(err)
 
 	task.Status = "Completed"
-	err = suite.repository.UpdateFullTask(task.ID, task)
+	err = suite.repository.UpdateFullTask(context.TODO(), task.ID, task)
 	suite.NoError(err)
 }
 
@@ -100,14 +161,14 @@ func (suite *TaskRepositorySuite) TestUpdateSomeTask() {
 		CreatedBy:   primitive.NewObjectID(),
 	}
 
-	err := suite.repository.AddTask(task)
+	err := suite.repository.AddTask(context.TODO(), task)
 	suite.NoError(err)
 
 	update := map[string]interface{}{
 		"status": "Completed",
 	}
 
-	err = suite.repository.UpdateSomeTask(task.ID, update)
+	err = suite.repository.UpdateSomeTask(context.TODO(), task.ID, update)
 	suite.NoError(err)
 }
 
@@ -121,10 +182,10 @@ func (suite *TaskRepositorySuite) TestDeleteTask() {
 		CreatedBy:   primitive.NewObjectID(),
 	}
 
-	err := suite.repository.AddTask(task)
+	err := suite.repository.AddTask(context.TODO(), task)
 	suite.NoError(err)
 
-	err = suite.repository.DeleteTask(task.ID)
+	err = suite.repository.DeleteTask(context.TODO(), task.ID)
 	suite.NoError(err)
 }
 
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+	"task_manager_testing/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type TaskSeriesRepository struct {
+	collection *mongo.Collection
+}
+
+func NewTaskSeriesRepository(client *mongo.Client, dbName, collectionName string) *TaskSeriesRepository {
+	return &TaskSeriesRepository{collection: client.Database(dbName).Collection(collectionName)}
+}
+
+// EnsureIndexes indexes the lookup of the series due for a new occurrence.
+func (sr *TaskSeriesRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := sr.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "ended", Value: 1}, {Key: "latest_due", Value: 1}}},
+	})
+	logResult(ctx, "task_series.create_index", err)
+	return err
+}
+
+func (sr *TaskSeriesRepository) CreateSeries(ctx context.Context, series domain.TaskSeries) error {
+	series.OrgID = domain.ScopedOrg(ctx, series.OrgID)
+	_, err := sr.collection.InsertOne(ctx, &series)
+	logResult(ctx, "task_series.insert", err, slog.String("series_id", series.ID.Hex()))
+	return err
+}
+
+func (sr *TaskSeriesRepository) GetSeries(ctx context.Context, id primitive.ObjectID) (domain.TaskSeries, error) {
+	var series domain.TaskSeries
+	err := sr.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&series)
+	logResult(ctx, "task_series.find_one", err, slog.String("series_id", id.Hex()))
+	return series, err
+}
+
+func (sr *TaskSeriesRepository) UpdateSeries(ctx context.Context, series domain.TaskSeries) error {
+	_, err := sr.collection.UpdateOne(ctx, bson.M{"_id": series.ID}, bson.M{"$set": bson.M{
+		"recurrence":       series.Recurrence,
+		"start":            series.Start,
+		"start_occurrence": series.StartOccurrence,
+		"title":            series.Title,
+		"description":      series.Description,
+		"latest_due":       series.LatestDue,
+		"ended":            series.Ended,
+	}})
+	logResult(ctx, "task_series.update", err, slog.String("series_id", series.ID.Hex()))
+	return err
+}
+
+// AdvanceSeries only matches the series while from is its latest occurrence,
+// so concurrent generators cannot both advance it.
+func (sr *TaskSeriesRepository) AdvanceSeries(ctx context.Context, id primitive.ObjectID, from int, latestDue primitive.DateTime) (bool, error) {
+	result, err := sr.collection.UpdateOne(ctx, bson.M{"_id": id, "occurrences": from, "ended": false}, bson.M{
+		"$set": bson.M{"occurrences": from + 1, "latest_due": latestDue},
+	})
+	logResult(ctx, "task_series.advance", err, slog.String("series_id", id.Hex()), slog.Int("occurrence", from+1))
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+func (sr *TaskSeriesRepository) EndSeries(ctx context.Context, id primitive.ObjectID) error {
+	_, err := sr.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"ended": true}})
+	logResult(ctx, "task_series.end", err, slog.String("series_id", id.Hex()))
+	return err
+}
+
+func (sr *TaskSeriesRepository) GetDueSeries(ctx context.Context, before time.Time) ([]domain.TaskSeries, error) {
+	cursor, err := sr.collection.Find(ctx, bson.M{"ended": false, "latest_due": bson.M{"$lt": primitive.NewDateTimeFromTime(before)}})
+	logResult(ctx, "task_series.find_due", err)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var series []domain.TaskSeries
+	if err := cursor.All(ctx, &series); err != nil {
+		return nil, err
+	}
+	return series, nil
+}
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"task_manager_testing/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type TimeEntryRepository struct {
+	collection *mongo.Collection
+}
+
+func NewTimeEntryRepository(client *mongo.Client, dbName, collectionName string) *TimeEntryRepository {
+	return &TimeEntryRepository{collection: client.Database(dbName).Collection(collectionName)}
+}
+
+// EnsureIndexes indexes the entries of a task, of a user and of an
+// organization, and allows one running entry per user.
+func (tr *TimeEntryRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := tr.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "task_id", Value: 1}, {Key: "started_at", Value: 1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "started_at", Value: 1}}},
+		{Keys: bson.D{{Key: "org_id", Value: 1}, {Key: "started_at", Value: 1}}},
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}},
+			Options: options.Index().SetName("one_running_timer_per_user").SetUnique(true).SetPartialFilterExpression(bson.M{"running": true}),
+		},
+	})
+	logResult(ctx, "time_entries.create_index", err)
+	return err
+}
+
+func (tr *TimeEntryRepository) StartEntry(ctx context.Context, entry domain.TimeEntry) error {
+	entry.OrgID = domain.ScopedOrg(ctx, entry.OrgID)
+	_, err := tr.collection.InsertOne(ctx, &entry)
+	if mongo.IsDuplicateKeyError(err) {
+		logResult(ctx, "time_entries.insert", nil, slog.Bool("duplicate", true))
+		return domain.ErrTimerRunning
+	}
+	logResult(ctx, "time_entries.insert", err, slog.String("entry_id", entry.ID.Hex()))
+	return err
+}
+
+func (tr *TimeEntryRepository) StopEntry(ctx context.Context, userID, taskID primitive.ObjectID, stoppedAt time.Time) (domain.TimeEntry, error) {
+	var entry domain.TimeEntry
+	err := tr.collection.FindOne(ctx, bson.M{"user_id": userID, "task_id": taskID, "running": true}).Decode(&entry)
+	logResult(ctx, "time_entries.find_running", err, slog.String("task_id", taskID.Hex()))
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.TimeEntry{}, domain.ErrTimerNotRunning
+	}
+	if err != nil {
+		return domain.TimeEntry{}, err
+	}
+
+	stopTimeEntry(&entry, stoppedAt)
+	result, err := tr.collection.UpdateOne(ctx, bson.M{"_id": entry.ID, "running": true}, bson.M{"$set": bson.M{
+		"stopped_at": entry.StoppedAt,
+		"running":    false,
+		"seconds":    entry.Seconds,
+	}})
+	logResult(ctx, "time_entries.stop", err, slog.String("entry_id", entry.ID.Hex()))
+	if err != nil {
+		return domain.TimeEntry{}, err
+	}
+	if result.MatchedCount == 0 {
+		// Stopped concurrently
+		return domain.TimeEntry{}, domain.ErrTimerNotRunning
+	}
+	return entry, nil
+}
+
+func (tr *TimeEntryRepository) GetEntries(ctx context.Context, taskID primitive.ObjectID) ([]domain.TimeEntry, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "started_at", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := tr.collection.Find(ctx, bson.M{"task_id": taskID}, opts)
+	logResult(ctx, "time_entries.find", err, slog.String("task_id", taskID.Hex()))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var entries []domain.TimeEntry
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (tr *TimeEntryRepository) TimeByUser(ctx context.Context, query domain.TimeQuery) ([]domain.UserTime, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: timeQueryFilter(ctx, query)}},
+		{{Key: "$group", Value: bson.D{{Key: "_id", Value: "$user_id"}, {Key: "seconds", Value: bson.D{{Key: "$sum", Value: "$seconds"}}}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "seconds", Value: -1}, {Key: "_id", Value: 1}}}},
+	}
+	cursor, err := tr.collection.Aggregate(ctx, pipeline)
+	logResult(ctx, "time_entries.time_by_user", err)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var totals []domain.UserTime
+	if err := cursor.All(ctx, &totals); err != nil {
+		return nil, err
+	}
+	return totals, nil
+}
+
+func (tr *TimeEntryRepository) TimeByWeek(ctx context.Context, query domain.TimeQuery) ([]domain.WeekTime, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: timeQueryFilter(ctx, query)}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: bson.D{
+				{Key: "year", Value: bson.D{{Key: "$isoWeekYear", Value: "$started_at"}}},
+				{Key: "week", Value: bson.D{{Key: "$isoWeek", Value: "$started_at"}}},
+			}},
+			{Key: "seconds", Value: bson.D{{Key: "$sum", Value: "$seconds"}}},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id.year", Value: 1}, {Key: "_id.week", Value: 1}}}},
+	}
+	cursor, err := tr.collection.Aggregate(ctx, pipeline)
+	logResult(ctx, "time_entries.time_by_week", err)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var weeks []domain.WeekTime
+	for cursor.Next(ctx) {
+		var row struct {
+			ID struct {
+				Year int `bson:"year"`
+				Week int `bson:"week"`
+			} `bson:"_id"`
+			Seconds int64 `bson:"seconds"`
+		}
+		if err := cursor.Decode(&row); err != nil {
+			return nil, err
+		}
+		weeks = append(weeks, domain.WeekTime{Year: row.ID.Year, Week: row.ID.Week, Seconds: row.Seconds})
+	}
+	return weeks, cursor.Err()
+}
+
+// timeQueryFilter selects the stopped entries matching query in the
+// organization of ctx.
+func timeQueryFilter(ctx context.Context, query domain.TimeQuery) bson.M {
+	filter := orgScoped(ctx, bson.M{"running": false})
+	if !query.UserID.IsZero() {
+		filter["user_id"] = query.UserID
+	}
+	started := bson.M{}
+	if !query.From.IsZero() {
+		started["$gte"] = primitive.NewDateTimeFromTime(query.From)
+	}
+	if !query.To.IsZero() {
+		started["$lt"] = primitive.NewDateTimeFromTime(query.To)
+	}
+	if len(started) > 0 {
+		filter["started_at"] = started
+	}
+	return filter
+}
+
+// stopTimeEntry stops entry at stoppedAt and sets its tracked seconds.
+func stopTimeEntry(entry *domain.TimeEntry, stoppedAt time.Time) {
+	entry.StoppedAt = primitive.NewDateTimeFromTime(stoppedAt)
+	entry.Running = false
+	entry.Seconds = int64(stoppedAt.Sub(entry.StartedAt.Time()) / time.Second)
+	if entry.Seconds < 0 {
+		entry.Seconds = 0
+	}
+}
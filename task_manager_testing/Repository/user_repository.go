@@ -2,12 +2,14 @@ package repository
 
 import (
 	"context"
+	"log/slog"
 	infrastructure "task_manager_testing/Infrastructure"
 	"task_manager_testing/domain"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type UserRepository struct {
@@ -19,13 +21,18 @@ func NewUserRepository(client *mongo.Client, dbName, collectionName string) *Use
 	return &UserRepository{collection: collection}
 }
 
-// RegisterUser adds a new user to the database.
-func (ur *UserRepository) RegisterUser(username, password, role string) error {
+// RegisterUser adds a new user to the organization of ctx.
+func (ur *UserRepository) RegisterUser(ctx context.Context, username, password, role string) error {
 
 	id := primitive.NewObjectID()
-	user := domain.User{ID: id, Username: username, Password: password, Role: role}
+	user := domain.User{ID: id, Username: username, Password: password, Role: role, OrgID: domain.ScopedOrg(ctx, nil)}
 
-	_, err := ur.collection.InsertOne(context.TODO(), &user)
+	_, err := ur.collection.InsertOne(ctx, &user)
+	if mongo.IsDuplicateKeyError(err) {
+		logResult(ctx, "users.insert", nil, slog.Bool("duplicate", true))
+		return domain.ErrUserExists
+	}
+	logResult(ctx, "users.insert", err, slog.String("target_user_id", id.Hex()))
 	if err != nil {
 		return err
 	}
@@ -33,15 +40,16 @@ func (ur *UserRepository) RegisterUser(username, password, role string) error {
 }
 
 // Login authenticates a user.
-func (ur *UserRepository) Login(username, password string) (domain.User, error) {
+func (ur *UserRepository) Login(ctx context.Context, username, password string) (domain.User, error) {
 	var user domain.User
 
-	err := ur.collection.FindOne(context.TODO(), bson.M{"username": username}).Decode(&user)
+	err := ur.collection.FindOne(ctx, orgScoped(ctx, bson.M{"username": username})).Decode(&user)
+	logResult(ctx, "users.find_by_username", err)
 	if err != nil {
 		return domain.User{}, err
 	}
 
-	err = infrastructure.ComparePasswords(user.Password, password)
+	err = infrastructure.ComparePasswordsContext(ctx, user.Password, password)
 	if err != nil {
 		return domain.User{}, err
 	}
@@ -50,10 +58,11 @@ func (ur *UserRepository) Login(username, password string) (domain.User, error)
 }
 
 // get user by id
-func (ur *UserRepository) GetUserById(id primitive.ObjectID) (domain.User, error) {
+func (ur *UserRepository) GetUserById(ctx context.Context, id primitive.ObjectID) (domain.User, error) {
 	var user domain.User
 
-	err := ur.collection.FindOne(context.TODO(), bson.M{"_id": id}).Decode(&user)
+	err := ur.collection.FindOne(ctx, orgScoped(ctx, bson.M{"_id": id})).Decode(&user)
+	logResult(ctx, "users.find_one", err, slog.String("target_user_id", id.Hex()))
 	if err != nil {
 		return domain.User{}, err
 	}
@@ -61,14 +70,15 @@ func (ur *UserRepository) GetUserById(id primitive.ObjectID) (domain.User, error
 }
 
 // GetAllUsers returns all users from the database.
-func (ur *UserRepository) GetAllUsers() ([]domain.User, error) {
+func (ur *UserRepository) GetAllUsers(ctx context.Context) ([]domain.User, error) {
 	var users []domain.User
-	cursor, err := ur.collection.Find(context.Background(), bson.M{})
+	cursor, err := ur.collection.Find(ctx, orgScoped(ctx, bson.M{}))
+	logResult(ctx, "users.find_all", err)
 	if err != nil {
 		return nil, err
 	}
-	defer cursor.Close(context.Background())
-	for cursor.Next(context.Background()) {
+	defer cursor.Close(ctx)
+	for cursor.Next(ctx) {
 		var user domain.User
 		cursor.Decode(&user)
 		users = append(users, user)
@@ -76,10 +86,53 @@ func (ur *UserRepository) GetAllUsers() ([]domain.User, error) {
 	return users, nil
 }
 
+// GetUserByCalendarToken finds the user owning a calendar feed token hash.
+func (ur *UserRepository) GetUserByCalendarToken(ctx context.Context, tokenHash string) (domain.User, error) {
+	var user domain.User
+
+	err := ur.collection.FindOne(ctx, orgScoped(ctx, bson.M{"calendar_token": tokenHash})).Decode(&user)
+	logResult(ctx, "users.find_by_calendar_token", err)
+	if err != nil {
+		return domain.User{}, err
+	}
+	return user, nil
+}
+
+// GetUserByUsername finds a user by username.
+func (ur *UserRepository) GetUserByUsername(ctx context.Context, username string) (domain.User, error) {
+	var user domain.User
+
+	err := ur.collection.FindOne(ctx, orgScoped(ctx, bson.M{"username": username})).Decode(&user)
+	logResult(ctx, "users.find_by_username", err)
+	if err != nil {
+		return domain.User{}, err
+	}
+	return user, nil
+}
+
+// EnsureIndexes keeps usernames unique across organizations, since users log
+// in by username alone, and indexes the calendar feed tokens, which are
+// looked up on every feed refresh, and the users of an organization.
+func (ur *UserRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := ur.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "username", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "calendar_token", Value: 1}}, Options: options.Index().SetUnique(true).SetSparse(true)},
+		{Keys: bson.D{{Key: "org_id", Value: 1}}},
+	})
+	logResult(ctx, "users.create_index", err)
+	return err
+}
+
 // UpdateUser updates a user in the database.
-func (ur *UserRepository) UpdateUser(oid primitive.ObjectID, user domain.User) error {
+func (ur *UserRepository) UpdateUser(ctx context.Context, oid primitive.ObjectID, user domain.User) error {
 
-	_, err := ur.collection.ReplaceOne(context.TODO(), bson.M{"_id": oid}, &user)
+	user.OrgID = domain.ScopedOrg(ctx, user.OrgID)
+	_, err := ur.collection.ReplaceOne(ctx, orgScoped(ctx, bson.M{"_id": oid}), &user)
+	if mongo.IsDuplicateKeyError(err) {
+		logResult(ctx, "users.replace", nil, slog.Bool("duplicate", true))
+		return domain.ErrUserExists
+	}
+	logResult(ctx, "users.replace", err, slog.String("target_user_id", oid.Hex()))
 	if err != nil {
 		return err
 	}
@@ -87,8 +140,9 @@ func (ur *UserRepository) UpdateUser(oid primitive.ObjectID, user domain.User) e
 }
 
 // DeleteUser deletes a user from the database.
-func (ur *UserRepository) DeleteUser(id primitive.ObjectID) error {
-	_, err := ur.collection.DeleteOne(context.TODO(), bson.M{"_id": id})
+func (ur *UserRepository) DeleteUser(ctx context.Context, id primitive.ObjectID) error {
+	_, err := ur.collection.DeleteOne(ctx, orgScoped(ctx, bson.M{"_id": id}))
+	logResult(ctx, "users.delete", err, slog.String("target_user_id", id.Hex()))
 	if err != nil {
 		return err
 	}
@@ -40,12 +40,12 @@ func (suite *UserRepositorySuite) TestAddUser() {
 		Password: "12345678",
 		Role:     "user",
 	}
-	err := suite.repository.RegisterUser(user.Username, user.Password, user.Role)
+	err := suite.repository.RegisterUser(context.TODO(), user.Username, user.Password, user.Role)
 	suite.NoError(err)
 }
 
 func (suite *UserRepositorySuite) TestGetAllUsers() {
-	users, err := suite.repository.GetAllUsers()
+	users, err := suite.repository.GetAllUsers(context.TODO())
 	suite.NoError(err)
 	suite.Empty(users)
 }
@@ -60,7 +60,7 @@ func (suite *UserRepositorySuite) TestGetUserById() {
 	})
 	suite.NoError(err)
 
-	user, err := suite.repository.GetUserById(id)
+	user, err := suite.repository.GetUserById(context.TODO(), id)
 	suite.NoError(err)
 	suite.NotEmpty(user)
 	suite.Equal("tester1", user.Username)
@@ -84,7 +84,7 @@ func (suite *UserRepositorySuite) TestUpdateUser() {
 		Role:     "admin",
 	}
 
-	err = suite.repository.UpdateUser(id, updatedUser)
+	err = suite.repository.UpdateUser(context.TODO(), id, updatedUser)
 	suite.NoError(err)
 
 	userFromDb := domain.User{}
@@ -105,11 +105,11 @@ func (suite *UserRepositorySuite) TestDeleteUser() {
 	})
 	suite.NoError(err)
 
-	err = suite.repository.DeleteUser(id)
+	err = suite.repository.DeleteUser(context.TODO(), id)
 	suite.NoError(err)
 
 	// Verify the user is deleted
-	user, err := suite.repository.GetUserById(id)
+	user, err := suite.repository.GetUserById(context.TODO(), id)
 	suite.Error(err)
 	suite.Empty(user)
 }
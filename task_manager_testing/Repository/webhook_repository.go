@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+	"task_manager_testing/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// WebhookRepository stores webhooks and their deliveries in two collections.
+type WebhookRepository struct {
+	webhooks   *mongo.Collection
+	deliveries *mongo.Collection
+}
+
+func NewWebhookRepository(client *mongo.Client, dbName, webhooksCollection, deliveriesCollection string) *WebhookRepository {
+	db := client.Database(dbName)
+	return &WebhookRepository{webhooks: db.Collection(webhooksCollection), deliveries: db.Collection(deliveriesCollection)}
+}
+
+// EnsureIndexes indexes the event lookups, the delivery log and the queue of
+// due deliveries.
+func (wr *WebhookRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := wr.webhooks.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "owner_id", Value: 1}}},
+		{Keys: bson.D{{Key: "events", Value: 1}, {Key: "active", Value: 1}}},
+	})
+	logResult(ctx, "webhooks.create_index", err)
+	if err != nil {
+		return err
+	}
+
+	_, err = wr.deliveries.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "webhook_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}}},
+	})
+	logResult(ctx, "webhook_deliveries.create_index", err)
+	return err
+}
+
+func (wr *WebhookRepository) CreateWebhook(ctx context.Context, webhook domain.Webhook) error {
+	_, err := wr.webhooks.InsertOne(ctx, &webhook)
+	logResult(ctx, "webhooks.insert", err, slog.String("webhook_id", webhook.ID.Hex()))
+	return err
+}
+
+func (wr *WebhookRepository) GetWebhook(ctx context.Context, id primitive.ObjectID) (domain.Webhook, error) {
+	var webhook domain.Webhook
+	err := wr.webhooks.FindOne(ctx, bson.M{"_id": id}).Decode(&webhook)
+	logResult(ctx, "webhooks.find_one", err, slog.String("webhook_id", id.Hex()))
+	return webhook, err
+}
+
+func (wr *WebhookRepository) GetWebhooks(ctx context.Context, ownerID primitive.ObjectID) ([]domain.Webhook, error) {
+	webhooks, err := wr.findWebhooks(ctx, bson.M{"owner_id": ownerID})
+	logResult(ctx, "webhooks.find_mine", err, slog.Int("count", len(webhooks)))
+	return webhooks, err
+}
+
+func (wr *WebhookRepository) GetWebhooksForEvent(ctx context.Context, eventType string) ([]domain.Webhook, error) {
+	webhooks, err := wr.findWebhooks(ctx, bson.M{"events": eventType, "active": true})
+	logResult(ctx, "webhooks.find_for_event", err, slog.Int("count", len(webhooks)))
+	return webhooks, err
+}
+
+func (wr *WebhookRepository) UpdateWebhook(ctx context.Context, webhook domain.Webhook) error {
+	_, err := wr.webhooks.ReplaceOne(ctx, bson.M{"_id": webhook.ID}, &webhook)
+	logResult(ctx, "webhooks.replace", err, slog.String("webhook_id", webhook.ID.Hex()))
+	return err
+}
+
+func (wr *WebhookRepository) DeleteWebhook(ctx context.Context, id primitive.ObjectID) error {
+	_, err := wr.webhooks.DeleteOne(ctx, bson.M{"_id": id})
+	logResult(ctx, "webhooks.delete", err, slog.String("webhook_id", id.Hex()))
+	if err != nil {
+		return err
+	}
+	_, err = wr.deliveries.DeleteMany(ctx, bson.M{"webhook_id": id})
+	logResult(ctx, "webhook_deliveries.delete", err, slog.String("webhook_id", id.Hex()))
+	return err
+}
+
+func (wr *WebhookRepository) CreateDelivery(ctx context.Context, delivery domain.WebhookDelivery) error {
+	_, err := wr.deliveries.InsertOne(ctx, &delivery)
+	logResult(ctx, "webhook_deliveries.insert", err, slog.String("delivery_id", delivery.ID.Hex()))
+	return err
+}
+
+func (wr *WebhookRepository) GetDelivery(ctx context.Context, id primitive.ObjectID) (domain.WebhookDelivery, error) {
+	var delivery domain.WebhookDelivery
+	err := wr.deliveries.FindOne(ctx, bson.M{"_id": id}).Decode(&delivery)
+	logResult(ctx, "webhook_deliveries.find_one", err, slog.String("delivery_id", id.Hex()))
+	return delivery, err
+}
+
+func (wr *WebhookRepository) GetDeliveries(ctx context.Context, webhookID primitive.ObjectID, limit int) ([]domain.WebhookDelivery, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).SetLimit(int64(limit))
+	cursor, err := wr.deliveries.Find(ctx, bson.M{"webhook_id": webhookID}, opts)
+	logResult(ctx, "webhook_deliveries.find", err, slog.String("webhook_id", webhookID.Hex()))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var deliveries []domain.WebhookDelivery
+	if err := cursor.All(ctx, &deliveries); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (wr *WebhookRepository) ClaimDueDelivery(ctx context.Context, now time.Time, lease time.Duration) (domain.WebhookDelivery, error) {
+	var delivery domain.WebhookDelivery
+	err := wr.deliveries.FindOneAndUpdate(ctx,
+		bson.M{"status": domain.DeliveryPending, "next_attempt_at": bson.M{"$lte": primitive.NewDateTimeFromTime(now)}},
+		bson.M{"$set": bson.M{"next_attempt_at": primitive.NewDateTimeFromTime(now.Add(lease))}},
+		options.FindOneAndUpdate().SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).SetReturnDocument(options.Before),
+	).Decode(&delivery)
+	logResult(ctx, "webhook_deliveries.claim", err)
+	return delivery, err
+}
+
+func (wr *WebhookRepository) UpdateDelivery(ctx context.Context, delivery domain.WebhookDelivery) error {
+	_, err := wr.deliveries.ReplaceOne(ctx, bson.M{"_id": delivery.ID}, &delivery)
+	logResult(ctx, "webhook_deliveries.replace", err, slog.String("delivery_id", delivery.ID.Hex()))
+	return err
+}
+
+// findWebhooks decodes every webhook matching filter.
+func (wr *WebhookRepository) findWebhooks(ctx context.Context, filter bson.M) ([]domain.Webhook, error) {
+	cursor, err := wr.webhooks.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var webhooks []domain.Webhook
+	if err := cursor.All(ctx, &webhooks); err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
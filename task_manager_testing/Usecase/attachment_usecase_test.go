@@ -0,0 +1,199 @@
+package usecase_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"strings"
+	"testing"
+
+	usecase "task_manager_testing/Usecase"
+	"task_manager_testing/domain"
+	"task_manager_testing/mocks"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AttachmentUsecaseSuite defines the suite for attachment usecase tests
+type AttachmentUsecaseSuite struct {
+	suite.Suite
+	attachmentRepo    *mocks.AttachmentRepository
+	blobs             *mocks.BlobStore
+	taskRepo          *mocks.TaskRepository
+	userRepo          *mocks.UserRepository
+	attachmentUsecase *usecase.AttachmentUsecase
+
+	owner domain.User
+	task  domain.Task
+	// stored is the content last written to the blob store
+	stored []byte
+}
+
+// SetupTest sets up the necessary resources before each test
+func (suite *AttachmentUsecaseSuite) SetupTest() {
+	suite.attachmentRepo = &mocks.AttachmentRepository{}
+	suite.blobs = &mocks.BlobStore{}
+	suite.taskRepo = &mocks.TaskRepository{}
+	suite.userRepo = &mocks.UserRepository{}
+	suite.attachmentUsecase = usecase.NewAttachmentUsecase(suite.attachmentRepo, suite.blobs, suite.taskRepo, suite.userRepo, usecase.AttachmentPolicy{
+		MaxFileBytes:   1000,
+		UserQuotaBytes: 5000,
+	})
+
+	suite.owner = domain.User{ID: primitive.NewObjectID(), Username: "alice", Role: "user"}
+	suite.task = domain.Task{ID: primitive.NewObjectID(), Title: "Attached", CreatedBy: suite.owner.ID}
+	suite.taskRepo.On("GetTaskById", mock.Anything, suite.task.ID).Return(suite.task, nil).Maybe()
+	suite.stored = nil
+}
+
+// TearDownTest checks the mock expectations after each test
+func (suite *AttachmentUsecaseSuite) TearDownTest() {
+	suite.attachmentRepo.AssertExpectations(suite.T())
+	suite.blobs.AssertExpectations(suite.T())
+	suite.taskRepo.AssertExpectations(suite.T())
+	suite.userRepo.AssertExpectations(suite.T())
+}
+
+// expectPut stores what the usecase writes to the blob store
+func (suite *AttachmentUsecaseSuite) expectPut() {
+	suite.blobs.On("Put", mock.Anything, mock.AnythingOfType("string"), mock.Anything).Return(nil).Once().
+		Run(func(args mock.Arguments) {
+			suite.stored, _ = io.ReadAll(args.Get(2).(io.Reader))
+		})
+}
+
+// TestUpload tests that the type is sniffed and the size and checksum recorded
+func (suite *AttachmentUsecaseSuite) TestUpload() {
+	content := append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{1}, 600)...)
+	sum := sha256.Sum256(content)
+
+	suite.attachmentRepo.On("GetUsage", mock.Anything, suite.owner.ID).Return(int64(4000), nil)
+	suite.expectPut()
+	suite.attachmentRepo.On("CreateAttachment", mock.Anything, mock.AnythingOfType("domain.Attachment"), int64(5000)).Return(nil)
+
+	attachment, err := suite.attachmentUsecase.Upload(context.Background(), suite.owner, suite.task.ID, `C:\Users\alice\screen.png`, bytes.NewReader(content))
+
+	suite.Require().NoError(err)
+	suite.Equal("screen.png", attachment.Filename)
+	suite.Equal("image/png", attachment.ContentType)
+	suite.Equal(int64(len(content)), attachment.Size)
+	suite.Equal(hex.EncodeToString(sum[:]), attachment.SHA256)
+	suite.Equal(attachment.ID.Hex(), attachment.BlobKey)
+	suite.Equal(suite.owner.ID, attachment.OwnerID)
+	suite.Equal(content, suite.stored)
+}
+
+// TestUploadLimits tests that files over the size limit or the quota are removed and rejected
+func (suite *AttachmentUsecaseSuite) TestUploadLimits() {
+	suite.Run("file too large", func() {
+		suite.SetupTest()
+		suite.attachmentRepo.On("GetUsage", mock.Anything, suite.owner.ID).Return(int64(0), nil)
+		suite.expectPut()
+		suite.blobs.On("Delete", mock.Anything, mock.AnythingOfType("string")).Return(nil).Once()
+
+		_, err := suite.attachmentUsecase.Upload(context.Background(), suite.owner, suite.task.ID, "big.txt", strings.NewReader(strings.Repeat("a", 1001)))
+
+		suite.ErrorIs(err, domain.ErrAttachmentTooLarge)
+		suite.Len(suite.stored, 1001)
+		suite.TearDownTest()
+	})
+
+	suite.Run("quota left", func() {
+		suite.SetupTest()
+		suite.attachmentRepo.On("GetUsage", mock.Anything, suite.owner.ID).Return(int64(4900), nil)
+		suite.expectPut()
+		suite.blobs.On("Delete", mock.Anything, mock.AnythingOfType("string")).Return(nil).Once()
+
+		_, err := suite.attachmentUsecase.Upload(context.Background(), suite.owner, suite.task.ID, "notes.txt", strings.NewReader(strings.Repeat("a", 101)))
+
+		suite.ErrorIs(err, domain.ErrQuotaExceeded)
+		suite.TearDownTest()
+	})
+
+	suite.Run("quota taken by a concurrent upload", func() {
+		suite.SetupTest()
+		suite.attachmentRepo.On("GetUsage", mock.Anything, suite.owner.ID).Return(int64(4000), nil)
+		suite.expectPut()
+		suite.attachmentRepo.On("CreateAttachment", mock.Anything, mock.AnythingOfType("domain.Attachment"), int64(5000)).Return(domain.ErrQuotaExceeded).Once()
+		suite.blobs.On("Delete", mock.Anything, mock.AnythingOfType("string")).Return(nil).Once()
+
+		_, err := suite.attachmentUsecase.Upload(context.Background(), suite.owner, suite.task.ID, "notes.txt", strings.NewReader("notes"))
+
+		suite.ErrorIs(err, domain.ErrQuotaExceeded)
+		suite.TearDownTest()
+	})
+
+	suite.Run("quota used up", func() {
+		suite.SetupTest()
+		suite.attachmentRepo.On("GetUsage", mock.Anything, suite.owner.ID).Return(int64(5000), nil)
+
+		_, err := suite.attachmentUsecase.Upload(context.Background(), suite.owner, suite.task.ID, "notes.txt", strings.NewReader("a"))
+
+		suite.ErrorIs(err, domain.ErrQuotaExceeded)
+		suite.TearDownTest()
+	})
+}
+
+// TestUploadRejected tests uploads refused before anything is stored
+func (suite *AttachmentUsecaseSuite) TestUploadRejected() {
+	bob := domain.User{ID: primitive.NewObjectID(), Username: "bob", Role: "user"}
+	suite.attachmentRepo.On("GetUsage", mock.Anything, suite.owner.ID).Return(int64(0), nil).Once()
+
+	_, err := suite.attachmentUsecase.Upload(context.Background(), bob, suite.task.ID, "a.txt", strings.NewReader("a"))
+	suite.Error(err)
+
+	_, err = suite.attachmentUsecase.Upload(context.Background(), suite.owner, suite.task.ID, "bad\x00name", strings.NewReader("a"))
+	suite.ErrorIs(err, domain.ErrInvalidAttachment)
+
+	_, err = suite.attachmentUsecase.Upload(context.Background(), suite.owner, suite.task.ID, "empty.txt", strings.NewReader(""))
+	suite.ErrorIs(err, domain.ErrInvalidAttachment)
+}
+
+// TestDownload tests that content is only returned when it matches its checksum
+func (suite *AttachmentUsecaseSuite) TestDownload() {
+	sum := sha256.Sum256([]byte("hello"))
+	attachment := domain.Attachment{ID: primitive.NewObjectID(), TaskID: suite.task.ID, OwnerID: suite.owner.ID, Size: 5, SHA256: hex.EncodeToString(sum[:]), BlobKey: "key"}
+	suite.attachmentRepo.On("GetAttachment", mock.Anything, attachment.ID).Return(attachment, nil)
+	suite.blobs.On("Get", mock.Anything, "key").Return(io.NopCloser(strings.NewReader("hello")), nil).Once()
+	suite.blobs.On("Get", mock.Anything, "key").Return(io.NopCloser(strings.NewReader("hellO")), nil).Once()
+	suite.blobs.On("Get", mock.Anything, "key").Return(nil, domain.ErrBlobNotFound).Once()
+
+	_, content, err := suite.attachmentUsecase.Download(context.Background(), suite.owner, suite.task.ID, attachment.ID)
+	suite.Require().NoError(err)
+	data, _ := io.ReadAll(content)
+	suite.Equal("hello", string(data))
+
+	_, _, err = suite.attachmentUsecase.Download(context.Background(), suite.owner, suite.task.ID, attachment.ID)
+	suite.ErrorIs(err, domain.ErrAttachmentCorrupted)
+
+	_, _, err = suite.attachmentUsecase.Download(context.Background(), suite.owner, suite.task.ID, attachment.ID)
+	suite.ErrorIs(err, domain.ErrAttachmentCorrupted)
+}
+
+// TestDelete tests that only the uploader or a higher role deletes an attachment
+func (suite *AttachmentUsecaseSuite) TestDelete() {
+	admin := domain.User{ID: primitive.NewObjectID(), Username: "carol", Role: "admin"}
+	byAdmin := domain.Attachment{ID: primitive.NewObjectID(), TaskID: suite.task.ID, OwnerID: admin.ID, BlobKey: "admin"}
+	byOwner := domain.Attachment{ID: primitive.NewObjectID(), TaskID: suite.task.ID, OwnerID: suite.owner.ID, BlobKey: "owner"}
+	suite.attachmentRepo.On("GetAttachment", mock.Anything, byAdmin.ID).Return(byAdmin, nil)
+	suite.attachmentRepo.On("GetAttachment", mock.Anything, byOwner.ID).Return(byOwner, nil)
+	suite.userRepo.On("GetUserById", mock.Anything, admin.ID).Return(admin, nil)
+	suite.userRepo.On("GetUserById", mock.Anything, suite.owner.ID).Return(suite.owner, nil)
+	suite.attachmentRepo.On("DeleteAttachment", mock.Anything, byOwner.ID).Return(nil).Once()
+	suite.blobs.On("Delete", mock.Anything, "owner").Return(nil).Once()
+
+	err := suite.attachmentUsecase.Delete(context.Background(), suite.owner, suite.task.ID, byAdmin.ID)
+	suite.ErrorIs(err, domain.ErrForbidden)
+
+	err = suite.attachmentUsecase.Delete(context.Background(), admin, suite.task.ID, byOwner.ID)
+	suite.NoError(err)
+}
+
+// TestAttachmentUsecaseSuite is the entry point for running the suite tests
+func TestAttachmentUsecaseSuite(t *testing.T) {
+	suite.Run(t, new(AttachmentUsecaseSuite))
+}
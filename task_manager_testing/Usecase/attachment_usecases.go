@@ -0,0 +1,237 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"path"
+	"strings"
+	infrastructure "task_manager_testing/Infrastructure"
+	"task_manager_testing/domain"
+	"time"
+	"unicode"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// sniffLength is how many leading bytes are used to detect the content type.
+const sniffLength = 512
+
+// AttachmentPolicy limits what users may upload.
+type AttachmentPolicy struct {
+	// MaxFileBytes is the largest single attachment
+	MaxFileBytes int64
+	// UserQuotaBytes is the total size of the attachments one user may keep
+	UserQuotaBytes int64
+}
+
+type AttachmentUsecase struct {
+	attachmentRepo domain.AttachmentRepository
+	blobs          domain.BlobStore
+	taskRepo       domain.TaskRepository
+	userRepo       domain.UserRepository
+	policy         AttachmentPolicy
+}
+
+func NewAttachmentUsecase(attachmentRepo domain.AttachmentRepository, blobs domain.BlobStore, taskRepo domain.TaskRepository, userRepo domain.UserRepository, policy AttachmentPolicy) *AttachmentUsecase {
+	return &AttachmentUsecase{attachmentRepo: attachmentRepo, blobs: blobs, taskRepo: taskRepo, userRepo: userRepo, policy: policy}
+}
+
+// Upload streams content to the blob store while computing its size and
+// checksum. Content over the size limit or the owner's remaining quota is
+// removed again and rejected. The quota is checked again when the attachment
+// is stored, so that concurrent uploads cannot exceed it together.
+func (au *AttachmentUsecase) Upload(ctx context.Context, actor domain.User, taskID primitive.ObjectID, filename string, content io.Reader) (attachment domain.Attachment, err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "AttachmentUsecase.Upload")
+	defer infrastructure.EndSpan(span, &err)
+
+	if _, err := visibleTask(ctx, au.taskRepo, actor, taskID); err != nil {
+		return domain.Attachment{}, err
+	}
+	if filename, err = validateFilename(filename); err != nil {
+		return domain.Attachment{}, err
+	}
+
+	usage, err := au.attachmentRepo.GetUsage(ctx, actor.ID)
+	if err != nil {
+		return domain.Attachment{}, err
+	}
+	remaining := au.policy.UserQuotaBytes - usage
+	if remaining <= 0 {
+		return domain.Attachment{}, fmt.Errorf("%w: %d of %d bytes used", domain.ErrQuotaExceeded, usage, au.policy.UserQuotaBytes)
+	}
+	limit := min(au.policy.MaxFileBytes, remaining)
+
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(content, head)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return domain.Attachment{}, err
+	}
+	if n == 0 {
+		return domain.Attachment{}, fmt.Errorf("%w: the file is empty", domain.ErrInvalidAttachment)
+	}
+	head = head[:n]
+
+	attachment = domain.Attachment{
+		ID:          primitive.NewObjectID(),
+		TaskID:      taskID,
+		OwnerID:     actor.ID,
+		Filename:    filename,
+		ContentType: http.DetectContentType(head),
+		CreatedAt:   primitive.NewDateTimeFromTime(time.Now()),
+	}
+	attachment.BlobKey = attachment.ID.Hex()
+
+	// One byte past the limit is read to tell a full file from a larger one
+	hash := sha256.New()
+	counter := &countingWriter{}
+	source := io.TeeReader(io.LimitReader(io.MultiReader(bytes.NewReader(head), content), limit+1), io.MultiWriter(hash, counter))
+	if err := au.blobs.Put(ctx, attachment.BlobKey, source); err != nil {
+		return domain.Attachment{}, err
+	}
+	if counter.n > limit {
+		au.removeBlob(ctx, attachment.BlobKey)
+		if limit < au.policy.MaxFileBytes {
+			return domain.Attachment{}, fmt.Errorf("%w: only %d bytes of the quota are left", domain.ErrQuotaExceeded, remaining)
+		}
+		return domain.Attachment{}, fmt.Errorf("%w: files cannot be larger than %d bytes", domain.ErrAttachmentTooLarge, au.policy.MaxFileBytes)
+	}
+	attachment.Size = counter.n
+	attachment.SHA256 = hex.EncodeToString(hash.Sum(nil))
+
+	if err := au.attachmentRepo.CreateAttachment(ctx, attachment, au.policy.UserQuotaBytes); err != nil {
+		au.removeBlob(ctx, attachment.BlobKey)
+		return domain.Attachment{}, err
+	}
+
+	slog.InfoContext(ctx, "attachment uploaded", slog.String("attachment_id", attachment.ID.Hex()), slog.String("task_id", taskID.Hex()),
+		slog.Int64("size", attachment.Size), slog.String("content_type", attachment.ContentType))
+	return attachment, nil
+}
+
+func (au *AttachmentUsecase) GetAttachments(ctx context.Context, actor domain.User, taskID primitive.ObjectID) (attachments []domain.Attachment, err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "AttachmentUsecase.GetAttachments")
+	defer infrastructure.EndSpan(span, &err)
+
+	if _, err := visibleTask(ctx, au.taskRepo, actor, taskID); err != nil {
+		return nil, err
+	}
+	return au.attachmentRepo.GetAttachments(ctx, taskID)
+}
+
+// Download reads the whole content and checks its size and checksum before
+// returning it, so that corrupted content is never sent as a success.
+func (au *AttachmentUsecase) Download(ctx context.Context, actor domain.User, taskID, id primitive.ObjectID) (attachment domain.Attachment, content io.ReadCloser, err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "AttachmentUsecase.Download")
+	defer infrastructure.EndSpan(span, &err)
+
+	attachment, err = au.taskAttachment(ctx, actor, taskID, id)
+	if err != nil {
+		return domain.Attachment{}, nil, err
+	}
+
+	blob, err := au.blobs.Get(ctx, attachment.BlobKey)
+	if errors.Is(err, domain.ErrBlobNotFound) {
+		return domain.Attachment{}, nil, fmt.Errorf("%w: the content is missing", domain.ErrAttachmentCorrupted)
+	}
+	if err != nil {
+		return domain.Attachment{}, nil, err
+	}
+	defer blob.Close()
+
+	data, err := io.ReadAll(io.LimitReader(blob, attachment.Size+1))
+	if err != nil {
+		return domain.Attachment{}, nil, err
+	}
+	sum := sha256.Sum256(data)
+	if int64(len(data)) != attachment.Size || hex.EncodeToString(sum[:]) != attachment.SHA256 {
+		slog.ErrorContext(ctx, "attachment checksum mismatch", slog.String("attachment_id", id.Hex()), slog.String("blob_key", attachment.BlobKey))
+		return domain.Attachment{}, nil, domain.ErrAttachmentCorrupted
+	}
+	return attachment, io.NopCloser(bytes.NewReader(data)), nil
+}
+
+// Delete removes the attachment and its content. Admins may delete the
+// uploads of users, and root users those of users and admins.
+func (au *AttachmentUsecase) Delete(ctx context.Context, actor domain.User, taskID, id primitive.ObjectID) (err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "AttachmentUsecase.Delete")
+	defer infrastructure.EndSpan(span, &err)
+
+	attachment, err := au.taskAttachment(ctx, actor, taskID, id)
+	if err != nil {
+		return err
+	}
+	if attachment.OwnerID != actor.ID {
+		// A missing uploader no longer holds a role
+		owner, _ := au.userRepo.GetUserById(ctx, attachment.OwnerID)
+		if !outranks(actor, owner) {
+			return fmt.Errorf("%w: you can only delete your own attachments or those of users below your role", domain.ErrForbidden)
+		}
+	}
+
+	// The record goes first, so a failure never leaves an attachment without content
+	if err := au.attachmentRepo.DeleteAttachment(ctx, id); err != nil {
+		return err
+	}
+	au.removeBlob(ctx, attachment.BlobKey)
+
+	slog.InfoContext(ctx, "attachment deleted", slog.String("attachment_id", id.Hex()))
+	return nil
+}
+
+// taskAttachment returns one of the task's attachments when actor may see it.
+func (au *AttachmentUsecase) taskAttachment(ctx context.Context, actor domain.User, taskID, id primitive.ObjectID) (domain.Attachment, error) {
+	if _, err := visibleTask(ctx, au.taskRepo, actor, taskID); err != nil {
+		return domain.Attachment{}, err
+	}
+	attachment, err := au.attachmentRepo.GetAttachment(ctx, id)
+	if err != nil {
+		return domain.Attachment{}, err
+	}
+	if attachment.TaskID != taskID {
+		return domain.Attachment{}, mongo.ErrNoDocuments
+	}
+	return attachment, nil
+}
+
+// removeBlob deletes content that is no longer referenced. Failures only get
+// logged; they leave an orphaned blob but no broken attachment.
+func (au *AttachmentUsecase) removeBlob(ctx context.Context, key string) {
+	if err := au.blobs.Delete(ctx, key); err != nil {
+		slog.WarnContext(ctx, "attachment content not removed", slog.String("blob_key", key), slog.Any("error", err))
+	}
+}
+
+// validateFilename keeps the base name of filename, as browsers may send a
+// full path, and rejects names that are blank, too long or contain control
+// characters.
+func validateFilename(filename string) (string, error) {
+	filename = strings.TrimSpace(path.Base(strings.ReplaceAll(filename, `\`, "/")))
+	if filename == "" || filename == "." || filename == "/" {
+		return "", fmt.Errorf("%w: a filename is required", domain.ErrInvalidAttachment)
+	}
+	if len(filename) > 255 {
+		return "", fmt.Errorf("%w: the filename cannot be longer than 255 bytes", domain.ErrInvalidAttachment)
+	}
+	if strings.IndexFunc(filename, unicode.IsControl) >= 0 {
+		return "", fmt.Errorf("%w: the filename contains control characters", domain.ErrInvalidAttachment)
+	}
+	return filename, nil
+}
+
+// countingWriter counts the bytes written to it.
+type countingWriter struct {
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	cw.n += int64(len(p))
+	return len(p), nil
+}
@@ -0,0 +1,142 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sort"
+	infrastructure "task_manager_testing/Infrastructure"
+	"task_manager_testing/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// GetBoard returns the tasks created by createdBy in a column per status,
+// ordered by rank. Tasks stored before ranks existed come last in creation
+// order.
+func (tu *TaskUsecase) GetBoard(ctx context.Context, createdBy primitive.ObjectID) (board domain.Board, err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "TaskUsecase.GetBoard")
+	defer infrastructure.EndSpan(span, &err)
+
+	tasks, err := tu.TaskRepository.FindTasks(ctx, domain.TaskFilter{CreatedBy: createdBy})
+	if err != nil {
+		return domain.Board{}, err
+	}
+	markOverdue(tasks, time.Now())
+	sort.SliceStable(tasks, func(i, j int) bool {
+		if tasks[i].Rank == "" || tasks[j].Rank == "" {
+			return tasks[j].Rank == "" && tasks[i].Rank != ""
+		}
+		return tasks[i].Rank < tasks[j].Rank
+	})
+
+	board.Columns = make([]domain.BoardColumn, len(domain.TaskStatuses))
+	columns := make(map[string]int, len(domain.TaskStatuses))
+	for i, status := range domain.TaskStatuses {
+		board.Columns[i] = domain.BoardColumn{Status: status, Tasks: []domain.Task{}}
+		columns[status] = i
+	}
+	for _, task := range tasks {
+		if i, ok := columns[task.Status]; ok {
+			board.Columns[i].Tasks = append(board.Columns[i].Tasks, task)
+		}
+	}
+	return board, nil
+}
+
+// MoveTask ranks the task id between the neighbours of move and sets its
+// status with a single write, so a move never touches another task. The
+// neighbours must be tasks of the same creator in the target column, with
+// After ranked before Before. Ranks are unique per creator across every
+// column, so the new rank also sorts before the next rank of the creator in
+// any column.
+func (tu *TaskUsecase) MoveTask(ctx context.Context, id primitive.ObjectID, move domain.TaskMove) (task domain.Task, err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "TaskUsecase.MoveTask")
+	defer infrastructure.EndSpan(span, &err)
+
+	if err := validateStatus(move.Status); err != nil {
+		return domain.Task{}, invalid("%v", err)
+	}
+
+	var rank string
+	err = tu.recordRanked(ctx, func(ctx context.Context) ([]domain.TaskEvent, error) {
+		return tu.update(ctx, id, func(ctx context.Context, before domain.Task) error {
+			lower, err := tu.neighbourRank(ctx, before, move.After, move.Status)
+			if err != nil {
+				return err
+			}
+			upper, err := tu.neighbourRank(ctx, before, move.Before, move.Status)
+			if err != nil {
+				return err
+			}
+			if !move.After.IsZero() && lower == "" {
+				return invalid("tasks cannot be moved below task %s, which has no rank", move.After.Hex())
+			}
+			if upper != "" && lower >= upper {
+				return invalid("task %s is not ranked before task %s, reload the board", move.After.Hex(), move.Before.Hex())
+			}
+
+			ranks, err := tu.creatorRanks(ctx, before)
+			if err != nil {
+				return err
+			}
+			if move.After.IsZero() && move.Before.IsZero() && len(ranks) > 0 {
+				lower = ranks[len(ranks)-1]
+			}
+			// Tasks of other columns may be ranked between the neighbours
+			next := sort.Search(len(ranks), func(i int) bool { return ranks[i] > lower })
+			if next < len(ranks) && (upper == "" || ranks[next] < upper) {
+				upper = ranks[next]
+			}
+			rank = domain.RankBetween(lower, upper)
+			return tu.TaskRepository.UpdateSomeTask(ctx, id, map[string]interface{}{"status": move.Status, "rank": rank})
+		})
+	})
+	if err != nil {
+		return domain.Task{}, err
+	}
+
+	slog.InfoContext(ctx, "task moved", slog.String("task_id", id.Hex()), slog.String("status", move.Status), slog.String("rank", rank))
+	return tu.GetTaskById(ctx, id)
+}
+
+// neighbourRank returns the rank of the neighbour id of task in the column of
+// status, or an empty rank for a zero id.
+func (tu *TaskUsecase) neighbourRank(ctx context.Context, task domain.Task, id primitive.ObjectID, status string) (string, error) {
+	if id.IsZero() {
+		return "", nil
+	}
+	if id == task.ID {
+		return "", invalid("a task cannot be moved next to itself")
+	}
+	neighbour, err := tu.TaskRepository.GetTaskById(ctx, id)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return "", invalid("task %s is not in the %s column", id.Hex(), status)
+	}
+	if err != nil {
+		return "", err
+	}
+	if neighbour.CreatedBy != task.CreatedBy || neighbour.Status != status {
+		return "", invalid("task %s is not in the %s column", id.Hex(), status)
+	}
+	return neighbour.Rank, nil
+}
+
+// creatorRanks returns the ranks of the other tasks of the creator of task,
+// in every column, sorted.
+func (tu *TaskUsecase) creatorRanks(ctx context.Context, task domain.Task) ([]string, error) {
+	tasks, err := tu.TaskRepository.FindTasks(ctx, domain.TaskFilter{CreatedBy: task.CreatedBy})
+	if err != nil {
+		return nil, err
+	}
+	ranks := make([]string, 0, len(tasks))
+	for _, other := range tasks {
+		if other.ID != task.ID && other.Rank != "" {
+			ranks = append(ranks, other.Rank)
+		}
+	}
+	sort.Strings(ranks)
+	return ranks, nil
+}
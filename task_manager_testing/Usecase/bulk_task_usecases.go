@@ -0,0 +1,176 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	infrastructure "task_manager_testing/Infrastructure"
+	"task_manager_testing/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// BulkTasks runs ops in order and reports the outcome of each. Creates must
+// already carry their ID and creator. Every other operation loads the task
+// and checks it with authorize first.
+//
+// In atomic mode the operations run in one transaction that stops at the
+// first failure. The failed operation reports its error and every other one
+// reports ErrRolledBack.
+func (tu *TaskUsecase) BulkTasks(ctx context.Context, ops []domain.BulkTaskOperation, atomic bool, authorize domain.TaskAuthorizer) (results []domain.BulkTaskResult, err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "TaskUsecase.BulkTasks")
+	defer infrastructure.EndSpan(span, &err)
+
+	if len(ops) == 0 {
+		return nil, invalid("at least one operation is required")
+	}
+	if len(ops) > domain.MaxBulkTaskOperations {
+		return nil, invalid("at most %d operations are allowed", domain.MaxBulkTaskOperations)
+	}
+
+	results = make([]domain.BulkTaskResult, len(ops))
+	if !atomic {
+		for i, op := range ops {
+			var blobKeys []string
+			err := tu.record(ctx, func(ctx context.Context) (events []domain.TaskEvent, err error) {
+				events, blobKeys, err = tu.runBulkOperation(ctx, op, authorize)
+				return events, err
+			})
+			if err == nil {
+				tu.removeBlobs(ctx, blobKeys)
+			}
+			results[i] = bulkResult(i, op, err)
+		}
+		slog.InfoContext(ctx, "bulk task operations completed", slog.Int("operations", len(ops)), slog.Bool("atomic", false))
+		return results, nil
+	}
+
+	if tu.Transactor == nil {
+		return nil, invalid("atomic bulk operations are not supported")
+	}
+
+	failedIndex := -1
+	var opErr error
+	var events []domain.TaskEvent
+	var blobKeys []string
+	err = tu.Transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		// The transaction may be retried, so start from scratch every time
+		failedIndex, opErr, events, blobKeys = -1, nil, nil, nil
+		for i, op := range ops {
+			opEvents, opBlobKeys, err := tu.runBulkOperation(ctx, op, authorize)
+			if err != nil {
+				failedIndex, opErr = i, err
+				return err
+			}
+			events = append(events, opEvents...)
+			blobKeys = append(blobKeys, opBlobKeys...)
+		}
+		if tu.Outbox != nil {
+			return tu.Outbox.AddEvents(ctx, events)
+		}
+		return nil
+	})
+	if err != nil && failedIndex < 0 {
+		// The transaction itself failed, for example on commit
+		return nil, err
+	}
+
+	// Without an outbox, events are only published once the transaction committed
+	if failedIndex < 0 && tu.Outbox == nil {
+		tu.publish(ctx, events...)
+	}
+	if failedIndex < 0 {
+		tu.removeBlobs(ctx, blobKeys)
+	}
+	for i, op := range ops {
+		switch {
+		case failedIndex < 0:
+			results[i] = bulkResult(i, op, nil)
+		case i == failedIndex:
+			results[i] = bulkResult(i, op, opErr)
+		default:
+			results[i] = bulkResult(i, op, domain.ErrRolledBack)
+		}
+	}
+	slog.InfoContext(ctx, "bulk task operations completed", slog.Int("operations", len(ops)), slog.Bool("atomic", true), slog.Bool("committed", failedIndex < 0))
+	return results, nil
+}
+
+// runBulkOperation applies a single operation and returns its events and,
+// for deletions, the keys of the attachment content to remove once the
+// operation is stored.
+func (tu *TaskUsecase) runBulkOperation(ctx context.Context, op domain.BulkTaskOperation, authorize domain.TaskAuthorizer) ([]domain.TaskEvent, []string, error) {
+	if op.Op == domain.BulkCreate {
+		if op.Task == nil {
+			return nil, nil, invalid("task is required")
+		}
+		if err := validateTask(*op.Task); err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", domain.ErrInvalidTask, err)
+		}
+		op.Task.Labels = nil
+		events, err := tu.createTask(ctx, *op.Task)
+		return events, nil, err
+	}
+
+	var update map[string]interface{}
+	switch op.Op {
+	case domain.BulkDelete:
+	case domain.BulkUpdate:
+		if len(op.Fields) == 0 {
+			return nil, nil, invalid("fields are required")
+		}
+		if err := validateTaskUpdate(op.Fields); err != nil {
+			return nil, nil, err
+		}
+		update = op.Fields
+	case domain.BulkStatus:
+		if err := validateStatus(op.Status); err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", domain.ErrInvalidTask, err)
+		}
+		update = map[string]interface{}{"status": op.Status}
+	default:
+		return nil, nil, invalid("unknown operation %q", op.Op)
+	}
+
+	id, err := primitive.ObjectIDFromHex(op.ID)
+	if err != nil {
+		return nil, nil, invalid("invalid task ID %q", op.ID)
+	}
+	task, err := tu.TaskRepository.GetTaskById(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := authorize(ctx, task); err != nil {
+		return nil, nil, err
+	}
+
+	if op.Op == domain.BulkDelete {
+		return tu.deleteTask(ctx, task)
+	}
+	if err := tu.TaskRepository.UpdateSomeTask(ctx, id, update); err != nil {
+		return nil, nil, err
+	}
+	after, err := tu.TaskRepository.GetTaskById(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	events, err := tu.changeEvents(ctx, task, after)
+	return events, nil, err
+}
+
+// invalid builds an error wrapping ErrInvalidTask.
+func invalid(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", domain.ErrInvalidTask, fmt.Sprintf(format, args...))
+}
+
+// bulkResult reports the outcome err of the operation at index i.
+func bulkResult(i int, op domain.BulkTaskOperation, err error) domain.BulkTaskResult {
+	result := domain.BulkTaskResult{Index: i, Op: op.Op, ID: op.ID, Err: err}
+	if op.Op == domain.BulkCreate && op.Task != nil {
+		result.ID = op.Task.ID.Hex()
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
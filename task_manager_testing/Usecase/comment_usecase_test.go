@@ -0,0 +1,196 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	usecase "task_manager_testing/Usecase"
+	"task_manager_testing/domain"
+	"task_manager_testing/mocks"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// CommentUsecaseSuite defines the suite for comment usecase tests
+type CommentUsecaseSuite struct {
+	suite.Suite
+	commentRepo      *mocks.CommentRepository
+	taskRepo         *mocks.TaskRepository
+	userRepo         *mocks.UserRepository
+	notificationRepo *mocks.NotificationRepository
+	commentUsecase   *usecase.CommentUsecase
+
+	owner domain.User
+	task  domain.Task
+}
+
+// SetupTest sets up the necessary resources before each test
+func (suite *CommentUsecaseSuite) SetupTest() {
+	suite.commentRepo = &mocks.CommentRepository{}
+	suite.taskRepo = &mocks.TaskRepository{}
+	suite.userRepo = &mocks.UserRepository{}
+	suite.notificationRepo = &mocks.NotificationRepository{}
+	suite.commentUsecase = usecase.NewCommentUsecase(suite.commentRepo, suite.taskRepo, suite.userRepo, suite.notificationRepo)
+
+	suite.owner = domain.User{ID: primitive.NewObjectID(), Username: "alice", Role: "user"}
+	suite.task = domain.Task{ID: primitive.NewObjectID(), Title: "Discussed", CreatedBy: suite.owner.ID}
+	suite.taskRepo.On("GetTaskById", mock.Anything, suite.task.ID).Return(suite.task, nil).Maybe()
+}
+
+// TearDownTest checks the mock expectations after each test
+func (suite *CommentUsecaseSuite) TearDownTest() {
+	suite.commentRepo.AssertExpectations(suite.T())
+	suite.taskRepo.AssertExpectations(suite.T())
+	suite.userRepo.AssertExpectations(suite.T())
+	suite.notificationRepo.AssertExpectations(suite.T())
+}
+
+// TestAddComment tests that mentions are resolved and notified to the users who can see the task
+func (suite *CommentUsecaseSuite) TestAddComment() {
+	admin := domain.User{ID: primitive.NewObjectID(), Username: "carol", Role: "admin"}
+	bob := domain.User{ID: primitive.NewObjectID(), Username: "bob", Role: "user"}
+
+	suite.userRepo.On("GetUserByUsername", mock.Anything, "carol").Return(admin, nil).Once()
+	suite.userRepo.On("GetUserByUsername", mock.Anything, "bob").Return(bob, nil).Once()
+	suite.userRepo.On("GetUserByUsername", mock.Anything, "alice").Return(suite.owner, nil).Once()
+	suite.userRepo.On("GetUserByUsername", mock.Anything, "nobody").Return(domain.User{}, mongo.ErrNoDocuments).Once()
+	suite.commentRepo.On("CreateComment", mock.Anything, mock.AnythingOfType("domain.Comment")).Return(nil)
+	// Bob cannot see the task and the author is not told about herself
+	suite.notificationRepo.On("CreateNotification", mock.Anything, mock.MatchedBy(func(n domain.Notification) bool {
+		return n.UserID == admin.ID && n.Kind == domain.NotificationMention && n.TaskID == suite.task.ID
+	})).Return(true, nil).Once()
+
+	body := "  @carol and @bob: see `@dave`, mail me at alice@example.com, thanks @carol. @alice @nobody  "
+	comment, err := suite.commentUsecase.AddComment(context.Background(), suite.owner, suite.task.ID, nil, body)
+
+	suite.Require().NoError(err)
+	suite.Equal(suite.owner.ID, comment.AuthorID)
+	suite.Equal("alice", comment.AuthorName)
+	suite.Equal(suite.task.ID, comment.TaskID)
+	suite.Nil(comment.ParentID)
+	suite.Equal("@carol and @bob: see `@dave`, mail me at alice@example.com, thanks @carol. @alice @nobody", comment.Body)
+	suite.Equal([]domain.CommentMention{{UserID: admin.ID, Username: "carol"}, {UserID: bob.ID, Username: "bob"}, {UserID: suite.owner.ID, Username: "alice"}}, comment.Mentions)
+}
+
+// TestAddCommentRejected tests the comments that are refused
+func (suite *CommentUsecaseSuite) TestAddCommentRejected() {
+	bob := domain.User{ID: primitive.NewObjectID(), Username: "bob", Role: "user"}
+	otherTask := primitive.NewObjectID()
+	foreign := domain.Comment{ID: primitive.NewObjectID(), TaskID: otherTask}
+	deleted := domain.Comment{ID: primitive.NewObjectID(), TaskID: suite.task.ID, Deleted: true}
+	suite.commentRepo.On("GetComment", mock.Anything, foreign.ID).Return(foreign, nil)
+	suite.commentRepo.On("GetComment", mock.Anything, deleted.ID).Return(deleted, nil)
+
+	_, err := suite.commentUsecase.AddComment(context.Background(), bob, suite.task.ID, nil, "Hello")
+	suite.ErrorIs(err, mongo.ErrNoDocuments)
+
+	_, err = suite.commentUsecase.AddComment(context.Background(), suite.owner, suite.task.ID, nil, " \n ")
+	suite.ErrorIs(err, domain.ErrInvalidComment)
+
+	_, err = suite.commentUsecase.AddComment(context.Background(), suite.owner, suite.task.ID, &foreign.ID, "Reply")
+	suite.ErrorIs(err, domain.ErrInvalidComment)
+
+	_, err = suite.commentUsecase.AddComment(context.Background(), suite.owner, suite.task.ID, &deleted.ID, "Reply")
+	suite.ErrorIs(err, domain.ErrInvalidComment)
+}
+
+// TestUpdateComment tests that only the author edits a comment
+func (suite *CommentUsecaseSuite) TestUpdateComment() {
+	admin := domain.User{ID: primitive.NewObjectID(), Username: "carol", Role: "admin"}
+	comment := domain.Comment{ID: primitive.NewObjectID(), TaskID: suite.task.ID, AuthorID: suite.owner.ID, Body: "Old"}
+	suite.commentRepo.On("GetComment", mock.Anything, comment.ID).Return(comment, nil)
+	suite.commentRepo.On("UpdateComment", mock.Anything, mock.MatchedBy(func(c domain.Comment) bool {
+		return c.ID == comment.ID && c.Body == "New" && c.EditedAt != 0
+	})).Return(nil).Once()
+
+	_, err := suite.commentUsecase.UpdateComment(context.Background(), admin, suite.task.ID, comment.ID, "New")
+	suite.ErrorIs(err, domain.ErrForbidden)
+
+	updated, err := suite.commentUsecase.UpdateComment(context.Background(), suite.owner, suite.task.ID, comment.ID, "New")
+	suite.Require().NoError(err)
+	suite.Equal("New", updated.Body)
+	suite.Empty(updated.Mentions)
+}
+
+// TestDeleteCommentRoles tests that moderators can only delete the comments of lower roles
+func (suite *CommentUsecaseSuite) TestDeleteCommentRoles() {
+	cases := []struct {
+		actor   string
+		author  string
+		allowed bool
+	}{
+		{"user", "user", false},
+		{"admin", "user", true},
+		{"admin", "admin", false},
+		{"root", "admin", true},
+		{"root", "root", false},
+	}
+
+	for _, tc := range cases {
+		suite.Run(tc.actor+" deletes "+tc.author, func() {
+			suite.SetupTest()
+			actor := domain.User{ID: primitive.NewObjectID(), Role: tc.actor}
+			author := domain.User{ID: primitive.NewObjectID(), Role: tc.author}
+			comment := domain.Comment{ID: primitive.NewObjectID(), TaskID: suite.task.ID, AuthorID: author.ID}
+			if tc.actor == "user" {
+				// Users may only delete on their own tasks
+				actor.ID = suite.owner.ID
+			}
+			suite.commentRepo.On("GetComment", mock.Anything, comment.ID).Return(comment, nil)
+			suite.userRepo.On("GetUserById", mock.Anything, author.ID).Return(author, nil)
+			if tc.allowed {
+				suite.commentRepo.On("CountReplies", mock.Anything, comment.ID).Return(int64(0), nil)
+				suite.commentRepo.On("DeleteComment", mock.Anything, comment.ID).Return(nil)
+			}
+
+			err := suite.commentUsecase.DeleteComment(context.Background(), actor, suite.task.ID, comment.ID)
+
+			if tc.allowed {
+				suite.NoError(err)
+			} else {
+				suite.ErrorIs(err, domain.ErrForbidden)
+			}
+			suite.TearDownTest()
+		})
+	}
+}
+
+// TestDeleteCommentWithReplies tests that a comment with replies is blanked instead of removed
+func (suite *CommentUsecaseSuite) TestDeleteCommentWithReplies() {
+	comment := domain.Comment{ID: primitive.NewObjectID(), TaskID: suite.task.ID, AuthorID: suite.owner.ID, Body: "Question", Mentions: []domain.CommentMention{{Username: "carol"}}}
+	suite.commentRepo.On("GetComment", mock.Anything, comment.ID).Return(comment, nil)
+	suite.commentRepo.On("CountReplies", mock.Anything, comment.ID).Return(int64(2), nil)
+	suite.commentRepo.On("UpdateComment", mock.Anything, mock.MatchedBy(func(c domain.Comment) bool {
+		return c.ID == comment.ID && c.Deleted && c.Body == "" && c.Mentions == nil
+	})).Return(nil)
+
+	suite.NoError(suite.commentUsecase.DeleteComment(context.Background(), suite.owner, suite.task.ID, comment.ID))
+}
+
+// TestGetComments tests that replies are nested under the comments they answer
+func (suite *CommentUsecaseSuite) TestGetComments() {
+	first := domain.Comment{ID: primitive.NewObjectID(), TaskID: suite.task.ID, Body: "First"}
+	second := domain.Comment{ID: primitive.NewObjectID(), TaskID: suite.task.ID, Body: "Second"}
+	reply := domain.Comment{ID: primitive.NewObjectID(), TaskID: suite.task.ID, ParentID: &first.ID, Body: "Reply"}
+	nested := domain.Comment{ID: primitive.NewObjectID(), TaskID: suite.task.ID, ParentID: &reply.ID, Body: "Nested"}
+	suite.commentRepo.On("GetComments", mock.Anything, suite.task.ID).Return([]domain.Comment{first, second, reply, nested}, nil)
+
+	threads, err := suite.commentUsecase.GetComments(context.Background(), domain.User{ID: primitive.NewObjectID(), Role: "root"}, suite.task.ID)
+
+	suite.Require().NoError(err)
+	suite.Require().Len(threads, 2)
+	suite.Equal("First", threads[0].Body)
+	suite.Equal("Second", threads[1].Body)
+	suite.Require().Len(threads[0].Replies, 1)
+	suite.Equal("Reply", threads[0].Replies[0].Body)
+	suite.Require().Len(threads[0].Replies[0].Replies, 1)
+	suite.Equal("Nested", threads[0].Replies[0].Replies[0].Body)
+}
+
+// TestCommentUsecaseSuite is the entry point for running the suite tests
+func TestCommentUsecaseSuite(t *testing.T) {
+	suite.Run(t, new(CommentUsecaseSuite))
+}
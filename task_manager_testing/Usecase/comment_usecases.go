@@ -0,0 +1,332 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	infrastructure "task_manager_testing/Infrastructure"
+	"task_manager_testing/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	// mentionPattern matches @username at the start of the body or after a
+	// character that cannot be part of an email address
+	mentionPattern = regexp.MustCompile(`(?:^|[^\w@.])@([A-Za-z0-9_][A-Za-z0-9_.-]*)`)
+	// codePattern matches Markdown code blocks and spans, where an @ is not a mention
+	codePattern = regexp.MustCompile("(?s)```.*?```|`[^`\n]*`")
+)
+
+type CommentUsecase struct {
+	commentRepo      domain.CommentRepository
+	taskRepo         domain.TaskRepository
+	userRepo         domain.UserRepository
+	notificationRepo domain.NotificationRepository
+}
+
+func NewCommentUsecase(commentRepo domain.CommentRepository, taskRepo domain.TaskRepository, userRepo domain.UserRepository, notificationRepo domain.NotificationRepository) *CommentUsecase {
+	return &CommentUsecase{commentRepo: commentRepo, taskRepo: taskRepo, userRepo: userRepo, notificationRepo: notificationRepo}
+}
+
+func (cu *CommentUsecase) AddComment(ctx context.Context, actor domain.User, taskID primitive.ObjectID, parentID *primitive.ObjectID, body string) (comment domain.Comment, err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "CommentUsecase.AddComment")
+	defer infrastructure.EndSpan(span, &err)
+
+	task, err := visibleTask(ctx, cu.taskRepo, actor, taskID)
+	if err != nil {
+		return domain.Comment{}, err
+	}
+	if body, err = validateComment(body); err != nil {
+		return domain.Comment{}, err
+	}
+	if parentID != nil {
+		parent, err := cu.commentRepo.GetComment(ctx, *parentID)
+		if errors.Is(err, mongo.ErrNoDocuments) || (err == nil && parent.TaskID != taskID) {
+			return domain.Comment{}, fmt.Errorf("%w: the comment replied to does not exist on this task", domain.ErrInvalidComment)
+		}
+		if err != nil {
+			return domain.Comment{}, err
+		}
+		if parent.Deleted {
+			return domain.Comment{}, fmt.Errorf("%w: cannot reply to a deleted comment", domain.ErrInvalidComment)
+		}
+	}
+
+	comment = domain.Comment{
+		ID:         primitive.NewObjectID(),
+		TaskID:     taskID,
+		ParentID:   parentID,
+		AuthorID:   actor.ID,
+		AuthorName: actor.Username,
+		Body:       body,
+		CreatedAt:  primitive.NewDateTimeFromTime(time.Now()),
+	}
+	mentioned, err := cu.resolveMentions(ctx, body)
+	if err != nil {
+		return domain.Comment{}, err
+	}
+	comment.Mentions = mentions(mentioned)
+
+	if err := cu.commentRepo.CreateComment(ctx, comment); err != nil {
+		return domain.Comment{}, err
+	}
+	cu.notifyMentions(ctx, task, comment, mentioned)
+
+	slog.InfoContext(ctx, "comment added", slog.String("comment_id", comment.ID.Hex()), slog.String("task_id", taskID.Hex()), slog.Int("mentions", len(comment.Mentions)))
+	return comment, nil
+}
+
+func (cu *CommentUsecase) GetComments(ctx context.Context, actor domain.User, taskID primitive.ObjectID) (threads []domain.Comment, err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "CommentUsecase.GetComments")
+	defer infrastructure.EndSpan(span, &err)
+
+	if _, err := visibleTask(ctx, cu.taskRepo, actor, taskID); err != nil {
+		return nil, err
+	}
+	comments, err := cu.commentRepo.GetComments(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	return buildThreads(comments), nil
+}
+
+func (cu *CommentUsecase) UpdateComment(ctx context.Context, actor domain.User, taskID, id primitive.ObjectID, body string) (comment domain.Comment, err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "CommentUsecase.UpdateComment")
+	defer infrastructure.EndSpan(span, &err)
+
+	task, comment, err := cu.taskComment(ctx, actor, taskID, id)
+	if err != nil {
+		return domain.Comment{}, err
+	}
+	if comment.AuthorID != actor.ID {
+		return domain.Comment{}, fmt.Errorf("%w: you can only edit your own comments", domain.ErrForbidden)
+	}
+	if comment.Deleted {
+		return domain.Comment{}, fmt.Errorf("%w: a deleted comment cannot be edited", domain.ErrInvalidComment)
+	}
+	if body, err = validateComment(body); err != nil {
+		return domain.Comment{}, err
+	}
+
+	mentioned, err := cu.resolveMentions(ctx, body)
+	if err != nil {
+		return domain.Comment{}, err
+	}
+	comment.Body = body
+	comment.Mentions = mentions(mentioned)
+	comment.EditedAt = primitive.NewDateTimeFromTime(time.Now())
+	if err := cu.commentRepo.UpdateComment(ctx, comment); err != nil {
+		return domain.Comment{}, err
+	}
+	// Users mentioned before the edit were notified already
+	cu.notifyMentions(ctx, task, comment, mentioned)
+
+	slog.InfoContext(ctx, "comment edited", slog.String("comment_id", id.Hex()))
+	return comment, nil
+}
+
+// DeleteComment removes the comment, or blanks it when it has replies. Admins
+// may delete the comments of users, and root users those of users and admins.
+func (cu *CommentUsecase) DeleteComment(ctx context.Context, actor domain.User, taskID, id primitive.ObjectID) (err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "CommentUsecase.DeleteComment")
+	defer infrastructure.EndSpan(span, &err)
+
+	_, comment, err := cu.taskComment(ctx, actor, taskID, id)
+	if err != nil {
+		return err
+	}
+	if comment.Deleted {
+		return mongo.ErrNoDocuments
+	}
+	if comment.AuthorID != actor.ID {
+		// A missing author no longer holds a role
+		author, _ := cu.userRepo.GetUserById(ctx, comment.AuthorID)
+		if !outranks(actor, author) {
+			return fmt.Errorf("%w: you can only delete your own comments or those of users below your role", domain.ErrForbidden)
+		}
+	}
+
+	replies, err := cu.commentRepo.CountReplies(ctx, id)
+	if err != nil {
+		return err
+	}
+	if replies == 0 {
+		err = cu.deleteWithTombstones(ctx, comment)
+	} else {
+		comment.Body = ""
+		comment.Mentions = nil
+		comment.Deleted = true
+		err = cu.commentRepo.UpdateComment(ctx, comment)
+	}
+	if err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "comment deleted", slog.String("comment_id", id.Hex()), slog.Bool("kept_for_replies", replies > 0))
+	return nil
+}
+
+// deleteWithTombstones removes comment, which has no replies, and then every
+// deleted ancestor that was only kept for it.
+func (cu *CommentUsecase) deleteWithTombstones(ctx context.Context, comment domain.Comment) error {
+	for {
+		if err := cu.commentRepo.DeleteComment(ctx, comment.ID); err != nil {
+			return err
+		}
+		if comment.ParentID == nil {
+			return nil
+		}
+
+		parent, err := cu.commentRepo.GetComment(ctx, *comment.ParentID)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !parent.Deleted {
+			return nil
+		}
+		replies, err := cu.commentRepo.CountReplies(ctx, parent.ID)
+		if err != nil || replies > 0 {
+			return err
+		}
+		comment = parent
+	}
+}
+
+// taskComment returns the task and one of its comments when actor may see them.
+func (cu *CommentUsecase) taskComment(ctx context.Context, actor domain.User, taskID, id primitive.ObjectID) (domain.Task, domain.Comment, error) {
+	task, err := visibleTask(ctx, cu.taskRepo, actor, taskID)
+	if err != nil {
+		return domain.Task{}, domain.Comment{}, err
+	}
+	comment, err := cu.commentRepo.GetComment(ctx, id)
+	if err != nil {
+		return domain.Task{}, domain.Comment{}, err
+	}
+	if comment.TaskID != taskID {
+		return domain.Task{}, domain.Comment{}, mongo.ErrNoDocuments
+	}
+	return task, comment, nil
+}
+
+// resolveMentions returns the users named in body, in order of first mention.
+// Names that belong to no user are plain text.
+func (cu *CommentUsecase) resolveMentions(ctx context.Context, body string) ([]domain.User, error) {
+	var users []domain.User
+	seen := make(map[string]bool)
+	for _, username := range parseMentions(body) {
+		if seen[username] {
+			continue
+		}
+		seen[username] = true
+
+		user, err := cu.userRepo.GetUserByUsername(ctx, username)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+// notifyMentions tells the mentioned users who can see task about comment.
+// Each user is notified once per comment, and failures only get logged since
+// the comment is already stored.
+func (cu *CommentUsecase) notifyMentions(ctx context.Context, task domain.Task, comment domain.Comment, mentioned []domain.User) {
+	for _, user := range mentioned {
+		if user.ID == comment.AuthorID || !canSeeTask(user, task) {
+			continue
+		}
+		_, err := cu.notificationRepo.CreateNotification(ctx, domain.Notification{
+			ID:        primitive.NewObjectID(),
+			UserID:    user.ID,
+			TaskID:    task.ID,
+			Kind:      domain.NotificationMention,
+			Message:   fmt.Sprintf("%s mentioned you in a comment on task %q.", comment.AuthorName, task.Title),
+			DueDate:   task.DueDate,
+			CreatedAt: primitive.NewDateTimeFromTime(time.Now()),
+			Key:       fmt.Sprintf("%s:%s:%s", comment.ID.Hex(), domain.NotificationMention, user.ID.Hex()),
+		})
+		if err != nil {
+			slog.WarnContext(ctx, "mention notification failed", slog.String("comment_id", comment.ID.Hex()), slog.String("target_user_id", user.ID.Hex()), slog.Any("error", err))
+		}
+	}
+}
+
+// validateComment trims body and checks that it is neither blank nor too long.
+func validateComment(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return "", fmt.Errorf("%w: body cannot be empty", domain.ErrInvalidComment)
+	}
+	if len(body) > domain.MaxCommentLength {
+		return "", fmt.Errorf("%w: body cannot be longer than %d bytes", domain.ErrInvalidComment, domain.MaxCommentLength)
+	}
+	return body, nil
+}
+
+// parseMentions returns the usernames mentioned in a Markdown body, skipping
+// code. A trailing dot or dash is taken as punctuation.
+func parseMentions(body string) []string {
+	var usernames []string
+	for _, match := range mentionPattern.FindAllStringSubmatch(codePattern.ReplaceAllString(body, " "), -1) {
+		if username := strings.TrimRight(match[1], ".-"); username != "" {
+			usernames = append(usernames, username)
+		}
+	}
+	return usernames
+}
+
+// mentions links the mentioned users in a comment.
+func mentions(users []domain.User) []domain.CommentMention {
+	mentions := []domain.CommentMention{}
+	for _, user := range users {
+		mentions = append(mentions, domain.CommentMention{UserID: user.ID, Username: user.Username})
+	}
+	return mentions
+}
+
+// buildThreads nests the replies in comments, which are oldest first, under
+// the comments they answer. Replies whose parent is missing become top-level.
+func buildThreads(comments []domain.Comment) []domain.Comment {
+	children := make(map[primitive.ObjectID][]int)
+	exists := make(map[primitive.ObjectID]bool)
+	for _, comment := range comments {
+		exists[comment.ID] = true
+	}
+
+	var roots []int
+	for i, comment := range comments {
+		if comment.ParentID != nil && exists[*comment.ParentID] {
+			children[*comment.ParentID] = append(children[*comment.ParentID], i)
+		} else {
+			roots = append(roots, i)
+		}
+	}
+
+	var nest func(i int) domain.Comment
+	nest = func(i int) domain.Comment {
+		comment := comments[i]
+		for _, child := range children[comment.ID] {
+			comment.Replies = append(comment.Replies, nest(child))
+		}
+		return comment
+	}
+
+	threads := []domain.Comment{}
+	for _, i := range roots {
+		threads = append(threads, nest(i))
+	}
+	return threads
+}
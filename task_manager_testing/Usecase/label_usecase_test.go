@@ -0,0 +1,161 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	usecase "task_manager_testing/Usecase"
+	"task_manager_testing/domain"
+	"task_manager_testing/mocks"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// LabelUsecaseSuite defines the suite for label usecase tests
+type LabelUsecaseSuite struct {
+	suite.Suite
+	labelRepo    *mocks.LabelRepository
+	taskRepo     *mocks.TaskRepository
+	userRepo     *mocks.UserRepository
+	tasks        *mocks.TaskUsecase
+	labelUsecase *usecase.LabelUsecase
+
+	owner  domain.User
+	admin  domain.User
+	task   domain.Task
+	global domain.Label
+	mine   domain.Label
+	theirs domain.Label
+}
+
+// SetupTest sets up the necessary resources before each test
+func (suite *LabelUsecaseSuite) SetupTest() {
+	suite.labelRepo = &mocks.LabelRepository{}
+	suite.taskRepo = &mocks.TaskRepository{}
+	suite.userRepo = &mocks.UserRepository{}
+	suite.tasks = &mocks.TaskUsecase{}
+	suite.labelUsecase = usecase.NewLabelUsecase(suite.labelRepo, suite.taskRepo, suite.userRepo, suite.tasks)
+
+	suite.owner = domain.User{ID: primitive.NewObjectID(), Username: "alice", Role: "user"}
+	suite.admin = domain.User{ID: primitive.NewObjectID(), Username: "carol", Role: "admin"}
+	other := primitive.NewObjectID()
+	suite.global = domain.Label{ID: primitive.NewObjectID(), Name: "Bug", Scope: domain.LabelScopeGlobal}
+	suite.mine = domain.Label{ID: primitive.NewObjectID(), Name: "Later", Scope: domain.LabelScopePersonal, OwnerID: &suite.owner.ID}
+	suite.theirs = domain.Label{ID: primitive.NewObjectID(), Name: "Review", Scope: domain.LabelScopePersonal, OwnerID: &other}
+	suite.task = domain.Task{ID: primitive.NewObjectID(), Title: "Labelled", CreatedBy: suite.owner.ID}
+
+	for _, label := range []domain.Label{suite.global, suite.mine, suite.theirs} {
+		suite.labelRepo.On("GetLabel", mock.Anything, label.ID).Return(label, nil).Maybe()
+	}
+	suite.labelRepo.On("GetLabel", mock.Anything, mock.Anything).Return(domain.Label{}, mongo.ErrNoDocuments).Maybe()
+	suite.userRepo.On("GetUserById", mock.Anything, suite.owner.ID).Return(suite.owner, nil).Maybe()
+}
+
+// TearDownTest checks the mock expectations after each test
+func (suite *LabelUsecaseSuite) TearDownTest() {
+	suite.labelRepo.AssertExpectations(suite.T())
+	suite.taskRepo.AssertExpectations(suite.T())
+	suite.userRepo.AssertExpectations(suite.T())
+	suite.tasks.AssertExpectations(suite.T())
+}
+
+// TestCreateLabel tests the scopes, defaults and validation of new labels
+func (suite *LabelUsecaseSuite) TestCreateLabel() {
+	suite.labelRepo.On("CreateLabel", mock.Anything, mock.AnythingOfType("domain.Label")).Return(nil).Twice()
+
+	label, err := suite.labelUsecase.CreateLabel(context.Background(), suite.owner, "  Needs Design ", "", "")
+	suite.Require().NoError(err)
+	suite.Equal("Needs Design", label.Name)
+	suite.Equal("needs design", label.NameKey)
+	suite.Equal("#808080", label.Color)
+	suite.Equal(domain.LabelScopePersonal, label.Scope)
+	suite.Equal(&suite.owner.ID, label.OwnerID)
+
+	label, err = suite.labelUsecase.CreateLabel(context.Background(), suite.admin, "Bug", "#D73A4A", domain.LabelScopeGlobal)
+	suite.Require().NoError(err)
+	suite.Equal("#d73a4a", label.Color)
+	suite.Nil(label.OwnerID)
+
+	_, err = suite.labelUsecase.CreateLabel(context.Background(), suite.owner, "Bug", "", domain.LabelScopeGlobal)
+	suite.ErrorIs(err, domain.ErrForbidden)
+
+	_, err = suite.labelUsecase.CreateLabel(context.Background(), suite.owner, "Bug", "red", "")
+	suite.ErrorIs(err, domain.ErrInvalidLabel)
+
+	_, err = suite.labelUsecase.CreateLabel(context.Background(), suite.owner, " ", "", "")
+	suite.ErrorIs(err, domain.ErrInvalidLabel)
+
+	_, err = suite.labelUsecase.CreateLabel(context.Background(), suite.owner, "Bug", "", "team")
+	suite.ErrorIs(err, domain.ErrInvalidLabel)
+}
+
+// TestManageLabels tests who may change and delete labels
+func (suite *LabelUsecaseSuite) TestManageLabels() {
+	name := "Defect"
+	suite.labelRepo.On("UpdateLabel", mock.Anything, mock.MatchedBy(func(label domain.Label) bool {
+		return label.ID == suite.global.ID && label.Name == name
+	})).Return(nil).Once()
+	suite.taskRepo.On("RemoveLabel", mock.Anything, suite.mine.ID).Return(nil).Once()
+	suite.labelRepo.On("DeleteLabel", mock.Anything, suite.mine.ID).Return(nil).Once()
+
+	_, err := suite.labelUsecase.UpdateLabel(context.Background(), suite.owner, suite.global.ID, domain.LabelUpdate{Name: &name})
+	suite.ErrorIs(err, domain.ErrForbidden)
+
+	label, err := suite.labelUsecase.UpdateLabel(context.Background(), suite.admin, suite.global.ID, domain.LabelUpdate{Name: &name})
+	suite.Require().NoError(err)
+	suite.Equal("Defect", label.Name)
+
+	err = suite.labelUsecase.DeleteLabel(context.Background(), suite.owner, suite.theirs.ID)
+	suite.ErrorIs(err, mongo.ErrNoDocuments)
+
+	err = suite.labelUsecase.DeleteLabel(context.Background(), suite.owner, suite.mine.ID)
+	suite.NoError(err)
+}
+
+// TestSetTaskLabels tests that labels other users put on the task are kept
+func (suite *LabelUsecaseSuite) TestSetTaskLabels() {
+	suite.task.Labels = []primitive.ObjectID{suite.theirs.ID, suite.global.ID}
+	suite.taskRepo.On("GetTaskById", mock.Anything, suite.task.ID).Return(suite.task, nil)
+	expected := []primitive.ObjectID{suite.theirs.ID, suite.mine.ID}
+	suite.tasks.On("SetTaskLabels", mock.Anything, suite.task.ID, expected).Return(nil).Once()
+	suite.tasks.On("GetTaskById", mock.Anything, suite.task.ID).Return(domain.Task{ID: suite.task.ID, Labels: expected}, nil).Once()
+
+	task, err := suite.labelUsecase.SetTaskLabels(context.Background(), suite.owner, suite.task.ID, []primitive.ObjectID{suite.mine.ID, suite.mine.ID})
+	suite.Require().NoError(err)
+	suite.Equal(expected, task.Labels)
+
+	_, err = suite.labelUsecase.SetTaskLabels(context.Background(), suite.owner, suite.task.ID, []primitive.ObjectID{primitive.NewObjectID()})
+	suite.ErrorIs(err, domain.ErrInvalidLabel)
+}
+
+// TestTaskLabelAccess tests who may label a task
+func (suite *LabelUsecaseSuite) TestTaskLabelAccess() {
+	bob := domain.User{ID: primitive.NewObjectID(), Username: "bob", Role: "user"}
+	root := domain.User{ID: primitive.NewObjectID(), Username: "dave", Role: "root"}
+	byRoot := domain.Task{ID: primitive.NewObjectID(), CreatedBy: root.ID}
+	suite.taskRepo.On("GetTaskById", mock.Anything, suite.task.ID).Return(suite.task, nil)
+	suite.taskRepo.On("GetTaskById", mock.Anything, byRoot.ID).Return(byRoot, nil)
+	suite.userRepo.On("GetUserById", mock.Anything, root.ID).Return(root, nil)
+	suite.tasks.On("SetTaskLabels", mock.Anything, suite.task.ID, []primitive.ObjectID{suite.global.ID}).Return(nil).Once()
+	suite.tasks.On("GetTaskById", mock.Anything, suite.task.ID).Return(suite.task, nil).Once()
+
+	_, err := suite.labelUsecase.AddTaskLabel(context.Background(), bob, suite.task.ID, suite.global.ID)
+	suite.ErrorIs(err, mongo.ErrNoDocuments)
+
+	_, err = suite.labelUsecase.AddTaskLabel(context.Background(), suite.admin, byRoot.ID, suite.global.ID)
+	suite.ErrorIs(err, domain.ErrForbidden)
+
+	_, err = suite.labelUsecase.AddTaskLabel(context.Background(), suite.admin, suite.task.ID, suite.global.ID)
+	suite.NoError(err)
+
+	_, err = suite.labelUsecase.RemoveTaskLabel(context.Background(), suite.owner, suite.task.ID, suite.global.ID)
+	suite.ErrorIs(err, mongo.ErrNoDocuments)
+}
+
+// TestLabelUsecaseSuite is the entry point for running the suite tests
+func TestLabelUsecaseSuite(t *testing.T) {
+	suite.Run(t, new(LabelUsecaseSuite))
+}
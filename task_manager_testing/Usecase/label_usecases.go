@@ -0,0 +1,299 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	infrastructure "task_manager_testing/Infrastructure"
+	"task_manager_testing/domain"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// defaultLabelColor is the color of labels created without one.
+const defaultLabelColor = "#808080"
+
+// maxLabelNameLength is the longest label name, in characters.
+const maxLabelNameLength = 50
+
+// colorPattern matches #rrggbb hex colors.
+var colorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+type LabelUsecase struct {
+	labelRepo domain.LabelRepository
+	taskRepo  domain.TaskRepository
+	userRepo  domain.UserRepository
+	// tasks stores the labels of tasks so that the change is published like
+	// any other task update
+	tasks domain.TaskUsecase
+}
+
+func NewLabelUsecase(labelRepo domain.LabelRepository, taskRepo domain.TaskRepository, userRepo domain.UserRepository, tasks domain.TaskUsecase) *LabelUsecase {
+	return &LabelUsecase{labelRepo: labelRepo, taskRepo: taskRepo, userRepo: userRepo, tasks: tasks}
+}
+
+func (lu *LabelUsecase) CreateLabel(ctx context.Context, actor domain.User, name, color, scope string) (label domain.Label, err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "LabelUsecase.CreateLabel")
+	defer infrastructure.EndSpan(span, &err)
+
+	label = domain.Label{
+		ID:        primitive.NewObjectID(),
+		Scope:     scope,
+		CreatedAt: primitive.NewDateTimeFromTime(time.Now()),
+	}
+	switch scope {
+	case "", domain.LabelScopePersonal:
+		label.Scope = domain.LabelScopePersonal
+		label.OwnerID = &actor.ID
+	case domain.LabelScopeGlobal:
+		if !canManageGlobalLabels(actor) {
+			return domain.Label{}, fmt.Errorf("%w: only admins and root users can create global labels", domain.ErrForbidden)
+		}
+	default:
+		return domain.Label{}, fmt.Errorf("%w: scope must be %q or %q", domain.ErrInvalidLabel, domain.LabelScopeGlobal, domain.LabelScopePersonal)
+	}
+	if err := setLabelFields(&label, name, color); err != nil {
+		return domain.Label{}, err
+	}
+
+	if err := lu.labelRepo.CreateLabel(ctx, label); err != nil {
+		return domain.Label{}, err
+	}
+
+	slog.InfoContext(ctx, "label created", slog.String("label_id", label.ID.Hex()), slog.String("scope", label.Scope))
+	return label, nil
+}
+
+// GetLabels returns the global labels and actor's personal labels.
+func (lu *LabelUsecase) GetLabels(ctx context.Context, actor domain.User) (labels []domain.Label, err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "LabelUsecase.GetLabels")
+	defer infrastructure.EndSpan(span, &err)
+
+	return lu.labelRepo.GetLabels(ctx, actor.ID)
+}
+
+func (lu *LabelUsecase) UpdateLabel(ctx context.Context, actor domain.User, id primitive.ObjectID, update domain.LabelUpdate) (label domain.Label, err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "LabelUsecase.UpdateLabel")
+	defer infrastructure.EndSpan(span, &err)
+
+	label, err = lu.manageableLabel(ctx, actor, id)
+	if err != nil {
+		return domain.Label{}, err
+	}
+	name, color := label.Name, label.Color
+	if update.Name != nil {
+		name = *update.Name
+	}
+	if update.Color != nil {
+		color = *update.Color
+	}
+	if err := setLabelFields(&label, name, color); err != nil {
+		return domain.Label{}, err
+	}
+
+	if err := lu.labelRepo.UpdateLabel(ctx, label); err != nil {
+		return domain.Label{}, err
+	}
+
+	slog.InfoContext(ctx, "label updated", slog.String("label_id", id.Hex()))
+	return label, nil
+}
+
+// DeleteLabel takes the label off every task before removing it, so that a
+// failure can be retried without leaving tasks with an unknown label.
+func (lu *LabelUsecase) DeleteLabel(ctx context.Context, actor domain.User, id primitive.ObjectID) (err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "LabelUsecase.DeleteLabel")
+	defer infrastructure.EndSpan(span, &err)
+
+	if _, err := lu.manageableLabel(ctx, actor, id); err != nil {
+		return err
+	}
+	if err := lu.taskRepo.RemoveLabel(ctx, id); err != nil {
+		return err
+	}
+	if err := lu.labelRepo.DeleteLabel(ctx, id); err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "label deleted", slog.String("label_id", id.Hex()))
+	return nil
+}
+
+// SetTaskLabels replaces the labels actor can use on the task. The personal
+// labels other users put on the task are kept.
+func (lu *LabelUsecase) SetTaskLabels(ctx context.Context, actor domain.User, taskID primitive.ObjectID, labelIDs []primitive.ObjectID) (task domain.Task, err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "LabelUsecase.SetTaskLabels")
+	defer infrastructure.EndSpan(span, &err)
+
+	task, err = editableTask(ctx, lu.taskRepo, lu.userRepo, actor, taskID)
+	if err != nil {
+		return domain.Task{}, err
+	}
+
+	var labels []primitive.ObjectID
+	for _, id := range task.Labels {
+		if _, err := usableLabel(ctx, lu.labelRepo, actor, id); errors.Is(err, mongo.ErrNoDocuments) {
+			labels = append(labels, id)
+		} else if err != nil {
+			return domain.Task{}, err
+		}
+	}
+	for _, id := range labelIDs {
+		if containsID(labels, id) {
+			continue
+		}
+		if _, err := lu.labelFor(ctx, actor, task, id); err != nil {
+			return domain.Task{}, err
+		}
+		labels = append(labels, id)
+	}
+	return lu.storeTaskLabels(ctx, taskID, labels)
+}
+
+func (lu *LabelUsecase) AddTaskLabel(ctx context.Context, actor domain.User, taskID, labelID primitive.ObjectID) (task domain.Task, err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "LabelUsecase.AddTaskLabel")
+	defer infrastructure.EndSpan(span, &err)
+
+	task, err = editableTask(ctx, lu.taskRepo, lu.userRepo, actor, taskID)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	if _, err := lu.labelFor(ctx, actor, task, labelID); err != nil {
+		return domain.Task{}, err
+	}
+	if containsID(task.Labels, labelID) {
+		return lu.tasks.GetTaskById(ctx, taskID)
+	}
+	return lu.storeTaskLabels(ctx, taskID, append(task.Labels, labelID))
+}
+
+func (lu *LabelUsecase) RemoveTaskLabel(ctx context.Context, actor domain.User, taskID, labelID primitive.ObjectID) (task domain.Task, err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "LabelUsecase.RemoveTaskLabel")
+	defer infrastructure.EndSpan(span, &err)
+
+	task, err = editableTask(ctx, lu.taskRepo, lu.userRepo, actor, taskID)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	if _, err := usableLabel(ctx, lu.labelRepo, actor, labelID); err != nil {
+		return domain.Task{}, err
+	}
+	if !containsID(task.Labels, labelID) {
+		return domain.Task{}, mongo.ErrNoDocuments
+	}
+
+	labels := make([]primitive.ObjectID, 0, len(task.Labels)-1)
+	for _, id := range task.Labels {
+		if id != labelID {
+			labels = append(labels, id)
+		}
+	}
+	return lu.storeTaskLabels(ctx, taskID, labels)
+}
+
+// storeTaskLabels checks the number of labels, stores them and returns the
+// updated task.
+func (lu *LabelUsecase) storeTaskLabels(ctx context.Context, taskID primitive.ObjectID, labels []primitive.ObjectID) (domain.Task, error) {
+	if len(labels) > domain.MaxTaskLabels {
+		return domain.Task{}, fmt.Errorf("%w: a task can have at most %d labels", domain.ErrInvalidLabel, domain.MaxTaskLabels)
+	}
+	if err := lu.tasks.SetTaskLabels(ctx, taskID, labels); err != nil {
+		return domain.Task{}, err
+	}
+	return lu.tasks.GetTaskById(ctx, taskID)
+}
+
+// labelFor returns a label actor may put on task. Labels actor cannot use,
+// and labels of another organization than the task's, which only root users
+// see, are reported as invalid input rather than as a missing task.
+func (lu *LabelUsecase) labelFor(ctx context.Context, actor domain.User, task domain.Task, id primitive.ObjectID) (domain.Label, error) {
+	label, err := usableLabel(ctx, lu.labelRepo, actor, id)
+	if errors.Is(err, mongo.ErrNoDocuments) || (err == nil && !domain.SameOrg(label.OrgID, task.OrgID)) {
+		return domain.Label{}, fmt.Errorf("%w: label %s does not exist", domain.ErrInvalidLabel, id.Hex())
+	}
+	return label, err
+}
+
+// manageableLabel returns a label actor may change: their own personal
+// labels, and global labels for admins and root users.
+func (lu *LabelUsecase) manageableLabel(ctx context.Context, actor domain.User, id primitive.ObjectID) (domain.Label, error) {
+	label, err := usableLabel(ctx, lu.labelRepo, actor, id)
+	if err != nil {
+		return domain.Label{}, err
+	}
+	if label.Scope == domain.LabelScopeGlobal && !canManageGlobalLabels(actor) {
+		return domain.Label{}, fmt.Errorf("%w: only admins and root users can change global labels", domain.ErrForbidden)
+	}
+	return label, nil
+}
+
+// usableLabel returns the label when it is global or one of actor's personal
+// labels, and reports other labels as missing.
+func usableLabel(ctx context.Context, labelRepo domain.LabelRepository, actor domain.User, id primitive.ObjectID) (domain.Label, error) {
+	label, err := labelRepo.GetLabel(ctx, id)
+	if err != nil {
+		return domain.Label{}, err
+	}
+	if label.OwnerID != nil && *label.OwnerID != actor.ID {
+		return domain.Label{}, mongo.ErrNoDocuments
+	}
+	return label, nil
+}
+
+func canManageGlobalLabels(actor domain.User) bool {
+	return actor.Role == "admin" || actor.Role == "root"
+}
+
+// setLabelFields validates name and color and stores them in label. A blank
+// color falls back to a neutral grey.
+func setLabelFields(label *domain.Label, name, color string) error {
+	name, err := validateName(name, maxLabelNameLength)
+	if err != nil {
+		return fmt.Errorf("%w: %v", domain.ErrInvalidLabel, err)
+	}
+	color = strings.TrimSpace(color)
+	if color == "" {
+		color = defaultLabelColor
+	}
+	if !colorPattern.MatchString(color) {
+		return fmt.Errorf("%w: color must be a hex color like #1f6feb", domain.ErrInvalidLabel)
+	}
+
+	label.Name = name
+	label.NameKey = strings.ToLower(name)
+	label.Color = strings.ToLower(color)
+	return nil
+}
+
+// validateName trims name and checks that it is present, at most max
+// characters long and free of control characters.
+func validateName(name string, max int) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", errors.New("a name is required")
+	}
+	if utf8.RuneCountInString(name) > max {
+		return "", fmt.Errorf("the name cannot be longer than %d characters", max)
+	}
+	if strings.IndexFunc(name, unicode.IsControl) >= 0 {
+		return "", errors.New("the name contains control characters")
+	}
+	return name, nil
+}
+
+// containsID reports whether ids contains id.
+func containsID(ids []primitive.ObjectID, id primitive.ObjectID) bool {
+	for _, existing := range ids {
+		if existing == id {
+			return true
+		}
+	}
+	return false
+}
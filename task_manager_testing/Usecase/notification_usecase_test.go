@@ -0,0 +1,84 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	usecase "task_manager_testing/Usecase"
+	"task_manager_testing/domain"
+	"task_manager_testing/mocks"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// NotificationUsecaseSuite defines the suite for notification usecase tests
+type NotificationUsecaseSuite struct {
+	suite.Suite
+	notificationRepo    *mocks.NotificationRepository
+	taskRepo            *mocks.TaskRepository
+	notificationUsecase *usecase.NotificationUsecase
+}
+
+// SetupTest sets up the necessary resources before each test
+func (suite *NotificationUsecaseSuite) SetupTest() {
+	suite.notificationRepo = &mocks.NotificationRepository{}
+	suite.taskRepo = &mocks.TaskRepository{}
+	suite.notificationUsecase = usecase.NewNotificationUsecase(suite.notificationRepo, suite.taskRepo)
+}
+
+// TearDownTest checks the mock expectations after each test
+func (suite *NotificationUsecaseSuite) TearDownTest() {
+	suite.notificationRepo.AssertExpectations(suite.T())
+	suite.taskRepo.AssertExpectations(suite.T())
+}
+
+// TestCheckDueTasks tests that due and overdue tasks produce keyed notifications
+func (suite *NotificationUsecaseSuite) TestCheckDueTasks() {
+	now := time.Date(2030, 1, 15, 12, 0, 0, 0, time.UTC)
+	dueSoon := domain.Task{ID: primitive.NewObjectID(), Title: "Soon", Status: "Not Started", CreatedBy: primitive.NewObjectID(), DueDate: primitive.NewDateTimeFromTime(now.Add(time.Hour))}
+	overdue := domain.Task{ID: primitive.NewObjectID(), Title: "Late", Status: "In Progress", CreatedBy: primitive.NewObjectID(), DueDate: primitive.NewDateTimeFromTime(now.Add(-time.Hour))}
+	failing := domain.Task{ID: primitive.NewObjectID(), Title: "Broken", Status: "In Progress", DueDate: primitive.NewDateTimeFromTime(now.Add(-time.Hour))}
+
+	var stored []domain.Notification
+	suite.taskRepo.On("GetTasksDueBefore", mock.Anything, now.Add(24*time.Hour)).Return([]domain.Task{dueSoon, overdue, failing}, nil)
+	suite.notificationRepo.On("CreateNotification", mock.Anything, mock.MatchedBy(func(n domain.Notification) bool { return n.TaskID == dueSoon.ID })).Return(true, nil).
+		Run(func(args mock.Arguments) { stored = append(stored, args.Get(1).(domain.Notification)) })
+	// The overdue reminder was already sent during an earlier check
+	suite.notificationRepo.On("CreateNotification", mock.Anything, mock.MatchedBy(func(n domain.Notification) bool { return n.TaskID == overdue.ID })).Return(false, nil).
+		Run(func(args mock.Arguments) { stored = append(stored, args.Get(1).(domain.Notification)) })
+	suite.notificationRepo.On("CreateNotification", mock.Anything, mock.MatchedBy(func(n domain.Notification) bool { return n.TaskID == failing.ID })).Return(false, errors.New("write failed"))
+
+	created, err := suite.notificationUsecase.CheckDueTasks(context.Background(), now, 24*time.Hour)
+
+	suite.Error(err)
+	suite.Equal(1, created)
+	suite.Require().Len(stored, 2)
+	suite.Equal(domain.NotificationDueSoon, stored[0].Kind)
+	suite.Equal(dueSoon.CreatedBy, stored[0].UserID)
+	suite.Equal(domain.NotificationOverdue, stored[1].Kind)
+	suite.NotEqual(stored[0].Key, stored[1].Key)
+}
+
+// TestGetNotifications tests that the page size is capped
+func (suite *NotificationUsecaseSuite) TestGetNotifications() {
+	userId := primitive.NewObjectID()
+	notifications := []domain.Notification{{ID: primitive.NewObjectID(), UserID: userId}}
+
+	suite.notificationRepo.On("GetNotifications", mock.Anything, userId, true, domain.MaxNotificationsPage).Return(notifications, nil)
+	suite.notificationRepo.On("CountUnread", mock.Anything, userId).Return(int64(1), nil)
+
+	result, unread, err := suite.notificationUsecase.GetNotifications(context.Background(), userId, true, 0)
+
+	suite.Require().NoError(err)
+	suite.Equal(notifications, result)
+	suite.Equal(int64(1), unread)
+}
+
+// TestNotificationUsecaseSuite is the entry point for running the suite tests
+func TestNotificationUsecaseSuite(t *testing.T) {
+	suite.Run(t, new(NotificationUsecaseSuite))
+}
@@ -0,0 +1,113 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	infrastructure "task_manager_testing/Infrastructure"
+	"task_manager_testing/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type NotificationUsecase struct {
+	notificationRepo domain.NotificationRepository
+	taskRepo         domain.TaskRepository
+}
+
+func NewNotificationUsecase(notificationRepo domain.NotificationRepository, taskRepo domain.TaskRepository) *NotificationUsecase {
+	return &NotificationUsecase{notificationRepo: notificationRepo, taskRepo: taskRepo}
+}
+
+// CheckDueTasks records a due-soon and later an overdue notification for each
+// unfinished task. Notifications are keyed by task, kind and due date, so each
+// is sent once, and moving the due date sends new ones.
+func (nu *NotificationUsecase) CheckDueTasks(ctx context.Context, now time.Time, dueSoon time.Duration) (created int, err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "NotificationUsecase.CheckDueTasks")
+	defer infrastructure.EndSpan(span, &err)
+
+	tasks, err := nu.taskRepo.GetTasksDueBefore(ctx, now.Add(dueSoon))
+	if err != nil {
+		return 0, err
+	}
+
+	var errs []error
+	for _, task := range tasks {
+		notification := dueNotification(task, now)
+		stored, err := nu.notificationRepo.CreateNotification(ctx, notification)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if stored {
+			created++
+		}
+	}
+
+	if created > 0 {
+		slog.InfoContext(ctx, "due date notifications created", slog.Int("count", created), slog.Int("tasks", len(tasks)))
+	}
+	return created, errors.Join(errs...)
+}
+
+// dueNotification builds the notification about task at now.
+func dueNotification(task domain.Task, now time.Time) domain.Notification {
+	kind := domain.NotificationDueSoon
+	message := fmt.Sprintf("Task %q is due %s.", task.Title, task.DueDate.Time().UTC().Format(time.RFC1123))
+	if task.IsOverdue(now) {
+		kind = domain.NotificationOverdue
+		message = fmt.Sprintf("Task %q is overdue since %s.", task.Title, task.DueDate.Time().UTC().Format(time.RFC1123))
+	}
+
+	return domain.Notification{
+		ID:        primitive.NewObjectID(),
+		UserID:    task.CreatedBy,
+		TaskID:    task.ID,
+		Kind:      kind,
+		Message:   message,
+		DueDate:   task.DueDate,
+		CreatedAt: primitive.NewDateTimeFromTime(now),
+		Key:       fmt.Sprintf("%s:%s:%d", task.ID.Hex(), kind, int64(task.DueDate)),
+	}
+}
+
+func (nu *NotificationUsecase) GetNotifications(ctx context.Context, userID primitive.ObjectID, unreadOnly bool, limit int) (notifications []domain.Notification, unread int64, err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "NotificationUsecase.GetNotifications")
+	defer infrastructure.EndSpan(span, &err)
+
+	if limit <= 0 || limit > domain.MaxNotificationsPage {
+		limit = domain.MaxNotificationsPage
+	}
+
+	notifications, err = nu.notificationRepo.GetNotifications(ctx, userID, unreadOnly, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	unread, err = nu.notificationRepo.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return notifications, unread, nil
+}
+
+func (nu *NotificationUsecase) MarkRead(ctx context.Context, userID, id primitive.ObjectID) (err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "NotificationUsecase.MarkRead")
+	defer infrastructure.EndSpan(span, &err)
+
+	return nu.notificationRepo.MarkRead(ctx, userID, id, time.Now())
+}
+
+func (nu *NotificationUsecase) MarkAllRead(ctx context.Context, userID primitive.ObjectID) (updated int64, err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "NotificationUsecase.MarkAllRead")
+	defer infrastructure.EndSpan(span, &err)
+
+	updated, err = nu.notificationRepo.MarkAllRead(ctx, userID, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	slog.InfoContext(ctx, "notifications marked as read", slog.Int64("count", updated))
+	return updated, nil
+}
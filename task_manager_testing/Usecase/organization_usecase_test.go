@@ -0,0 +1,97 @@
+package usecase_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	usecase "task_manager_testing/Usecase"
+	"task_manager_testing/domain"
+	"task_manager_testing/mocks"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// OrganizationUsecaseSuite defines the suite for organization usecase tests
+type OrganizationUsecaseSuite struct {
+	suite.Suite
+	orgRepo             *mocks.OrganizationRepository
+	organizationUsecase *usecase.OrganizationUsecase
+
+	org   domain.Organization
+	root  domain.User
+	admin domain.User
+}
+
+// SetupTest sets up the necessary resources before each test
+func (suite *OrganizationUsecaseSuite) SetupTest() {
+	suite.orgRepo = &mocks.OrganizationRepository{}
+	suite.organizationUsecase = usecase.NewOrganizationUsecase(suite.orgRepo)
+
+	suite.org = domain.Organization{ID: primitive.NewObjectID(), Name: "Acme"}
+	suite.root = domain.User{ID: primitive.NewObjectID(), Username: "root", Role: "root"}
+	suite.admin = domain.User{ID: primitive.NewObjectID(), Username: "carol", Role: "admin", OrgID: &suite.org.ID}
+}
+
+// TearDownTest checks the mock expectations after each test
+func (suite *OrganizationUsecaseSuite) TearDownTest() {
+	suite.orgRepo.AssertExpectations(suite.T())
+}
+
+// TestCreateOrganization tests that root users create organizations with a
+// trimmed name and a case-insensitive key
+func (suite *OrganizationUsecaseSuite) TestCreateOrganization() {
+	suite.orgRepo.On("AddOrganization", mock.Anything, mock.MatchedBy(func(org domain.Organization) bool {
+		return org.Name == "Acme Corp" && org.NameKey == "acme corp" && !org.ID.IsZero()
+	})).Return(nil)
+
+	org, err := suite.organizationUsecase.CreateOrganization(context.Background(), suite.root, "  Acme Corp ")
+	suite.Require().NoError(err)
+	suite.Equal("Acme Corp", org.Name)
+}
+
+// TestCreateOrganizationRejected tests the validation and the permissions of
+// organization creation
+func (suite *OrganizationUsecaseSuite) TestCreateOrganizationRejected() {
+	ctx := context.Background()
+	_, err := suite.organizationUsecase.CreateOrganization(ctx, suite.admin, "Acme")
+	suite.ErrorIs(err, domain.ErrForbidden)
+	_, err = suite.organizationUsecase.CreateOrganization(ctx, suite.root, "   ")
+	suite.ErrorIs(err, domain.ErrInvalidOrganization)
+	_, err = suite.organizationUsecase.CreateOrganization(ctx, suite.root, strings.Repeat("a", domain.MaxOrganizationNameLength+1))
+	suite.ErrorIs(err, domain.ErrInvalidOrganization)
+}
+
+// TestGetOrganization tests that users other than root only find their own
+// organization
+func (suite *OrganizationUsecaseSuite) TestGetOrganization() {
+	suite.orgRepo.On("GetOrganizationById", mock.Anything, suite.org.ID).Return(suite.org, nil).Twice()
+
+	ctx := context.Background()
+	org, err := suite.organizationUsecase.GetOrganizationById(ctx, suite.admin, suite.org.ID)
+	suite.Require().NoError(err)
+	suite.Equal(suite.org, org)
+	_, err = suite.organizationUsecase.GetOrganizationById(ctx, suite.root, suite.org.ID)
+	suite.NoError(err)
+	_, err = suite.organizationUsecase.GetOrganizationById(ctx, suite.admin, primitive.NewObjectID())
+	suite.ErrorIs(err, mongo.ErrNoDocuments)
+}
+
+// TestGetAllOrganizations tests that only root users list organizations
+func (suite *OrganizationUsecaseSuite) TestGetAllOrganizations() {
+	suite.orgRepo.On("GetAllOrganizations", mock.Anything).Return([]domain.Organization{suite.org}, nil)
+
+	orgs, err := suite.organizationUsecase.GetAllOrganizations(context.Background(), suite.root)
+	suite.Require().NoError(err)
+	suite.Equal([]domain.Organization{suite.org}, orgs)
+	_, err = suite.organizationUsecase.GetAllOrganizations(context.Background(), suite.admin)
+	suite.ErrorIs(err, domain.ErrForbidden)
+}
+
+// TestOrganizationUsecaseSuite is the entry point for running the suite tests
+func TestOrganizationUsecaseSuite(t *testing.T) {
+	suite.Run(t, new(OrganizationUsecaseSuite))
+}
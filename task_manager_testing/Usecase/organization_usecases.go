@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	infrastructure "task_manager_testing/Infrastructure"
+	"task_manager_testing/domain"
+	"time"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type OrganizationUsecase struct {
+	orgRepo domain.OrganizationRepository
+}
+
+func NewOrganizationUsecase(orgRepo domain.OrganizationRepository) *OrganizationUsecase {
+	return &OrganizationUsecase{orgRepo: orgRepo}
+}
+
+func (ou *OrganizationUsecase) CreateOrganization(ctx context.Context, actor domain.User, name string) (org domain.Organization, err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "OrganizationUsecase.CreateOrganization")
+	defer infrastructure.EndSpan(span, &err)
+
+	if actor.Role != "root" {
+		return domain.Organization{}, fmt.Errorf("%w: only root users can create organizations", domain.ErrForbidden)
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return domain.Organization{}, fmt.Errorf("%w: name is required", domain.ErrInvalidOrganization)
+	}
+	if utf8.RuneCountInString(name) > domain.MaxOrganizationNameLength {
+		return domain.Organization{}, fmt.Errorf("%w: name must be at most %d characters", domain.ErrInvalidOrganization, domain.MaxOrganizationNameLength)
+	}
+
+	org = domain.Organization{
+		ID:        primitive.NewObjectID(),
+		Name:      name,
+		NameKey:   strings.ToLower(name),
+		CreatedAt: primitive.NewDateTimeFromTime(time.Now()),
+	}
+	if err := ou.orgRepo.AddOrganization(ctx, org); err != nil {
+		return domain.Organization{}, err
+	}
+
+	slog.InfoContext(ctx, "organization created", slog.String("org_id", org.ID.Hex()), slog.String("name", name))
+	return org, nil
+}
+
+// GetOrganizationById returns the organization with id. Users other than root
+// only find their own organization.
+func (ou *OrganizationUsecase) GetOrganizationById(ctx context.Context, actor domain.User, id primitive.ObjectID) (org domain.Organization, err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "OrganizationUsecase.GetOrganizationById")
+	defer infrastructure.EndSpan(span, &err)
+
+	if actor.Role != "root" && (actor.OrgID == nil || *actor.OrgID != id) {
+		return domain.Organization{}, mongo.ErrNoDocuments
+	}
+	return ou.orgRepo.GetOrganizationById(ctx, id)
+}
+
+func (ou *OrganizationUsecase) GetAllOrganizations(ctx context.Context, actor domain.User) (orgs []domain.Organization, err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "OrganizationUsecase.GetAllOrganizations")
+	defer infrastructure.EndSpan(span, &err)
+
+	if actor.Role != "root" {
+		return nil, fmt.Errorf("%w: only root users can list organizations", domain.ErrForbidden)
+	}
+	return ou.orgRepo.GetAllOrganizations(ctx)
+}
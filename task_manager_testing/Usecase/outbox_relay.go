@@ -0,0 +1,153 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	infrastructure "task_manager_testing/Infrastructure"
+	"task_manager_testing/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// TaskEventSinks are the named destinations of task events, such as the live
+// streams and the webhooks.
+type TaskEventSinks map[string]domain.TaskEventPublisher
+
+// PublishTaskEvent sends event to every sink and reports every failure.
+func (sinks TaskEventSinks) PublishTaskEvent(ctx context.Context, event domain.TaskEvent) error {
+	var errs []error
+	for _, name := range sinks.names() {
+		if err := sinks[name].PublishTaskEvent(ctx, event); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// names returns the sink names in a stable order.
+func (sinks TaskEventSinks) names() []string {
+	names := make([]string, 0, len(sinks))
+	for name := range sinks {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// OutboxPolicy controls how often the outbox is polled and how failed
+// deliveries are retried.
+type OutboxPolicy struct {
+	PollInterval time.Duration
+	// Lease is how long a claimed message is hidden from other relays
+	Lease time.Duration
+	// InitialBackoff is the wait after the first failure; it doubles after
+	// every further failure up to MaxBackoff
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// MaxAttempts is how many failed deliveries a message gets before it is
+	// dead lettered; zero retries forever
+	MaxAttempts int
+	// BatchSize is the largest number of messages relayed by one RunOnce
+	BatchSize int
+}
+
+// OutboxRelay delivers the events stored in the outbox to the sinks at least
+// once. A sink that fails gets the event again later; sinks that already
+// received it are skipped, but may see it twice if the relay stops before
+// recording their success. Consumers deduplicate by event ID.
+type OutboxRelay struct {
+	outbox domain.OutboxRepository
+	sinks  TaskEventSinks
+	policy OutboxPolicy
+}
+
+func NewOutboxRelay(outbox domain.OutboxRepository, sinks TaskEventSinks, policy OutboxPolicy) *OutboxRelay {
+	return &OutboxRelay{outbox: outbox, sinks: sinks, policy: policy}
+}
+
+// Run relays due messages right away and then every poll interval until ctx is done.
+func (or *OutboxRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(or.policy.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		or.RunOnce(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce relays the messages that are due now.
+func (or *OutboxRelay) RunOnce(ctx context.Context) {
+	if _, err := or.Relay(ctx, time.Now()); err != nil && ctx.Err() == nil {
+		slog.ErrorContext(ctx, "outbox relay failed", slog.String("error", err.Error()))
+	}
+}
+
+// Relay claims the messages due at now one at a time and delivers them to
+// every sink that has not received them yet.
+func (or *OutboxRelay) Relay(ctx context.Context, now time.Time) (relayed int, err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "OutboxRelay.Relay")
+	defer infrastructure.EndSpan(span, &err)
+
+	for relayed < or.policy.BatchSize {
+		message, err := or.outbox.ClaimMessage(ctx, now, or.policy.Lease)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			break
+		}
+		if err != nil {
+			return relayed, err
+		}
+
+		relayed++
+		if err := or.deliver(ctx, message, now); err != nil {
+			return relayed, err
+		}
+	}
+
+	if relayed > 0 {
+		slog.InfoContext(ctx, "outbox messages relayed", slog.Int("count", relayed))
+	}
+	return relayed, nil
+}
+
+// deliver sends message to the sinks still missing it, then removes it,
+// schedules another attempt or dead letters it after the last attempt.
+func (or *OutboxRelay) deliver(ctx context.Context, message domain.OutboxMessage, now time.Time) error {
+	var errs []error
+	for _, name := range or.sinks.names() {
+		if slices.Contains(message.DeliveredTo, name) {
+			continue
+		}
+		if err := or.sinks[name].PublishTaskEvent(ctx, message.Event); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		message.DeliveredTo = append(message.DeliveredTo, name)
+	}
+	if len(errs) == 0 {
+		return or.outbox.DeleteMessage(ctx, message.ID)
+	}
+
+	message.Attempts++
+	message.LastError = errors.Join(errs...).Error()
+	if or.policy.MaxAttempts > 0 && message.Attempts >= or.policy.MaxAttempts {
+		deadLetteredAt := primitive.NewDateTimeFromTime(now)
+		message.DeadLetteredAt = &deadLetteredAt
+		slog.ErrorContext(ctx, "outbox message dead lettered",
+			slog.String("event_id", message.Event.ID), slog.Int("attempts", message.Attempts), slog.String("error", message.LastError))
+		return or.outbox.DeadLetterMessage(ctx, message)
+	}
+	message.NextAttemptAt = primitive.NewDateTimeFromTime(now.Add(exponentialBackoff(or.policy.InitialBackoff, or.policy.MaxBackoff, message.Attempts)))
+	slog.WarnContext(ctx, "outbox message not delivered",
+		slog.String("event_id", message.Event.ID), slog.Int("attempts", message.Attempts), slog.String("error", message.LastError))
+	return or.outbox.RetryMessage(ctx, message)
+}
@@ -0,0 +1,120 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	usecase "task_manager_testing/Usecase"
+	"task_manager_testing/domain"
+	"task_manager_testing/mocks"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// OutboxRelaySuite defines the suite for outbox relay tests
+type OutboxRelaySuite struct {
+	suite.Suite
+	outbox   *mocks.OutboxRepository
+	streams  *mocks.TaskEventPublisher
+	webhooks *mocks.TaskEventPublisher
+	relay    *usecase.OutboxRelay
+}
+
+var testOutboxPolicy = usecase.OutboxPolicy{
+	PollInterval:   time.Second,
+	Lease:          time.Minute,
+	InitialBackoff: time.Second,
+	MaxBackoff:     time.Minute,
+	MaxAttempts:    5,
+	BatchSize:      10,
+}
+
+// SetupTest sets up the necessary resources before each test
+func (suite *OutboxRelaySuite) SetupTest() {
+	suite.outbox = &mocks.OutboxRepository{}
+	suite.streams = &mocks.TaskEventPublisher{}
+	suite.webhooks = &mocks.TaskEventPublisher{}
+	suite.relay = usecase.NewOutboxRelay(suite.outbox, usecase.TaskEventSinks{"streams": suite.streams, "webhooks": suite.webhooks}, testOutboxPolicy)
+}
+
+// TearDownTest checks the mock expectations after each test
+func (suite *OutboxRelaySuite) TearDownTest() {
+	suite.outbox.AssertExpectations(suite.T())
+	suite.streams.AssertExpectations(suite.T())
+	suite.webhooks.AssertExpectations(suite.T())
+}
+
+// TestRelay tests that messages reach every sink once and failures are retried
+func (suite *OutboxRelaySuite) TestRelay() {
+	now := time.Date(2030, 1, 15, 12, 0, 0, 0, time.UTC)
+	delivered := domain.OutboxMessage{ID: primitive.NewObjectID(), Event: domain.TaskEvent{ID: "event-1"}, DeliveredTo: []string{}}
+	// The webhooks received this event during an earlier attempt
+	retried := domain.OutboxMessage{ID: primitive.NewObjectID(), Event: domain.TaskEvent{ID: "event-2"}, DeliveredTo: []string{"webhooks"}, Attempts: 2}
+
+	suite.outbox.On("ClaimMessage", mock.Anything, now, testOutboxPolicy.Lease).Return(delivered, nil).Once()
+	suite.outbox.On("ClaimMessage", mock.Anything, now, testOutboxPolicy.Lease).Return(retried, nil).Once()
+	suite.outbox.On("ClaimMessage", mock.Anything, now, testOutboxPolicy.Lease).Return(domain.OutboxMessage{}, mongo.ErrNoDocuments).Once()
+	suite.streams.On("PublishTaskEvent", mock.Anything, delivered.Event).Return(nil).Once()
+	suite.webhooks.On("PublishTaskEvent", mock.Anything, delivered.Event).Return(nil).Once()
+	suite.streams.On("PublishTaskEvent", mock.Anything, retried.Event).Return(errors.New("stream closed")).Once()
+	suite.outbox.On("DeleteMessage", mock.Anything, delivered.ID).Return(nil).Once()
+
+	var rescheduled domain.OutboxMessage
+	suite.outbox.On("RetryMessage", mock.Anything, mock.Anything).Return(nil).Once().
+		Run(func(args mock.Arguments) { rescheduled = args.Get(1).(domain.OutboxMessage) })
+
+	relayed, err := suite.relay.Relay(context.Background(), now)
+
+	suite.Require().NoError(err)
+	suite.Equal(2, relayed)
+	suite.Equal(retried.ID, rescheduled.ID)
+	suite.Equal(3, rescheduled.Attempts)
+	suite.Equal([]string{"webhooks"}, rescheduled.DeliveredTo)
+	suite.Contains(rescheduled.LastError, "streams: stream closed")
+	// The third wait doubles twice from one second
+	suite.Equal(now.Add(4*time.Second), rescheduled.NextAttemptAt.Time().UTC())
+}
+
+// TestDeadLetter tests that a message failing its last attempt is dead
+// lettered instead of retried
+func (suite *OutboxRelaySuite) TestDeadLetter() {
+	now := time.Date(2030, 1, 15, 12, 0, 0, 0, time.UTC)
+	failing := domain.OutboxMessage{ID: primitive.NewObjectID(), Event: domain.TaskEvent{ID: "event-1"}, DeliveredTo: []string{"streams"}, Attempts: 4}
+
+	suite.outbox.On("ClaimMessage", mock.Anything, now, testOutboxPolicy.Lease).Return(failing, nil).Once()
+	suite.outbox.On("ClaimMessage", mock.Anything, now, testOutboxPolicy.Lease).Return(domain.OutboxMessage{}, mongo.ErrNoDocuments).Once()
+	suite.webhooks.On("PublishTaskEvent", mock.Anything, failing.Event).Return(errors.New("down")).Once()
+
+	var deadLettered domain.OutboxMessage
+	suite.outbox.On("DeadLetterMessage", mock.Anything, mock.Anything).Return(nil).Once().
+		Run(func(args mock.Arguments) { deadLettered = args.Get(1).(domain.OutboxMessage) })
+
+	relayed, err := suite.relay.Relay(context.Background(), now)
+
+	suite.Require().NoError(err)
+	suite.Equal(1, relayed)
+	suite.Equal(failing.ID, deadLettered.ID)
+	suite.Equal(5, deadLettered.Attempts)
+	suite.Contains(deadLettered.LastError, "webhooks: down")
+	suite.Require().NotNil(deadLettered.DeadLetteredAt)
+	suite.Equal(now, deadLettered.DeadLetteredAt.Time().UTC())
+}
+
+// TestSinks tests that direct publishing reaches every sink and reports failures
+func (suite *OutboxRelaySuite) TestSinks() {
+	event := domain.TaskEvent{ID: "event-1"}
+	suite.streams.On("PublishTaskEvent", mock.Anything, event).Return(nil).Once()
+	suite.webhooks.On("PublishTaskEvent", mock.Anything, event).Return(errors.New("down")).Once()
+
+	err := usecase.TaskEventSinks{"streams": suite.streams, "webhooks": suite.webhooks}.PublishTaskEvent(context.Background(), event)
+	suite.EqualError(err, "webhooks: down")
+}
+
+func TestOutboxRelaySuite(t *testing.T) {
+	suite.Run(t, new(OutboxRelaySuite))
+}
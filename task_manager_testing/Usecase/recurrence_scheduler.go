@@ -0,0 +1,42 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"task_manager_testing/domain"
+	"time"
+)
+
+// RecurrenceScheduler periodically generates the next occurrence of recurring
+// tasks whose latest occurrence is past its due date.
+type RecurrenceScheduler struct {
+	tasks    domain.TaskUsecase
+	interval time.Duration
+}
+
+func NewRecurrenceScheduler(tasks domain.TaskUsecase, interval time.Duration) *RecurrenceScheduler {
+	return &RecurrenceScheduler{tasks: tasks, interval: interval}
+}
+
+// Run generates the due occurrences right away and then every interval until
+// ctx is done. A failed run is logged and retried at the next tick.
+func (rs *RecurrenceScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(rs.interval)
+	defer ticker.Stop()
+
+	for {
+		rs.RunOnce(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce generates the due occurrences once.
+func (rs *RecurrenceScheduler) RunOnce(ctx context.Context) {
+	if _, err := rs.tasks.GenerateDueOccurrences(ctx, time.Now()); err != nil && ctx.Err() == nil {
+		slog.ErrorContext(ctx, "occurrence generation failed", slog.String("error", err.Error()))
+	}
+}
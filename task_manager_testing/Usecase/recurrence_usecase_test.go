@@ -0,0 +1,223 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	usecase "task_manager_testing/Usecase"
+	"task_manager_testing/domain"
+	"task_manager_testing/mocks"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RecurrenceUsecaseSuite defines the suite for recurring task tests
+type RecurrenceUsecaseSuite struct {
+	suite.Suite
+	taskRepo    *mocks.TaskRepository
+	seriesRepo  *mocks.TaskSeriesRepository
+	taskUsecase *usecase.TaskUsecase
+}
+
+// SetupTest sets up the necessary resources before each test
+func (suite *RecurrenceUsecaseSuite) SetupTest() {
+	suite.taskRepo = &mocks.TaskRepository{}
+	suite.seriesRepo = &mocks.TaskSeriesRepository{}
+	suite.taskUsecase = usecase.NewTaskUsecase(suite.taskRepo, nil, nil, nil)
+	suite.taskUsecase.Series = suite.seriesRepo
+}
+
+// TearDownTest checks the mock expectations after each test
+func (suite *RecurrenceUsecaseSuite) TearDownTest() {
+	suite.taskRepo.AssertExpectations(suite.T())
+	suite.seriesRepo.AssertExpectations(suite.T())
+}
+
+// date returns midnight UTC of the day as a DateTime
+func date(year int, month time.Month, day int) primitive.DateTime {
+	return primitive.NewDateTimeFromTime(time.Date(year, month, day, 0, 0, 0, 0, time.UTC))
+}
+
+// TestAddRecurringTask tests that a recurring task starts its series
+func (suite *RecurrenceUsecaseSuite) TestAddRecurringTask() {
+	task := domain.Task{
+		ID: primitive.NewObjectID(), Title: "Standup", Description: "Daily sync", Status: "Not Started",
+		DueDate:    date(2026, time.October, 21),
+		Recurrence: &domain.Recurrence{Frequency: domain.RecurWeekly, ByWeekday: []string{"mo", "fr"}},
+	}
+	suite.seriesRepo.On("CreateSeries", mock.Anything, mock.MatchedBy(func(series domain.TaskSeries) bool {
+		return series.ID == task.ID && series.Start == task.DueDate && series.Occurrences == 1 &&
+			series.Recurrence.Interval == 1 && series.Recurrence.ByWeekday[0] == "MO" && series.Title == "Standup"
+	})).Return(nil).Once()
+	suite.taskRepo.On("AddTask", mock.Anything, mock.MatchedBy(func(stored domain.Task) bool {
+		return *stored.SeriesID == task.ID && stored.Occurrence == 1 && stored.Recurrence.ByWeekday[1] == "FR"
+	})).Return(nil).Once()
+
+	suite.Require().NoError(suite.taskUsecase.AddTask(context.Background(), task))
+
+	task.DueDate = 0
+	suite.Error(suite.taskUsecase.AddTask(context.Background(), task))
+	task.DueDate = date(2026, time.October, 21)
+	task.Recurrence = &domain.Recurrence{Frequency: domain.RecurDaily, ByWeekday: []string{"MO"}}
+	suite.Error(suite.taskUsecase.AddTask(context.Background(), task))
+	task.Recurrence = &domain.Recurrence{Frequency: "yearly"}
+	suite.Error(suite.taskUsecase.AddTask(context.Background(), task))
+}
+
+// TestCompletingGeneratesNextOccurrence tests that completing the latest
+// occurrence creates the next one with its labels
+func (suite *RecurrenceUsecaseSuite) TestCompletingGeneratesNextOccurrence() {
+	seriesID := primitive.NewObjectID()
+	rule := domain.Recurrence{Frequency: domain.RecurWeekly, Interval: 1, ByWeekday: []string{"MO", "FR"}}
+	series := domain.TaskSeries{ID: seriesID, Recurrence: rule, Start: date(2026, time.October, 21), StartOccurrence: 1, Title: "Standup", Description: "Daily sync", Occurrences: 1}
+	label := primitive.NewObjectID()
+	before := domain.Task{ID: seriesID, Status: "In Progress", Recurrence: &rule, SeriesID: &seriesID, Occurrence: 1, Labels: []primitive.ObjectID{label}}
+	after := before
+	after.Status = domain.TaskCompleted
+
+	suite.taskRepo.On("GetTaskById", mock.Anything, seriesID).Return(before, nil).Once()
+	suite.taskRepo.On("UpdateSomeTask", mock.Anything, seriesID, map[string]interface{}{"status": domain.TaskCompleted}).Return(nil).Once()
+	suite.taskRepo.On("GetTaskById", mock.Anything, seriesID).Return(after, nil).Once()
+	suite.seriesRepo.On("GetSeries", mock.Anything, seriesID).Return(series, nil).Once()
+	suite.seriesRepo.On("AdvanceSeries", mock.Anything, seriesID, 1, date(2026, time.October, 23)).Return(true, nil).Once()
+	suite.taskRepo.On("AddTask", mock.Anything, mock.MatchedBy(func(task domain.Task) bool {
+		return task.Occurrence == 2 && task.DueDate == date(2026, time.October, 23) && task.Status == "Not Started" &&
+			task.Title == "Standup" && len(task.Labels) == 1 && task.Labels[0] == label
+	})).Return(nil).Once()
+
+	suite.Require().NoError(suite.taskUsecase.UpdateSomeTask(context.Background(), seriesID, map[string]interface{}{"status": domain.TaskCompleted}))
+}
+
+// TestCompletingOlderOccurrence tests that only the latest occurrence generates the next
+func (suite *RecurrenceUsecaseSuite) TestCompletingOlderOccurrence() {
+	seriesID, id := primitive.NewObjectID(), primitive.NewObjectID()
+	rule := domain.Recurrence{Frequency: domain.RecurDaily}
+	before := domain.Task{ID: id, Status: "Not Started", Recurrence: &rule, SeriesID: &seriesID, Occurrence: 1}
+	after := before
+	after.Status = domain.TaskCompleted
+
+	suite.taskRepo.On("GetTaskById", mock.Anything, id).Return(before, nil).Once()
+	suite.taskRepo.On("UpdateSomeTask", mock.Anything, id, mock.Anything).Return(nil).Once()
+	suite.taskRepo.On("GetTaskById", mock.Anything, id).Return(after, nil).Once()
+	suite.seriesRepo.On("GetSeries", mock.Anything, seriesID).Return(domain.TaskSeries{ID: seriesID, Recurrence: rule, Occurrences: 2}, nil).Once()
+
+	suite.Require().NoError(suite.taskUsecase.UpdateSomeTask(context.Background(), id, map[string]interface{}{"status": domain.TaskCompleted}))
+}
+
+// TestGenerateDueOccurrences tests the due dates of the occurrences generated
+// by the scheduler and the end of exhausted series
+func (suite *RecurrenceUsecaseSuite) TestGenerateDueOccurrences() {
+	cases := []struct {
+		name string
+		rule domain.Recurrence
+		// start is the due date of occurrence 1 and latest the number of the latest occurrence
+		start  primitive.DateTime
+		latest int
+		next   primitive.DateTime
+	}{
+		{"every other day", domain.Recurrence{Frequency: domain.RecurDaily, Interval: 2}, date(2026, time.October, 1), 3, date(2026, time.October, 7)},
+		{"weekly", domain.Recurrence{Frequency: domain.RecurWeekly, Interval: 1}, date(2026, time.October, 1), 1, date(2026, time.October, 8)},
+		{"weekdays every other week", domain.Recurrence{Frequency: domain.RecurWeekly, Interval: 2, ByWeekday: []string{"TU", "TH"}}, date(2026, time.October, 6), 2, date(2026, time.October, 20)},
+		{"end of month", domain.Recurrence{Frequency: domain.RecurMonthly, Interval: 1}, date(2026, time.January, 31), 1, date(2026, time.February, 28)},
+		{"quarterly", domain.Recurrence{Frequency: domain.RecurMonthly, Interval: 3}, date(2026, time.January, 31), 1, date(2026, time.April, 30)},
+	}
+	var due []domain.TaskSeries
+	for _, c := range cases {
+		series := domain.TaskSeries{ID: primitive.NewObjectID(), Title: c.name, Recurrence: c.rule, Start: c.start, StartOccurrence: 1, Occurrences: c.latest}
+		due = append(due, series)
+		suite.seriesRepo.On("GetSeries", mock.Anything, series.ID).Return(series, nil).Once()
+		suite.seriesRepo.On("AdvanceSeries", mock.Anything, series.ID, c.latest, c.next).Return(true, nil).Once()
+		suite.taskRepo.On("FindTasks", mock.Anything, domain.TaskFilter{SeriesID: series.ID}).Return(nil, nil).Once()
+		suite.taskRepo.On("AddTask", mock.Anything, mock.MatchedBy(func(task domain.Task) bool {
+			return task.Title == c.name && task.DueDate == c.next && task.Occurrence == c.latest+1
+		})).Return(nil).Once()
+	}
+	ended := domain.TaskSeries{ID: primitive.NewObjectID(), Recurrence: domain.Recurrence{Frequency: domain.RecurDaily, Count: 2}, Start: date(2026, time.October, 1), StartOccurrence: 1, Occurrences: 2}
+	until := domain.TaskSeries{ID: primitive.NewObjectID(), Recurrence: domain.Recurrence{Frequency: domain.RecurDaily, Until: date(2026, time.October, 2)}, Start: date(2026, time.October, 1), StartOccurrence: 1, Occurrences: 2}
+	for _, series := range []domain.TaskSeries{ended, until} {
+		due = append(due, series)
+		suite.seriesRepo.On("GetSeries", mock.Anything, series.ID).Return(series, nil).Once()
+		suite.seriesRepo.On("EndSeries", mock.Anything, series.ID).Return(nil).Once()
+	}
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	suite.seriesRepo.On("GetDueSeries", mock.Anything, now).Return(due, nil).Once()
+
+	generated, err := suite.taskUsecase.GenerateDueOccurrences(context.Background(), now)
+	suite.Require().NoError(err)
+	suite.Equal(len(cases), generated)
+}
+
+// TestGenerateDueOccurrencesRetried tests that an occurrence whose transaction
+// was retried is counted once
+func (suite *RecurrenceUsecaseSuite) TestGenerateDueOccurrencesRetried() {
+	transactor := &mocks.Transactor{}
+	outbox := &mocks.OutboxRepository{}
+	suite.taskUsecase.Transactor, suite.taskUsecase.Outbox = transactor, outbox
+
+	series := domain.TaskSeries{ID: primitive.NewObjectID(), Title: "Standup", Recurrence: domain.Recurrence{Frequency: domain.RecurDaily, Interval: 1}, Start: date(2026, time.October, 1), StartOccurrence: 1, Occurrences: 1}
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	suite.seriesRepo.On("GetDueSeries", mock.Anything, now).Return([]domain.TaskSeries{series}, nil).Once()
+	suite.seriesRepo.On("GetSeries", mock.Anything, series.ID).Return(series, nil).Twice()
+	suite.seriesRepo.On("AdvanceSeries", mock.Anything, series.ID, 1, date(2026, time.October, 2)).Return(true, nil).Twice()
+	suite.taskRepo.On("FindTasks", mock.Anything, domain.TaskFilter{SeriesID: series.ID}).Return(nil, nil).Twice()
+	suite.taskRepo.On("AddTask", mock.Anything, mock.AnythingOfType("domain.Task")).Return(nil).Twice()
+	outbox.On("AddEvents", mock.Anything, mock.Anything).Return(nil).Twice()
+	// The first attempt is rolled back, as after a transient error
+	transactor.On("WithTransaction", mock.Anything, mock.Anything).Return(nil).Once().Run(func(args mock.Arguments) {
+		fn := args.Get(1).(func(context.Context) error)
+		suite.Require().NoError(fn(context.Background()))
+		suite.Require().NoError(fn(context.Background()))
+	})
+
+	generated, err := suite.taskUsecase.GenerateDueOccurrences(context.Background(), now)
+	suite.Require().NoError(err)
+	suite.Equal(1, generated)
+	transactor.AssertExpectations(suite.T())
+	outbox.AssertExpectations(suite.T())
+}
+
+// TestUpdateFutureOccurrences tests that a change for future occurrences
+// reaches the series and the later unfinished occurrences only
+func (suite *RecurrenceUsecaseSuite) TestUpdateFutureOccurrences() {
+	seriesID := primitive.NewObjectID()
+	rule := domain.Recurrence{Frequency: domain.RecurDaily, Interval: 1}
+	series := domain.TaskSeries{ID: seriesID, Recurrence: rule, Start: date(2026, time.October, 1), StartOccurrence: 1, Title: "Old", Description: "Same", Occurrences: 4}
+	var occurrences []domain.Task
+	for n := 1; n <= 4; n++ {
+		occurrences = append(occurrences, domain.Task{ID: primitive.NewObjectID(), Title: "Old", Status: "Not Started", DueDate: date(2026, time.October, n), Recurrence: &rule, SeriesID: &seriesID, Occurrence: n})
+	}
+	occurrences[2].Status = domain.TaskCompleted
+	edited := occurrences[1]
+
+	suite.taskRepo.On("GetTaskById", mock.Anything, edited.ID).Return(edited, nil)
+	suite.taskRepo.On("GetTaskById", mock.Anything, occurrences[3].ID).Return(occurrences[3], nil)
+	suite.seriesRepo.On("GetSeries", mock.Anything, seriesID).Return(series, nil).Once()
+	weekly := domain.Recurrence{Frequency: domain.RecurWeekly, Interval: 1}
+	suite.seriesRepo.On("UpdateSeries", mock.Anything, mock.MatchedBy(func(updated domain.TaskSeries) bool {
+		return updated.Title == "New" && updated.Description == "Same" && updated.Recurrence.Frequency == domain.RecurWeekly &&
+			updated.Start == edited.DueDate && updated.StartOccurrence == 2 && updated.LatestDue == date(2026, time.October, 16)
+	})).Return(nil).Once()
+	suite.taskRepo.On("FindTasks", mock.Anything, domain.TaskFilter{SeriesID: seriesID}).Return(occurrences, nil).Once()
+	suite.taskRepo.On("UpdateSomeTask", mock.Anything, edited.ID, map[string]interface{}{"title": "New", "recurrence": weekly}).Return(nil).Once()
+	suite.taskRepo.On("UpdateSomeTask", mock.Anything, occurrences[3].ID, map[string]interface{}{"title": "New", "recurrence": weekly, "due_date": date(2026, time.October, 16)}).Return(nil).Once()
+
+	err := suite.taskUsecase.UpdateFutureOccurrences(context.Background(), edited.ID, map[string]interface{}{
+		"title":      "New",
+		"recurrence": map[string]interface{}{"frequency": "weekly"},
+	})
+	suite.Require().NoError(err)
+
+	err = suite.taskUsecase.UpdateFutureOccurrences(context.Background(), edited.ID, map[string]interface{}{"status": domain.TaskCompleted})
+	suite.True(errors.Is(err, domain.ErrInvalidTask))
+	err = suite.taskUsecase.UpdateSomeTask(context.Background(), edited.ID, map[string]interface{}{"recurrence": nil})
+	suite.True(errors.Is(err, domain.ErrInvalidTask))
+}
+
+// TestRecurrenceUsecaseSuite runs the test suite
+func TestRecurrenceUsecaseSuite(t *testing.T) {
+	suite.Run(t, new(RecurrenceUsecaseSuite))
+}
@@ -0,0 +1,425 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	infrastructure "task_manager_testing/Infrastructure"
+	"task_manager_testing/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// weekdays maps the weekday codes of Recurrence.ByWeekday to weekdays.
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday, "TH": time.Thursday,
+	"FR": time.Friday, "SA": time.Saturday, "SU": time.Sunday,
+}
+
+// UpdateFutureOccurrences changes the title, description or recurrence of the
+// recurring task id and of every later occurrence that is not completed, and
+// stores them in the series template for the occurrences generated later. A
+// new recurrence starts at the due date of the task id; a null recurrence
+// stops the series.
+func (tu *TaskUsecase) UpdateFutureOccurrences(ctx context.Context, id primitive.ObjectID, fields map[string]interface{}) (err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "TaskUsecase.UpdateFutureOccurrences")
+	defer infrastructure.EndSpan(span, &err)
+
+	if tu.Series == nil {
+		return invalid("recurring tasks are not supported")
+	}
+	if len(fields) == 0 {
+		return invalid("fields are required")
+	}
+	var title, description *string
+	var rule *domain.Recurrence
+	_, setRule := fields["recurrence"]
+	for field, value := range fields {
+		switch field {
+		case "title", "description":
+			text, _ := value.(string)
+			if text == "" {
+				return invalid("task %s cannot be empty", field)
+			}
+			if field == "title" {
+				title = &text
+			} else {
+				description = &text
+			}
+		case "recurrence":
+			if rule, err = decodeRecurrence(value); err != nil {
+				return invalid("%v", err)
+			}
+		default:
+			return invalid("only title, description and recurrence can be changed for future occurrences")
+		}
+	}
+
+	updated := 0
+	err = tu.record(ctx, func(ctx context.Context) ([]domain.TaskEvent, error) {
+		task, err := tu.TaskRepository.GetTaskById(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if task.SeriesID == nil {
+			return nil, invalid("task %s is not recurring", id.Hex())
+		}
+		series, err := tu.Series.GetSeries(ctx, *task.SeriesID)
+		if err != nil {
+			return nil, err
+		}
+
+		if title != nil {
+			series.Title = *title
+		}
+		if description != nil {
+			series.Description = *description
+		}
+		switch {
+		case setRule && rule == nil:
+			series.Ended = true
+		case setRule:
+			if err := validateRecurrence(*rule, task.DueDate); err != nil {
+				return nil, invalid("%v", err)
+			}
+			series.Recurrence = normalizeRecurrence(*rule)
+			series.Start, series.StartOccurrence, series.Ended = task.DueDate, task.Occurrence, false
+			due, ok := occurrenceDue(series, series.Occurrences)
+			series.LatestDue, series.Ended = primitive.NewDateTimeFromTime(due), !ok
+		}
+		if err := tu.Series.UpdateSeries(ctx, series); err != nil {
+			return nil, err
+		}
+
+		occurrences, err := tu.TaskRepository.FindTasks(ctx, domain.TaskFilter{SeriesID: series.ID})
+		if err != nil {
+			return nil, err
+		}
+		var events []domain.TaskEvent
+		for _, occurrence := range occurrences {
+			if occurrence.Occurrence < task.Occurrence || (occurrence.ID != id && occurrence.Status == domain.TaskCompleted) {
+				continue
+			}
+			update := map[string]interface{}{}
+			if title != nil {
+				update["title"] = *title
+			}
+			if description != nil {
+				update["description"] = *description
+			}
+			if setRule {
+				if rule == nil {
+					update["recurrence"] = nil
+				} else {
+					update["recurrence"] = series.Recurrence
+				}
+			}
+			if rule != nil && occurrence.Occurrence > task.Occurrence {
+				if due, ok := occurrenceDue(series, occurrence.Occurrence); ok {
+					update["due_date"] = primitive.NewDateTimeFromTime(due)
+				}
+			}
+			occurrenceEvents, err := tu.update(ctx, occurrence.ID, func(ctx context.Context, _ domain.Task) error {
+				return tu.TaskRepository.UpdateSomeTask(ctx, occurrence.ID, update)
+			})
+			if err != nil {
+				return nil, err
+			}
+			events = append(events, occurrenceEvents...)
+			updated++
+		}
+		return events, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "future occurrences updated", slog.String("task_id", id.Hex()), slog.Int("occurrences", updated))
+	return nil
+}
+
+// GenerateDueOccurrences generates the next occurrence of every series whose
+// latest occurrence is due before now, at most one per series and call.
+func (tu *TaskUsecase) GenerateDueOccurrences(ctx context.Context, now time.Time) (generated int, err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "TaskUsecase.GenerateDueOccurrences")
+	defer infrastructure.EndSpan(span, &err)
+
+	if tu.Series == nil {
+		return 0, nil
+	}
+	due, err := tu.Series.GetDueSeries(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+	for _, series := range due {
+		// The transaction may be retried, so only count once it committed
+		var created bool
+		err := tu.record(ctx, func(ctx context.Context) ([]domain.TaskEvent, error) {
+			events, err := tu.generateOccurrence(ctx, series.ID, nil)
+			created = err == nil && len(events) > 0
+			return events, err
+		})
+		if err != nil {
+			return generated, err
+		}
+		if created {
+			generated++
+		}
+	}
+
+	slog.InfoContext(ctx, "due occurrences generated", slog.Int("series", len(due)), slog.Int("generated", generated))
+	return generated, nil
+}
+
+// createTask stores task, with its series when it recurs, and returns the
+// events of the change.
+func (tu *TaskUsecase) createTask(ctx context.Context, task domain.Task) ([]domain.TaskEvent, error) {
+	// The repository ranks the task after the other tasks of its creator,
+	// in the organization of ctx
+	task.SeriesID, task.Occurrence, task.Rank = nil, 0, ""
+	task.OrgID = domain.ScopedOrg(ctx, task.OrgID)
+	if task.Recurrence != nil {
+		if tu.Series == nil {
+			return nil, invalid("recurring tasks are not supported")
+		}
+		rule := normalizeRecurrence(*task.Recurrence)
+		task.Recurrence, task.SeriesID, task.Occurrence = &rule, &task.ID, 1
+		err := tu.Series.CreateSeries(ctx, domain.TaskSeries{
+			ID:              task.ID,
+			CreatedBy:       task.CreatedBy,
+			Recurrence:      rule,
+			Start:           task.DueDate,
+			StartOccurrence: 1,
+			Title:           task.Title,
+			Description:     task.Description,
+			Occurrences:     1,
+			LatestDue:       task.DueDate,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := tu.TaskRepository.AddTask(ctx, task); err != nil {
+		return nil, err
+	}
+	if err := tu.recordStatus(ctx, task, "", task.Status); err != nil {
+		return nil, err
+	}
+	return []domain.TaskEvent{newTaskEvent(domain.EventTaskCreated, task)}, nil
+}
+
+// changeEvents records the status change from before to after and returns
+// the events of the change, including the creation of the next occurrence
+// when the change completed the latest occurrence of a recurring task.
+func (tu *TaskUsecase) changeEvents(ctx context.Context, before, after domain.Task) ([]domain.TaskEvent, error) {
+	if err := tu.recordStatus(ctx, after, before.Status, after.Status); err != nil {
+		return nil, err
+	}
+	events := updateEvents(before, after)
+	if tu.Series == nil || after.SeriesID == nil || after.Status != domain.TaskCompleted || before.Status == domain.TaskCompleted {
+		return events, nil
+	}
+	next, err := tu.generateOccurrence(ctx, *after.SeriesID, &after)
+	if err != nil {
+		return nil, err
+	}
+	return append(events, next...), nil
+}
+
+// generateOccurrence creates the occurrence following the latest one of the
+// series, copying the labels, priority and estimates of latest, or of the
+// stored latest occurrence when latest is nil. Nothing is generated when latest is not the latest
+// occurrence, and the series ends when its rule yields no more occurrences.
+func (tu *TaskUsecase) generateOccurrence(ctx context.Context, seriesID primitive.ObjectID, latest *domain.Task) ([]domain.TaskEvent, error) {
+	series, err := tu.Series.GetSeries(ctx, seriesID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if series.Ended || (latest != nil && latest.Occurrence != series.Occurrences) {
+		return nil, nil
+	}
+
+	next := series.Occurrences + 1
+	due, ok := occurrenceDue(series, next)
+	if !ok {
+		return nil, tu.Series.EndSeries(ctx, series.ID)
+	}
+	dueDate := primitive.NewDateTimeFromTime(due)
+	advanced, err := tu.Series.AdvanceSeries(ctx, series.ID, series.Occurrences, dueDate)
+	if err != nil || !advanced {
+		return nil, err
+	}
+
+	if latest == nil {
+		occurrences, err := tu.TaskRepository.FindTasks(ctx, domain.TaskFilter{SeriesID: series.ID})
+		if err != nil {
+			return nil, err
+		}
+		for i := range occurrences {
+			if occurrences[i].Occurrence == series.Occurrences {
+				latest = &occurrences[i]
+			}
+		}
+	}
+	rule := series.Recurrence
+	task := domain.Task{
+		ID:          primitive.NewObjectID(),
+		Title:       series.Title,
+		Description: series.Description,
+		DueDate:     dueDate,
+		Status:      "Not Started",
+		CreatedBy:   series.CreatedBy,
+		Recurrence:  &rule,
+		SeriesID:    &series.ID,
+		Occurrence:  next,
+		OrgID:       series.OrgID,
+	}
+	if latest != nil {
+		task.Labels = latest.Labels
+		task.Priority, task.StoryPoints, task.EstimateHours = latest.Priority, latest.StoryPoints, latest.EstimateHours
+	}
+	if err := tu.TaskRepository.AddTask(ctx, task); err != nil {
+		return nil, err
+	}
+	if err := tu.recordStatus(ctx, task, "", task.Status); err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "occurrence generated", slog.String("series_id", series.ID.Hex()), slog.Int("occurrence", next))
+	return []domain.TaskEvent{newTaskEvent(domain.EventTaskCreated, task)}, nil
+}
+
+// occurrenceDue returns the due date of occurrence n, counted from the start
+// of the series, or false when the rule ends before it.
+func occurrenceDue(series domain.TaskSeries, n int) (time.Time, bool) {
+	rule := series.Recurrence
+	if rule.Count > 0 && n > rule.Count {
+		return time.Time{}, false
+	}
+	interval := rule.Interval
+	if interval < 1 {
+		interval = 1
+	}
+	steps := n - series.StartOccurrence
+	due := series.Start.Time().UTC()
+	switch {
+	case rule.Frequency == domain.RecurDaily:
+		due = due.AddDate(0, 0, steps*interval)
+	case rule.Frequency == domain.RecurMonthly:
+		due = addMonths(due, steps*interval)
+	case len(rule.ByWeekday) == 0:
+		due = due.AddDate(0, 0, 7*steps*interval)
+	default:
+		var days [7]bool
+		for _, code := range rule.ByWeekday {
+			days[weekdays[code]] = true
+		}
+		for i := 0; i < steps; i++ {
+			due = nextWeekday(due, days, interval)
+		}
+	}
+	if rule.Until != 0 && due.After(rule.Until.Time()) {
+		return time.Time{}, false
+	}
+	return due, true
+}
+
+// addMonths adds months to t, keeping its day of month unless the month is
+// shorter, in which case the last day of the month is used.
+func addMonths(t time.Time, months int) time.Time {
+	year, month, day := t.Date()
+	first := time.Date(year, month+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	if last := first.AddDate(0, 1, -1).Day(); day > last {
+		day = last
+	}
+	return first.AddDate(0, 0, day-1)
+}
+
+// nextWeekday returns the first day after t that is in days, looking at the
+// rest of t's week and then at the week interval weeks later. Weeks start on
+// Monday.
+func nextWeekday(t time.Time, days [7]bool, interval int) time.Time {
+	for day := t.AddDate(0, 0, 1); day.Weekday() != time.Monday; day = day.AddDate(0, 0, 1) {
+		if days[day.Weekday()] {
+			return day
+		}
+	}
+	monday := t.AddDate(0, 0, 7*interval-(int(t.Weekday())+6)%7)
+	for i := 0; i < 7; i++ {
+		if day := monday.AddDate(0, 0, i); days[day.Weekday()] {
+			return day
+		}
+	}
+	return monday
+}
+
+// validateRecurrence checks rule for a task due at due.
+func validateRecurrence(rule domain.Recurrence, due primitive.DateTime) error {
+	if due == 0 {
+		return fmt.Errorf("a recurring task needs a due date")
+	}
+	switch rule.Frequency {
+	case domain.RecurDaily, domain.RecurWeekly, domain.RecurMonthly:
+	default:
+		return fmt.Errorf("recurrence frequency must be %q, %q or %q", domain.RecurDaily, domain.RecurWeekly, domain.RecurMonthly)
+	}
+	if rule.Interval < 0 {
+		return fmt.Errorf("recurrence interval cannot be negative")
+	}
+	if len(rule.ByWeekday) > 0 && rule.Frequency != domain.RecurWeekly {
+		return fmt.Errorf("recurrence weekdays are only allowed for weekly recurrences")
+	}
+	for _, code := range rule.ByWeekday {
+		if _, ok := weekdays[strings.ToUpper(code)]; !ok {
+			return fmt.Errorf("recurrence weekday %q must be one of MO, TU, WE, TH, FR, SA or SU", code)
+		}
+	}
+	if rule.Count < 0 {
+		return fmt.Errorf("recurrence count cannot be negative")
+	}
+	if rule.Until != 0 && rule.Until < due {
+		return fmt.Errorf("recurrence until cannot be before the due date")
+	}
+	return nil
+}
+
+// normalizeRecurrence returns rule with its defaults filled in.
+func normalizeRecurrence(rule domain.Recurrence) domain.Recurrence {
+	if rule.Interval == 0 {
+		rule.Interval = 1
+	}
+	codes := make([]string, len(rule.ByWeekday))
+	for i, code := range rule.ByWeekday {
+		codes[i] = strings.ToUpper(code)
+	}
+	rule.ByWeekday = codes
+	if len(codes) == 0 {
+		rule.ByWeekday = nil
+	}
+	return rule
+}
+
+// decodeRecurrence decodes the JSON value of a recurrence field, returning
+// nil for null.
+func decodeRecurrence(value interface{}) (*domain.Recurrence, error) {
+	if value == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var rule domain.Recurrence
+	if err := json.Unmarshal(raw, &rule); err != nil {
+		return nil, fmt.Errorf("invalid recurrence: %v", err)
+	}
+	return &rule, nil
+}
@@ -0,0 +1,42 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"task_manager_testing/domain"
+	"time"
+)
+
+// ReminderScheduler periodically turns due and overdue tasks into notifications.
+type ReminderScheduler struct {
+	notifications domain.NotificationUsecase
+	interval      time.Duration
+	dueSoon       time.Duration
+}
+
+func NewReminderScheduler(notifications domain.NotificationUsecase, interval, dueSoon time.Duration) *ReminderScheduler {
+	return &ReminderScheduler{notifications: notifications, interval: interval, dueSoon: dueSoon}
+}
+
+// Run checks the tasks right away and then every interval until ctx is done.
+// A failed check is logged and retried at the next tick.
+func (rs *ReminderScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(rs.interval)
+	defer ticker.Stop()
+
+	for {
+		rs.RunOnce(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce checks the tasks once.
+func (rs *ReminderScheduler) RunOnce(ctx context.Context) {
+	if _, err := rs.notifications.CheckDueTasks(ctx, time.Now(), rs.dueSoon); err != nil && ctx.Err() == nil {
+		slog.ErrorContext(ctx, "due date check failed", slog.String("error", err.Error()))
+	}
+}
@@ -0,0 +1,121 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	usecase "task_manager_testing/Usecase"
+	"task_manager_testing/domain"
+	"task_manager_testing/mocks"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ReportUsecaseSuite defines the suite for report usecase tests
+type ReportUsecaseSuite struct {
+	suite.Suite
+	reportRepo    *mocks.ReportRepository
+	userRepo      *mocks.UserRepository
+	reportUsecase *usecase.ReportUsecase
+
+	user  domain.User
+	admin domain.User
+}
+
+// SetupTest sets up the necessary resources before each test
+func (suite *ReportUsecaseSuite) SetupTest() {
+	suite.reportRepo = &mocks.ReportRepository{}
+	suite.userRepo = &mocks.UserRepository{}
+	suite.reportUsecase = usecase.NewReportUsecase(suite.reportRepo, suite.userRepo)
+
+	suite.user = domain.User{ID: primitive.NewObjectID(), Username: "alice", Role: "user"}
+	suite.admin = domain.User{ID: primitive.NewObjectID(), Username: "carol", Role: "admin"}
+}
+
+// TearDownTest checks the mock expectations after each test
+func (suite *ReportUsecaseSuite) TearDownTest() {
+	suite.reportRepo.AssertExpectations(suite.T())
+	suite.userRepo.AssertExpectations(suite.T())
+}
+
+// day returns midnight UTC of a day
+func day(year int, month time.Month, dayOfMonth int) time.Time {
+	return time.Date(year, month, dayOfMonth, 0, 0, 0, 0, time.UTC)
+}
+
+// TestThroughput tests that periods are aligned and empty periods filled in
+func (suite *ReportUsecaseSuite) TestThroughput() {
+	suite.reportRepo.On("Throughput", mock.Anything, domain.ReportQuery{
+		CreatedBy: suite.user.ID, From: day(2024, 1, 1), To: day(2024, 1, 24), Period: domain.ReportWeek,
+	}).Return([]domain.PeriodCount{{Start: day(2024, 1, 8), Created: 2, Completed: 1}}, nil)
+
+	counts, err := suite.reportUsecase.Throughput(context.Background(), suite.user, domain.ReportQuery{From: day(2024, 1, 3), To: day(2024, 1, 24)})
+	suite.Require().NoError(err)
+	suite.Equal([]domain.PeriodCount{
+		{Start: day(2024, 1, 1)},
+		{Start: day(2024, 1, 8), Created: 2, Completed: 1},
+		{Start: day(2024, 1, 15)},
+		{Start: day(2024, 1, 22)},
+	}, counts)
+}
+
+// TestCycleTime tests the summary of the cycle times
+func (suite *ReportUsecaseSuite) TestCycleTime() {
+	query := domain.ReportQuery{From: day(2024, 1, 1), To: day(2024, 2, 1), Period: domain.ReportMonth}
+	scoped := query
+	scoped.CreatedBy = suite.user.ID
+	suite.reportRepo.On("CycleTimes", mock.Anything, scoped).Return([]domain.TaskCycleTime{{Hours: 8}, {Hours: 1}, {Hours: 10}, {Hours: 3}}, nil)
+
+	report, err := suite.reportUsecase.CycleTime(context.Background(), suite.user, query)
+	suite.Require().NoError(err)
+	suite.Len(report.Tasks, 4)
+	suite.Equal(5.5, report.AverageHours)
+	suite.Equal(5.5, report.MedianHours)
+	suite.Equal(1.0, report.ShortestHours)
+	suite.Equal(10.0, report.LongestHours)
+}
+
+// TestOverdue tests that admins see every user with their names
+func (suite *ReportUsecaseSuite) TestOverdue() {
+	suite.reportRepo.On("OverdueByUser", mock.Anything, primitive.NilObjectID, mock.AnythingOfType("time.Time")).
+		Return([]domain.UserOverdue{{UserID: suite.user.ID, Overdue: 3}}, nil)
+	suite.userRepo.On("GetUserById", mock.Anything, suite.user.ID).Return(suite.user, nil)
+
+	overdue, err := suite.reportUsecase.Overdue(context.Background(), suite.admin, domain.ReportQuery{})
+	suite.Require().NoError(err)
+	suite.Equal([]domain.UserOverdue{{UserID: suite.user.ID, Username: "alice", Overdue: 3}}, overdue)
+}
+
+// TestBurndown tests the daily points and the ideal line
+func (suite *ReportUsecaseSuite) TestBurndown() {
+	suite.reportRepo.On("OpenTasks", mock.Anything, suite.user.ID, []time.Time{day(2024, 1, 2), day(2024, 1, 3), day(2024, 1, 4)}).Return([]int{4, 3, 1}, nil)
+
+	points, err := suite.reportUsecase.Burndown(context.Background(), suite.user, domain.ReportQuery{From: day(2024, 1, 1), To: day(2024, 1, 4), Period: domain.ReportMonth})
+	suite.Require().NoError(err)
+	suite.Equal([]domain.BurndownPoint{
+		{Date: day(2024, 1, 1), Remaining: 4, Ideal: 4},
+		{Date: day(2024, 1, 2), Remaining: 3, Ideal: 2},
+		{Date: day(2024, 1, 3), Remaining: 1, Ideal: 0},
+	}, points)
+}
+
+// TestReportQueries tests the validation and the scope of report queries
+func (suite *ReportUsecaseSuite) TestReportQueries() {
+	ctx := context.Background()
+	_, err := suite.reportUsecase.Throughput(ctx, suite.user, domain.ReportQuery{Period: "year"})
+	suite.ErrorIs(err, domain.ErrInvalidReport)
+	_, err = suite.reportUsecase.Throughput(ctx, suite.user, domain.ReportQuery{From: day(2024, 2, 1), To: day(2024, 1, 1)})
+	suite.ErrorIs(err, domain.ErrInvalidReport)
+	_, err = suite.reportUsecase.Burndown(ctx, suite.user, domain.ReportQuery{From: day(2022, 1, 1), To: day(2024, 1, 1)})
+	suite.ErrorIs(err, domain.ErrInvalidReport)
+	_, err = suite.reportUsecase.Overdue(ctx, suite.user, domain.ReportQuery{CreatedBy: suite.admin.ID})
+	suite.ErrorIs(err, domain.ErrForbidden)
+}
+
+// TestReportUsecaseSuite is the entry point for running the suite tests
+func TestReportUsecaseSuite(t *testing.T) {
+	suite.Run(t, new(ReportUsecaseSuite))
+}
@@ -0,0 +1,196 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	infrastructure "task_manager_testing/Infrastructure"
+	"task_manager_testing/domain"
+	"time"
+)
+
+type ReportUsecase struct {
+	reportRepo domain.ReportRepository
+	userRepo   domain.UserRepository
+}
+
+func NewReportUsecase(reportRepo domain.ReportRepository, userRepo domain.UserRepository) *ReportUsecase {
+	return &ReportUsecase{reportRepo: reportRepo, userRepo: userRepo}
+}
+
+// Throughput returns the tasks created and completed in every period of
+// query, empty periods included. The first period is counted whole.
+func (ru *ReportUsecase) Throughput(ctx context.Context, actor domain.User, query domain.ReportQuery) (counts []domain.PeriodCount, err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "ReportUsecase.Throughput")
+	defer infrastructure.EndSpan(span, &err)
+
+	if query, err = scopeReport(actor, query, time.Now()); err != nil {
+		return nil, err
+	}
+	query.From = domain.PeriodStart(query.From, query.Period)
+	stored, err := ru.reportRepo.Throughput(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	byStart := make(map[time.Time]domain.PeriodCount, len(stored))
+	for _, count := range stored {
+		byStart[count.Start] = count
+	}
+	for _, start := range periodStarts(query) {
+		count, ok := byStart[start]
+		if !ok {
+			count = domain.PeriodCount{Start: start}
+		}
+		counts = append(counts, count)
+	}
+	return counts, nil
+}
+
+// CycleTime returns the cycle times of the tasks completed in query with
+// their average, median and extremes.
+func (ru *ReportUsecase) CycleTime(ctx context.Context, actor domain.User, query domain.ReportQuery) (report domain.CycleTimeReport, err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "ReportUsecase.CycleTime")
+	defer infrastructure.EndSpan(span, &err)
+
+	if query, err = scopeReport(actor, query, time.Now()); err != nil {
+		return domain.CycleTimeReport{}, err
+	}
+	tasks, err := ru.reportRepo.CycleTimes(ctx, query)
+	if err != nil {
+		return domain.CycleTimeReport{}, err
+	}
+
+	report.Tasks = []domain.TaskCycleTime{}
+	if len(tasks) == 0 {
+		return report, nil
+	}
+	report.Tasks = tasks
+	hours := make([]float64, len(tasks))
+	total := 0.0
+	for i, task := range tasks {
+		hours[i] = task.Hours
+		total += task.Hours
+	}
+	sort.Float64s(hours)
+	report.AverageHours = total / float64(len(hours))
+	report.ShortestHours, report.LongestHours = hours[0], hours[len(hours)-1]
+	if middle := len(hours) / 2; len(hours)%2 == 1 {
+		report.MedianHours = hours[middle]
+	} else {
+		report.MedianHours = (hours[middle-1] + hours[middle]) / 2
+	}
+	return report, nil
+}
+
+// Overdue returns the number of overdue tasks per user. Only the creator of
+// query is used.
+func (ru *ReportUsecase) Overdue(ctx context.Context, actor domain.User, query domain.ReportQuery) (overdue []domain.UserOverdue, err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "ReportUsecase.Overdue")
+	defer infrastructure.EndSpan(span, &err)
+
+	now := time.Now()
+	if query, err = scopeReport(actor, query, now); err != nil {
+		return nil, err
+	}
+	overdue, err = ru.reportRepo.OverdueByUser(ctx, query.CreatedBy, now)
+	if err != nil {
+		return nil, err
+	}
+	for i := range overdue {
+		if user, err := ru.userRepo.GetUserById(ctx, overdue[i].UserID); err == nil {
+			overdue[i].Username = user.Username
+		}
+	}
+	return overdue, nil
+}
+
+// Burndown returns the tasks open at the end of every day of query, and the
+// ideal line going evenly from the tasks open on the first day to none on the
+// last.
+func (ru *ReportUsecase) Burndown(ctx context.Context, actor domain.User, query domain.ReportQuery) (points []domain.BurndownPoint, err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "ReportUsecase.Burndown")
+	defer infrastructure.EndSpan(span, &err)
+
+	query.Period = domain.ReportDay
+	if query, err = scopeReport(actor, query, time.Now()); err != nil {
+		return nil, err
+	}
+	days := periodStarts(query)
+	ends := make([]time.Time, len(days))
+	for i, day := range days {
+		ends[i] = day.AddDate(0, 0, 1)
+	}
+	open, err := ru.reportRepo.OpenTasks(ctx, query.CreatedBy, ends)
+	if err != nil {
+		return nil, err
+	}
+
+	points = make([]domain.BurndownPoint, len(days))
+	for i, day := range days {
+		points[i] = domain.BurndownPoint{Date: day, Remaining: open[i]}
+		if len(days) > 1 {
+			points[i].Ideal = float64(open[0]) * float64(len(days)-1-i) / float64(len(days)-1)
+		}
+	}
+	return points, nil
+}
+
+// reportSpans are the default lengths of a report per period.
+var reportSpans = map[string]func(time.Time) time.Time{
+	domain.ReportDay:   func(to time.Time) time.Time { return to.AddDate(0, 0, -30) },
+	domain.ReportWeek:  func(to time.Time) time.Time { return to.AddDate(0, 0, -12*7) },
+	domain.ReportMonth: func(to time.Time) time.Time { return to.AddDate(0, -12, 0) },
+}
+
+// scopeReport checks query, fills in its defaults at now and limits users to
+// reports on their own tasks. Reports run to the end of today by default, so
+// changes made a moment ago are counted.
+func scopeReport(actor domain.User, query domain.ReportQuery, now time.Time) (domain.ReportQuery, error) {
+	if query.Period == "" {
+		query.Period = domain.ReportWeek
+	}
+	span, ok := reportSpans[query.Period]
+	if !ok {
+		return domain.ReportQuery{}, fmt.Errorf("%w: period must be one of %q, %q or %q", domain.ErrInvalidReport, domain.ReportDay, domain.ReportWeek, domain.ReportMonth)
+	}
+	if query.To.IsZero() {
+		query.To = domain.PeriodStart(now, domain.ReportDay).AddDate(0, 0, 1)
+	}
+	if query.From.IsZero() {
+		query.From = span(query.To)
+	}
+	if !query.From.Before(query.To) {
+		return domain.ReportQuery{}, fmt.Errorf("%w: from must be before to", domain.ErrInvalidReport)
+	}
+	if len(periodStarts(query)) > domain.MaxReportPoints {
+		return domain.ReportQuery{}, fmt.Errorf("%w: a report covers at most %d %ss", domain.ErrInvalidReport, domain.MaxReportPoints, query.Period)
+	}
+
+	if actor.Role == "admin" || actor.Role == "root" {
+		return query, nil
+	}
+	if !query.CreatedBy.IsZero() && query.CreatedBy != actor.ID {
+		return domain.ReportQuery{}, fmt.Errorf("%w: you can only see reports on your own tasks", domain.ErrForbidden)
+	}
+	query.CreatedBy = actor.ID
+	return query, nil
+}
+
+// periodStarts returns the starts of the periods overlapping [From, To),
+// stopping after one more than domain.MaxReportPoints.
+func periodStarts(query domain.ReportQuery) []time.Time {
+	var starts []time.Time
+	for start := domain.PeriodStart(query.From, query.Period); start.Before(query.To) && len(starts) <= domain.MaxReportPoints; {
+		starts = append(starts, start)
+		switch query.Period {
+		case domain.ReportWeek:
+			start = start.AddDate(0, 0, 7)
+		case domain.ReportMonth:
+			start = start.AddDate(0, 1, 0)
+		default:
+			start = start.AddDate(0, 0, 1)
+		}
+	}
+	return starts
+}
@@ -0,0 +1,128 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	infrastructure "task_manager_testing/Infrastructure"
+	"task_manager_testing/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// maxFilterNameLength is the longest saved filter name, in characters.
+const maxFilterNameLength = 100
+
+type SavedFilterUsecase struct {
+	filterRepo domain.SavedFilterRepository
+	labelRepo  domain.LabelRepository
+	tasks      domain.TaskUsecase
+}
+
+func NewSavedFilterUsecase(filterRepo domain.SavedFilterRepository, labelRepo domain.LabelRepository, tasks domain.TaskUsecase) *SavedFilterUsecase {
+	return &SavedFilterUsecase{filterRepo: filterRepo, labelRepo: labelRepo, tasks: tasks}
+}
+
+// SaveFilter stores filter for actor. Its labels must be labels actor can use;
+// a label deleted later simply matches no task.
+func (fu *SavedFilterUsecase) SaveFilter(ctx context.Context, actor domain.User, filter domain.SavedFilter) (saved domain.SavedFilter, err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "SavedFilterUsecase.SaveFilter")
+	defer infrastructure.EndSpan(span, &err)
+
+	name, err := validateName(filter.Name, maxFilterNameLength)
+	if err != nil {
+		return domain.SavedFilter{}, fmt.Errorf("%w: %v", domain.ErrInvalidFilter, err)
+	}
+	if filter.LabelMatch == "" {
+		filter.LabelMatch = domain.LabelMatchAll
+	}
+	labels := []primitive.ObjectID{}
+	for _, id := range filter.Labels {
+		if containsID(labels, id) {
+			continue
+		}
+		_, err := usableLabel(ctx, fu.labelRepo, actor, id)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.SavedFilter{}, fmt.Errorf("%w: label %s does not exist", domain.ErrInvalidFilter, id.Hex())
+		}
+		if err != nil {
+			return domain.SavedFilter{}, err
+		}
+		labels = append(labels, id)
+	}
+
+	saved = domain.SavedFilter{
+		ID:         primitive.NewObjectID(),
+		UserID:     actor.ID,
+		Name:       name,
+		NameKey:    strings.ToLower(name),
+		Status:     filter.Status,
+		Labels:     labels,
+		LabelMatch: filter.LabelMatch,
+		CreatedAt:  primitive.NewDateTimeFromTime(time.Now()),
+	}
+	if err := validateTaskFilter(saved.TaskFilter()); err != nil {
+		return domain.SavedFilter{}, fmt.Errorf("%w: %v", domain.ErrInvalidFilter, err)
+	}
+
+	if err := fu.filterRepo.CreateFilter(ctx, saved); err != nil {
+		return domain.SavedFilter{}, err
+	}
+
+	slog.InfoContext(ctx, "filter saved", slog.String("filter_id", saved.ID.Hex()))
+	return saved, nil
+}
+
+func (fu *SavedFilterUsecase) GetFilters(ctx context.Context, actor domain.User) (filters []domain.SavedFilter, err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "SavedFilterUsecase.GetFilters")
+	defer infrastructure.EndSpan(span, &err)
+
+	return fu.filterRepo.GetFilters(ctx, actor.ID)
+}
+
+func (fu *SavedFilterUsecase) DeleteFilter(ctx context.Context, actor domain.User, id primitive.ObjectID) (err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "SavedFilterUsecase.DeleteFilter")
+	defer infrastructure.EndSpan(span, &err)
+
+	if _, err := fu.ownFilter(ctx, actor, id); err != nil {
+		return err
+	}
+	if err := fu.filterRepo.DeleteFilter(ctx, id); err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "filter deleted", slog.String("filter_id", id.Hex()))
+	return nil
+}
+
+// RunFilter applies the filter to actor's own tasks.
+func (fu *SavedFilterUsecase) RunFilter(ctx context.Context, actor domain.User, id primitive.ObjectID) (filter domain.SavedFilter, tasks []domain.Task, err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "SavedFilterUsecase.RunFilter")
+	defer infrastructure.EndSpan(span, &err)
+
+	filter, err = fu.ownFilter(ctx, actor, id)
+	if err != nil {
+		return domain.SavedFilter{}, nil, err
+	}
+	tasks, err = fu.tasks.FindTasks(ctx, filter.TaskFilter())
+	if err != nil {
+		return domain.SavedFilter{}, nil, err
+	}
+	return filter, tasks, nil
+}
+
+// ownFilter returns one of actor's filters and reports others as missing.
+func (fu *SavedFilterUsecase) ownFilter(ctx context.Context, actor domain.User, id primitive.ObjectID) (domain.SavedFilter, error) {
+	filter, err := fu.filterRepo.GetFilter(ctx, id)
+	if err != nil {
+		return domain.SavedFilter{}, err
+	}
+	if filter.UserID != actor.ID {
+		return domain.SavedFilter{}, mongo.ErrNoDocuments
+	}
+	return filter, nil
+}
@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"task_manager_testing/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// roleRanks orders the roles of the hierarchy used for moderation.
+var roleRanks = map[string]int{"user": 1, "admin": 2, "root": 3}
+
+// canSeeTask reports whether user may read task, its discussion and its
+// attachments.
+func canSeeTask(user domain.User, task domain.Task) bool {
+	return user.Role == "admin" || user.Role == "root" || task.CreatedBy == user.ID
+}
+
+// outranks reports whether actor may moderate what other wrote: admins
+// moderate users, and root users moderate users and admins.
+func outranks(actor, other domain.User) bool {
+	return roleRanks[actor.Role] > roleRanks[other.Role]
+}
+
+// visibleTask returns the task when actor may see it, and reports other tasks
+// as missing.
+func visibleTask(ctx context.Context, taskRepo domain.TaskRepository, actor domain.User, taskID primitive.ObjectID) (domain.Task, error) {
+	task, err := taskRepo.GetTaskById(ctx, taskID)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	if !canSeeTask(actor, task) {
+		return domain.Task{}, mongo.ErrNoDocuments
+	}
+	return task, nil
+}
+
+// editableTask returns the task when actor may change it: users change their
+// own tasks, admins also those of users, and root users those of users and
+// admins. Visible tasks actor may not change are reported as ErrForbidden.
+func editableTask(ctx context.Context, taskRepo domain.TaskRepository, userRepo domain.UserRepository, actor domain.User, taskID primitive.ObjectID) (domain.Task, error) {
+	task, err := visibleTask(ctx, taskRepo, actor, taskID)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	if task.CreatedBy != actor.ID {
+		// A missing creator no longer holds a role
+		creator, _ := userRepo.GetUserById(ctx, task.CreatedBy)
+		if !outranks(actor, creator) {
+			return domain.Task{}, fmt.Errorf("%w: you can only change your own tasks or those of users below your role", domain.ErrForbidden)
+		}
+	}
+	return task, nil
+}
@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"task_manager_testing/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// newTaskEvent builds an event of eventType about task.
+func newTaskEvent(eventType string, task domain.Task) domain.TaskEvent {
+	return domain.TaskEvent{
+		ID:         primitive.NewObjectID().Hex(),
+		Type:       eventType,
+		OccurredAt: time.Now().UTC(),
+		Task:       task,
+	}
+}
+
+// updateEvents returns the events of a change from before to after: always
+// task.updated, and task.completed when the change finished the task.
+func updateEvents(before, after domain.Task) []domain.TaskEvent {
+	events := []domain.TaskEvent{newTaskEvent(domain.EventTaskUpdated, after)}
+	if after.Status == domain.TaskCompleted && before.Status != domain.TaskCompleted {
+		events = append(events, newTaskEvent(domain.EventTaskCompleted, after))
+	}
+	return events
+}
+
+// rankAttempts bounds how often a change that lost the rank it chose to a
+// concurrent write is run again.
+const rankAttempts = 5
+
+// record runs change and delivers the events it returns. With an outbox the
+// events are stored in the same transaction as the change, so they cannot be
+// lost once the change is stored; otherwise they are published after it. A
+// transaction that fails with domain.ErrRankTaken is rolled back as a whole
+// and run again, ranking against the tasks stored meanwhile.
+func (tu *TaskUsecase) record(ctx context.Context, change func(ctx context.Context) ([]domain.TaskEvent, error)) error {
+	if tu.Outbox == nil {
+		events, err := change(ctx)
+		if err != nil {
+			return err
+		}
+		tu.publish(ctx, events...)
+		return nil
+	}
+
+	write := func(ctx context.Context) error {
+		events, err := change(ctx)
+		if err != nil {
+			return err
+		}
+		return tu.Outbox.AddEvents(ctx, events)
+	}
+	if tu.Transactor == nil {
+		return write(ctx)
+	}
+	for attempt := 1; ; attempt++ {
+		err := tu.Transactor.WithTransaction(ctx, write)
+		if !errors.Is(err, domain.ErrRankTaken) || attempt == rankAttempts {
+			return err
+		}
+	}
+}
+
+// recordRanked is record for a change whose only write is the one giving a
+// task its rank, which can be run again outside a transaction as well.
+func (tu *TaskUsecase) recordRanked(ctx context.Context, change func(ctx context.Context) ([]domain.TaskEvent, error)) error {
+	for attempt := 1; ; attempt++ {
+		err := tu.record(ctx, change)
+		transactional := tu.Outbox != nil && tu.Transactor != nil
+		if transactional || !errors.Is(err, domain.ErrRankTaken) || attempt == rankAttempts {
+			return err
+		}
+	}
+}
+
+// publish hands events to the publisher. The change they describe is already
+// stored, so a failure is logged rather than returned.
+func (tu *TaskUsecase) publish(ctx context.Context, events ...domain.TaskEvent) {
+	if tu.Events == nil {
+		return
+	}
+	for _, event := range events {
+		if err := tu.Events.PublishTaskEvent(ctx, event); err != nil {
+			slog.ErrorContext(ctx, "task event not published",
+				slog.String("event", event.Type), slog.String("task_id", event.Task.ID.Hex()), slog.String("error", err.Error()))
+		}
+	}
+}
+
+// recordStatus stores the change of task from status from to status to when
+// status changes are recorded.
+func (tu *TaskUsecase) recordStatus(ctx context.Context, task domain.Task, from, to string) error {
+	if tu.StatusChanges == nil || from == to {
+		return nil
+	}
+	return tu.StatusChanges.AddStatusChange(ctx, domain.StatusChange{
+		ID:        primitive.NewObjectID(),
+		TaskID:    task.ID,
+		CreatedBy: task.CreatedBy,
+		From:      from,
+		To:        to,
+		At:        primitive.NewDateTimeFromTime(time.Now()),
+		OrgID:     task.OrgID,
+	})
+}
@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	infrastructure "task_manager_testing/Infrastructure"
+	"task_manager_testing/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ExportTasks streams the tasks created by createdBy, or every task when
+// createdBy is primitive.NilObjectID, to fn.
+func (tu *TaskUsecase) ExportTasks(ctx context.Context, createdBy primitive.ObjectID, fn func(domain.Task) error) (err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "TaskUsecase.ExportTasks")
+	defer infrastructure.EndSpan(span, &err)
+
+	count := 0
+	now := time.Now()
+	err = tu.TaskRepository.StreamTasks(ctx, createdBy, func(task domain.Task) error {
+		count++
+		task.Overdue = task.IsOverdue(now)
+		return fn(task)
+	})
+	if err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "tasks exported", slog.Int("count", count))
+	return nil
+}
+
+// ImportTasks validates every row with the rules used by AddTask and, unless
+// dryRun is set, creates the valid ones. Invalid rows do not stop the import.
+func (tu *TaskUsecase) ImportTasks(ctx context.Context, rows []domain.TaskImportRow, dryRun bool) (results []domain.TaskImportResult, err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "TaskUsecase.ImportTasks")
+	defer infrastructure.EndSpan(span, &err)
+
+	if len(rows) == 0 {
+		return nil, invalid("at least one row is required")
+	}
+	if len(rows) > domain.MaxImportRows {
+		return nil, invalid("at most %d rows can be imported at once", domain.MaxImportRows)
+	}
+
+	created := 0
+	results = make([]domain.TaskImportResult, len(rows))
+	for i, row := range rows {
+		result := domain.TaskImportResult{Row: row.Row, Errors: row.Errors}
+		for _, problem := range taskProblems(row.Task) {
+			result.Errors = append(result.Errors, problem.Error())
+		}
+
+		switch {
+		case len(result.Errors) > 0:
+			result.Status = domain.ImportInvalid
+		case dryRun:
+			result.Status = domain.ImportValid
+		default:
+			row.Task.Labels = nil
+			err := tu.record(ctx, func(ctx context.Context) ([]domain.TaskEvent, error) {
+				return tu.createTask(ctx, row.Task)
+			})
+			if err != nil {
+				result.Status = domain.ImportFailed
+				result.Errors = append(result.Errors, err.Error())
+				break
+			}
+			result.Status = domain.ImportCreated
+			result.ID = row.Task.ID.Hex()
+			created++
+		}
+		results[i] = result
+	}
+
+	slog.InfoContext(ctx, "tasks imported", slog.Int("rows", len(rows)), slog.Int("created", created), slog.Bool("dry_run", dryRun))
+	return results, nil
+}
@@ -1,6 +1,8 @@
 package usecase_test
 
 import (
+	"context"
+	"errors"
 	"testing"
 
 	usecase "task_manager_testing/Usecase"
@@ -8,48 +10,91 @@ import (
 	"task_manager_testing/mocks"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 // TaskUsecaseSuite defines the suite for task usecase tests
 type TaskUsecaseSuite struct {
 	suite.Suite
 	taskRepo   *mocks.TaskRepository
+	transactor *mocks.Transactor
+	events     *mocks.TaskEventPublisher
 	taskUsecase *usecase.TaskUsecase
 }
 // SetupTest sets up the necessary resources before each test
 func (suite *TaskUsecaseSuite) SetupTest() {
 	suite.taskRepo = &mocks.TaskRepository{}
-	suite.taskUsecase = usecase.NewTaskUsecase(suite.taskRepo)
+	suite.transactor = &mocks.Transactor{}
+	suite.events = &mocks.TaskEventPublisher{}
+	suite.events.On("PublishTaskEvent", mock.Anything, mock.Anything).Return(nil).Maybe()
+	suite.taskUsecase = usecase.NewTaskUsecase(suite.taskRepo, suite.transactor, nil, suite.events)
 }
 
 // TearDownTest clears resources after each test
 func (suite *TaskUsecaseSuite) TearDownTest() {
 	// Reset the mock expectations
 	suite.taskRepo.AssertExpectations(suite.T())
+	suite.transactor.AssertExpectations(suite.T())
+	suite.events.AssertExpectations(suite.T())
+}
+
+// isEvent matches a published event of the given type
+func isEvent(eventType string) interface{} {
+	return mock.MatchedBy(func(event domain.TaskEvent) bool { return event.Type == eventType })
 }
 
 // TestAddTask tests the AddTask use case
 func (suite *TaskUsecaseSuite) TestAddTask() {
 	task := domain.Task{ID: primitive.NewObjectID(), Title: "Task 1", Description: "Description 1", Status: "Completed", CreatedBy: primitive.NewObjectID()}
 
-	suite.taskRepo.On("AddTask", task).Return(nil)
+	suite.taskRepo.On("AddTask", mock.Anything, task).Return(nil)
 
-	err := suite.taskUsecase.AddTask(task)
+	err := suite.taskUsecase.AddTask(context.Background(), task)
 
 	assert.Nil(suite.T(), err)
+	suite.events.AssertCalled(suite.T(), "PublishTaskEvent", mock.Anything, isEvent(domain.EventTaskCreated))
 }
 
 // TestDeleteTask tests the DeleteTask use case
 func (suite *TaskUsecaseSuite) TestDeleteTask() {
 	id := primitive.NewObjectID()
 
-	suite.taskRepo.On("DeleteTask", id).Return(nil)
+	suite.taskRepo.On("GetTaskById", mock.Anything, id).Return(domain.Task{ID: id}, nil)
+	suite.taskRepo.On("DeleteTask", mock.Anything, id).Return(nil)
 
-	err := suite.taskUsecase.DeleteTask(id)
+	err := suite.taskUsecase.DeleteTask(context.Background(), id)
 
 	assert.Nil(suite.T(), err)
+	suite.events.AssertCalled(suite.T(), "PublishTaskEvent", mock.Anything, isEvent(domain.EventTaskDeleted))
+}
+
+// TestDeleteTaskRemovesDiscussion tests that the comments and attachments
+// of a deleted task are removed together with the attachment content
+func (suite *TaskUsecaseSuite) TestDeleteTaskRemovesDiscussion() {
+	id := primitive.NewObjectID()
+	commentRepo := &mocks.CommentRepository{}
+	attachmentRepo := &mocks.AttachmentRepository{}
+	blobs := &mocks.BlobStore{}
+	suite.taskUsecase.Comments = commentRepo
+	suite.taskUsecase.Attachments = attachmentRepo
+	suite.taskUsecase.Blobs = blobs
+
+	suite.taskRepo.On("GetTaskById", mock.Anything, id).Return(domain.Task{ID: id}, nil)
+	suite.taskRepo.On("DeleteTask", mock.Anything, id).Return(nil)
+	commentRepo.On("DeleteTaskComments", mock.Anything, id).Return(nil).Once()
+	attachmentRepo.On("DeleteTaskAttachments", mock.Anything, id).Return([]domain.Attachment{{BlobKey: "first"}, {BlobKey: "second"}}, nil).Once()
+	blobs.On("Delete", mock.Anything, "first").Return(nil).Once()
+	blobs.On("Delete", mock.Anything, "second").Return(errors.New("disk full")).Once()
+
+	err := suite.taskUsecase.DeleteTask(context.Background(), id)
+
+	suite.NoError(err)
+	commentRepo.AssertExpectations(suite.T())
+	attachmentRepo.AssertExpectations(suite.T())
+	blobs.AssertExpectations(suite.T())
 }
 
 // TestGetAllTasks tests the GetAllTasks use case
@@ -59,9 +104,9 @@ func (suite *TaskUsecaseSuite) TestGetAllTasks() {
 		{ID: primitive.NewObjectID(), Title: "Task 2", Description: "Description 2", Status: "Pending", CreatedBy: primitive.NewObjectID()},
 	}
 
-	suite.taskRepo.On("GetAllTasks").Return(tasks, nil)
+	suite.taskRepo.On("GetAllTasks", mock.Anything, mock.Anything).Return(tasks, nil)
 
-	result, err := suite.taskUsecase.GetAllTasks()
+	result, err := suite.taskUsecase.GetAllTasks(context.Background())
 
 	assert.Nil(suite.T(), err)
 	assert.Equal(suite.T(), tasks, result)
@@ -72,9 +117,9 @@ func (suite *TaskUsecaseSuite) TestGetTaskById() {
 	id := primitive.NewObjectID()
 	task := domain.Task{ID: id, Title: "Task 1", Description: "Description 1", Status: "Completed", CreatedBy: primitive.NewObjectID()}
 
-	suite.taskRepo.On("GetTaskById", id).Return(task, nil)
+	suite.taskRepo.On("GetTaskById", mock.Anything, id).Return(task, nil)
 
-	result, err := suite.taskUsecase.GetTaskById(id)
+	result, err := suite.taskUsecase.GetTaskById(context.Background(), id)
 
 	assert.Nil(suite.T(), err)
 	assert.Equal(suite.T(), task, result)
@@ -85,11 +130,18 @@ func (suite *TaskUsecaseSuite) TestUpdateFullTask() {
 	id := primitive.NewObjectID()
 	task := domain.Task{ID: id, Title: "Task 1", Description: "Description 1", Status: "Completed", CreatedBy: primitive.NewObjectID()}
 
-	suite.taskRepo.On("UpdateFullTask", id, task).Return(nil)
+	before := task
+	before.Status = "In Progress"
 
-	err := suite.taskUsecase.UpdateFullTask(id, task)
+	suite.taskRepo.On("GetTaskById", mock.Anything, id).Return(before, nil).Once()
+	suite.taskRepo.On("UpdateFullTask", mock.Anything, id, task).Return(nil)
+	suite.taskRepo.On("GetTaskById", mock.Anything, id).Return(task, nil).Once()
+
+	err := suite.taskUsecase.UpdateFullTask(context.Background(), id, task)
 
 	assert.Nil(suite.T(), err)
+	suite.events.AssertCalled(suite.T(), "PublishTaskEvent", mock.Anything, isEvent(domain.EventTaskUpdated))
+	suite.events.AssertCalled(suite.T(), "PublishTaskEvent", mock.Anything, isEvent(domain.EventTaskCompleted))
 }
 
 // TestUpdateSomeTask tests the UpdateSomeTask use case
@@ -97,11 +149,176 @@ func (suite *TaskUsecaseSuite) TestUpdateSomeTask() {
 	id := primitive.NewObjectID()
 	task := map[string]interface{}{"status": "Completed"}
 
-	suite.taskRepo.On("UpdateSomeTask", id, task).Return(nil)
+	stored := domain.Task{ID: id, Status: "Completed"}
+
+	suite.taskRepo.On("GetTaskById", mock.Anything, id).Return(stored, nil)
+	suite.taskRepo.On("UpdateSomeTask", mock.Anything, id, task).Return(nil)
 
-	err := suite.taskUsecase.UpdateSomeTask(id, task)
+	err := suite.taskUsecase.UpdateSomeTask(context.Background(), id, task)
 
 	assert.Nil(suite.T(), err)
+	// The task was already completed, so only an update is published
+	suite.events.AssertCalled(suite.T(), "PublishTaskEvent", mock.Anything, isEvent(domain.EventTaskUpdated))
+	suite.events.AssertNotCalled(suite.T(), "PublishTaskEvent", mock.Anything, isEvent(domain.EventTaskCompleted))
+}
+
+// TestUpdateRejectedFields tests that single and bulk updates refuse the same
+// fields
+func (suite *TaskUsecaseSuite) TestUpdateRejectedFields() {
+	ctx := context.Background()
+	id := primitive.NewObjectID()
+	allow := func(context.Context, domain.Task) error { return nil }
+
+	for _, field := range []string{"_id", "id", "created_by", "org_id", "labels", "rank", "recurrence", "series_id", "occurrence"} {
+		fields := map[string]interface{}{field: "x"}
+		suite.ErrorIs(suite.taskUsecase.UpdateSomeTask(ctx, id, fields), domain.ErrInvalidTask, field)
+
+		results, err := suite.taskUsecase.BulkTasks(ctx, []domain.BulkTaskOperation{{Op: domain.BulkUpdate, ID: id.Hex(), Fields: fields}}, false, allow)
+		suite.Require().NoError(err)
+		suite.ErrorIs(results[0].Err, domain.ErrInvalidTask, field)
+	}
+	suite.ErrorIs(suite.taskUsecase.UpdateSomeTask(ctx, id, map[string]interface{}{"status": "Done"}), domain.ErrInvalidTask)
+	suite.taskRepo.AssertNotCalled(suite.T(), "UpdateSomeTask", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestTaskPriorityAndEstimates tests the validation of priorities and estimates
+func (suite *TaskUsecaseSuite) TestTaskPriorityAndEstimates() {
+	ctx := context.Background()
+	for _, task := range []domain.Task{
+		{Title: "Task", Status: "Pending", Priority: "critical"},
+		{Title: "Task", Status: "Pending", StoryPoints: domain.MaxStoryPoints + 1},
+		{Title: "Task", Status: "Pending", EstimateHours: -1},
+	} {
+		suite.Error(suite.taskUsecase.AddTask(ctx, task))
+	}
+	for _, fields := range []map[string]interface{}{
+		{"priority": "someday"},
+		{"priority": 3},
+		{"story_points": -2.0},
+		{"estimate_hours": "four"},
+	} {
+		suite.ErrorIs(suite.taskUsecase.UpdateSomeTask(ctx, primitive.NewObjectID(), fields), domain.ErrInvalidTask)
+	}
+
+	id := primitive.NewObjectID()
+	fields := map[string]interface{}{"priority": domain.PriorityUrgent, "story_points": 8.0, "estimate_hours": 12}
+	suite.taskRepo.On("GetTaskById", mock.Anything, id).Return(domain.Task{ID: id, Status: "Pending"}, nil)
+	suite.taskRepo.On("UpdateSomeTask", mock.Anything, id, fields).Return(nil)
+	suite.NoError(suite.taskUsecase.UpdateSomeTask(ctx, id, fields))
+}
+
+// TestGetBoard tests that tasks are grouped by status in rank order
+func (suite *TaskUsecaseSuite) TestGetBoard() {
+	owner := primitive.NewObjectID()
+	task := func(title, status, rank string) domain.Task {
+		return domain.Task{ID: primitive.NewObjectID(), Title: title, Status: status, Rank: rank, CreatedBy: owner}
+	}
+	suite.taskRepo.On("FindTasks", mock.Anything, domain.TaskFilter{CreatedBy: owner}).Return([]domain.Task{
+		task("Old", "Not Started", ""),
+		task("Second", "Not Started", "2"),
+		task("Done", "Completed", "3"),
+		task("First", "Not Started", "11"),
+	}, nil)
+
+	board, err := suite.taskUsecase.GetBoard(context.Background(), owner)
+	suite.Require().NoError(err)
+	suite.Require().Len(board.Columns, 3)
+	titles := func(column domain.BoardColumn) []string {
+		var titles []string
+		for _, task := range column.Tasks {
+			titles = append(titles, task.Title)
+		}
+		return titles
+	}
+	suite.Equal("Not Started", board.Columns[0].Status)
+	suite.Equal([]string{"First", "Second", "Old"}, titles(board.Columns[0]))
+	suite.Equal("In Progress", board.Columns[1].Status)
+	suite.Empty(board.Columns[1].Tasks)
+	suite.Equal([]string{"Done"}, titles(board.Columns[2]))
+}
+
+// TestMoveTask tests that a move writes the status and the rank of the moved task only
+func (suite *TaskUsecaseSuite) TestMoveTask() {
+	owner := primitive.NewObjectID()
+	moved := domain.Task{ID: primitive.NewObjectID(), Status: "Not Started", Rank: "1", CreatedBy: owner}
+	above := domain.Task{ID: primitive.NewObjectID(), Status: "In Progress", Rank: "2", CreatedBy: owner}
+	below := domain.Task{ID: primitive.NewObjectID(), Status: "In Progress", Rank: "3", CreatedBy: owner}
+	for _, task := range []domain.Task{moved, above, below} {
+		suite.taskRepo.On("GetTaskById", mock.Anything, task.ID).Return(task, nil)
+	}
+	suite.taskRepo.On("FindTasks", mock.Anything, domain.TaskFilter{CreatedBy: owner}).Return([]domain.Task{moved, above, below}, nil)
+	suite.taskRepo.On("UpdateSomeTask", mock.Anything, moved.ID, map[string]interface{}{"status": "In Progress", "rank": "21"}).Return(nil).Once()
+
+	_, err := suite.taskUsecase.MoveTask(context.Background(), moved.ID, domain.TaskMove{Status: "In Progress", After: above.ID, Before: below.ID})
+	suite.Require().NoError(err)
+
+	for _, move := range []domain.TaskMove{
+		{Status: "Blocked"},
+		{Status: "Not Started", After: above.ID},
+		{Status: "In Progress", After: below.ID, Before: above.ID},
+		{Status: "In Progress", After: moved.ID},
+	} {
+		_, err := suite.taskUsecase.MoveTask(context.Background(), moved.ID, move)
+		suite.ErrorIs(err, domain.ErrInvalidTask)
+	}
+	suite.ErrorIs(suite.taskUsecase.UpdateSomeTask(context.Background(), moved.ID, map[string]interface{}{"rank": "5"}), domain.ErrInvalidTask)
+}
+
+// TestMoveTaskRanksAcrossColumns tests that a moved task is ranked apart from
+// the tasks of the other columns, and ranked again when a concurrent move took
+// its rank
+func (suite *TaskUsecaseSuite) TestMoveTaskRanksAcrossColumns() {
+	owner := primitive.NewObjectID()
+	moved := domain.Task{ID: primitive.NewObjectID(), Status: "Not Started", Rank: "1", CreatedBy: owner}
+	waiting := domain.Task{ID: primitive.NewObjectID(), Status: "Not Started", Rank: "3", CreatedBy: owner}
+	started := domain.Task{ID: primitive.NewObjectID(), Status: "In Progress", Rank: "2", CreatedBy: owner}
+	for _, task := range []domain.Task{moved, started} {
+		suite.taskRepo.On("GetTaskById", mock.Anything, task.ID).Return(task, nil)
+	}
+	suite.taskRepo.On("FindTasks", mock.Anything, domain.TaskFilter{CreatedBy: owner}).Return([]domain.Task{moved, waiting, started}, nil).Times(3)
+
+	// The bottom of In Progress sorts after every task of the creator, and
+	// the task of another column between the neighbour and the end bounds it
+	suite.taskRepo.On("UpdateSomeTask", mock.Anything, moved.ID, map[string]interface{}{"status": "In Progress", "rank": "4"}).Return(nil).Once()
+	_, err := suite.taskUsecase.MoveTask(context.Background(), moved.ID, domain.TaskMove{Status: "In Progress"})
+	suite.Require().NoError(err)
+	suite.taskRepo.On("UpdateSomeTask", mock.Anything, moved.ID, map[string]interface{}{"status": "In Progress", "rank": "21"}).Return(nil).Once()
+	_, err = suite.taskUsecase.MoveTask(context.Background(), moved.ID, domain.TaskMove{Status: "In Progress", After: started.ID})
+	suite.Require().NoError(err)
+
+	// A concurrent move took rank 4 meanwhile
+	taken := domain.Task{ID: primitive.NewObjectID(), Status: "Completed", Rank: "4", CreatedBy: owner}
+	suite.taskRepo.On("UpdateSomeTask", mock.Anything, moved.ID, map[string]interface{}{"status": "In Progress", "rank": "4"}).Return(domain.ErrRankTaken).Once()
+	suite.taskRepo.On("FindTasks", mock.Anything, domain.TaskFilter{CreatedBy: owner}).Return([]domain.Task{moved, waiting, started, taken}, nil)
+	suite.taskRepo.On("UpdateSomeTask", mock.Anything, moved.ID, map[string]interface{}{"status": "In Progress", "rank": "5"}).Return(nil).Once()
+	_, err = suite.taskUsecase.MoveTask(context.Background(), moved.ID, domain.TaskMove{Status: "In Progress"})
+	suite.Require().NoError(err)
+}
+
+// TestStatusChanges tests that creations, status changes and deletions are recorded
+func (suite *TaskUsecaseSuite) TestStatusChanges() {
+	changes := &mocks.StatusChangeRepository{}
+	defer changes.AssertExpectations(suite.T())
+	suite.taskUsecase.StatusChanges = changes
+	change := func(from, to string) interface{} {
+		return mock.MatchedBy(func(change domain.StatusChange) bool { return change.From == from && change.To == to })
+	}
+
+	task := domain.Task{ID: primitive.NewObjectID(), Title: "Task", Description: "Tracked", Status: "Not Started", CreatedBy: primitive.NewObjectID()}
+	started := task
+	started.Status = domain.TaskInProgress
+	suite.taskRepo.On("AddTask", mock.Anything, task).Return(nil)
+	suite.taskRepo.On("GetTaskById", mock.Anything, task.ID).Return(task, nil).Once()
+	suite.taskRepo.On("UpdateSomeTask", mock.Anything, task.ID, map[string]interface{}{"status": domain.TaskInProgress}).Return(nil)
+	suite.taskRepo.On("GetTaskById", mock.Anything, task.ID).Return(started, nil)
+	suite.taskRepo.On("DeleteTask", mock.Anything, task.ID).Return(nil)
+	changes.On("AddStatusChange", mock.Anything, change("", "Not Started")).Return(nil).Once()
+	changes.On("AddStatusChange", mock.Anything, change("Not Started", domain.TaskInProgress)).Return(nil).Once()
+	changes.On("AddStatusChange", mock.Anything, change(domain.TaskInProgress, "")).Return(nil).Once()
+
+	suite.Require().NoError(suite.taskUsecase.AddTask(context.Background(), task))
+	suite.Require().NoError(suite.taskUsecase.UpdateSomeTask(context.Background(), task.ID, map[string]interface{}{"status": domain.TaskInProgress}))
+	suite.Require().NoError(suite.taskUsecase.DeleteTask(context.Background(), task.ID))
 }
 
 // TestGetMyTasks tests the GetMyTasks use case
@@ -112,14 +329,223 @@ func (suite *TaskUsecaseSuite) TestGetMyTasks() {
 		{ID: primitive.NewObjectID(), Title: "Task 2", Description: "Description 2", Status: "Pending", CreatedBy: userId},
 	}
 
-	suite.taskRepo.On("GetMyTasks", userId).Return(tasks, nil)
+	suite.taskRepo.On("GetMyTasks", mock.Anything, userId).Return(tasks, nil)
 
-	result, err := suite.taskUsecase.GetMyTasks(userId)
+	result, err := suite.taskUsecase.GetMyTasks(context.Background(), userId)
 
 	assert.Nil(suite.T(), err)
 	assert.Equal(suite.T(), tasks, result)
 }
 
+// TestFindTasks tests that filters are validated before tasks are looked up
+func (suite *TaskUsecaseSuite) TestFindTasks() {
+	filter := domain.TaskFilter{CreatedBy: primitive.NewObjectID(), Labels: []primitive.ObjectID{primitive.NewObjectID()}, LabelMatch: domain.LabelMatchAny}
+	tasks := []domain.Task{{ID: primitive.NewObjectID(), Status: "Not Started", Labels: filter.Labels}}
+
+	suite.taskRepo.On("FindTasks", mock.Anything, filter).Return(tasks, nil).Once()
+
+	result, err := suite.taskUsecase.FindTasks(context.Background(), filter)
+	suite.NoError(err)
+	suite.Equal(tasks, result)
+
+	_, err = suite.taskUsecase.FindTasks(context.Background(), domain.TaskFilter{LabelMatch: "some"})
+	suite.ErrorIs(err, domain.ErrInvalidTask)
+
+	_, err = suite.taskUsecase.FindTasks(context.Background(), domain.TaskFilter{Status: "Done"})
+	suite.ErrorIs(err, domain.ErrInvalidTask)
+}
+
+// TestTaskLabelsKept tests that task updates cannot change labels
+func (suite *TaskUsecaseSuite) TestTaskLabelsKept() {
+	id := primitive.NewObjectID()
+	labels := []primitive.ObjectID{primitive.NewObjectID()}
+	stored := domain.Task{ID: id, Title: "Task 1", Description: "Description 1", Status: "In Progress", Labels: labels}
+	replacement := domain.Task{ID: id, Title: "Task 1", Description: "Changed", Status: "In Progress"}
+
+	suite.taskRepo.On("GetTaskById", mock.Anything, id).Return(stored, nil)
+	suite.taskRepo.On("UpdateFullTask", mock.Anything, id, mock.MatchedBy(func(task domain.Task) bool {
+		return task.Description == "Changed" && assert.ObjectsAreEqual(labels, task.Labels)
+	})).Return(nil).Once()
+
+	err := suite.taskUsecase.UpdateFullTask(context.Background(), id, replacement)
+	suite.NoError(err)
+
+	err = suite.taskUsecase.UpdateSomeTask(context.Background(), id, map[string]interface{}{"labels": []string{"x"}})
+	suite.ErrorIs(err, domain.ErrInvalidTask)
+}
+
+// allowAll authorizes every task
+func allowAll(ctx context.Context, task domain.Task) error {
+	return nil
+}
+
+// TestBulkTasks tests the BulkTasks use case without a transaction
+func (suite *TaskUsecaseSuite) TestBulkTasks() {
+	newTask := domain.Task{ID: primitive.NewObjectID(), Title: "Task 1", Description: "Description 1", Status: "Not Started", CreatedBy: primitive.NewObjectID()}
+	existing := domain.Task{ID: primitive.NewObjectID(), Title: "Task 2", Description: "Description 2", Status: "In Progress", CreatedBy: primitive.NewObjectID()}
+	forbidden := domain.Task{ID: primitive.NewObjectID(), CreatedBy: primitive.NewObjectID()}
+	missing := primitive.NewObjectID()
+
+	suite.taskRepo.On("AddTask", mock.Anything, newTask).Return(nil)
+	suite.taskRepo.On("GetTaskById", mock.Anything, existing.ID).Return(existing, nil)
+	suite.taskRepo.On("GetTaskById", mock.Anything, forbidden.ID).Return(forbidden, nil)
+	suite.taskRepo.On("GetTaskById", mock.Anything, missing).Return(domain.Task{}, mongo.ErrNoDocuments)
+	suite.taskRepo.On("UpdateSomeTask", mock.Anything, existing.ID, map[string]interface{}{"status": "Completed"}).Return(nil)
+
+	authorize := func(ctx context.Context, task domain.Task) error {
+		if task.ID == forbidden.ID {
+			return domain.ErrForbidden
+		}
+		return nil
+	}
+	results, err := suite.taskUsecase.BulkTasks(context.Background(), []domain.BulkTaskOperation{
+		{Op: domain.BulkCreate, Task: &newTask},
+		{Op: domain.BulkStatus, ID: existing.ID.Hex(), Status: "Completed"},
+		{Op: domain.BulkDelete, ID: forbidden.ID.Hex()},
+		{Op: domain.BulkDelete, ID: missing.Hex()},
+		{Op: domain.BulkStatus, ID: existing.ID.Hex(), Status: "Done"},
+		{Op: "archive", ID: existing.ID.Hex()},
+	}, false, authorize)
+
+	suite.Require().NoError(err)
+	suite.Require().Len(results, 6)
+	suite.NoError(results[0].Err)
+	suite.Equal(newTask.ID.Hex(), results[0].ID)
+	suite.NoError(results[1].Err)
+	suite.ErrorIs(results[2].Err, domain.ErrForbidden)
+	suite.ErrorIs(results[3].Err, mongo.ErrNoDocuments)
+	suite.ErrorIs(results[4].Err, domain.ErrInvalidTask)
+	suite.ErrorIs(results[5].Err, domain.ErrInvalidTask)
+	suite.taskRepo.AssertNotCalled(suite.T(), "DeleteTask", mock.Anything, mock.Anything)
+}
+
+// TestBulkTasksAtomic tests that an atomic bulk request stops at the first failure
+func (suite *TaskUsecaseSuite) TestBulkTasksAtomic() {
+	existing := domain.Task{ID: primitive.NewObjectID(), Status: "In Progress"}
+	invalidTask := domain.Task{ID: primitive.NewObjectID(), Title: "No description"}
+
+	suite.transactor.On("WithTransaction", mock.Anything, mock.Anything).Return(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	})
+	suite.taskRepo.On("GetTaskById", mock.Anything, existing.ID).Return(existing, nil)
+	suite.taskRepo.On("DeleteTask", mock.Anything, existing.ID).Return(nil)
+
+	results, err := suite.taskUsecase.BulkTasks(context.Background(), []domain.BulkTaskOperation{
+		{Op: domain.BulkDelete, ID: existing.ID.Hex()},
+		{Op: domain.BulkCreate, Task: &invalidTask},
+		{Op: domain.BulkDelete, ID: existing.ID.Hex()},
+	}, true, allowAll)
+
+	suite.Require().NoError(err)
+	suite.ErrorIs(results[0].Err, domain.ErrRolledBack)
+	suite.ErrorIs(results[1].Err, domain.ErrInvalidTask)
+	suite.ErrorIs(results[2].Err, domain.ErrRolledBack)
+	suite.taskRepo.AssertNumberOfCalls(suite.T(), "DeleteTask", 1)
+	// Nothing happened, so nothing is published
+	suite.events.AssertNotCalled(suite.T(), "PublishTaskEvent", mock.Anything, mock.Anything)
+}
+
+// TestOutbox tests that events are stored in the transaction of their change
+func (suite *TaskUsecaseSuite) TestOutbox() {
+	outbox := &mocks.OutboxRepository{}
+	defer outbox.AssertExpectations(suite.T())
+	taskUsecase := usecase.NewTaskUsecase(suite.taskRepo, suite.transactor, outbox, suite.events)
+
+	task := domain.Task{ID: primitive.NewObjectID(), Title: "Task 1", Description: "Description 1", Status: "Completed", CreatedBy: primitive.NewObjectID()}
+	inTransaction := false
+	suite.transactor.On("WithTransaction", mock.Anything, mock.Anything).Return(func(ctx context.Context, fn func(context.Context) error) error {
+		inTransaction = true
+		defer func() { inTransaction = false }()
+		return fn(ctx)
+	})
+	suite.taskRepo.On("AddTask", mock.Anything, task).Return(nil)
+	outbox.On("AddEvents", mock.Anything, mock.MatchedBy(func(events []domain.TaskEvent) bool {
+		return inTransaction && len(events) == 1 && events[0].Type == domain.EventTaskCreated
+	})).Return(nil).Once()
+
+	suite.NoError(taskUsecase.AddTask(context.Background(), task))
+
+	// A failed outbox write fails the change so the transaction rolls back
+	outbox.On("AddEvents", mock.Anything, mock.Anything).Return(errors.New("outbox unavailable")).Once()
+	suite.Error(taskUsecase.AddTask(context.Background(), task))
+
+	// Events go through the outbox only
+	suite.events.AssertNotCalled(suite.T(), "PublishTaskEvent", mock.Anything, mock.Anything)
+}
+
+// TestBulkTasksLimits tests that empty and oversized bulk requests are rejected
+func (suite *TaskUsecaseSuite) TestBulkTasksLimits() {
+	_, err := suite.taskUsecase.BulkTasks(context.Background(), nil, false, allowAll)
+	suite.ErrorIs(err, domain.ErrInvalidTask)
+
+	ops := make([]domain.BulkTaskOperation, domain.MaxBulkTaskOperations+1)
+	_, err = suite.taskUsecase.BulkTasks(context.Background(), ops, false, allowAll)
+	suite.ErrorIs(err, domain.ErrInvalidTask)
+}
+
+// TestImportTasks tests that valid rows are created and invalid rows are reported
+func (suite *TaskUsecaseSuite) TestImportTasks() {
+	valid := domain.Task{ID: primitive.NewObjectID(), Title: "Task 1", Description: "Description 1", Status: "Not Started", CreatedBy: primitive.NewObjectID()}
+	failing := domain.Task{ID: primitive.NewObjectID(), Title: "Task 2", Description: "Description 2", Status: "Completed", CreatedBy: primitive.NewObjectID()}
+
+	suite.taskRepo.On("AddTask", mock.Anything, valid).Return(nil)
+	suite.taskRepo.On("AddTask", mock.Anything, failing).Return(errors.New("write failed"))
+
+	results, err := suite.taskUsecase.ImportTasks(context.Background(), []domain.TaskImportRow{
+		{Row: 1, Task: valid},
+		{Row: 2, Task: domain.Task{Title: "No description", Status: "Done"}},
+		{Row: 3, Task: valid, Errors: []string{"invalid due date"}},
+		{Row: 4, Task: failing},
+	}, false)
+
+	suite.Require().NoError(err)
+	suite.Equal(domain.ImportCreated, results[0].Status)
+	suite.Equal(valid.ID.Hex(), results[0].ID)
+	suite.Equal(domain.ImportInvalid, results[1].Status)
+	suite.Len(results[1].Errors, 2)
+	suite.Equal(domain.ImportInvalid, results[2].Status)
+	suite.Equal(domain.ImportFailed, results[3].Status)
+	suite.taskRepo.AssertNumberOfCalls(suite.T(), "AddTask", 2)
+}
+
+// TestImportTasksDryRun tests that a dry run creates nothing
+func (suite *TaskUsecaseSuite) TestImportTasksDryRun() {
+	task := domain.Task{ID: primitive.NewObjectID(), Title: "Task 1", Description: "Description 1", Status: "Not Started"}
+
+	results, err := suite.taskUsecase.ImportTasks(context.Background(), []domain.TaskImportRow{{Row: 1, Task: task}}, true)
+
+	suite.Require().NoError(err)
+	suite.Equal(domain.ImportValid, results[0].Status)
+	suite.Empty(results[0].ID)
+
+	_, err = suite.taskUsecase.ImportTasks(context.Background(), make([]domain.TaskImportRow, domain.MaxImportRows+1), true)
+	suite.ErrorIs(err, domain.ErrInvalidTask)
+}
+
+// TestExportTasks tests that every streamed task reaches the callback
+func (suite *TaskUsecaseSuite) TestExportTasks() {
+	userId := primitive.NewObjectID()
+	tasks := []domain.Task{{ID: primitive.NewObjectID(), CreatedBy: userId}, {ID: primitive.NewObjectID(), CreatedBy: userId}}
+
+	suite.taskRepo.On("StreamTasks", mock.Anything, userId, mock.Anything).Return(func(ctx context.Context, createdBy primitive.ObjectID, fn func(domain.Task) error) error {
+		for _, task := range tasks {
+			if err := fn(task); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	var exported []domain.Task
+	err := suite.taskUsecase.ExportTasks(context.Background(), userId, func(task domain.Task) error {
+		exported = append(exported, task)
+		return nil
+	})
+
+	suite.Require().NoError(err)
+	suite.Equal(tasks, exported)
+}
+
 // TestTaskUsecaseSuite is the entry point for running the suite tests
 func TestTaskUsecaseSuite(t *testing.T) {
 	suite.Run(t, new(TaskUsecaseSuite))
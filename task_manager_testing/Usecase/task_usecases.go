@@ -1,8 +1,12 @@
 package usecase
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
+	infrastructure "task_manager_testing/Infrastructure"
 	"task_manager_testing/domain"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -10,64 +14,394 @@ import (
 // apply use cases for all epositories
 type TaskUsecase struct {
 	TaskRepository domain.TaskRepository
+	Transactor     domain.Transactor
+	// Outbox, when set, stores the events of every change in the change's
+	// transaction for a relay to deliver
+	Outbox domain.OutboxRepository
+	// Events otherwise receives the events right after every change
+	Events domain.TaskEventPublisher
+	// Series, when set, stores the series of recurring tasks
+	Series domain.TaskSeriesRepository
+	// StatusChanges, when set, records the status changes the reports are
+	// built on
+	StatusChanges domain.StatusChangeRepository
+	// Comments, when set, has the comments on deleted tasks removed together
+	// with them
+	Comments domain.CommentRepository
+	// Attachments and Blobs, when set, have the attachments of deleted tasks
+	// removed together with them
+	Attachments domain.AttachmentRepository
+	Blobs       domain.BlobStore
 }
 
-func NewTaskUsecase(taskRepository domain.TaskRepository) *TaskUsecase {
-	return &TaskUsecase{TaskRepository: taskRepository}
+func NewTaskUsecase(taskRepository domain.TaskRepository, transactor domain.Transactor, outbox domain.OutboxRepository, events domain.TaskEventPublisher) *TaskUsecase {
+	return &TaskUsecase{TaskRepository: taskRepository, Transactor: transactor, Outbox: outbox, Events: events}
 }
 
-func (tu *TaskUsecase) AddTask(task domain.Task) error {
-	if task.Title == "" {
-		return fmt.Errorf("task title cannot be empty")
+func (tu *TaskUsecase) AddTask(ctx context.Context, task domain.Task) (err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "TaskUsecase.AddTask")
+	defer infrastructure.EndSpan(span, &err)
+
+	if err := validateTask(task); err != nil {
+		return err
 	}
-	if task.Description == "" {
-		return fmt.Errorf("task description cannot be empty")
+	// Labels are checked and attached by a LabelUsecase
+	task.Labels = nil
+
+	err = tu.record(ctx, func(ctx context.Context) ([]domain.TaskEvent, error) {
+		return tu.createTask(ctx, task)
+	})
+	if err != nil {
+		return err
 	}
-	if task.Status == "" {
-		return fmt.Errorf("task status cannot be empty")
+
+	slog.InfoContext(ctx, "task created", slog.String("task_id", task.ID.Hex()), slog.String("status", task.Status))
+	return nil
+}
+
+func (tu *TaskUsecase) GetAllTasks(ctx context.Context) (tasks []domain.Task, err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "TaskUsecase.GetAllTasks")
+	defer infrastructure.EndSpan(span, &err)
+
+	tasks, err = tu.TaskRepository.GetAllTasks(ctx)
+	markOverdue(tasks, time.Now())
+	return tasks, err
+}
+
+func (tu *TaskUsecase) GetMyTasks(ctx context.Context, userId primitive.ObjectID) (tasks []domain.Task, err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "TaskUsecase.GetMyTasks")
+	defer infrastructure.EndSpan(span, &err)
+
+	tasks, err = tu.TaskRepository.GetMyTasks(ctx, userId)
+	markOverdue(tasks, time.Now())
+	return tasks, err
+}
+
+func (tu *TaskUsecase) GetTaskById(ctx context.Context, id primitive.ObjectID) (task domain.Task, err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "TaskUsecase.GetTaskById")
+	defer infrastructure.EndSpan(span, &err)
+
+	task, err = tu.TaskRepository.GetTaskById(ctx, id)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	task.Overdue = task.IsOverdue(time.Now())
+	return task, nil
+}
+
+func (tu *TaskUsecase) UpdateFullTask(ctx context.Context, id primitive.ObjectID, task domain.Task) (err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "TaskUsecase.UpdateFullTask")
+	defer infrastructure.EndSpan(span, &err)
+
+	// The recurrence is only changed for future occurrences
+	task.Recurrence = nil
+	if err := validateTask(task); err != nil {
+		return err
 	}
 
-	if task.Status != "Not Started" && task.Status != "In Progress" && task.Status != "Completed" {
-		return fmt.Errorf("task status must be one of 'Not Started', 'In Progress', or 'Completed'")
+	err = tu.record(ctx, func(ctx context.Context) ([]domain.TaskEvent, error) {
+		return tu.update(ctx, id, func(ctx context.Context, before domain.Task) error {
+			// A replaced task keeps its creator, its organization, its labels,
+			// its rank and its place in its series
+			task.CreatedBy, task.OrgID, task.Labels, task.Rank = before.CreatedBy, before.OrgID, before.Labels, before.Rank
+			task.Recurrence, task.SeriesID, task.Occurrence = before.Recurrence, before.SeriesID, before.Occurrence
+			return tu.TaskRepository.UpdateFullTask(ctx, id, task)
+		})
+	})
+	if err != nil {
+		return err
 	}
 
-	return tu.TaskRepository.AddTask(task)
+	slog.InfoContext(ctx, "task replaced", slog.String("task_id", id.Hex()), slog.String("status", task.Status))
+	return nil
 }
 
-func (tu *TaskUsecase) GetAllTasks() ([]domain.Task, error) {
-	return tu.TaskRepository.GetAllTasks()
+func (tu *TaskUsecase) UpdateSomeTask(ctx context.Context, id primitive.ObjectID, task map[string]interface{}) (err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "TaskUsecase.UpdateSomeTask")
+	defer infrastructure.EndSpan(span, &err)
+
+	if err := validateTaskUpdate(task); err != nil {
+		return err
+	}
+
+	err = tu.record(ctx, func(ctx context.Context) ([]domain.TaskEvent, error) {
+		return tu.update(ctx, id, func(ctx context.Context, _ domain.Task) error {
+			return tu.TaskRepository.UpdateSomeTask(ctx, id, task)
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	fields := make([]string, 0, len(task))
+	for field := range task {
+		fields = append(fields, field)
+	}
+	slog.InfoContext(ctx, "task updated", slog.String("task_id", id.Hex()), slog.Any("fields", fields))
+	return nil
+}
+
+func (tu *TaskUsecase) DeleteTask(ctx context.Context, id primitive.ObjectID) (err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "TaskUsecase.DeleteTask")
+	defer infrastructure.EndSpan(span, &err)
+
+	var blobKeys []string
+	err = tu.record(ctx, func(ctx context.Context) (events []domain.TaskEvent, err error) {
+		task, err := tu.TaskRepository.GetTaskById(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		events, blobKeys, err = tu.deleteTask(ctx, task)
+		return events, err
+	})
+	if err != nil {
+		return err
+	}
+	tu.removeBlobs(ctx, blobKeys)
+
+	slog.InfoContext(ctx, "task deleted", slog.String("task_id", id.Hex()))
+	return nil
 }
 
-func (tu *TaskUsecase) GetMyTasks(userId primitive.ObjectID) ([]domain.Task, error) {
-	return tu.TaskRepository.GetMyTasks(userId)
+// deleteTask removes task with its comments and attachments and returns the
+// events of the deletion and the keys of the attachment content. The content
+// is only removed with removeBlobs once the change is stored, as a rolled
+// back transaction would otherwise leave attachments without content.
+func (tu *TaskUsecase) deleteTask(ctx context.Context, task domain.Task) ([]domain.TaskEvent, []string, error) {
+	if err := tu.TaskRepository.DeleteTask(ctx, task.ID); err != nil {
+		return nil, nil, err
+	}
+	if err := tu.recordStatus(ctx, task, task.Status, ""); err != nil {
+		return nil, nil, err
+	}
+
+	if tu.Comments != nil {
+		if err := tu.Comments.DeleteTaskComments(ctx, task.ID); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var blobKeys []string
+	if tu.Attachments != nil {
+		attachments, err := tu.Attachments.DeleteTaskAttachments(ctx, task.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, attachment := range attachments {
+			blobKeys = append(blobKeys, attachment.BlobKey)
+		}
+	}
+	return []domain.TaskEvent{newTaskEvent(domain.EventTaskDeleted, task)}, blobKeys, nil
 }
 
-func (tu *TaskUsecase) GetTaskById(id primitive.ObjectID) (domain.Task, error) {
-	return tu.TaskRepository.GetTaskById(id)
+// removeBlobs deletes the content of the attachments of deleted tasks.
+// Failures only get logged; they leave an orphaned blob but no broken
+// attachment.
+func (tu *TaskUsecase) removeBlobs(ctx context.Context, keys []string) {
+	if tu.Blobs == nil {
+		return
+	}
+	for _, key := range keys {
+		if err := tu.Blobs.Delete(ctx, key); err != nil {
+			slog.WarnContext(ctx, "attachment content not removed", slog.String("blob_key", key), slog.Any("error", err))
+		}
+	}
 }
 
-func (tu *TaskUsecase) UpdateFullTask(id primitive.ObjectID, task domain.Task) error {
+// FindTasks returns the tasks matching filter. Label filters require every
+// label unless LabelMatch is domain.LabelMatchAny.
+func (tu *TaskUsecase) FindTasks(ctx context.Context, filter domain.TaskFilter) (tasks []domain.Task, err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "TaskUsecase.FindTasks")
+	defer infrastructure.EndSpan(span, &err)
+
+	if err := validateTaskFilter(filter); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidTask, err)
+	}
+	tasks, err = tu.TaskRepository.FindTasks(ctx, filter)
+	markOverdue(tasks, time.Now())
+	return tasks, err
+}
+
+// SetTaskLabels stores labels as the labels of the task id.
+func (tu *TaskUsecase) SetTaskLabels(ctx context.Context, id primitive.ObjectID, labels []primitive.ObjectID) (err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "TaskUsecase.SetTaskLabels")
+	defer infrastructure.EndSpan(span, &err)
+
+	if labels == nil {
+		labels = []primitive.ObjectID{}
+	}
+	err = tu.record(ctx, func(ctx context.Context) ([]domain.TaskEvent, error) {
+		return tu.update(ctx, id, func(ctx context.Context, _ domain.Task) error {
+			return tu.TaskRepository.UpdateSomeTask(ctx, id, map[string]interface{}{"labels": labels})
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "task labels set", slog.String("task_id", id.Hex()), slog.Int("labels", len(labels)))
+	return nil
+}
+
+// update runs write on the task id, given the task before the change, and
+// returns the events of the change, which may generate the next occurrence of
+// a recurring task.
+func (tu *TaskUsecase) update(ctx context.Context, id primitive.ObjectID, write func(ctx context.Context, before domain.Task) error) ([]domain.TaskEvent, error) {
+	before, err := tu.TaskRepository.GetTaskById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := write(ctx, before); err != nil {
+		return nil, err
+	}
+	after, err := tu.TaskRepository.GetTaskById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return tu.changeEvents(ctx, before, after)
+}
+
+// markOverdue sets the Overdue flag of every task at now.
+func markOverdue(tasks []domain.Task, now time.Time) {
+	for i := range tasks {
+		tasks[i].Overdue = tasks[i].IsOverdue(now)
+	}
+}
+
+// validateTask checks the fields every stored task must have.
+func validateTask(task domain.Task) error {
+	if problems := taskProblems(task); len(problems) > 0 {
+		return problems[0]
+	}
+	return nil
+}
+
+// taskProblems returns every validation error of task.
+func taskProblems(task domain.Task) []error {
+	var problems []error
 	if task.Title == "" {
-		return fmt.Errorf("task title cannot be empty")
+		problems = append(problems, fmt.Errorf("task title cannot be empty"))
 	}
 	if task.Description == "" {
-		return fmt.Errorf("task description cannot be empty")
+		problems = append(problems, fmt.Errorf("task description cannot be empty"))
 	}
 	if task.Status == "" {
-		return fmt.Errorf("task status cannot be empty")
+		problems = append(problems, fmt.Errorf("task status cannot be empty"))
+	} else if err := validateStatus(task.Status); err != nil {
+		problems = append(problems, err)
+	}
+	if task.Recurrence != nil {
+		if err := validateRecurrence(*task.Recurrence, task.DueDate); err != nil {
+			problems = append(problems, err)
+		}
 	}
+	if task.Priority != "" {
+		if err := validatePriority(task.Priority); err != nil {
+			problems = append(problems, err)
+		}
+	}
+	if err := validateEstimates(task.StoryPoints, task.EstimateHours); err != nil {
+		problems = append(problems, err)
+	}
+	return problems
+}
 
-	if task.Status != "Not Started" && task.Status != "In Progress" && task.Status != "Completed" {
-		return fmt.Errorf("task status must be one of 'Not Started', 'In Progress', or 'Completed'")
+// validateTaskUpdate checks the fields of a partial update, made on its own or
+// in bulk. Identity, ownership, organization, labels, rank and the place in a
+// series have endpoints of their own or cannot change at all.
+func validateTaskUpdate(fields map[string]interface{}) error {
+	for field, value := range fields {
+		switch field {
+		case "_id", "id", "created_by", "org_id":
+			return invalid("field %s cannot be changed", field)
+		case "labels":
+			return invalid("field labels cannot be changed here, use the task label endpoints")
+		case "rank":
+			return invalid("field rank cannot be changed here, move the task on the board")
+		case "recurrence", "series_id", "occurrence":
+			return invalid("field %s can only be changed for future occurrences", field)
+		case "status":
+			status, _ := value.(string)
+			if err := validateStatus(status); err != nil {
+				return invalid("%v", err)
+			}
+		}
+	}
+	if err := validateTaskFields(fields); err != nil {
+		return invalid("%v", err)
+	}
+	return nil
+}
+
+// validateTaskFields checks the priority and the estimates among the fields
+// of a partial update.
+func validateTaskFields(fields map[string]interface{}) error {
+	if value, ok := fields["priority"]; ok {
+		priority, _ := value.(string)
+		if err := validatePriority(priority); err != nil {
+			return err
+		}
+	}
+	var points, hours float64
+	for field, estimate := range map[string]*float64{"story_points": &points, "estimate_hours": &hours} {
+		value, ok := fields[field]
+		if !ok {
+			continue
+		}
+		switch number := value.(type) {
+		case float64:
+			*estimate = number
+		case int:
+			*estimate = float64(number)
+		case int32:
+			*estimate = float64(number)
+		case int64:
+			*estimate = float64(number)
+		default:
+			return fmt.Errorf("task %s must be a number", field)
+		}
+	}
+	return validateEstimates(points, hours)
+}
+
+func validatePriority(priority string) error {
+	switch priority {
+	case domain.PriorityLow, domain.PriorityMedium, domain.PriorityHigh, domain.PriorityUrgent:
+		return nil
 	}
+	return fmt.Errorf("task priority must be one of %q, %q, %q or %q", domain.PriorityLow, domain.PriorityMedium, domain.PriorityHigh, domain.PriorityUrgent)
+}
 
-	return tu.TaskRepository.UpdateFullTask(id, task)
+// validateEstimates checks that the estimates are within their bounds.
+func validateEstimates(storyPoints, estimateHours float64) error {
+	if storyPoints < 0 || storyPoints > domain.MaxStoryPoints {
+		return fmt.Errorf("task story points must be between 0 and %d", domain.MaxStoryPoints)
+	}
+	if estimateHours < 0 || estimateHours > domain.MaxEstimateHours {
+		return fmt.Errorf("task estimate hours must be between 0 and %d", domain.MaxEstimateHours)
+	}
+	return nil
 }
 
-func (tu *TaskUsecase) UpdateSomeTask(id primitive.ObjectID, task map[string]interface{}) error {
-	return tu.TaskRepository.UpdateSomeTask(id, task)
+// validateTaskFilter checks the status, labels and label match of filter.
+func validateTaskFilter(filter domain.TaskFilter) error {
+	if filter.Status != "" {
+		if err := validateStatus(filter.Status); err != nil {
+			return err
+		}
+	}
+	if len(filter.Labels) > domain.MaxTaskLabels {
+		return fmt.Errorf("at most %d labels can be filtered on", domain.MaxTaskLabels)
+	}
+	if filter.LabelMatch != "" && filter.LabelMatch != domain.LabelMatchAll && filter.LabelMatch != domain.LabelMatchAny {
+		return fmt.Errorf("label match must be %q or %q", domain.LabelMatchAll, domain.LabelMatchAny)
+	}
+	return nil
 }
 
-func (tu *TaskUsecase) DeleteTask(id primitive.ObjectID) error {
-	return tu.TaskRepository.DeleteTask(id)
+func validateStatus(status string) error {
+	if status != "Not Started" && status != "In Progress" && status != "Completed" {
+		return fmt.Errorf("task status must be one of 'Not Started', 'In Progress', or 'Completed'")
+	}
+	return nil
 }
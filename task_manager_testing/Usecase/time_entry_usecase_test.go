@@ -0,0 +1,141 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	usecase "task_manager_testing/Usecase"
+	"task_manager_testing/domain"
+	"task_manager_testing/mocks"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// TimeEntryUsecaseSuite defines the suite for time tracking usecase tests
+type TimeEntryUsecaseSuite struct {
+	suite.Suite
+	entryRepo        *mocks.TimeEntryRepository
+	taskRepo         *mocks.TaskRepository
+	userRepo         *mocks.UserRepository
+	timeEntryUsecase *usecase.TimeEntryUsecase
+
+	owner domain.User
+	other domain.User
+	admin domain.User
+	task  domain.Task
+}
+
+// SetupTest sets up the necessary resources before each test
+func (suite *TimeEntryUsecaseSuite) SetupTest() {
+	suite.entryRepo = &mocks.TimeEntryRepository{}
+	suite.taskRepo = &mocks.TaskRepository{}
+	suite.userRepo = &mocks.UserRepository{}
+	suite.timeEntryUsecase = usecase.NewTimeEntryUsecase(suite.entryRepo, suite.taskRepo, suite.userRepo)
+
+	suite.owner = domain.User{ID: primitive.NewObjectID(), Username: "alice", Role: "user"}
+	suite.other = domain.User{ID: primitive.NewObjectID(), Username: "bob", Role: "user"}
+	suite.admin = domain.User{ID: primitive.NewObjectID(), Username: "carol", Role: "admin"}
+	suite.task = domain.Task{ID: primitive.NewObjectID(), Title: "Tracked", CreatedBy: suite.owner.ID}
+
+	suite.taskRepo.On("GetTaskById", mock.Anything, suite.task.ID).Return(suite.task, nil).Maybe()
+	for _, user := range []domain.User{suite.owner, suite.other, suite.admin} {
+		suite.userRepo.On("GetUserById", mock.Anything, user.ID).Return(user, nil).Maybe()
+	}
+}
+
+// TearDownTest checks the mock expectations after each test
+func (suite *TimeEntryUsecaseSuite) TearDownTest() {
+	suite.entryRepo.AssertExpectations(suite.T())
+	suite.taskRepo.AssertExpectations(suite.T())
+	suite.userRepo.AssertExpectations(suite.T())
+}
+
+// TestStartTimer tests that timers start on visible tasks only
+func (suite *TimeEntryUsecaseSuite) TestStartTimer() {
+	suite.entryRepo.On("StartEntry", mock.Anything, mock.MatchedBy(func(entry domain.TimeEntry) bool {
+		return entry.TaskID == suite.task.ID && entry.UserID == suite.owner.ID && entry.Running
+	})).Return(nil).Once()
+
+	entry, err := suite.timeEntryUsecase.StartTimer(context.Background(), suite.owner, suite.task.ID)
+	suite.Require().NoError(err)
+	suite.False(entry.ID.IsZero())
+	suite.True(entry.Running)
+
+	_, err = suite.timeEntryUsecase.StartTimer(context.Background(), suite.other, suite.task.ID)
+	suite.ErrorIs(err, mongo.ErrNoDocuments)
+
+	suite.entryRepo.On("StartEntry", mock.Anything, mock.Anything).Return(domain.ErrTimerRunning).Once()
+	_, err = suite.timeEntryUsecase.StartTimer(context.Background(), suite.admin, suite.task.ID)
+	suite.ErrorIs(err, domain.ErrTimerRunning)
+}
+
+// TestStopTimer tests that the caller's timer is stopped
+func (suite *TimeEntryUsecaseSuite) TestStopTimer() {
+	stopped := domain.TimeEntry{ID: primitive.NewObjectID(), TaskID: suite.task.ID, UserID: suite.owner.ID, Seconds: 90}
+	suite.entryRepo.On("StopEntry", mock.Anything, suite.owner.ID, suite.task.ID, mock.AnythingOfType("time.Time")).Return(stopped, nil).Once()
+	suite.entryRepo.On("StopEntry", mock.Anything, suite.admin.ID, suite.task.ID, mock.AnythingOfType("time.Time")).Return(domain.TimeEntry{}, domain.ErrTimerNotRunning).Once()
+
+	entry, err := suite.timeEntryUsecase.StopTimer(context.Background(), suite.owner, suite.task.ID)
+	suite.Require().NoError(err)
+	suite.Equal(int64(90), entry.Seconds)
+
+	_, err = suite.timeEntryUsecase.StopTimer(context.Background(), suite.admin, suite.task.ID)
+	suite.ErrorIs(err, domain.ErrTimerNotRunning)
+}
+
+// TestGetTaskTime tests the totals per user, running entries included
+func (suite *TimeEntryUsecaseSuite) TestGetTaskTime() {
+	started := primitive.NewDateTimeFromTime(time.Now().Add(-time.Hour))
+	suite.entryRepo.On("GetEntries", mock.Anything, suite.task.ID).Return([]domain.TimeEntry{
+		{UserID: suite.owner.ID, Seconds: 600},
+		{UserID: suite.admin.ID, Seconds: 300},
+		{UserID: suite.owner.ID, StartedAt: started, Running: true},
+	}, nil)
+
+	taskTime, err := suite.timeEntryUsecase.GetTaskTime(context.Background(), suite.owner, suite.task.ID)
+	suite.Require().NoError(err)
+	suite.Len(taskTime.Entries, 3)
+	suite.Require().Len(taskTime.Users, 2)
+	suite.Equal("alice", taskTime.Users[0].Username)
+	suite.InDelta(600+3600, taskTime.Users[0].Seconds, 2)
+	suite.Equal("carol", taskTime.Users[1].Username)
+	suite.Equal(int64(300), taskTime.Users[1].Seconds)
+	suite.InDelta(900+3600, taskTime.Seconds, 2)
+}
+
+// TestTimeReportsScope tests that users only see their own time
+func (suite *TimeEntryUsecaseSuite) TestTimeReportsScope() {
+	ctx := context.Background()
+	suite.entryRepo.On("TimeByUser", mock.Anything, domain.TimeQuery{UserID: suite.owner.ID}).Return([]domain.UserTime{{UserID: suite.owner.ID, Seconds: 60}}, nil).Once()
+	suite.entryRepo.On("TimeByUser", mock.Anything, domain.TimeQuery{}).Return([]domain.UserTime{{UserID: suite.other.ID, Seconds: 120}, {UserID: suite.owner.ID, Seconds: 60}}, nil).Once()
+	suite.entryRepo.On("TimeByWeek", mock.Anything, domain.TimeQuery{UserID: suite.owner.ID}).Return([]domain.WeekTime{{Year: 2024, Week: 1, Seconds: 60}}, nil).Once()
+
+	totals, err := suite.timeEntryUsecase.TimeByUser(ctx, suite.owner, domain.TimeQuery{})
+	suite.Require().NoError(err)
+	suite.Equal([]domain.UserTime{{UserID: suite.owner.ID, Username: "alice", Seconds: 60}}, totals)
+
+	totals, err = suite.timeEntryUsecase.TimeByUser(ctx, suite.admin, domain.TimeQuery{})
+	suite.Require().NoError(err)
+	suite.Len(totals, 2)
+	suite.Equal("bob", totals[0].Username)
+
+	weeks, err := suite.timeEntryUsecase.TimeByWeek(ctx, suite.owner, domain.TimeQuery{})
+	suite.Require().NoError(err)
+	suite.Len(weeks, 1)
+
+	_, err = suite.timeEntryUsecase.TimeByUser(ctx, suite.owner, domain.TimeQuery{UserID: suite.other.ID})
+	suite.ErrorIs(err, domain.ErrForbidden)
+
+	now := time.Now()
+	_, err = suite.timeEntryUsecase.TimeByWeek(ctx, suite.admin, domain.TimeQuery{From: now, To: now.Add(-time.Hour)})
+	suite.ErrorIs(err, domain.ErrInvalidTimeQuery)
+}
+
+// TestTimeEntryUsecaseSuite is the entry point for running the suite tests
+func TestTimeEntryUsecaseSuite(t *testing.T) {
+	suite.Run(t, new(TimeEntryUsecaseSuite))
+}
@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	infrastructure "task_manager_testing/Infrastructure"
+	"task_manager_testing/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type TimeEntryUsecase struct {
+	entryRepo domain.TimeEntryRepository
+	taskRepo  domain.TaskRepository
+	userRepo  domain.UserRepository
+}
+
+func NewTimeEntryUsecase(entryRepo domain.TimeEntryRepository, taskRepo domain.TaskRepository, userRepo domain.UserRepository) *TimeEntryUsecase {
+	return &TimeEntryUsecase{entryRepo: entryRepo, taskRepo: taskRepo, userRepo: userRepo}
+}
+
+func (tu *TimeEntryUsecase) StartTimer(ctx context.Context, actor domain.User, taskID primitive.ObjectID) (entry domain.TimeEntry, err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "TimeEntryUsecase.StartTimer")
+	defer infrastructure.EndSpan(span, &err)
+
+	if _, err := visibleTask(ctx, tu.taskRepo, actor, taskID); err != nil {
+		return domain.TimeEntry{}, err
+	}
+	entry = domain.TimeEntry{
+		ID:        primitive.NewObjectID(),
+		TaskID:    taskID,
+		UserID:    actor.ID,
+		StartedAt: primitive.NewDateTimeFromTime(time.Now()),
+		Running:   true,
+	}
+	if err := tu.entryRepo.StartEntry(ctx, entry); err != nil {
+		return domain.TimeEntry{}, err
+	}
+
+	slog.InfoContext(ctx, "timer started", slog.String("task_id", taskID.Hex()), slog.String("entry_id", entry.ID.Hex()))
+	return entry, nil
+}
+
+func (tu *TimeEntryUsecase) StopTimer(ctx context.Context, actor domain.User, taskID primitive.ObjectID) (entry domain.TimeEntry, err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "TimeEntryUsecase.StopTimer")
+	defer infrastructure.EndSpan(span, &err)
+
+	if _, err := visibleTask(ctx, tu.taskRepo, actor, taskID); err != nil {
+		return domain.TimeEntry{}, err
+	}
+	entry, err = tu.entryRepo.StopEntry(ctx, actor.ID, taskID, time.Now())
+	if err != nil {
+		return domain.TimeEntry{}, err
+	}
+
+	slog.InfoContext(ctx, "timer stopped", slog.String("task_id", taskID.Hex()), slog.String("entry_id", entry.ID.Hex()), slog.Int64("seconds", entry.Seconds))
+	return entry, nil
+}
+
+// GetTaskTime counts running entries up to now.
+func (tu *TimeEntryUsecase) GetTaskTime(ctx context.Context, actor domain.User, taskID primitive.ObjectID) (taskTime domain.TaskTime, err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "TimeEntryUsecase.GetTaskTime")
+	defer infrastructure.EndSpan(span, &err)
+
+	if _, err := visibleTask(ctx, tu.taskRepo, actor, taskID); err != nil {
+		return domain.TaskTime{}, err
+	}
+	entries, err := tu.entryRepo.GetEntries(ctx, taskID)
+	if err != nil {
+		return domain.TaskTime{}, err
+	}
+
+	now := time.Now()
+	taskTime = domain.TaskTime{Entries: entries, Users: []domain.UserTime{}}
+	if entries == nil {
+		taskTime.Entries = []domain.TimeEntry{}
+	}
+	index := make(map[primitive.ObjectID]int)
+	for _, entry := range entries {
+		seconds := entry.Seconds
+		if entry.Running {
+			seconds = int64(now.Sub(entry.StartedAt.Time()) / time.Second)
+		}
+		i, ok := index[entry.UserID]
+		if !ok {
+			i = len(taskTime.Users)
+			index[entry.UserID] = i
+			taskTime.Users = append(taskTime.Users, domain.UserTime{UserID: entry.UserID})
+		}
+		taskTime.Users[i].Seconds += seconds
+		taskTime.Seconds += seconds
+	}
+	sort.SliceStable(taskTime.Users, func(i, j int) bool { return taskTime.Users[i].Seconds > taskTime.Users[j].Seconds })
+	tu.nameUsers(ctx, taskTime.Users)
+	return taskTime, nil
+}
+
+func (tu *TimeEntryUsecase) TimeByUser(ctx context.Context, actor domain.User, query domain.TimeQuery) (totals []domain.UserTime, err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "TimeEntryUsecase.TimeByUser")
+	defer infrastructure.EndSpan(span, &err)
+
+	if query, err = scopeTimeQuery(actor, query); err != nil {
+		return nil, err
+	}
+	totals, err = tu.entryRepo.TimeByUser(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	tu.nameUsers(ctx, totals)
+	return totals, nil
+}
+
+func (tu *TimeEntryUsecase) TimeByWeek(ctx context.Context, actor domain.User, query domain.TimeQuery) (weeks []domain.WeekTime, err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "TimeEntryUsecase.TimeByWeek")
+	defer infrastructure.EndSpan(span, &err)
+
+	if query, err = scopeTimeQuery(actor, query); err != nil {
+		return nil, err
+	}
+	return tu.entryRepo.TimeByWeek(ctx, query)
+}
+
+// nameUsers fills in the usernames of totals. Deleted users keep an empty name.
+func (tu *TimeEntryUsecase) nameUsers(ctx context.Context, totals []domain.UserTime) {
+	for i := range totals {
+		if user, err := tu.userRepo.GetUserById(ctx, totals[i].UserID); err == nil {
+			totals[i].Username = user.Username
+		}
+	}
+}
+
+// scopeTimeQuery checks query and limits users to their own time.
+func scopeTimeQuery(actor domain.User, query domain.TimeQuery) (domain.TimeQuery, error) {
+	if !query.From.IsZero() && !query.To.IsZero() && !query.From.Before(query.To) {
+		return domain.TimeQuery{}, fmt.Errorf("%w: from must be before to", domain.ErrInvalidTimeQuery)
+	}
+	if actor.Role == "admin" || actor.Role == "root" {
+		return query, nil
+	}
+	if !query.UserID.IsZero() && query.UserID != actor.ID {
+		return domain.TimeQuery{}, fmt.Errorf("%w: you can only see your own tracked time", domain.ErrForbidden)
+	}
+	query.UserID = actor.ID
+	return query, nil
+}
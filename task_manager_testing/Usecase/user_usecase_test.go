@@ -1,8 +1,8 @@
 package usecase_test
 
 import (
+	"context"
 	"fmt"
-	// "log"
 	"testing"
 
 	// infrastructure "task_manager_testing/Infrastructure"
@@ -38,9 +38,9 @@ func (suite *UserUsecaseSuite) TestRegisterUser() {
 		Role:     "user",
 	}
 
-	suite.userRepo.On("RegisterUser", user.Username, mock.AnythingOfType("string"), user.Role).Return(nil).
+	suite.userRepo.On("RegisterUser", mock.Anything, user.Username, mock.AnythingOfType("string"), user.Role).Return(nil).
 		Run(func(args mock.Arguments) {
-			password := args.Get(1).(string)
+			password := args.Get(2).(string)
 			err := infrastructure.ComparePasswords(password, user.Password)
 			suite.NoError(err)
 			user.Password = password
@@ -48,7 +48,7 @@ func (suite *UserUsecaseSuite) TestRegisterUser() {
 		})
 
 	// Execute the test case
-	err := suite.userUsecase.RegisterUser(user.Username, user.Password, user.Role)
+	err := suite.userUsecase.RegisterUser(context.Background(), user.Username, user.Password, user.Role)
 
 	// Verify the test results
 	suite.Require().NoError(err)
@@ -106,10 +106,10 @@ func (suite *UserUsecaseSuite) TestLogin() {
 			}
 
 			// Mock the Login method
-			suite.userRepo.On("Login", tc.user.Username, mock.AnythingOfType("string")).Return(tc.mockReturn, tc.mockError)
+			suite.userRepo.On("Login", mock.Anything, tc.user.Username, mock.AnythingOfType("string")).Return(tc.mockReturn, tc.mockError)
 
 			// Execute the test case
-			result, err := suite.userUsecase.Login(tc.user.Username, tc.inputPassword)
+			result, err := suite.userUsecase.Login(context.Background(), tc.user.Username, tc.inputPassword)
 
 			// Verify the test results
 			if tc.expectedError {
@@ -139,10 +139,10 @@ func (suite *UserUsecaseSuite) TestGetAllUsers() {
 		},
 	}
 
-	suite.userRepo.On("GetAllUsers").Return(users, nil)
+	suite.userRepo.On("GetAllUsers", mock.Anything, mock.Anything).Return(users, nil)
 
 	// Execute the test case
-	result, err := suite.userUsecase.GetAllUsers()
+	result, err := suite.userUsecase.GetAllUsers(context.Background())
 
 	// Verify the test results
 	suite.Require().NoError(err)
@@ -207,10 +207,10 @@ func (suite *UserUsecaseSuite) TestGetUserById() {
 			}
 
 			// Mock the GetUserById method to return the user or error
-			suite.userRepo.On("GetUserById", tc.userID).Return(tc.storedUser, tc.mockError)
+			suite.userRepo.On("GetUserById", mock.Anything, tc.userID).Return(tc.storedUser, tc.mockError)
 
 			// Call the GetUserById usecase method
-			result, err := suite.userUsecase.GetUserById(tc.userID)
+			result, err := suite.userUsecase.GetUserById(context.Background(), tc.userID)
 
 			if tc.expectedError {
 				suite.Error(err)
@@ -230,43 +230,113 @@ func (suite *UserUsecaseSuite) TestGetUserById() {
 	}
 }
 
-// func (suite *UserUsecaseSuite) TestUpdateUser() {
-// 	// Setup the test case
-// 	user := domain.User{
-// 		ID:       primitive.NewObjectID(),
-// 		Username: "tester1",
-// 		Password: "12345678",
-// 		Role:     "user",
-// 	}
+// TestUpdateUser tests that UpdateUser only changes the provided fields
+func (suite *UserUsecaseSuite) TestUpdateUser() {
+	hashedPassword, err := infrastructure.HashPassword("12345678")
+	suite.Require().NoError(err)
+
+	stored := domain.User{
+		ID:       primitive.NewObjectID(),
+		Username: "tester1",
+		Password: hashedPassword,
+		Role:     "user",
+	}
+
+	suite.userRepo.On("GetUserById", mock.Anything, stored.ID).Return(stored, nil)
+	suite.userRepo.On("UpdateUser", mock.Anything, stored.ID, mock.AnythingOfType("domain.User")).Return(nil).
+		Run(func(args mock.Arguments) {
+			updated := args.Get(2).(domain.User)
+			suite.Equal("tester2", updated.Username)
+			suite.Equal("user", updated.Role)
+			// The password must be left untouched when not provided
+			suite.Equal(hashedPassword, updated.Password)
+		})
+
+	err = suite.userUsecase.UpdateUser(context.Background(), stored.ID, domain.User{Username: "tester2"})
+
+	suite.Require().NoError(err)
+}
+
+// TestUpdateUserWithPassword tests that a provided password is re-hashed
+func (suite *UserUsecaseSuite) TestUpdateUserWithPassword() {
+	stored := domain.User{
+		ID:       primitive.NewObjectID(),
+		Username: "tester1",
+		Password: "old-hash",
+		Role:     "user",
+	}
+
+	suite.userRepo.On("GetUserById", mock.Anything, stored.ID).Return(stored, nil)
+	suite.userRepo.On("UpdateUser", mock.Anything, stored.ID, mock.AnythingOfType("domain.User")).Return(nil).
+		Run(func(args mock.Arguments) {
+			updated := args.Get(2).(domain.User)
+			suite.Equal("tester1", updated.Username)
+			suite.NoError(infrastructure.ComparePasswords(updated.Password, "newPassword"))
+		})
 
-// 	// Hash the password before setting it in the mock repository
-// 	// hashedPassword, err := infrastructure.HashPassword(user.Password)
-// 	// suite.Require().NoError(err)
+	err := suite.userUsecase.UpdateUser(context.Background(), stored.ID, domain.User{Password: "newPassword"})
 
-// 	// // Update the user object with the hashed password
-// 	// storedUser := user
-// 	// storedUser.Password = hashedPassword
+	suite.Require().NoError(err)
+}
+
+// TestChangePassword tests the ChangePassword functionality
+func (suite *UserUsecaseSuite) TestChangePassword() {
+	hashedPassword, err := infrastructure.HashPassword("oldPassword")
+	suite.Require().NoError(err)
+
+	testCases := []struct {
+		name          string
+		oldPassword   string
+		newPassword   string
+		expectedError error
+	}{
+		{
+			name:        "Valid change",
+			oldPassword: "oldPassword",
+			newPassword: "newPassword",
+		},
+		{
+			name:          "Wrong old password",
+			oldPassword:   "wrongPassword",
+			newPassword:   "newPassword",
+			expectedError: domain.ErrInvalidPassword,
+		},
+		{
+			name:          "Empty new password",
+			oldPassword:   "oldPassword",
+			newPassword:   "",
+			expectedError: domain.ErrEmptyPassword,
+		},
+	}
 
-// 	// Mock the UpdateUser method to return the user with the hashed password
-// 	// suite.userRepo.On("UpdateUser", user.ID, user).Return(nil)
-// 	suite.userRepo.On("UpdateUser", user.ID, user).Return(nil).
-// 		Run(func(args mock.Arguments) {
-// 			password := args.Get(1).(string)
-// 			log.Print(password)
-// 			err := infrastructure.ComparePasswords(password, user.Password)
-// 			suite.NoError(err)
-// 			user.Password = password
-// 		})
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			suite.SetupTest()
+			stored := domain.User{ID: primitive.NewObjectID(), Username: "tester1", Password: hashedPassword, Role: "user"}
 
-// 	// Call the UpdateUser usecase method
+			if tc.newPassword != "" {
+				suite.userRepo.On("GetUserById", mock.Anything, stored.ID).Return(stored, nil)
+			}
+			if tc.expectedError == nil {
+				suite.userRepo.On("UpdateUser", mock.Anything, stored.ID, mock.AnythingOfType("domain.User")).Return(nil).
+					Run(func(args mock.Arguments) {
+						updated := args.Get(2).(domain.User)
+						suite.NoError(infrastructure.ComparePasswords(updated.Password, tc.newPassword))
+					})
+			}
 
-// 	err := suite.userUsecase.UpdateUser(user.ID, user)
-// 	// Verify the test results
-// 	suite.Require().NoError(err)
+			err := suite.userUsecase.ChangePassword(context.Background(), stored.ID, tc.oldPassword, tc.newPassword)
 
-// 	suite.userRepo.AssertExpectations(suite.T())
+			if tc.expectedError != nil {
+				suite.ErrorIs(err, tc.expectedError)
+			} else {
+				suite.NoError(err)
+			}
 
-// }
+			suite.userRepo.AssertExpectations(suite.T())
+		})
+	}
+}
 
 func (suite *UserUsecaseSuite) TestDeleteUser() {
 	user := domain.User{
@@ -310,10 +380,10 @@ func (suite *UserUsecaseSuite) TestDeleteUser() {
 	for _, tc := range testCases {
 		suite.Run(tc.name, func() {
 			// Mock the DeleteUser method to return the error if expected
-			suite.userRepo.On("DeleteUser", tc.userID).Return(tc.mockReturnErr)
+			suite.userRepo.On("DeleteUser", mock.Anything, tc.userID).Return(tc.mockReturnErr)
 
 			// Call the DeleteUser usecase method
-			err := suite.userUsecase.DeleteUser(tc.userID)
+			err := suite.userUsecase.DeleteUser(context.Background(), tc.userID)
 
 			// Verify the test results
 			if tc.expectedError {
@@ -329,6 +399,31 @@ func (suite *UserUsecaseSuite) TestDeleteUser() {
 }
 
 // TearDownTest clears resources after each test
+// TestRotateCalendarToken tests that only the hash of a new calendar token is stored
+func (suite *UserUsecaseSuite) TestRotateCalendarToken() {
+	id := primitive.NewObjectID()
+	user := domain.User{ID: id, Username: "tester1", CalendarToken: "old-hash"}
+
+	var stored domain.User
+	suite.userRepo.On("GetUserById", mock.Anything, id).Return(user, nil).Once()
+	suite.userRepo.On("UpdateUser", mock.Anything, id, mock.AnythingOfType("domain.User")).Return(nil).Once().
+		Run(func(args mock.Arguments) {
+			stored = args.Get(2).(domain.User)
+		})
+
+	token, err := suite.userUsecase.RotateCalendarToken(context.Background(), id)
+
+	suite.Require().NoError(err)
+	suite.NotEmpty(token)
+	suite.Equal(infrastructure.HashCalendarToken(token), stored.CalendarToken)
+	suite.Equal("tester1", stored.Username)
+
+	suite.userRepo.On("GetUserByCalendarToken", mock.Anything, stored.CalendarToken).Return(stored, nil).Once()
+	found, err := suite.userUsecase.GetUserByCalendarToken(context.Background(), token)
+	suite.Require().NoError(err)
+	suite.Equal(id, found.ID)
+}
+
 func (suite *UserUsecaseSuite) TearDownTest() {
 	// Reset the mock expectations
 	suite.userRepo.AssertExpectations(suite.T())
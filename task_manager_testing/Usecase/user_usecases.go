@@ -1,55 +1,212 @@
 package usecase
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
 	infrastructure "task_manager_testing/Infrastructure"
 	"task_manager_testing/domain"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 type UserUsecase struct {
 	userRepo domain.UserRepository
+	// Organizations, when set, is checked for the organization users
+	// register in
+	Organizations domain.OrganizationRepository
 }
 
 func NewUserUsecase(userRepo domain.UserRepository) *UserUsecase {
 	return &UserUsecase{userRepo: userRepo}
 }
 
-func (uu *UserUsecase) RegisterUser(username, password, role string) error {
-	hashedPassword,err := infrastructure.HashPassword(password)
+func (uu *UserUsecase) RegisterUser(ctx context.Context, username, password, role string) (err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "UserUsecase.RegisterUser")
+	defer infrastructure.EndSpan(span, &err)
+
+	// The user is registered in the organization of ctx, which must exist
+	if orgID, ok := domain.OrgFromContext(ctx); ok && !orgID.IsZero() && uu.Organizations != nil {
+		_, err := uu.Organizations.GetOrganizationById(ctx, orgID)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return fmt.Errorf("%w: organization %s does not exist", domain.ErrInvalidOrganization, orgID.Hex())
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	hashedPassword,err := infrastructure.HashPasswordContext(ctx, password)
 	if err != nil {
 		return err
 	}
 
 	
-	return uu.userRepo.RegisterUser(username, string(hashedPassword), role)
+	if err := uu.userRepo.RegisterUser(ctx, username, string(hashedPassword), role); err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "user registered", slog.String("username", username), slog.String("role", role))
+	return nil
 }
 
-func (uu *UserUsecase) Login(username, password string) (domain.User, error) {
-	return uu.userRepo.Login(username, password)
+func (uu *UserUsecase) Login(ctx context.Context, username, password string) (user domain.User, err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "UserUsecase.Login")
+	defer infrastructure.EndSpan(span, &err)
+
+	user, err = uu.userRepo.Login(ctx, username, password)
+	if err != nil {
+		slog.WarnContext(ctx, "login failed", slog.String("username", username))
+		return domain.User{}, err
+	}
+
+	slog.InfoContext(ctx, "login succeeded", slog.String("username", username))
+	return user, nil
 }
 
-func (uu *UserUsecase) GetUserById(id primitive.ObjectID) (domain.User, error) {
+func (uu *UserUsecase) GetUserById(ctx context.Context, id primitive.ObjectID) (user domain.User, err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "UserUsecase.GetUserById")
+	defer infrastructure.EndSpan(span, &err)
+
 	
-	return uu.userRepo.GetUserById(id)
+	return uu.userRepo.GetUserById(ctx, id)
 }
 
-func (uu *UserUsecase) UpdateUser(id primitive.ObjectID, user domain.User) error {
-	
+// UpdateUser applies a partial update to the user. Fields left empty in user
+// keep their stored value, so the password is only re-hashed when provided.
+func (uu *UserUsecase) UpdateUser(ctx context.Context, id primitive.ObjectID, user domain.User) (err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "UserUsecase.UpdateUser")
+	defer infrastructure.EndSpan(span, &err)
+
+	existing, err := uu.userRepo.GetUserById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if user.Username != "" {
+		existing.Username = user.Username
+	}
+	if user.Role != "" {
+		existing.Role = user.Role
+	}
+	if user.Password != "" {
+		hashedPassword, err := infrastructure.HashPasswordContext(ctx, user.Password)
+		if err != nil {
+			return err
+		}
+		existing.Password = hashedPassword
+	}
+	existing.ID = id
+
+	if err := uu.userRepo.UpdateUser(ctx, id, existing); err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "user updated", slog.String("target_user_id", id.Hex()), slog.Bool("password_changed", user.Password != ""))
+	return nil
+}
+
+// ChangePassword replaces the user's password after verifying the old one.
+func (uu *UserUsecase) ChangePassword(ctx context.Context, id primitive.ObjectID, oldPassword, newPassword string) (err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "UserUsecase.ChangePassword")
+	defer infrastructure.EndSpan(span, &err)
+
+	if newPassword == "" {
+		return domain.ErrEmptyPassword
+	}
+
+	existing, err := uu.userRepo.GetUserById(ctx, id)
+	if err != nil {
+		return err
+	}
 
-	hashedPassword,err := infrastructure.HashPassword(user.Password)
+	if err := infrastructure.ComparePasswordsContext(ctx, existing.Password, oldPassword); err != nil {
+		slog.WarnContext(ctx, "password change rejected", slog.String("reason", "old password mismatch"))
+		return domain.ErrInvalidPassword
+	}
+
+	hashedPassword, err := infrastructure.HashPasswordContext(ctx, newPassword)
+	if err != nil {
+		return err
+	}
+	existing.Password = hashedPassword
+
+	if err := uu.userRepo.UpdateUser(ctx, id, existing); err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "password changed", slog.String("target_user_id", id.Hex()))
+	return nil
+}
+
+func (uu *UserUsecase) DeleteUser(ctx context.Context, id primitive.ObjectID) (err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "UserUsecase.DeleteUser")
+	defer infrastructure.EndSpan(span, &err)
+
+	if err := uu.userRepo.DeleteUser(ctx, id); err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "user deleted", slog.String("target_user_id", id.Hex()))
+	return nil
+}
+
+func (uu *UserUsecase) GetAllUsers(ctx context.Context) (users []domain.User, err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "UserUsecase.GetAllUsers")
+	defer infrastructure.EndSpan(span, &err)
+
+	return uu.userRepo.GetAllUsers(ctx)
+}
+
+// RotateCalendarToken gives the user a new calendar feed token. Only its hash is
+// stored, so the returned token cannot be shown again.
+func (uu *UserUsecase) RotateCalendarToken(ctx context.Context, id primitive.ObjectID) (token string, err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "UserUsecase.RotateCalendarToken")
+	defer infrastructure.EndSpan(span, &err)
+
+	token, hash, err := infrastructure.NewCalendarToken()
 	if err != nil {
+		return "", err
+	}
+	if err := uu.setCalendarToken(ctx, id, hash); err != nil {
+		return "", err
+	}
+
+	slog.InfoContext(ctx, "calendar token rotated", slog.String("target_user_id", id.Hex()))
+	return token, nil
+}
+
+// RevokeCalendarToken removes the user's calendar feed token.
+func (uu *UserUsecase) RevokeCalendarToken(ctx context.Context, id primitive.ObjectID) (err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "UserUsecase.RevokeCalendarToken")
+	defer infrastructure.EndSpan(span, &err)
+
+	if err := uu.setCalendarToken(ctx, id, ""); err != nil {
 		return err
 	}
-	newUser := domain.User{ID: id, Username: user.Username, Password: string(hashedPassword), Role: user.Role}
 
-	return uu.userRepo.UpdateUser(id, newUser)
+	slog.InfoContext(ctx, "calendar token revoked", slog.String("target_user_id", id.Hex()))
+	return nil
 }
 
-func (uu *UserUsecase) DeleteUser(id primitive.ObjectID) error {
-	return uu.userRepo.DeleteUser(id)
+// GetUserByCalendarToken finds the owner of a calendar feed token.
+func (uu *UserUsecase) GetUserByCalendarToken(ctx context.Context, token string) (user domain.User, err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "UserUsecase.GetUserByCalendarToken")
+	defer infrastructure.EndSpan(span, &err)
+
+	if token == "" {
+		return domain.User{}, mongo.ErrNoDocuments
+	}
+	return uu.userRepo.GetUserByCalendarToken(ctx, infrastructure.HashCalendarToken(token))
 }
 
-func (uu *UserUsecase) GetAllUsers() ([]domain.User, error) {
-	return uu.userRepo.GetAllUsers()
+func (uu *UserUsecase) setCalendarToken(ctx context.Context, id primitive.ObjectID, hash string) error {
+	existing, err := uu.userRepo.GetUserById(ctx, id)
+	if err != nil {
+		return err
+	}
+	existing.CalendarToken = hash
+	return uu.userRepo.UpdateUser(ctx, id, existing)
 }
@@ -0,0 +1,40 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"task_manager_testing/domain"
+	"time"
+)
+
+// WebhookDispatcher periodically sends the webhook deliveries that are due.
+type WebhookDispatcher struct {
+	webhooks domain.WebhookUsecase
+	interval time.Duration
+}
+
+func NewWebhookDispatcher(webhooks domain.WebhookUsecase, interval time.Duration) *WebhookDispatcher {
+	return &WebhookDispatcher{webhooks: webhooks, interval: interval}
+}
+
+// Run sends due deliveries right away and then every interval until ctx is done.
+func (wd *WebhookDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(wd.interval)
+	defer ticker.Stop()
+
+	for {
+		wd.RunOnce(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce sends the deliveries that are due now.
+func (wd *WebhookDispatcher) RunOnce(ctx context.Context) {
+	if _, err := wd.webhooks.DeliverDue(ctx, time.Now()); err != nil && ctx.Err() == nil {
+		slog.ErrorContext(ctx, "webhook dispatch failed", slog.String("error", err.Error()))
+	}
+}
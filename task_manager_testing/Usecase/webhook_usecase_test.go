@@ -0,0 +1,184 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	usecase "task_manager_testing/Usecase"
+	"task_manager_testing/domain"
+	"task_manager_testing/mocks"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// WebhookUsecaseSuite defines the suite for webhook usecase tests
+type WebhookUsecaseSuite struct {
+	suite.Suite
+	webhookRepo    *mocks.WebhookRepository
+	sender         *mocks.WebhookSender
+	webhookUsecase *usecase.WebhookUsecase
+}
+
+var testWebhookPolicy = usecase.WebhookPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: time.Minute,
+	MaxBackoff:     90 * time.Second,
+	Lease:          time.Minute,
+	BatchSize:      10,
+}
+
+// SetupTest sets up the necessary resources before each test
+func (suite *WebhookUsecaseSuite) SetupTest() {
+	suite.webhookRepo = &mocks.WebhookRepository{}
+	suite.sender = &mocks.WebhookSender{}
+	suite.webhookUsecase = usecase.NewWebhookUsecase(suite.webhookRepo, suite.sender, testWebhookPolicy)
+}
+
+// TearDownTest checks the mock expectations after each test
+func (suite *WebhookUsecaseSuite) TearDownTest() {
+	suite.webhookRepo.AssertExpectations(suite.T())
+	suite.sender.AssertExpectations(suite.T())
+}
+
+// TestCreateWebhook tests that webhooks are validated and get a secret
+func (suite *WebhookUsecaseSuite) TestCreateWebhook() {
+	ownerID := primitive.NewObjectID()
+	suite.webhookRepo.On("CreateWebhook", mock.Anything, mock.Anything).Return(nil).Once()
+
+	created, err := suite.webhookUsecase.CreateWebhook(context.Background(), domain.Webhook{
+		OwnerID: ownerID,
+		URL:     "https://example.com/hook",
+		Events:  []string{domain.EventTaskCreated, domain.EventTaskCreated},
+		Active:  true,
+	})
+	suite.Require().NoError(err)
+	suite.False(created.ID.IsZero())
+	suite.NotEmpty(created.Secret)
+	suite.Equal([]string{domain.EventTaskCreated}, created.Events)
+
+	invalid := []domain.Webhook{
+		{URL: "ftp://example.com", Events: []string{domain.EventTaskCreated}},
+		{URL: "/relative", Events: []string{domain.EventTaskCreated}},
+		{URL: "https://example.com"},
+		{URL: "https://example.com", Events: []string{"task.archived"}},
+		{URL: "https://example.com", Events: []string{domain.EventTaskCreated}, Secret: "short"},
+	}
+	for _, webhook := range invalid {
+		_, err := suite.webhookUsecase.CreateWebhook(context.Background(), webhook)
+		suite.ErrorIs(err, domain.ErrInvalidWebhook, webhook.URL)
+	}
+}
+
+// TestPublishTaskEvent tests that only the webhooks in scope get a delivery
+func (suite *WebhookUsecaseSuite) TestPublishTaskEvent() {
+	ownerID := primitive.NewObjectID()
+	events := []string{domain.EventTaskCreated}
+	own := domain.Webhook{ID: primitive.NewObjectID(), OwnerID: ownerID, Events: events, Active: true}
+	other := domain.Webhook{ID: primitive.NewObjectID(), OwnerID: primitive.NewObjectID(), Events: events, Active: true}
+	admin := domain.Webhook{ID: primitive.NewObjectID(), OwnerID: primitive.NewObjectID(), Events: events, Active: true, AllTasks: true}
+	event := domain.TaskEvent{ID: "event-1", Type: domain.EventTaskCreated, Task: domain.Task{ID: primitive.NewObjectID(), CreatedBy: ownerID}}
+
+	var deliveries []domain.WebhookDelivery
+	suite.webhookRepo.On("GetWebhooksForEvent", mock.Anything, domain.EventTaskCreated).Return([]domain.Webhook{own, other, admin}, nil)
+	suite.webhookRepo.On("CreateDelivery", mock.Anything, mock.Anything).Return(nil).
+		Run(func(args mock.Arguments) { deliveries = append(deliveries, args.Get(1).(domain.WebhookDelivery)) })
+
+	suite.Require().NoError(suite.webhookUsecase.PublishTaskEvent(context.Background(), event))
+
+	suite.Require().Len(deliveries, 2)
+	suite.Equal(own.ID, deliveries[0].WebhookID)
+	suite.Equal(admin.ID, deliveries[1].WebhookID)
+	for _, delivery := range deliveries {
+		suite.Equal(domain.DeliveryPending, delivery.Status)
+		suite.Equal("event-1", delivery.EventID)
+		suite.Contains(delivery.Payload, `"type":"task.created"`)
+	}
+}
+
+// TestDeliverDue tests that failed attempts back off and eventually give up
+func (suite *WebhookUsecaseSuite) TestDeliverDue() {
+	now := time.Date(2030, 1, 15, 12, 0, 0, 0, time.UTC)
+	webhook := domain.Webhook{ID: primitive.NewObjectID(), URL: "https://example.com/hook", Secret: "secret", Active: true}
+	succeeding := domain.WebhookDelivery{ID: primitive.NewObjectID(), WebhookID: webhook.ID, Payload: "ok", Status: domain.DeliveryPending}
+	retried := domain.WebhookDelivery{ID: primitive.NewObjectID(), WebhookID: webhook.ID, Payload: "retry", Status: domain.DeliveryPending,
+		Attempts: []domain.WebhookAttempt{{StatusCode: 500}}}
+	exhausted := domain.WebhookDelivery{ID: primitive.NewObjectID(), WebhookID: webhook.ID, Payload: "give up", Status: domain.DeliveryPending,
+		Attempts: []domain.WebhookAttempt{{StatusCode: 500}, {StatusCode: 500}}}
+
+	suite.webhookRepo.On("ClaimDueDelivery", mock.Anything, now, testWebhookPolicy.Lease).Return(succeeding, nil).Once()
+	suite.webhookRepo.On("ClaimDueDelivery", mock.Anything, now, testWebhookPolicy.Lease).Return(retried, nil).Once()
+	suite.webhookRepo.On("ClaimDueDelivery", mock.Anything, now, testWebhookPolicy.Lease).Return(exhausted, nil).Once()
+	suite.webhookRepo.On("ClaimDueDelivery", mock.Anything, now, testWebhookPolicy.Lease).Return(domain.WebhookDelivery{}, mongo.ErrNoDocuments).Once()
+	// The webhook is loaded once for all of its deliveries
+	suite.webhookRepo.On("GetWebhook", mock.Anything, webhook.ID).Return(webhook, nil).Once()
+	suite.sender.On("SendWebhook", mock.Anything, mock.MatchedBy(func(req domain.WebhookRequest) bool { return string(req.Payload) == "ok" })).Return(204, nil)
+	suite.sender.On("SendWebhook", mock.Anything, mock.MatchedBy(func(req domain.WebhookRequest) bool { return string(req.Payload) == "retry" })).Return(503, nil)
+	suite.sender.On("SendWebhook", mock.Anything, mock.MatchedBy(func(req domain.WebhookRequest) bool { return string(req.Payload) == "give up" })).Return(0, errors.New("connection refused"))
+
+	updated := map[primitive.ObjectID]domain.WebhookDelivery{}
+	suite.webhookRepo.On("UpdateDelivery", mock.Anything, mock.Anything).Return(nil).
+		Run(func(args mock.Arguments) {
+			delivery := args.Get(1).(domain.WebhookDelivery)
+			updated[delivery.ID] = delivery
+		})
+
+	attempted, err := suite.webhookUsecase.DeliverDue(context.Background(), now)
+
+	suite.Require().NoError(err)
+	suite.Equal(3, attempted)
+	suite.Equal(domain.DeliverySucceeded, updated[succeeding.ID].Status)
+	suite.Len(updated[succeeding.ID].Attempts, 1)
+
+	suite.Equal(domain.DeliveryPending, updated[retried.ID].Status)
+	suite.Equal(503, updated[retried.ID].Attempts[1].StatusCode)
+	// The second wait doubles to two minutes and is capped at 90 seconds
+	suite.Equal(now.Add(90*time.Second), updated[retried.ID].NextAttemptAt.Time().UTC())
+
+	suite.Equal(domain.DeliveryFailed, updated[exhausted.ID].Status)
+	suite.Equal("connection refused", updated[exhausted.ID].Attempts[2].Error)
+	suite.Zero(updated[exhausted.ID].NextAttemptAt)
+}
+
+// TestRedeliver tests that redeliveries repeat the payload of their webhook only
+func (suite *WebhookUsecaseSuite) TestRedeliver() {
+	ownerID := primitive.NewObjectID()
+	webhook := domain.Webhook{ID: primitive.NewObjectID(), OwnerID: ownerID}
+	original := domain.WebhookDelivery{ID: primitive.NewObjectID(), WebhookID: webhook.ID, EventID: "event-1", Event: domain.EventTaskDeleted, Payload: "{}", Status: domain.DeliveryFailed}
+	foreign := domain.WebhookDelivery{ID: primitive.NewObjectID(), WebhookID: primitive.NewObjectID()}
+
+	suite.webhookRepo.On("GetWebhook", mock.Anything, webhook.ID).Return(webhook, nil)
+	suite.webhookRepo.On("GetDelivery", mock.Anything, original.ID).Return(original, nil)
+	suite.webhookRepo.On("GetDelivery", mock.Anything, foreign.ID).Return(foreign, nil)
+	suite.webhookRepo.On("CreateDelivery", mock.Anything, mock.Anything).Return(nil).Once()
+
+	delivery, err := suite.webhookUsecase.Redeliver(context.Background(), ownerID, webhook.ID, original.ID)
+	suite.Require().NoError(err)
+	suite.NotEqual(original.ID, delivery.ID)
+	suite.Equal(&original.ID, delivery.RedeliveryOf)
+	suite.Equal(original.Payload, delivery.Payload)
+	suite.Equal(domain.DeliveryPending, delivery.Status)
+
+	_, err = suite.webhookUsecase.Redeliver(context.Background(), ownerID, webhook.ID, foreign.ID)
+	suite.ErrorIs(err, mongo.ErrNoDocuments)
+	_, err = suite.webhookUsecase.Redeliver(context.Background(), primitive.NewObjectID(), webhook.ID, original.ID)
+	suite.ErrorIs(err, mongo.ErrNoDocuments)
+}
+
+// TestBackoff tests the doubling and the cap of the retry delay
+func (suite *WebhookUsecaseSuite) TestBackoff() {
+	policy := usecase.WebhookPolicy{InitialBackoff: 30 * time.Second, MaxBackoff: 5 * time.Minute}
+	suite.Equal(30*time.Second, policy.Backoff(1))
+	suite.Equal(time.Minute, policy.Backoff(2))
+	suite.Equal(4*time.Minute, policy.Backoff(4))
+	suite.Equal(5*time.Minute, policy.Backoff(5))
+	suite.Equal(5*time.Minute, policy.Backoff(50))
+}
+
+func TestWebhookUsecaseSuite(t *testing.T) {
+	suite.Run(t, new(WebhookUsecaseSuite))
+}
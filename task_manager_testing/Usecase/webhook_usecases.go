@@ -0,0 +1,342 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	infrastructure "task_manager_testing/Infrastructure"
+	"task_manager_testing/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// WebhookPolicy controls how deliveries are retried.
+type WebhookPolicy struct {
+	// MaxAttempts is how often a delivery is tried before it fails for good
+	MaxAttempts int
+	// InitialBackoff is the wait after the first failure; it doubles after
+	// every further failure up to MaxBackoff
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// Lease is how long a claimed delivery is hidden from other workers
+	Lease time.Duration
+	// BatchSize is the largest number of deliveries sent by one DeliverDue
+	BatchSize int
+}
+
+// Backoff returns the wait before the attempt following failed attempts.
+func (p WebhookPolicy) Backoff(failed int) time.Duration {
+	return exponentialBackoff(p.InitialBackoff, p.MaxBackoff, failed)
+}
+
+// exponentialBackoff doubles initial for every failure after the first, up to max.
+func exponentialBackoff(initial, max time.Duration, failed int) time.Duration {
+	backoff := initial
+	for i := 1; i < failed && backoff < max; i++ {
+		backoff *= 2
+	}
+	return min(backoff, max)
+}
+
+type WebhookUsecase struct {
+	webhookRepo domain.WebhookRepository
+	sender      domain.WebhookSender
+	policy      WebhookPolicy
+}
+
+func NewWebhookUsecase(webhookRepo domain.WebhookRepository, sender domain.WebhookSender, policy WebhookPolicy) *WebhookUsecase {
+	return &WebhookUsecase{webhookRepo: webhookRepo, sender: sender, policy: policy}
+}
+
+func (wu *WebhookUsecase) CreateWebhook(ctx context.Context, webhook domain.Webhook) (created domain.Webhook, err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "WebhookUsecase.CreateWebhook")
+	defer infrastructure.EndSpan(span, &err)
+
+	if webhook.Events, err = validateWebhook(webhook.URL, webhook.Events); err != nil {
+		return domain.Webhook{}, err
+	}
+	if webhook.Secret == "" {
+		if webhook.Secret, err = infrastructure.NewWebhookSecret(); err != nil {
+			return domain.Webhook{}, err
+		}
+	} else if len(webhook.Secret) < 16 {
+		return domain.Webhook{}, fmt.Errorf("%w: secret must be at least 16 characters", domain.ErrInvalidWebhook)
+	}
+	webhook.ID = primitive.NewObjectID()
+	webhook.CreatedAt = primitive.NewDateTimeFromTime(time.Now())
+	webhook.OrgID = domain.ScopedOrg(ctx, webhook.OrgID)
+
+	if err := wu.webhookRepo.CreateWebhook(ctx, webhook); err != nil {
+		return domain.Webhook{}, err
+	}
+
+	slog.InfoContext(ctx, "webhook created", slog.String("webhook_id", webhook.ID.Hex()), slog.Any("events", webhook.Events))
+	return webhook, nil
+}
+
+func (wu *WebhookUsecase) GetWebhooks(ctx context.Context, ownerID primitive.ObjectID) (webhooks []domain.Webhook, err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "WebhookUsecase.GetWebhooks")
+	defer infrastructure.EndSpan(span, &err)
+
+	return wu.webhookRepo.GetWebhooks(ctx, ownerID)
+}
+
+func (wu *WebhookUsecase) GetWebhook(ctx context.Context, ownerID, id primitive.ObjectID) (webhook domain.Webhook, err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "WebhookUsecase.GetWebhook")
+	defer infrastructure.EndSpan(span, &err)
+
+	return wu.ownedWebhook(ctx, ownerID, id)
+}
+
+func (wu *WebhookUsecase) UpdateWebhook(ctx context.Context, ownerID, id primitive.ObjectID, update domain.WebhookUpdate) (webhook domain.Webhook, err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "WebhookUsecase.UpdateWebhook")
+	defer infrastructure.EndSpan(span, &err)
+
+	webhook, err = wu.ownedWebhook(ctx, ownerID, id)
+	if err != nil {
+		return domain.Webhook{}, err
+	}
+
+	if update.URL != nil {
+		webhook.URL = *update.URL
+	}
+	if update.Events != nil {
+		webhook.Events = *update.Events
+	}
+	if update.Active != nil {
+		webhook.Active = *update.Active
+	}
+	if webhook.Events, err = validateWebhook(webhook.URL, webhook.Events); err != nil {
+		return domain.Webhook{}, err
+	}
+
+	if err := wu.webhookRepo.UpdateWebhook(ctx, webhook); err != nil {
+		return domain.Webhook{}, err
+	}
+
+	slog.InfoContext(ctx, "webhook updated", slog.String("webhook_id", id.Hex()), slog.Bool("active", webhook.Active))
+	return webhook, nil
+}
+
+func (wu *WebhookUsecase) DeleteWebhook(ctx context.Context, ownerID, id primitive.ObjectID) (err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "WebhookUsecase.DeleteWebhook")
+	defer infrastructure.EndSpan(span, &err)
+
+	if _, err := wu.ownedWebhook(ctx, ownerID, id); err != nil {
+		return err
+	}
+	if err := wu.webhookRepo.DeleteWebhook(ctx, id); err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "webhook deleted", slog.String("webhook_id", id.Hex()))
+	return nil
+}
+
+func (wu *WebhookUsecase) GetDeliveries(ctx context.Context, ownerID, webhookID primitive.ObjectID, limit int) (deliveries []domain.WebhookDelivery, err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "WebhookUsecase.GetDeliveries")
+	defer infrastructure.EndSpan(span, &err)
+
+	if _, err := wu.ownedWebhook(ctx, ownerID, webhookID); err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > domain.MaxWebhookDeliveriesPage {
+		limit = domain.MaxWebhookDeliveriesPage
+	}
+	return wu.webhookRepo.GetDeliveries(ctx, webhookID, limit)
+}
+
+func (wu *WebhookUsecase) Redeliver(ctx context.Context, ownerID, webhookID, deliveryID primitive.ObjectID) (delivery domain.WebhookDelivery, err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "WebhookUsecase.Redeliver")
+	defer infrastructure.EndSpan(span, &err)
+
+	if _, err := wu.ownedWebhook(ctx, ownerID, webhookID); err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	original, err := wu.webhookRepo.GetDelivery(ctx, deliveryID)
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	if original.WebhookID != webhookID {
+		return domain.WebhookDelivery{}, mongo.ErrNoDocuments
+	}
+
+	delivery = newDelivery(webhookID, original.EventID, original.Event, original.Payload)
+	delivery.RedeliveryOf = &original.ID
+	if err := wu.webhookRepo.CreateDelivery(ctx, delivery); err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+
+	slog.InfoContext(ctx, "webhook redelivery queued", slog.String("delivery_id", delivery.ID.Hex()), slog.String("redelivery_of", original.ID.Hex()))
+	return delivery, nil
+}
+
+// PublishTaskEvent queues a delivery of event for every webhook that wants it.
+func (wu *WebhookUsecase) PublishTaskEvent(ctx context.Context, event domain.TaskEvent) (err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "WebhookUsecase.PublishTaskEvent")
+	defer infrastructure.EndSpan(span, &err)
+
+	webhooks, err := wu.webhookRepo.GetWebhooksForEvent(ctx, event.Type)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, webhook := range webhooks {
+		if !webhook.Wants(event) {
+			continue
+		}
+		if err := wu.webhookRepo.CreateDelivery(ctx, newDelivery(webhook.ID, event.ID, event.Type, string(payload))); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// DeliverDue claims due deliveries one at a time and sends them. A failed
+// attempt is retried after the policy's backoff until MaxAttempts is reached.
+func (wu *WebhookUsecase) DeliverDue(ctx context.Context, now time.Time) (attempted int, err error) {
+	ctx, span := infrastructure.StartSpan(ctx, "WebhookUsecase.DeliverDue")
+	defer infrastructure.EndSpan(span, &err)
+
+	webhooks := map[primitive.ObjectID]domain.Webhook{}
+	for attempted < wu.policy.BatchSize {
+		delivery, err := wu.webhookRepo.ClaimDueDelivery(ctx, now, wu.policy.Lease)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			break
+		}
+		if err != nil {
+			return attempted, err
+		}
+
+		webhook, found := webhooks[delivery.WebhookID]
+		if !found {
+			webhook, err = wu.webhookRepo.GetWebhook(ctx, delivery.WebhookID)
+			if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+				return attempted, err
+			}
+			webhooks[delivery.WebhookID] = webhook
+		}
+
+		attempted++
+		if err := wu.webhookRepo.UpdateDelivery(ctx, wu.attempt(ctx, webhook, delivery, now)); err != nil {
+			return attempted, err
+		}
+	}
+
+	if attempted > 0 {
+		slog.InfoContext(ctx, "webhook deliveries attempted", slog.Int("count", attempted))
+	}
+	return attempted, nil
+}
+
+// attempt sends delivery to webhook once and returns the delivery updated
+// with the outcome.
+func (wu *WebhookUsecase) attempt(ctx context.Context, webhook domain.Webhook, delivery domain.WebhookDelivery, now time.Time) domain.WebhookDelivery {
+	start := time.Now()
+	attempt := domain.WebhookAttempt{At: primitive.NewDateTimeFromTime(start)}
+
+	if webhook.ID.IsZero() || !webhook.Active {
+		// Nothing to send to any more
+		attempt.Error = "webhook is deleted or disabled"
+		delivery.Attempts = append(delivery.Attempts, attempt)
+		delivery.Status = domain.DeliveryFailed
+		delivery.NextAttemptAt = 0
+		return delivery
+	}
+
+	status, err := wu.sender.SendWebhook(ctx, domain.WebhookRequest{
+		URL:        webhook.URL,
+		Secret:     webhook.Secret,
+		Event:      delivery.Event,
+		DeliveryID: delivery.ID.Hex(),
+		Payload:    []byte(delivery.Payload),
+	})
+	attempt.DurationMsec = time.Since(start).Milliseconds()
+	attempt.StatusCode = status
+	if err != nil {
+		attempt.Error = err.Error()
+	} else if status < 200 || status > 299 {
+		attempt.Error = fmt.Sprintf("receiver answered with status %d", status)
+	}
+	delivery.Attempts = append(delivery.Attempts, attempt)
+
+	switch {
+	case attempt.Error == "":
+		delivery.Status = domain.DeliverySucceeded
+		delivery.NextAttemptAt = 0
+	case len(delivery.Attempts) >= wu.policy.MaxAttempts:
+		delivery.Status = domain.DeliveryFailed
+		delivery.NextAttemptAt = 0
+		slog.WarnContext(ctx, "webhook delivery failed for good", slog.String("delivery_id", delivery.ID.Hex()), slog.String("error", attempt.Error))
+	default:
+		delivery.NextAttemptAt = primitive.NewDateTimeFromTime(now.Add(wu.policy.Backoff(len(delivery.Attempts))))
+	}
+	return delivery
+}
+
+// ownedWebhook loads the webhook and hides it from everyone but its owner.
+func (wu *WebhookUsecase) ownedWebhook(ctx context.Context, ownerID, id primitive.ObjectID) (domain.Webhook, error) {
+	webhook, err := wu.webhookRepo.GetWebhook(ctx, id)
+	if err != nil {
+		return domain.Webhook{}, err
+	}
+	if webhook.OwnerID != ownerID {
+		return domain.Webhook{}, mongo.ErrNoDocuments
+	}
+	return webhook, nil
+}
+
+// newDelivery builds a pending delivery that is due right away.
+func newDelivery(webhookID primitive.ObjectID, eventID, eventType, payload string) domain.WebhookDelivery {
+	now := primitive.NewDateTimeFromTime(time.Now())
+	return domain.WebhookDelivery{
+		ID:            primitive.NewObjectID(),
+		WebhookID:     webhookID,
+		EventID:       eventID,
+		Event:         eventType,
+		Payload:       payload,
+		Status:        domain.DeliveryPending,
+		Attempts:      []domain.WebhookAttempt{},
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+}
+
+// validateWebhook checks the target URL and returns the event filter without
+// duplicates.
+func validateWebhook(rawURL string, events []string) ([]string, error) {
+	target, err := url.Parse(rawURL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return nil, fmt.Errorf("%w: url must be an absolute http or https URL", domain.ErrInvalidWebhook)
+	}
+	if len(events) == 0 {
+		return nil, fmt.Errorf("%w: at least one event is required", domain.ErrInvalidWebhook)
+	}
+
+	seen := map[string]bool{}
+	var unique []string
+	for _, event := range events {
+		known := false
+		for _, eventType := range domain.TaskEventTypes {
+			known = known || eventType == event
+		}
+		if !known {
+			return nil, fmt.Errorf("%w: unknown event %q", domain.ErrInvalidWebhook, event)
+		}
+		if !seen[event] {
+			seen[event] = true
+			unique = append(unique, event)
+		}
+	}
+	return unique, nil
+}
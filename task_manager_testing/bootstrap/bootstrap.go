@@ -0,0 +1,342 @@
+// Package bootstrap is the composition root of the task manager. It creates
+// every repository, usecase and controller exactly once and wires them into
+// the HTTP router.
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"task_manager_testing/Delivery/routers"
+	infrastructure "task_manager_testing/Infrastructure"
+	repository "task_manager_testing/Repository"
+	usecase "task_manager_testing/Usecase"
+	"task_manager_testing/config"
+	"task_manager_testing/config/database"
+	"task_manager_testing/domain"
+
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Repositories are the persistence adapters the application is built on.
+type Repositories struct {
+	Tasks       domain.TaskRepository
+	Users       domain.UserRepository
+	Idempotency domain.IdempotencyRepository
+	// Transactor runs all-or-nothing changes across the repositories
+	Transactor    domain.Transactor
+	Notifications domain.NotificationRepository
+	Webhooks      domain.WebhookRepository
+	// Outbox holds the task events written in the same transaction as the tasks
+	Outbox domain.OutboxRepository
+	// Comments holds the discussion on tasks
+	Comments domain.CommentRepository
+	// Attachments holds the metadata of task files and Blobs their content
+	Attachments domain.AttachmentRepository
+	Blobs       domain.BlobStore
+	// Labels holds task labels and SavedFilters the named filters of users
+	Labels       domain.LabelRepository
+	SavedFilters domain.SavedFilterRepository
+	// Series holds the rules and templates of recurring tasks
+	Series domain.TaskSeriesRepository
+	// TimeEntries holds the time tracked on tasks
+	TimeEntries domain.TimeEntryRepository
+	// Reports holds the status changes of tasks and builds the reports
+	Reports domain.ReportRepository
+	// Organizations holds the organizations tasks and users are isolated in
+	Organizations domain.OrganizationRepository
+}
+
+// Infrastructure groups the cross-cutting services shared by every layer.
+type Infrastructure struct {
+	Database       domain.Pinger
+	Logger         *slog.Logger
+	Metrics        *infrastructure.Metrics
+	RateLimitStore infrastructure.RateLimitStore
+	// WebhookSender sends webhook deliveries; nil uses an HTTP client
+	WebhookSender domain.WebhookSender
+
+	// closers release the connections opened for the services above
+	closers []func() error
+}
+
+// Close releases the connections held by the shared services.
+func (infra Infrastructure) Close() error {
+	var errs []error
+	for _, closer := range infra.closers {
+		errs = append(errs, closer())
+	}
+	return errors.Join(errs...)
+}
+
+// NewMongoRepositories creates one MongoDB repository per collection.
+// Attachment content goes to GridFS or to a local directory, as configured.
+func NewMongoRepositories(cfg *config.Config, client *mongo.Client) (Repositories, error) {
+	var (
+		blobs domain.BlobStore
+		err   error
+	)
+	switch cfg.Attachments.Store {
+	case "local":
+		blobs, err = repository.NewLocalBlobStore(cfg.Attachments.Dir)
+	default:
+		blobs, err = repository.NewGridFSBlobStore(client, cfg.Mongo.Database, cfg.Mongo.BlobsBucket)
+	}
+	if err != nil {
+		return Repositories{}, err
+	}
+
+	return Repositories{
+		Tasks: repository.NewTaskRepository(client, cfg.Mongo.Database, cfg.Mongo.TasksCollection),
+		Users: repository.NewUserRepository(client, cfg.Mongo.Database, cfg.Mongo.UsersCollection),
+
+		Idempotency: repository.NewIdempotencyRepository(client, cfg.Mongo.Database, cfg.Mongo.IdempotencyCollection),
+		Transactor:  repository.NewMongoTransactor(client),
+
+		Notifications: repository.NewNotificationRepository(client, cfg.Mongo.Database, cfg.Mongo.NotificationsCollection),
+		Webhooks:      repository.NewWebhookRepository(client, cfg.Mongo.Database, cfg.Mongo.WebhooksCollection, cfg.Mongo.WebhookDeliveriesCollection),
+		Outbox:        repository.NewOutboxRepository(client, cfg.Mongo.Database, cfg.Mongo.OutboxCollection),
+		Comments:      repository.NewCommentRepository(client, cfg.Mongo.Database, cfg.Mongo.CommentsCollection),
+
+		Attachments: repository.NewAttachmentRepository(client, cfg.Mongo.Database, cfg.Mongo.AttachmentsCollection),
+		Blobs:       blobs,
+
+		Labels:       repository.NewLabelRepository(client, cfg.Mongo.Database, cfg.Mongo.LabelsCollection),
+		SavedFilters: repository.NewSavedFilterRepository(client, cfg.Mongo.Database, cfg.Mongo.SavedFiltersCollection),
+		Series:       repository.NewTaskSeriesRepository(client, cfg.Mongo.Database, cfg.Mongo.SeriesCollection),
+
+		TimeEntries: repository.NewTimeEntryRepository(client, cfg.Mongo.Database, cfg.Mongo.TimeEntriesCollection),
+		Reports:     repository.NewReportRepository(client, cfg.Mongo.Database, cfg.Mongo.TasksCollection, cfg.Mongo.StatusChangesCollection),
+
+		Organizations: repository.NewOrganizationRepository(client, cfg.Mongo.Database, cfg.Mongo.OrganizationsCollection),
+	}, nil
+}
+
+// EnsureMongoIndexes creates the indexes the MongoDB repositories rely on.
+func EnsureMongoIndexes(ctx context.Context, cfg *config.Config, client *mongo.Client) error {
+	if err := repository.NewTaskRepository(client, cfg.Mongo.Database, cfg.Mongo.TasksCollection).EnsureIndexes(ctx); err != nil {
+		return err
+	}
+	if err := repository.NewUserRepository(client, cfg.Mongo.Database, cfg.Mongo.UsersCollection).EnsureIndexes(ctx); err != nil {
+		return err
+	}
+	if err := repository.NewNotificationRepository(client, cfg.Mongo.Database, cfg.Mongo.NotificationsCollection).EnsureIndexes(ctx); err != nil {
+		return err
+	}
+	if err := repository.NewWebhookRepository(client, cfg.Mongo.Database, cfg.Mongo.WebhooksCollection, cfg.Mongo.WebhookDeliveriesCollection).EnsureIndexes(ctx); err != nil {
+		return err
+	}
+	if err := repository.NewOutboxRepository(client, cfg.Mongo.Database, cfg.Mongo.OutboxCollection).EnsureIndexes(ctx); err != nil {
+		return err
+	}
+	if err := repository.NewCommentRepository(client, cfg.Mongo.Database, cfg.Mongo.CommentsCollection).EnsureIndexes(ctx); err != nil {
+		return err
+	}
+	if err := repository.NewAttachmentRepository(client, cfg.Mongo.Database, cfg.Mongo.AttachmentsCollection).EnsureIndexes(ctx); err != nil {
+		return err
+	}
+	if err := repository.NewLabelRepository(client, cfg.Mongo.Database, cfg.Mongo.LabelsCollection).EnsureIndexes(ctx); err != nil {
+		return err
+	}
+	if err := repository.NewSavedFilterRepository(client, cfg.Mongo.Database, cfg.Mongo.SavedFiltersCollection).EnsureIndexes(ctx); err != nil {
+		return err
+	}
+	if err := repository.NewTaskSeriesRepository(client, cfg.Mongo.Database, cfg.Mongo.SeriesCollection).EnsureIndexes(ctx); err != nil {
+		return err
+	}
+	if err := repository.NewTimeEntryRepository(client, cfg.Mongo.Database, cfg.Mongo.TimeEntriesCollection).EnsureIndexes(ctx); err != nil {
+		return err
+	}
+	if err := repository.NewReportRepository(client, cfg.Mongo.Database, cfg.Mongo.TasksCollection, cfg.Mongo.StatusChangesCollection).EnsureIndexes(ctx); err != nil {
+		return err
+	}
+	if err := repository.NewOrganizationRepository(client, cfg.Mongo.Database, cfg.Mongo.OrganizationsCollection).EnsureIndexes(ctx); err != nil {
+		return err
+	}
+	return repository.NewIdempotencyRepository(client, cfg.Mongo.Database, cfg.Mongo.IdempotencyCollection).EnsureIndexes(ctx)
+}
+
+// EnsureRootUser registers the root user of cfg unless a user with its
+// username exists. Nothing is done when no root user is configured.
+func EnsureRootUser(ctx context.Context, cfg *config.Config, repos Repositories) error {
+	if cfg.Root.Username == "" {
+		return nil
+	}
+	_, err := repos.Users.GetUserByUsername(ctx, cfg.Root.Username)
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return err
+	}
+	return usecase.NewUserUsecase(repos.Users).RegisterUser(ctx, cfg.Root.Username, cfg.Root.Password, "root")
+}
+
+// NewMongoInfrastructure creates the shared services for a MongoDB deployment.
+func NewMongoInfrastructure(cfg *config.Config, client *mongo.Client, logger *slog.Logger) Infrastructure {
+	infra := Infrastructure{
+		Database: database.NewMongoPinger(client),
+		Logger:   logger,
+		Metrics:  infrastructure.NewMetrics(),
+	}
+
+	switch cfg.RateLimit.Store {
+	case "redis":
+		redisClient := redis.NewClient(&redis.Options{Addr: cfg.RateLimit.RedisAddr})
+		infra.RateLimitStore = infrastructure.NewRedisRateLimitStore(redisClient)
+		infra.closers = append(infra.closers, redisClient.Close)
+	default:
+		infra.RateLimitStore = infrastructure.NewMemoryRateLimitStore()
+	}
+	return infra
+}
+
+// NewInMemoryRepositories creates repositories that keep their data in memory.
+func NewInMemoryRepositories() Repositories {
+	tasks := repository.NewInMemoryTaskRepository()
+	outbox := repository.NewInMemoryOutboxRepository()
+	series := repository.NewInMemoryTaskSeriesRepository()
+	reports := repository.NewInMemoryReportRepository(tasks)
+	comments := repository.NewInMemoryCommentRepository()
+	attachments := repository.NewInMemoryAttachmentRepository()
+	return Repositories{
+		Tasks: tasks,
+		Users: repository.NewInMemoryUserRepository(),
+
+		Idempotency: repository.NewInMemoryIdempotencyRepository(),
+		Transactor:  repository.NewInMemoryTransactor(tasks, outbox, series, reports, comments, attachments),
+
+		Notifications: repository.NewInMemoryNotificationRepository(),
+		Webhooks:      repository.NewInMemoryWebhookRepository(),
+		Outbox:        outbox,
+		Comments:      comments,
+
+		Attachments: attachments,
+		Blobs:       repository.NewInMemoryBlobStore(),
+
+		Labels:       repository.NewInMemoryLabelRepository(),
+		SavedFilters: repository.NewInMemorySavedFilterRepository(),
+		Series:       series,
+
+		TimeEntries: repository.NewInMemoryTimeEntryRepository(),
+		Reports:     reports,
+
+		Organizations: repository.NewInMemoryOrganizationRepository(),
+	}
+}
+
+// App is the assembled application: the HTTP handler and the background jobs
+// sharing its usecases.
+type App struct {
+	Router *gin.Engine
+	// Reminders creates due-date notifications while its Run method runs
+	Reminders *usecase.ReminderScheduler
+	// Recurrences generates the next occurrence of past-due recurring tasks
+	// while its Run method runs
+	Recurrences *usecase.RecurrenceScheduler
+	// Webhooks sends the queued webhook deliveries while its Run method runs
+	Webhooks *usecase.WebhookDispatcher
+	// Outbox relays the task events stored with each change while its Run
+	// method runs
+	Outbox *usecase.OutboxRelay
+	// Streams feeds the open task event streams; closing it ends them
+	Streams *infrastructure.TaskEventBus
+}
+
+const (
+	// webhookBatchSize is the largest number of deliveries sent per poll
+	webhookBatchSize = 50
+	// outboxBatchSize is the largest number of events relayed per poll
+	outboxBatchSize = 100
+	// outboxLease is how long a relay may take to deliver one event
+	outboxLease = time.Minute
+)
+
+// NewRouter builds the application on top of repos and returns its HTTP handler.
+func NewRouter(cfg *config.Config, repos Repositories, infra Infrastructure) *gin.Engine {
+	return NewApp(cfg, repos, infra).Router
+}
+
+// NewApp builds the usecases and controllers on top of repos. Requests are rate
+// limited in memory when infra has no rate limit store, and webhooks are sent
+// over HTTP when it has no webhook sender.
+func NewApp(cfg *config.Config, repos Repositories, infra Infrastructure) *App {
+	if infra.RateLimitStore == nil {
+		infra.RateLimitStore = infrastructure.NewMemoryRateLimitStore()
+	}
+	if infra.WebhookSender == nil {
+		// The networks were checked when the configuration was validated
+		networks, _ := cfg.Webhooks.Networks()
+		infra.WebhookSender = infrastructure.NewWebhookClient(cfg.Webhooks.Timeout.Duration, networks)
+	}
+
+	taskRepository := repository.NewInstrumentedTaskRepository(repos.Tasks, infra.Metrics)
+	userRepository := repository.NewInstrumentedUserRepository(repos.Users, infra.Metrics)
+
+	infra.Metrics.RegisterTaskCounter(repos.Tasks.CountTasksByStatus)
+
+	notificationUsecase := usecase.NewNotificationUsecase(repos.Notifications, taskRepository)
+	webhookUsecase := usecase.NewWebhookUsecase(repos.Webhooks, infra.WebhookSender, usecase.WebhookPolicy{
+		MaxAttempts:    cfg.Webhooks.MaxAttempts,
+		InitialBackoff: cfg.Webhooks.InitialBackoff.Duration,
+		MaxBackoff:     cfg.Webhooks.MaxBackoff.Duration,
+		Lease:          cfg.Webhooks.Timeout.Duration + time.Minute,
+		BatchSize:      webhookBatchSize,
+	})
+	// Every task event goes to the live streams and the webhooks, through the
+	// outbox when it is enabled
+	events := infrastructure.NewTaskEventBus(cfg.Events.History)
+	sinks := usecase.TaskEventSinks{"streams": events, "webhooks": webhookUsecase}
+	taskUsecase := usecase.NewTaskUsecase(taskRepository, repos.Transactor, nil, sinks)
+	if cfg.Outbox.Enabled {
+		taskUsecase = usecase.NewTaskUsecase(taskRepository, repos.Transactor, repos.Outbox, nil)
+	}
+	taskUsecase.Series = repos.Series
+	taskUsecase.StatusChanges = repos.Reports
+	taskUsecase.Comments = repos.Comments
+	taskUsecase.Attachments = repos.Attachments
+	taskUsecase.Blobs = repos.Blobs
+	userUsecase := usecase.NewUserUsecase(userRepository)
+	userUsecase.Organizations = repos.Organizations
+
+	router := routers.SetupRouter(cfg, routers.Dependencies{
+		TaskUsecase: taskUsecase,
+		UserUsecase: userUsecase,
+		Database:    infra.Database,
+		Logger:      infra.Logger,
+		Metrics:     infra.Metrics,
+		Idempotency: repos.Idempotency,
+
+		RateLimitStore:      infra.RateLimitStore,
+		NotificationUsecase: notificationUsecase,
+		WebhookUsecase:      webhookUsecase,
+		TaskEvents:          events,
+		CommentUsecase:      usecase.NewCommentUsecase(repos.Comments, taskRepository, userRepository, repos.Notifications),
+		AttachmentUsecase: usecase.NewAttachmentUsecase(repos.Attachments, repos.Blobs, taskRepository, userRepository, usecase.AttachmentPolicy{
+			MaxFileBytes:   cfg.Attachments.MaxFileBytes,
+			UserQuotaBytes: cfg.Attachments.UserQuotaBytes,
+		}),
+		LabelUsecase:       usecase.NewLabelUsecase(repos.Labels, taskRepository, userRepository, taskUsecase),
+		SavedFilterUsecase: usecase.NewSavedFilterUsecase(repos.SavedFilters, repos.Labels, taskUsecase),
+		TimeEntryUsecase:   usecase.NewTimeEntryUsecase(repos.TimeEntries, taskRepository, userRepository),
+		ReportUsecase:      usecase.NewReportUsecase(repos.Reports, userRepository),
+
+		OrganizationUsecase: usecase.NewOrganizationUsecase(repos.Organizations),
+	})
+	return &App{
+		Router:      router,
+		Reminders:   usecase.NewReminderScheduler(notificationUsecase, cfg.Reminders.Interval.Duration, cfg.Reminders.DueSoon.Duration),
+		Recurrences: usecase.NewRecurrenceScheduler(taskUsecase, cfg.Recurrence.Interval.Duration),
+		Webhooks:    usecase.NewWebhookDispatcher(webhookUsecase, cfg.Webhooks.PollInterval.Duration),
+		Outbox: usecase.NewOutboxRelay(repos.Outbox, sinks, usecase.OutboxPolicy{
+			PollInterval:   cfg.Outbox.PollInterval.Duration,
+			Lease:          outboxLease,
+			InitialBackoff: cfg.Outbox.InitialBackoff.Duration,
+			MaxBackoff:     cfg.Outbox.MaxBackoff.Duration,
+			MaxAttempts:    cfg.Outbox.MaxAttempts,
+			BatchSize:      outboxBatchSize,
+		}),
+		Streams: events,
+	}
+}
@@ -0,0 +1,1695 @@
+package bootstrap_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	infrastructure "task_manager_testing/Infrastructure"
+	"task_manager_testing/bootstrap"
+	"task_manager_testing/config"
+	"task_manager_testing/domain"
+	"task_manager_testing/mocks"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/net/websocket"
+)
+
+// AppSuite starts the whole HTTP stack on in-memory repositories
+type AppSuite struct {
+	suite.Suite
+	pinger        *mocks.Pinger
+	repos         bootstrap.Repositories
+	app           *bootstrap.App
+	testingServer *httptest.Server
+	// rootToken is the token of the configured root user, once logged in
+	rootToken string
+}
+
+func (suite *AppSuite) SetupTest() {
+	cfg := config.Default()
+	cfg.Root = config.RootConfig{Username: "root", Password: "password"}
+	// The webhook receivers of the tests listen on loopback
+	cfg.Webhooks.AllowedNetworks = []string{"127.0.0.0/8", "::1/128"}
+	// The in-memory transactor stands in for a replica set
+	cfg.Outbox.Enabled = true
+	suite.pinger = &mocks.Pinger{}
+	suite.rootToken = ""
+
+	suite.repos = bootstrap.NewInMemoryRepositories()
+	suite.Require().NoError(bootstrap.EnsureRootUser(context.Background(), &cfg, suite.repos))
+	suite.app = bootstrap.NewApp(&cfg, suite.repos, bootstrap.Infrastructure{
+		Database: suite.pinger,
+		Logger:   slog.New(slog.NewJSONHandler(io.Discard, nil)),
+		Metrics:  infrastructure.NewMetrics(),
+	})
+	suite.testingServer = httptest.NewServer(suite.app.Router)
+}
+
+func (suite *AppSuite) TearDownTest() {
+	suite.testingServer.Close()
+}
+
+// do sends a JSON request and decodes the JSON response into out
+func (suite *AppSuite) do(method, path, token string, body interface{}, out interface{}) int {
+	return suite.doWithHeader(method, path, token, nil, body, out)
+}
+
+// doWithHeader is do with extra request headers
+func (suite *AppSuite) doWithHeader(method, path, token string, header http.Header, body interface{}, out interface{}) int {
+	var reader io.Reader
+	if body != nil {
+		requestBody, err := json.Marshal(body)
+		suite.Require().NoError(err)
+		reader = bytes.NewReader(requestBody)
+	}
+
+	req, err := http.NewRequest(method, suite.testingServer.URL+path, reader)
+	suite.Require().NoError(err)
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	response, err := http.DefaultClient.Do(req)
+	suite.Require().NoError(err)
+	defer response.Body.Close()
+
+	if out != nil {
+		suite.Require().NoError(json.NewDecoder(response.Body).Decode(out))
+	}
+	return response.StatusCode
+}
+
+// doRaw sends a request body as is and returns the status and raw response body
+func (suite *AppSuite) doRaw(method, path, token, contentType, body string) (int, http.Header, string) {
+	req, err := http.NewRequest(method, suite.testingServer.URL+path, strings.NewReader(body))
+	suite.Require().NoError(err)
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	response, err := http.DefaultClient.Do(req)
+	suite.Require().NoError(err)
+	defer response.Body.Close()
+
+	responseBody, err := io.ReadAll(response.Body)
+	suite.Require().NoError(err)
+	return response.StatusCode, response.Header, string(responseBody)
+}
+
+// login registers a user and returns a token for it. Users register
+// themselves; other roles are created by the root user.
+func (suite *AppSuite) login(username, role string) string {
+	if role != "user" {
+		return suite.loginIn(username, role, "")
+	}
+	status := suite.do(http.MethodPost, "/register", "", map[string]string{"username": username, "password": "password"}, nil)
+	suite.Require().Equal(http.StatusCreated, status)
+	return suite.token(username)
+}
+
+// loginIn has the root user create a user in the organization orgID and
+// returns a token for it
+func (suite *AppSuite) loginIn(username, role, orgID string) string {
+	status := suite.do(http.MethodPost, "/users", suite.root(), map[string]string{"username": username, "password": "password", "role": role, "org_id": orgID}, nil)
+	suite.Require().Equal(http.StatusCreated, status)
+	return suite.token(username)
+}
+
+// root returns a token of the configured root user
+func (suite *AppSuite) root() string {
+	if suite.rootToken == "" {
+		suite.rootToken = suite.token("root")
+	}
+	return suite.rootToken
+}
+
+// token logs a user in and returns its token
+func (suite *AppSuite) token(username string) string {
+	var loginResponse struct {
+		Token string `json:"token"`
+	}
+	status := suite.do(http.MethodPost, "/login", "", map[string]string{"username": username, "password": "password"}, &loginResponse)
+	suite.Require().Equal(http.StatusOK, status)
+	suite.Require().NotEmpty(loginResponse.Token)
+	return loginResponse.Token
+}
+
+func (suite *AppSuite) TestTaskLifecycle() {
+	token := suite.login("tester1", "user")
+
+	task := map[string]interface{}{
+		"title":       "Write tests",
+		"description": "Cover the composition root",
+		"status":      "In Progress",
+		"due_date":    time.Now().Add(24 * time.Hour),
+	}
+	var created struct {
+		Task struct {
+			ID string `json:"id"`
+		} `json:"task"`
+	}
+	suite.Equal(http.StatusOK, suite.do(http.MethodPost, "/tasks", token, task, &created))
+	suite.NotEmpty(created.Task.ID)
+
+	var mine struct {
+		Tasks []map[string]interface{} `json:"tasks"`
+	}
+	suite.Equal(http.StatusOK, suite.do(http.MethodGet, "/tasks", token, nil, &mine))
+	suite.Len(mine.Tasks, 1)
+
+	suite.Equal(http.StatusOK, suite.do(http.MethodPut, "/tasks/"+created.Task.ID, token, map[string]string{"status": "Completed"}, nil))
+
+	var fetched struct {
+		Task struct {
+			Status string `json:"status"`
+		} `json:"task"`
+	}
+	suite.Equal(http.StatusOK, suite.do(http.MethodGet, "/tasks/"+created.Task.ID, token, nil, &fetched))
+	suite.Equal("Completed", fetched.Task.Status)
+
+	suite.Equal(http.StatusOK, suite.do(http.MethodDelete, "/tasks/"+created.Task.ID, token, nil, nil))
+	suite.Equal(http.StatusNotFound, suite.do(http.MethodGet, "/tasks/"+created.Task.ID, token, nil, nil))
+}
+
+// TestReplaceTaskKeepsCreator tests that a full update cannot hand a task to
+// another user
+func (suite *AppSuite) TestReplaceTaskKeepsCreator() {
+	alice := suite.login("alice", "user")
+	bob := suite.login("bob", "user")
+	var me struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodGet, "/me", bob, nil, &me))
+
+	var created struct {
+		Task domain.Task `json:"task"`
+	}
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodPost, "/tasks", alice, map[string]string{"title": "Mine", "description": "Keep it", "status": "Not Started"}, &created))
+	path := "/tasks/" + created.Task.ID.Hex()
+	var before, after struct {
+		Task domain.Task `json:"task"`
+	}
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodGet, path, alice, nil, &before))
+
+	// Claiming the task in the body does not get past the check of its creator
+	replacement := map[string]string{"title": "Taken", "description": "Keep it", "status": "Not Started", "created_by": me.Data.ID}
+	suite.Equal(http.StatusForbidden, suite.do(http.MethodPatch, path, bob, replacement, nil))
+
+	// Nor does the creator give it away
+	suite.Equal(http.StatusOK, suite.do(http.MethodPatch, path, alice, replacement, nil))
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodGet, path, alice, nil, &after))
+	suite.Equal("Taken", after.Task.Title)
+	suite.Equal(before.Task.CreatedBy, after.Task.CreatedBy)
+	suite.Equal(before.Task.Rank, after.Task.Rank)
+}
+
+func (suite *AppSuite) TestProtectedRoutesRequireToken() {
+	suite.Equal(http.StatusUnauthorized, suite.do(http.MethodGet, "/tasks", "", nil, nil))
+	suite.Equal(http.StatusUnauthorized, suite.do(http.MethodGet, "/me", "not-a-token", nil, nil))
+}
+
+func (suite *AppSuite) TestMe() {
+	token := suite.login("tester1", "user")
+
+	var me struct {
+		Data struct {
+			Username string `json:"username"`
+		} `json:"data"`
+	}
+	suite.Equal(http.StatusOK, suite.do(http.MethodGet, "/me", token, nil, &me))
+	suite.Equal("tester1", me.Data.Username)
+
+	status := suite.do(http.MethodPost, "/me/password", token, map[string]string{"old_password": "password", "new_password": "secret123"}, nil)
+	suite.Equal(http.StatusOK, status)
+	status = suite.do(http.MethodPost, "/login", "", map[string]string{"username": "tester1", "password": "secret123"}, nil)
+	suite.Equal(http.StatusOK, status)
+}
+
+func (suite *AppSuite) TestProbesAndMetrics() {
+	suite.pinger.On("Ping", mock.Anything).Return(nil).Once()
+	suite.Equal(http.StatusOK, suite.do(http.MethodGet, "/healthz", "", nil, nil))
+	suite.Equal(http.StatusOK, suite.do(http.MethodGet, "/readyz", "", nil, nil))
+
+	suite.login("tester1", "user")
+
+	response, err := http.Get(fmt.Sprintf("%s/metrics", suite.testingServer.URL))
+	suite.Require().NoError(err)
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	suite.Require().NoError(err)
+
+	suite.True(strings.Contains(string(body), `task_manager_login_attempts_total{result="success"} 1`))
+	suite.True(strings.Contains(string(body), `task_manager_http_request_duration_seconds_count{method="POST",route="/login",status="200"} 1`))
+	suite.pinger.AssertExpectations(suite.T())
+}
+
+func (suite *AppSuite) TestPublicRoutesAreRateLimited() {
+	credentials := map[string]string{"username": "nobody", "password": "password"}
+	burst := config.Default().RateLimit.Public.Burst
+	// Clients cannot pose as others through X-Forwarded-For without trusted proxies
+	for i := 0; i < burst; i++ {
+		header := http.Header{"X-Forwarded-For": {fmt.Sprintf("203.0.113.%d", i)}}
+		suite.Equal(http.StatusUnauthorized, suite.doWithHeader(http.MethodPost, "/login", "", header, credentials, nil))
+	}
+	header := http.Header{"X-Forwarded-For": {"198.51.100.1"}}
+	suite.Equal(http.StatusTooManyRequests, suite.doWithHeader(http.MethodPost, "/login", "", header, credentials, nil))
+
+	// Probes are never limited
+	suite.Equal(http.StatusOK, suite.do(http.MethodGet, "/healthz", "", nil, nil))
+}
+
+func (suite *AppSuite) TestSecurityMiddleware() {
+	// Oversized bodies are rejected before reaching a controller
+	body := strings.NewReader(fmt.Sprintf(`{"username":"%s"}`, strings.Repeat("a", int(config.Default().Server.MaxBodyBytes))))
+	response, err := http.Post(suite.testingServer.URL+"/register", "application/json", body)
+	suite.Require().NoError(err)
+	response.Body.Close()
+	suite.Equal(http.StatusRequestEntityTooLarge, response.StatusCode)
+	suite.Equal(infrastructure.ProblemContentType, response.Header.Get("Content-Type"))
+	suite.Equal("nosniff", response.Header.Get("X-Content-Type-Options"))
+	suite.NotEmpty(response.Header.Get("Strict-Transport-Security"))
+
+	// No origin is allowed by default
+	req, err := http.NewRequest(http.MethodOptions, suite.testingServer.URL+"/tasks", nil)
+	suite.Require().NoError(err)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	response, err = http.DefaultClient.Do(req)
+	suite.Require().NoError(err)
+	response.Body.Close()
+	suite.Equal(http.StatusForbidden, response.StatusCode)
+}
+
+func (suite *AppSuite) TestIdempotentTaskCreation() {
+	token := suite.login("tester1", "user")
+	header := http.Header{infrastructure.IdempotencyKeyHeader: {"create-task-1"}}
+	task := map[string]interface{}{
+		"title":       "Retry me",
+		"description": "Created once",
+		"status":      "Not Started",
+		"due_date":    time.Now().Add(time.Hour),
+	}
+
+	var first, retry map[string]interface{}
+	suite.Equal(http.StatusOK, suite.doWithHeader(http.MethodPost, "/tasks", token, header, task, &first))
+	suite.Equal(http.StatusOK, suite.doWithHeader(http.MethodPost, "/tasks", token, header, task, &retry))
+	suite.Equal(first, retry)
+
+	var mine struct {
+		Tasks []map[string]interface{} `json:"tasks"`
+	}
+	suite.Equal(http.StatusOK, suite.do(http.MethodGet, "/tasks", token, nil, &mine))
+	suite.Len(mine.Tasks, 1)
+
+	task["title"] = "Something else"
+	suite.Equal(http.StatusUnprocessableEntity, suite.doWithHeader(http.MethodPost, "/tasks", token, header, task, nil))
+}
+
+// bulkResponse is the body returned by POST /tasks/bulk
+type bulkResponse struct {
+	Failed  int `json:"failed"`
+	Results []struct {
+		ID     string `json:"id"`
+		Status int    `json:"status"`
+	} `json:"results"`
+}
+
+func (suite *AppSuite) TestBulkTasks() {
+	alice := suite.login("alice", "user")
+	bob := suite.login("bob", "user")
+
+	newTask := func(title string) map[string]interface{} {
+		return map[string]interface{}{"title": title, "description": "Sprint cleanup", "status": "In Progress"}
+	}
+	var created bulkResponse
+	status := suite.do(http.MethodPost, "/tasks/bulk", alice, map[string]interface{}{
+		"operations": []map[string]interface{}{
+			{"op": "create", "task": newTask("First")},
+			{"op": "create", "task": newTask("Second")},
+		},
+	}, &created)
+	suite.Require().Equal(http.StatusOK, status)
+	suite.Require().Len(created.Results, 2)
+	suite.Equal(http.StatusCreated, created.Results[0].Status)
+	first, second := created.Results[0].ID, created.Results[1].ID
+
+	// Other users cannot change alice's tasks
+	var denied bulkResponse
+	status = suite.do(http.MethodPost, "/tasks/bulk", bob, map[string]interface{}{
+		"operations": []map[string]interface{}{{"op": "delete", "id": first}},
+	}, &denied)
+	suite.Equal(http.StatusMultiStatus, status)
+	suite.Equal(http.StatusForbidden, denied.Results[0].Status)
+
+	// An atomic request with an invalid operation changes nothing
+	operations := []map[string]interface{}{
+		{"op": "status", "id": first, "status": "Completed"},
+		{"op": "delete", "id": second},
+		{"op": "update", "id": first, "fields": map[string]interface{}{"status": "Done"}},
+	}
+	var rolledBack bulkResponse
+	status = suite.do(http.MethodPost, "/tasks/bulk", alice, map[string]interface{}{"atomic": true, "operations": operations}, &rolledBack)
+	suite.Equal(http.StatusMultiStatus, status)
+	suite.Equal(3, rolledBack.Failed)
+	suite.Equal([]int{http.StatusFailedDependency, http.StatusFailedDependency, http.StatusBadRequest},
+		[]int{rolledBack.Results[0].Status, rolledBack.Results[1].Status, rolledBack.Results[2].Status})
+
+	var fetched struct {
+		Task struct {
+			Status string `json:"status"`
+		} `json:"task"`
+	}
+	suite.Equal(http.StatusOK, suite.do(http.MethodGet, "/tasks/"+first, alice, nil, &fetched))
+	suite.Equal("In Progress", fetched.Task.Status)
+	suite.Equal(http.StatusOK, suite.do(http.MethodGet, "/tasks/"+second, alice, nil, nil))
+
+	// Without atomic mode the valid operations are applied
+	var partial bulkResponse
+	status = suite.do(http.MethodPost, "/tasks/bulk", alice, map[string]interface{}{"operations": operations}, &partial)
+	suite.Equal(http.StatusMultiStatus, status)
+	suite.Equal(1, partial.Failed)
+	suite.Equal(http.StatusOK, suite.do(http.MethodGet, "/tasks/"+first, alice, nil, &fetched))
+	suite.Equal("Completed", fetched.Task.Status)
+	suite.Equal(http.StatusNotFound, suite.do(http.MethodGet, "/tasks/"+second, alice, nil, nil))
+}
+
+func (suite *AppSuite) TestImportAndExportTasks() {
+	alice := suite.login("alice", "user")
+	bob := suite.login("bob", "user")
+
+	csvFile := "Name,Notes,Due,status\n" +
+		"Write report,Quarterly numbers,2030-01-15,In Progress\n" +
+		",Missing title,2030-01-15,Completed\n" +
+		"Plan trip,Book hotels,next week,Not Started\n" +
+		"Too,many,fields,here,now\n"
+	path := "/tasks/import?columns=Name:title,Notes:description,Due:due_date"
+
+	type importResponse struct {
+		Created int `json:"created"`
+		Invalid int `json:"invalid"`
+		Results []struct {
+			Row    int      `json:"row"`
+			Status string   `json:"status"`
+			Errors []string `json:"errors"`
+		} `json:"results"`
+	}
+
+	// A dry run validates every row without creating anything
+	var dryRun importResponse
+	status, _, body := suite.doRaw(http.MethodPost, path+"&dry_run=true", alice, "text/csv", csvFile)
+	suite.Require().Equal(http.StatusMultiStatus, status)
+	suite.Require().NoError(json.Unmarshal([]byte(body), &dryRun))
+	suite.Equal(0, dryRun.Created)
+	suite.Equal(3, dryRun.Invalid)
+	suite.Equal("valid", dryRun.Results[0].Status)
+	suite.Equal([]int{2, 3, 4}, []int{dryRun.Results[1].Row, dryRun.Results[2].Row, dryRun.Results[3].Row})
+
+	var imported importResponse
+	status, _, body = suite.doRaw(http.MethodPost, path, alice, "text/csv", csvFile)
+	suite.Require().Equal(http.StatusMultiStatus, status)
+	suite.Require().NoError(json.Unmarshal([]byte(body), &imported))
+	suite.Equal(1, imported.Created)
+	suite.Equal("created", imported.Results[0].Status)
+
+	ndjson := `{"title": "Call bank", "description": "Ask about fees", "status": "Not Started"}` + "\n"
+	status, _, _ = suite.doRaw(http.MethodPost, "/tasks/import", bob, "application/x-ndjson", ndjson)
+	suite.Equal(http.StatusOK, status)
+
+	// Unknown mapping targets are rejected
+	status, _, _ = suite.doRaw(http.MethodPost, "/tasks/import?columns=Name:owner", alice, "text/csv", csvFile)
+	suite.Equal(http.StatusBadRequest, status)
+
+	// Users only export their own tasks
+	status, header, body := suite.doRaw(http.MethodGet, "/tasks/export?format=csv", alice, "", "")
+	suite.Require().Equal(http.StatusOK, status)
+	suite.Equal(`attachment; filename="tasks.csv"`, header.Get("Content-Disposition"))
+	lines := strings.Split(strings.TrimSpace(body), "\n")
+	suite.Require().Len(lines, 2)
+	suite.Equal("id,title,description,due_date,status,created_by", lines[0])
+	suite.Contains(lines[1], ",Write report,Quarterly numbers,2030-01-15T00:00:00Z,In Progress,")
+
+	status, _, body = suite.doRaw(http.MethodGet, "/tasks/export?format=ndjson", bob, "", "")
+	suite.Require().Equal(http.StatusOK, status)
+	suite.Equal(1, strings.Count(body, "\n"))
+	suite.Contains(body, `"title":"Call bank"`)
+
+	status, _, body = suite.doRaw(http.MethodGet, "/tasks/export", suite.login("carol", "admin"), "", "")
+	suite.Require().Equal(http.StatusOK, status)
+	var all []map[string]interface{}
+	suite.Require().NoError(json.Unmarshal([]byte(body), &all))
+	suite.Len(all, 2)
+
+	status, _, _ = suite.doRaw(http.MethodGet, "/tasks/export?format=xml", alice, "", "")
+	suite.Equal(http.StatusBadRequest, status)
+}
+
+// TestExportEscapesFormulas tests that CSV cells starting like a spreadsheet
+// formula are exported as text and imported as they were
+func (suite *AppSuite) TestExportEscapesFormulas() {
+	alice := suite.login("alice", "user")
+	for _, task := range []map[string]interface{}{
+		{"title": "=HYPERLINK(\"http://evil.example.com\")", "description": "+1 for this", "status": "Not Started"},
+		{"title": "@SUM(A1:A2)", "description": "-2 days", "status": "Not Started"},
+		{"title": "Plain", "description": "a=b", "status": "Not Started"},
+	} {
+		suite.Require().Equal(http.StatusOK, suite.do(http.MethodPost, "/tasks", alice, task, nil))
+	}
+
+	status, _, body := suite.doRaw(http.MethodGet, "/tasks/export?format=csv", alice, "", "")
+	suite.Require().Equal(http.StatusOK, status)
+	records, err := csv.NewReader(strings.NewReader(body)).ReadAll()
+	suite.Require().NoError(err)
+	suite.Require().Len(records, 4)
+	cells := map[string]string{}
+	for _, record := range records[1:] {
+		cells[record[1]] = record[2]
+	}
+	suite.Equal(map[string]string{
+		`'=HYPERLINK("http://evil.example.com")`: "'+1 for this",
+		"'@SUM(A1:A2)":                           "'-2 days",
+		"Plain":                                  "a=b",
+	}, cells)
+
+	// Importing the export gives the original values back
+	bob := suite.login("bob", "user")
+	status, _, _ = suite.doRaw(http.MethodPost, "/tasks/import", bob, "text/csv", body)
+	suite.Require().Equal(http.StatusOK, status)
+	var imported struct {
+		Tasks []domain.Task `json:"tasks"`
+	}
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodGet, "/tasks", bob, nil, &imported))
+	cells = map[string]string{}
+	for _, task := range imported.Tasks {
+		cells[task.Title] = task.Description
+	}
+	suite.Equal(map[string]string{
+		`=HYPERLINK("http://evil.example.com")`: "+1 for this",
+		"@SUM(A1:A2)":                           "-2 days",
+		"Plain":                                 "a=b",
+	}, cells)
+}
+
+func (suite *AppSuite) TestCalendarFeed() {
+	alice := suite.login("alice", "user")
+
+	task := map[string]interface{}{
+		"title":       "Ship release, finally",
+		"description": "Tag; build\npublish",
+		"status":      "In Progress",
+		"due_date":    time.Date(2030, 1, 15, 9, 30, 0, 0, time.UTC),
+	}
+	var created struct {
+		Task struct {
+			ID string `json:"id"`
+		} `json:"task"`
+	}
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodPost, "/tasks", alice, task, &created))
+	undated := map[string]interface{}{"title": "Someday", "description": "No due date", "status": "Not Started"}
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodPost, "/tasks", alice, undated, nil))
+
+	var feed struct {
+		Token string `json:"token"`
+		URL   string `json:"url"`
+	}
+	suite.Require().Equal(http.StatusCreated, suite.do(http.MethodPost, "/me/calendar-token", alice, nil, &feed))
+	suite.True(strings.HasSuffix(feed.URL, "/calendar/"+feed.Token+".ics"))
+	path := "/calendar/" + feed.Token + ".ics"
+
+	// The feed needs no bearer token
+	status, header, body := suite.doRaw(http.MethodGet, path, "", "", "")
+	suite.Require().Equal(http.StatusOK, status)
+	suite.Equal(infrastructure.ICalendarContentType, header.Get("Content-Type"))
+	suite.Contains(body, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n")
+	suite.Contains(body, "UID:"+created.Task.ID+"@task-manager\r\n")
+	suite.Contains(body, "DTSTART:20300115T093000Z\r\n")
+	suite.Contains(body, "SUMMARY:Ship release\\, finally\r\n")
+	suite.Contains(body, "DESCRIPTION:Tag\\; build\\npublish\r\n")
+	suite.Equal(1, strings.Count(body, "BEGIN:VEVENT"))
+
+	// Unchanged feeds are not sent again
+	req, err := http.NewRequest(http.MethodGet, suite.testingServer.URL+path, nil)
+	suite.Require().NoError(err)
+	req.Header.Set("If-None-Match", header.Get("ETag"))
+	response, err := http.DefaultClient.Do(req)
+	suite.Require().NoError(err)
+	response.Body.Close()
+	suite.Equal(http.StatusNotModified, response.StatusCode)
+
+	// To-dos carry the task status
+	suite.Equal(http.StatusOK, suite.do(http.MethodPut, "/tasks/"+created.Task.ID, alice, map[string]string{"status": "Completed"}, nil))
+	status, changed, body := suite.doRaw(http.MethodGet, path+"?type=todo", "", "", "")
+	suite.Require().Equal(http.StatusOK, status)
+	suite.NotEqual(header.Get("ETag"), changed.Get("ETag"))
+	suite.Contains(body, "BEGIN:VTODO\r\n")
+	suite.Contains(body, "DUE:20300115T093000Z\r\n")
+	suite.Contains(body, "STATUS:COMPLETED\r\n")
+
+	// Rotating or revoking the token disables the old URL
+	suite.Require().Equal(http.StatusCreated, suite.do(http.MethodPost, "/me/calendar-token", alice, nil, nil))
+	status, _, _ = suite.doRaw(http.MethodGet, path, "", "", "")
+	suite.Equal(http.StatusNotFound, status)
+
+	suite.Equal(http.StatusOK, suite.do(http.MethodDelete, "/me/calendar-token", alice, nil, nil))
+	status, _, _ = suite.doRaw(http.MethodGet, "/calendar/.ics", "", "", "")
+	suite.Equal(http.StatusNotFound, status)
+}
+
+func (suite *AppSuite) TestDueDateNotifications() {
+	alice := suite.login("alice", "user")
+	bob := suite.login("bob", "user")
+
+	newTask := func(title string, due time.Time, status string) string {
+		var created struct {
+			Task struct {
+				ID string `json:"id"`
+			} `json:"task"`
+		}
+		task := map[string]interface{}{"title": title, "description": "Reminder test", "status": status, "due_date": due}
+		suite.Require().Equal(http.StatusOK, suite.do(http.MethodPost, "/tasks", alice, task, &created))
+		return created.Task.ID
+	}
+	late := newTask("Late", time.Now().Add(-time.Hour), "In Progress")
+	newTask("Soon", time.Now().Add(time.Hour), "Not Started")
+	newTask("Done", time.Now().Add(-time.Hour), "Completed")
+	newTask("Later", time.Now().Add(72*time.Hour), "Not Started")
+
+	var fetched struct {
+		Task struct {
+			Overdue bool `json:"overdue"`
+		} `json:"task"`
+	}
+	suite.Equal(http.StatusOK, suite.do(http.MethodGet, "/tasks/"+late, alice, nil, &fetched))
+	suite.True(fetched.Task.Overdue)
+
+	// Running the check again does not repeat notifications
+	suite.app.Reminders.RunOnce(context.Background())
+	suite.app.Reminders.RunOnce(context.Background())
+
+	type notificationsResponse struct {
+		Notifications []struct {
+			ID     string `json:"id"`
+			TaskID string `json:"task_id"`
+			Kind   string `json:"kind"`
+		} `json:"notifications"`
+		Unread int `json:"unread"`
+	}
+	var list notificationsResponse
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodGet, "/notifications", alice, nil, &list))
+	suite.Require().Len(list.Notifications, 2)
+	suite.Equal(2, list.Unread)
+	kinds := map[string]string{}
+	for _, notification := range list.Notifications {
+		kinds[notification.Kind] = notification.TaskID
+	}
+	suite.Equal(late, kinds["overdue"])
+	suite.Contains(kinds, "due_soon")
+
+	// Notifications are private to their owner
+	suite.Equal(http.StatusNotFound, suite.do(http.MethodPost, "/notifications/"+list.Notifications[0].ID+"/read", bob, nil, nil))
+	suite.Equal(http.StatusOK, suite.do(http.MethodPost, "/notifications/"+list.Notifications[0].ID+"/read", alice, nil, nil))
+
+	var unread notificationsResponse
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodGet, "/notifications?unread=true", alice, nil, &unread))
+	suite.Len(unread.Notifications, 1)
+	suite.Equal(1, unread.Unread)
+
+	var markedAll struct {
+		Updated int `json:"updated"`
+	}
+	suite.Equal(http.StatusOK, suite.do(http.MethodPost, "/notifications/read", alice, nil, &markedAll))
+	suite.Equal(1, markedAll.Updated)
+	suite.Equal(http.StatusBadRequest, suite.do(http.MethodGet, "/notifications?limit=0", alice, nil, nil))
+}
+
+func (suite *AppSuite) TestWebhooks() {
+	alice := suite.login("alice", "user")
+	bob := suite.login("bob", "user")
+
+	// The receiver rejects the first request it gets and checks every signature
+	type received struct {
+		event     string
+		signature string
+		body      string
+	}
+	var (
+		mu       sync.Mutex
+		requests []received
+	)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		requests = append(requests, received{r.Header.Get(infrastructure.WebhookEventHeader), r.Header.Get(infrastructure.WebhookSignatureHeader), string(body)})
+		timestamp, _ := strconv.ParseInt(r.Header.Get(infrastructure.WebhookTimestampHeader), 10, 64)
+		if len(requests) == 1 || r.Header.Get(infrastructure.WebhookSignatureHeader) != infrastructure.SignWebhook("0123456789abcdef", timestamp, body) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	var created struct {
+		Webhook struct {
+			ID string `json:"id"`
+		} `json:"webhook"`
+		Secret string `json:"secret"`
+	}
+	hook := map[string]interface{}{"url": receiver.URL, "events": []string{"task.created", "task.completed"}, "secret": "0123456789abcdef"}
+	suite.Require().Equal(http.StatusCreated, suite.do(http.MethodPost, "/webhooks", alice, hook, &created))
+	suite.Equal("0123456789abcdef", created.Secret)
+	suite.Equal(http.StatusBadRequest, suite.do(http.MethodPost, "/webhooks", alice, map[string]interface{}{"url": receiver.URL, "events": []string{"task.archived"}}, nil))
+	suite.Equal(http.StatusNotFound, suite.do(http.MethodGet, "/webhooks/"+created.Webhook.ID, bob, nil, nil))
+
+	// Bob's tasks are out of scope and updates were not subscribed to
+	task := map[string]interface{}{"title": "Hooked", "description": "Webhook test", "status": "In Progress"}
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodPost, "/tasks", bob, task, nil))
+	var createdTask struct {
+		Task struct {
+			ID string `json:"id"`
+		} `json:"task"`
+	}
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodPost, "/tasks", alice, task, &createdTask))
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodPut, "/tasks/"+createdTask.Task.ID, alice, map[string]string{"status": "Completed"}, nil))
+
+	suite.app.Outbox.RunOnce(context.Background())
+	suite.app.Webhooks.RunOnce(context.Background())
+
+	type deliveriesResponse struct {
+		Deliveries []struct {
+			ID       string `json:"id"`
+			Event    string `json:"event"`
+			Status   string `json:"status"`
+			Attempts []struct {
+				StatusCode int `json:"status_code"`
+			} `json:"attempts"`
+			RedeliveryOf string `json:"redelivery_of"`
+		} `json:"deliveries"`
+	}
+	var log deliveriesResponse
+	deliveriesPath := "/webhooks/" + created.Webhook.ID + "/deliveries"
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodGet, deliveriesPath, alice, nil, &log))
+	suite.Require().Len(log.Deliveries, 2)
+	statuses := map[string]string{}
+	for _, delivery := range log.Deliveries {
+		statuses[delivery.Event] = delivery.Status
+		suite.Len(delivery.Attempts, 1)
+	}
+	// The first delivery failed and waits for its retry
+	suite.Equal(map[string]string{"task.created": "pending", "task.completed": "succeeded"}, statuses)
+
+	mu.Lock()
+	suite.Require().Len(requests, 2)
+	suite.Equal("task.created", requests[0].event)
+	suite.Contains(requests[0].body, createdTask.Task.ID)
+	suite.Equal("task.completed", requests[1].event)
+	mu.Unlock()
+
+	// A redelivery sends the same payload again right away
+	var failed string
+	for _, delivery := range log.Deliveries {
+		if delivery.Status == "pending" {
+			failed = delivery.ID
+		}
+	}
+	redeliverPath := deliveriesPath + "/" + failed + "/redeliver"
+	suite.Equal(http.StatusNotFound, suite.do(http.MethodPost, redeliverPath, bob, nil, nil))
+	suite.Require().Equal(http.StatusAccepted, suite.do(http.MethodPost, redeliverPath, alice, nil, nil))
+	suite.app.Webhooks.RunOnce(context.Background())
+
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodGet, deliveriesPath+"?limit=1", alice, nil, &log))
+	suite.Require().Len(log.Deliveries, 1)
+	suite.Equal(failed, log.Deliveries[0].RedeliveryOf)
+	suite.Equal("succeeded", log.Deliveries[0].Status)
+	mu.Lock()
+	suite.Require().Len(requests, 3)
+	suite.Equal(requests[0].body, requests[2].body)
+	mu.Unlock()
+
+	// Disabled webhooks get no new deliveries
+	suite.Equal(http.StatusOK, suite.do(http.MethodPatch, "/webhooks/"+created.Webhook.ID, alice, map[string]bool{"active": false}, nil))
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodPost, "/tasks", alice, task, nil))
+	suite.app.Outbox.RunOnce(context.Background())
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodGet, deliveriesPath, alice, nil, &log))
+	suite.Len(log.Deliveries, 3)
+
+	suite.Equal(http.StatusOK, suite.do(http.MethodDelete, "/webhooks/"+created.Webhook.ID, alice, nil, nil))
+	suite.Equal(http.StatusNotFound, suite.do(http.MethodGet, deliveriesPath, alice, nil, nil))
+}
+
+// serverSentEvent is one event read from a text/event-stream response
+type serverSentEvent struct {
+	ID    string
+	Event string
+	Data  string
+}
+
+// openEvents connects to the event stream and returns the events as they arrive
+func (suite *AppSuite) openEvents(token, lastEventID string) (<-chan serverSentEvent, func()) {
+	req, err := http.NewRequest(http.MethodGet, suite.testingServer.URL+"/events", nil)
+	suite.Require().NoError(err)
+	req.Header.Set("Authorization", "Bearer "+token)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	response, err := http.DefaultClient.Do(req)
+	suite.Require().NoError(err)
+	suite.Require().Equal(http.StatusOK, response.StatusCode)
+	suite.Equal("text/event-stream", response.Header.Get("Content-Type"))
+
+	events := make(chan serverSentEvent, 16)
+	go func() {
+		defer close(events)
+		scanner := bufio.NewScanner(response.Body)
+		var event serverSentEvent
+		for scanner.Scan() {
+			field, value, _ := strings.Cut(scanner.Text(), ": ")
+			switch field {
+			case "id":
+				event.ID = value
+			case "event":
+				event.Event = value
+			case "data":
+				event.Data = value
+			case "":
+				if event.Event != "" {
+					events <- event
+				}
+				event = serverSentEvent{}
+			}
+		}
+	}()
+	return events, func() { response.Body.Close() }
+}
+
+// next returns the next event of a stream or fails after a second
+func (suite *AppSuite) next(events <-chan serverSentEvent) serverSentEvent {
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(time.Second):
+		suite.FailNow("no event received")
+		return serverSentEvent{}
+	}
+}
+
+func (suite *AppSuite) TestTaskEventStreams() {
+	alice := suite.login("alice", "user")
+	bob := suite.login("bob", "user")
+	admin := suite.login("carol", "admin")
+
+	suite.Equal(http.StatusUnauthorized, suite.do(http.MethodGet, "/events", "", nil, nil))
+
+	aliceEvents, closeAlice := suite.openEvents(alice, "")
+	defer closeAlice()
+	wsURL := "ws" + strings.TrimPrefix(suite.testingServer.URL, "http") + "/events/ws?access_token=" + admin
+	conn, err := websocket.Dial(wsURL, "", suite.testingServer.URL)
+	suite.Require().NoError(err)
+	defer conn.Close()
+
+	newTask := func(token string) string {
+		var created struct {
+			Task struct {
+				ID string `json:"id"`
+			} `json:"task"`
+		}
+		task := map[string]interface{}{"title": "Live", "description": "Stream test", "status": "In Progress"}
+		suite.Require().Equal(http.StatusOK, suite.do(http.MethodPost, "/tasks", token, task, &created))
+		return created.Task.ID
+	}
+	newTask(bob)
+	taskID := newTask(alice)
+	// Events reach the streams once the relay took them from the outbox
+	suite.app.Outbox.RunOnce(context.Background())
+
+	// Alice only sees her own task
+	created := suite.next(aliceEvents)
+	suite.Equal("task.created", created.Event)
+	suite.Contains(created.Data, taskID)
+	closeAlice()
+
+	// Admins see every task
+	var message struct {
+		ID   string `json:"id"`
+		Type string `json:"type"`
+		Task struct {
+			ID string `json:"id"`
+		} `json:"task"`
+	}
+	suite.Require().NoError(conn.SetReadDeadline(time.Now().Add(time.Second)))
+	suite.Require().NoError(websocket.JSON.Receive(conn, &message))
+	suite.Equal("task.created", message.Type)
+	suite.Require().NoError(websocket.JSON.Receive(conn, &message))
+	suite.Equal(taskID, message.Task.ID)
+
+	// Changes made while disconnected are replayed on resume
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodPut, "/tasks/"+taskID, alice, map[string]string{"status": "Completed"}, nil))
+	suite.app.Outbox.RunOnce(context.Background())
+	resumed, closeResumed := suite.openEvents(alice, created.ID)
+	defer closeResumed()
+	suite.Equal("task.updated", suite.next(resumed).Event)
+	suite.Equal("task.completed", suite.next(resumed).Event)
+
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodDelete, "/tasks/"+taskID, alice, nil, nil))
+	suite.app.Outbox.RunOnce(context.Background())
+	suite.Equal("task.deleted", suite.next(resumed).Event)
+
+	// A rolled back change leaves no event behind
+	operations := []map[string]interface{}{
+		{"op": "create", "task": map[string]interface{}{"title": "Rolled back", "description": "Stream test", "status": "In Progress"}},
+		{"op": "create", "task": map[string]interface{}{"title": "Invalid"}},
+	}
+	suite.Require().Equal(http.StatusMultiStatus, suite.do(http.MethodPost, "/tasks/bulk", alice, map[string]interface{}{"atomic": true, "operations": operations}, nil))
+	suite.app.Outbox.RunOnce(context.Background())
+	select {
+	case event := <-resumed:
+		suite.Failf("unexpected event", "%s after a rollback", event.Event)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// An unknown event ID asks the client to reload
+	reset, closeReset := suite.openEvents(alice, "forgotten")
+	defer closeReset()
+	suite.Equal("reset", suite.next(reset).Event)
+}
+
+func (suite *AppSuite) TestShutdownEndsEventStreams() {
+	events, closeStream := suite.openEvents(suite.login("alice", "user"), "")
+	defer closeStream()
+
+	server := suite.testingServer.Config
+	server.RegisterOnShutdown(suite.app.Streams.Close)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	suite.Require().NoError(server.Shutdown(ctx))
+
+	_, open := <-events
+	suite.False(open)
+}
+
+func (suite *AppSuite) TestComments() {
+	alice := suite.login("alice", "user")
+	bob := suite.login("bob", "user")
+	admin := suite.login("carol", "admin")
+	root := suite.login("dave", "root")
+
+	var task struct {
+		Task struct {
+			ID string `json:"id"`
+		} `json:"task"`
+	}
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodPost, "/tasks", alice, map[string]interface{}{"title": "Discussed", "description": "Comment test", "status": "Not Started"}, &task))
+	comments := "/tasks/" + task.Task.ID + "/comments"
+
+	type comment struct {
+		ID       string `json:"id"`
+		ParentID string `json:"parent_id"`
+		Body     string `json:"body"`
+		EditedAt string `json:"edited_at"`
+		Deleted  bool   `json:"deleted"`
+		Mentions []struct {
+			Username string `json:"username"`
+		} `json:"mentions"`
+		Replies []comment `json:"replies"`
+	}
+	var question struct {
+		Comment comment `json:"comment"`
+	}
+	suite.Require().Equal(http.StatusCreated, suite.do(http.MethodPost, comments, alice, map[string]string{"body": "Can **@carol** review this? Ping `@dave` later, @nobody."}, &question))
+	suite.Require().Len(question.Comment.Mentions, 1)
+	suite.Equal("carol", question.Comment.Mentions[0].Username)
+
+	// Users only see the discussion of their own tasks
+	suite.Equal(http.StatusNotFound, suite.do(http.MethodGet, comments, bob, nil, nil))
+	suite.Equal(http.StatusNotFound, suite.do(http.MethodPost, comments, bob, map[string]string{"body": "Hi"}, nil))
+	suite.Equal(http.StatusBadRequest, suite.do(http.MethodPost, comments, alice, map[string]string{"body": "   "}, nil))
+
+	var answer struct {
+		Comment comment `json:"comment"`
+	}
+	suite.Require().Equal(http.StatusCreated, suite.do(http.MethodPost, comments, admin, map[string]string{"body": "Looks good @alice", "parent_id": question.Comment.ID}, &answer))
+	suite.Equal(question.Comment.ID, answer.Comment.ParentID)
+
+	// The mentioned users are notified
+	var notifications struct {
+		Notifications []struct {
+			Kind   string `json:"kind"`
+			TaskID string `json:"task_id"`
+		} `json:"notifications"`
+	}
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodGet, "/notifications", admin, nil, &notifications))
+	suite.Require().Len(notifications.Notifications, 1)
+	suite.Equal("mention", notifications.Notifications[0].Kind)
+	suite.Equal(task.Task.ID, notifications.Notifications[0].TaskID)
+
+	// Only authors edit their comments
+	suite.Equal(http.StatusForbidden, suite.do(http.MethodPatch, comments+"/"+question.Comment.ID, admin, map[string]string{"body": "Edited"}, nil))
+	var edited struct {
+		Comment comment `json:"comment"`
+	}
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodPatch, comments+"/"+question.Comment.ID, alice, map[string]string{"body": "Can @carol review this today?"}, &edited))
+	suite.NotEmpty(edited.Comment.EditedAt)
+
+	// Users cannot delete an admin's comment, root users can; a comment with
+	// replies is kept as a placeholder
+	suite.Equal(http.StatusForbidden, suite.do(http.MethodDelete, comments+"/"+answer.Comment.ID, alice, nil, nil))
+	suite.Equal(http.StatusOK, suite.do(http.MethodDelete, comments+"/"+question.Comment.ID, alice, nil, nil))
+
+	var thread struct {
+		Comments []comment `json:"comments"`
+	}
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodGet, comments, alice, nil, &thread))
+	suite.Require().Len(thread.Comments, 1)
+	suite.True(thread.Comments[0].Deleted)
+	suite.Empty(thread.Comments[0].Body)
+	suite.Require().Len(thread.Comments[0].Replies, 1)
+	suite.Equal("Looks good @alice", thread.Comments[0].Replies[0].Body)
+	suite.Equal(http.StatusBadRequest, suite.do(http.MethodPost, comments, alice, map[string]string{"body": "Too late", "parent_id": question.Comment.ID}, nil))
+
+	// Removing the last reply also removes the placeholder
+	suite.Equal(http.StatusOK, suite.do(http.MethodDelete, comments+"/"+answer.Comment.ID, root, nil, nil))
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodGet, comments, alice, nil, &thread))
+	suite.Empty(thread.Comments)
+
+	// Deleting a task, on its own or in bulk, removes its comments
+	var other struct {
+		Task struct {
+			ID string `json:"id"`
+		} `json:"task"`
+	}
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodPost, "/tasks", alice, map[string]interface{}{"title": "Bulk", "description": "Comment test", "status": "Not Started"}, &other))
+	suite.Require().Equal(http.StatusCreated, suite.do(http.MethodPost, comments, alice, map[string]string{"body": "Kept until the task goes"}, nil))
+	suite.Require().Equal(http.StatusCreated, suite.do(http.MethodPost, "/tasks/"+other.Task.ID+"/comments", alice, map[string]string{"body": "Bulk"}, nil))
+
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodDelete, "/tasks/"+task.Task.ID, alice, nil, nil))
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodPost, "/tasks/bulk", alice, map[string]interface{}{
+		"operations": []map[string]interface{}{{"op": "delete", "id": other.Task.ID}},
+	}, nil))
+	ctx := context.Background()
+	for _, id := range []string{task.Task.ID, other.Task.ID} {
+		taskID, err := primitive.ObjectIDFromHex(id)
+		suite.Require().NoError(err)
+		left, err := suite.repos.Comments.GetComments(ctx, taskID)
+		suite.Require().NoError(err)
+		suite.Empty(left, id)
+	}
+}
+
+// upload attaches content to a task as a multipart file and returns the status and response body
+func (suite *AppSuite) upload(token, taskID, filename string, content []byte) (int, string) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", filename)
+	suite.Require().NoError(err)
+	_, err = part.Write(content)
+	suite.Require().NoError(err)
+	suite.Require().NoError(writer.Close())
+
+	status, _, response := suite.doRaw(http.MethodPost, "/tasks/"+taskID+"/attachments", token, writer.FormDataContentType(), body.String())
+	return status, response
+}
+
+func (suite *AppSuite) TestAttachments() {
+	alice := suite.login("alice", "user")
+	bob := suite.login("bob", "user")
+	admin := suite.login("carol", "admin")
+
+	var task struct {
+		Task struct {
+			ID string `json:"id"`
+		} `json:"task"`
+	}
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodPost, "/tasks", alice, map[string]interface{}{"title": "Attached", "description": "Attachment test", "status": "Not Started"}, &task))
+
+	// Uploads may be larger than the body limit of the other routes
+	content := append([]byte("%PDF-1.7\n"), bytes.Repeat([]byte("spec "), 2<<20/5)...)
+	suite.Greater(int64(len(content)), config.Default().Server.MaxBodyBytes)
+	status, body := suite.upload(alice, task.Task.ID, "spec.pdf", content)
+	suite.Require().Equal(http.StatusCreated, status, body)
+
+	var uploaded struct {
+		Attachment struct {
+			ID          string `json:"id"`
+			ContentType string `json:"content_type"`
+			Size        int    `json:"size"`
+			SHA256      string `json:"sha256"`
+		} `json:"attachment"`
+	}
+	suite.Require().NoError(json.Unmarshal([]byte(body), &uploaded))
+	suite.Equal("application/pdf", uploaded.Attachment.ContentType)
+	suite.Equal(len(content), uploaded.Attachment.Size)
+	sum := sha256.Sum256(content)
+	suite.Equal(hex.EncodeToString(sum[:]), uploaded.Attachment.SHA256)
+
+	// The declared type is ignored in favour of the sniffed one
+	status, body = suite.upload(alice, task.Task.ID, "notes.png", []byte("just text"))
+	suite.Require().Equal(http.StatusCreated, status, body)
+	suite.Contains(body, `"content_type":"text/plain; charset=utf-8"`)
+
+	var list struct {
+		Attachments []struct {
+			Filename string `json:"filename"`
+		} `json:"attachments"`
+	}
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodGet, "/tasks/"+task.Task.ID+"/attachments", alice, nil, &list))
+	suite.Require().Len(list.Attachments, 2)
+	suite.Equal("spec.pdf", list.Attachments[0].Filename)
+
+	attachment := "/tasks/" + task.Task.ID + "/attachments/" + uploaded.Attachment.ID
+	status, header, downloaded := suite.doRaw(http.MethodGet, attachment, alice, "", "")
+	suite.Require().Equal(http.StatusOK, status)
+	suite.Equal(string(content), downloaded)
+	suite.Equal("application/pdf", header.Get("Content-Type"))
+	suite.Equal(`attachment; filename=spec.pdf`, header.Get("Content-Disposition"))
+	suite.Equal("sha-256=:"+base64.StdEncoding.EncodeToString(sum[:])+":", header.Get("Content-Digest"))
+	suite.Equal("nosniff", header.Get("X-Content-Type-Options"))
+
+	// Other users cannot see the files, and only higher roles delete them
+	status, _, _ = suite.doRaw(http.MethodGet, attachment, bob, "", "")
+	suite.Equal(http.StatusNotFound, status)
+	status, _ = suite.upload(bob, task.Task.ID, "intruder.txt", []byte("hi"))
+	suite.Equal(http.StatusNotFound, status)
+	status, body = suite.upload(alice, task.Task.ID, "", []byte("no name"))
+	suite.Equal(http.StatusBadRequest, status, body)
+
+	status, body = suite.upload(admin, task.Task.ID, "review.txt", []byte("Reviewed"))
+	suite.Require().Equal(http.StatusCreated, status, body)
+	var review struct {
+		Attachment struct {
+			ID string `json:"id"`
+		} `json:"attachment"`
+	}
+	suite.Require().NoError(json.Unmarshal([]byte(body), &review))
+	suite.Equal(http.StatusForbidden, suite.do(http.MethodDelete, "/tasks/"+task.Task.ID+"/attachments/"+review.Attachment.ID, alice, nil, nil))
+	suite.Equal(http.StatusOK, suite.do(http.MethodDelete, attachment, admin, nil, nil))
+	suite.Equal(http.StatusNotFound, suite.do(http.MethodGet, attachment, alice, nil, nil))
+
+	// Deleting a task, on its own or in bulk, removes its attachments and their content
+	var other struct {
+		Task struct {
+			ID string `json:"id"`
+		} `json:"task"`
+	}
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodPost, "/tasks", alice, map[string]interface{}{"title": "Bulk", "description": "Attachment test", "status": "Not Started"}, &other))
+	status, body = suite.upload(alice, other.Task.ID, "bulk.txt", []byte("Bulk"))
+	suite.Require().Equal(http.StatusCreated, status, body)
+	var bulkUpload struct {
+		Attachment struct {
+			ID string `json:"id"`
+		} `json:"attachment"`
+	}
+	suite.Require().NoError(json.Unmarshal([]byte(body), &bulkUpload))
+
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodDelete, "/tasks/"+task.Task.ID, alice, nil, nil))
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodPost, "/tasks/bulk", alice, map[string]interface{}{
+		"atomic": true, "operations": []map[string]interface{}{{"op": "delete", "id": other.Task.ID}},
+	}, nil))
+	ctx := context.Background()
+	for _, id := range []string{uploaded.Attachment.ID, review.Attachment.ID, bulkUpload.Attachment.ID} {
+		objectID, err := primitive.ObjectIDFromHex(id)
+		suite.Require().NoError(err)
+		_, err = suite.repos.Attachments.GetAttachment(ctx, objectID)
+		suite.Error(err, id)
+		_, err = suite.repos.Blobs.Get(ctx, id)
+		suite.ErrorIs(err, domain.ErrBlobNotFound, id)
+	}
+}
+
+// TestLabelsAndSavedFilters tests labelling tasks, filtering them by label and
+// running saved filters
+func (suite *AppSuite) TestLabelsAndSavedFilters() {
+	alice := suite.login("alice", "user")
+	bob := suite.login("bob", "user")
+	admin := suite.login("carol", "admin")
+
+	type label struct {
+		Label struct {
+			ID    string `json:"id"`
+			Color string `json:"color"`
+		} `json:"label"`
+	}
+	var bug, urgent, later label
+	suite.Require().Equal(http.StatusCreated, suite.do(http.MethodPost, "/labels", admin, map[string]string{"name": "Bug", "color": "#D73A4A", "scope": "global"}, &bug))
+	suite.Equal("#d73a4a", bug.Label.Color)
+	suite.Require().Equal(http.StatusCreated, suite.do(http.MethodPost, "/labels", admin, map[string]string{"name": "Urgent", "scope": "global"}, &urgent))
+	suite.Require().Equal(http.StatusCreated, suite.do(http.MethodPost, "/labels", alice, map[string]string{"name": "Later"}, &later))
+
+	// Users cannot create global labels, and names are unique in a scope
+	suite.Equal(http.StatusForbidden, suite.do(http.MethodPost, "/labels", alice, map[string]string{"name": "Chore", "scope": "global"}, nil))
+	suite.Equal(http.StatusConflict, suite.do(http.MethodPost, "/labels", admin, map[string]string{"name": "bug", "scope": "global"}, nil))
+	suite.Equal(http.StatusCreated, suite.do(http.MethodPost, "/labels", bob, map[string]string{"name": "Later"}, nil))
+
+	var labels struct {
+		Labels []struct {
+			Name string `json:"name"`
+		} `json:"labels"`
+	}
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodGet, "/labels", alice, nil, &labels))
+	suite.Len(labels.Labels, 3)
+
+	addTask := func(title string) string {
+		var task struct {
+			Task struct {
+				ID string `json:"id"`
+			} `json:"task"`
+		}
+		suite.Require().Equal(http.StatusOK, suite.do(http.MethodPost, "/tasks", alice, map[string]interface{}{"title": title, "description": "Label test", "status": "Not Started"}, &task))
+		return task.Task.ID
+	}
+	both, bugOnly, none := addTask("Both"), addTask("Bug only"), addTask("None")
+
+	var labelled struct {
+		Task struct {
+			Labels []string `json:"labels"`
+		} `json:"task"`
+	}
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodPut, "/tasks/"+both+"/labels", alice, map[string][]string{"labels": {bug.Label.ID, urgent.Label.ID}}, &labelled))
+	suite.Equal([]string{bug.Label.ID, urgent.Label.ID}, labelled.Task.Labels)
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodPost, "/tasks/"+bugOnly+"/labels/"+bug.Label.ID, alice, nil, nil))
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodPost, "/tasks/"+none+"/labels/"+later.Label.ID, alice, nil, nil))
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodDelete, "/tasks/"+none+"/labels/"+later.Label.ID, alice, nil, nil))
+
+	// Other users cannot label the task, and task updates do not change labels
+	suite.Equal(http.StatusNotFound, suite.do(http.MethodPost, "/tasks/"+none+"/labels/"+bug.Label.ID, bob, nil, nil))
+	suite.Equal(http.StatusBadRequest, suite.do(http.MethodPost, "/tasks/"+none+"/labels/0123456789abcdef01234567", alice, nil, nil))
+	suite.Equal(http.StatusBadRequest, suite.do(http.MethodPut, "/tasks/"+none, alice, map[string]interface{}{"labels": []string{bug.Label.ID}}, nil))
+
+	titles := func(path string) []string {
+		var result struct {
+			Tasks []struct {
+				Title string `json:"title"`
+			} `json:"tasks"`
+		}
+		suite.Require().Equal(http.StatusOK, suite.do(http.MethodGet, path, alice, nil, &result))
+		var titles []string
+		for _, task := range result.Tasks {
+			titles = append(titles, task.Title)
+		}
+		return titles
+	}
+	suite.Equal([]string{"Both"}, titles("/tasks?labels="+bug.Label.ID+","+urgent.Label.ID))
+	suite.Equal([]string{"Both", "Bug only"}, titles("/tasks?labels="+bug.Label.ID+","+urgent.Label.ID+"&match=any"))
+	suite.Equal([]string{"Both", "Bug only", "None"}, titles("/tasks"))
+	suite.Equal(http.StatusBadRequest, suite.do(http.MethodGet, "/tasks?labels=nope", alice, nil, nil))
+	suite.Equal(http.StatusBadRequest, suite.do(http.MethodGet, "/tasks?labels="+bug.Label.ID+"&match=most", alice, nil, nil))
+
+	// Saved filters run again on the current tasks
+	var filter struct {
+		Filter struct {
+			ID    string `json:"id"`
+			Match string `json:"match"`
+		} `json:"filter"`
+	}
+	suite.Require().Equal(http.StatusCreated, suite.do(http.MethodPost, "/filters", alice, map[string]interface{}{"name": "Bugs", "labels": []string{bug.Label.ID}}, &filter))
+	suite.Equal("all", filter.Filter.Match)
+	suite.Equal(http.StatusConflict, suite.do(http.MethodPost, "/filters", alice, map[string]interface{}{"name": "bugs"}, nil))
+	suite.Equal(http.StatusBadRequest, suite.do(http.MethodPost, "/filters", bob, map[string]interface{}{"name": "Hers", "labels": []string{later.Label.ID}}, nil))
+	suite.Equal(http.StatusNotFound, suite.do(http.MethodGet, "/filters/"+filter.Filter.ID+"/tasks", bob, nil, nil))
+	suite.Equal([]string{"Both", "Bug only"}, titles("/filters/"+filter.Filter.ID+"/tasks"))
+
+	// Deleting a label takes it off the tasks
+	suite.Equal(http.StatusForbidden, suite.do(http.MethodDelete, "/labels/"+bug.Label.ID, alice, nil, nil))
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodDelete, "/labels/"+bug.Label.ID, admin, nil, nil))
+	suite.Empty(titles("/filters/" + filter.Filter.ID + "/tasks"))
+	suite.Equal([]string{"Both"}, titles("/tasks?labels="+urgent.Label.ID))
+
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodDelete, "/filters/"+filter.Filter.ID, alice, nil, nil))
+	var filters struct {
+		Filters []interface{} `json:"filters"`
+	}
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodGet, "/filters", alice, nil, &filters))
+	suite.Empty(filters.Filters)
+}
+
+func (suite *AppSuite) TestRecurringTasks() {
+	alice := suite.login("alice", "user")
+
+	// Due three days ago, so every occurrence but the last is already past due
+	due := time.Now().UTC().Add(-72 * time.Hour).Truncate(time.Second)
+	var created struct {
+		Task struct {
+			ID string `json:"id"`
+		} `json:"task"`
+	}
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodPost, "/tasks", alice, map[string]interface{}{
+		"title": "Water plants", "description": "Recurrence test", "status": "Not Started", "due_date": due.Format(time.RFC3339),
+		"recurrence": map[string]interface{}{"frequency": "daily", "count": 3},
+	}, &created))
+	suite.Equal(http.StatusBadRequest, suite.do(http.MethodPost, "/tasks", alice, map[string]interface{}{
+		"title": "No due date", "description": "Recurrence test", "status": "Not Started",
+		"recurrence": map[string]interface{}{"frequency": "daily"},
+	}, nil))
+
+	type occurrence struct {
+		ID         string    `json:"id"`
+		Title      string    `json:"title"`
+		Status     string    `json:"status"`
+		DueDate    time.Time `json:"due_date"`
+		SeriesID   string    `json:"series_id"`
+		Occurrence int       `json:"occurrence"`
+	}
+	occurrences := func() []occurrence {
+		var result struct {
+			Tasks []occurrence `json:"tasks"`
+		}
+		suite.Require().Equal(http.StatusOK, suite.do(http.MethodGet, "/tasks", alice, nil, &result))
+		return result.Tasks
+	}
+
+	// Completing the task generates the next occurrence
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodPut, "/tasks/"+created.Task.ID, alice, map[string]string{"status": "Completed"}, nil))
+	tasks := occurrences()
+	suite.Require().Len(tasks, 2)
+	suite.Equal(created.Task.ID, tasks[1].SeriesID)
+	suite.Equal(2, tasks[1].Occurrence)
+	suite.Equal("Not Started", tasks[1].Status)
+	suite.True(due.Add(24 * time.Hour).Equal(tasks[1].DueDate))
+
+	// A change to this occurrence stays there, a change to the future ones reaches later occurrences
+	second := tasks[1].ID
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodPut, "/tasks/"+second, alice, map[string]string{"description": "Only this one"}, nil))
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodPut, "/tasks/"+second+"?scope=future", alice, map[string]string{"title": "Water the plants"}, nil))
+	suite.Equal(http.StatusBadRequest, suite.do(http.MethodPut, "/tasks/"+second+"?scope=all", alice, map[string]string{"title": "Nope"}, nil))
+	suite.Equal(http.StatusBadRequest, suite.do(http.MethodPut, "/tasks/"+second, alice, map[string]interface{}{"recurrence": nil}, nil))
+	suite.Equal(http.StatusBadRequest, suite.do(http.MethodPut, "/tasks/"+second+"?scope=future", alice, map[string]string{"status": "Completed"}, nil))
+
+	// The scheduler generates the occurrence after a past-due one, until the count is reached
+	suite.app.Recurrences.RunOnce(context.Background())
+	suite.app.Recurrences.RunOnce(context.Background())
+	tasks = occurrences()
+	suite.Require().Len(tasks, 3)
+	suite.Equal([]string{"Water plants", "Water the plants", "Water the plants"}, []string{tasks[0].Title, tasks[1].Title, tasks[2].Title})
+	suite.Equal(3, tasks[2].Occurrence)
+	suite.True(due.Add(48 * time.Hour).Equal(tasks[2].DueDate))
+
+	var third struct {
+		Task struct {
+			Description string `json:"description"`
+		} `json:"task"`
+	}
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodGet, "/tasks/"+tasks[2].ID, alice, nil, &third))
+	suite.Equal("Recurrence test", third.Task.Description)
+}
+
+func (suite *AppSuite) TestTimeTracking() {
+	alice := suite.login("alice", "user")
+	bob := suite.login("bob", "user")
+	admin := suite.login("carol", "admin")
+
+	var created struct {
+		Task struct {
+			ID       string `json:"id"`
+			Priority string `json:"priority"`
+		} `json:"task"`
+	}
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodPost, "/tasks", alice, map[string]interface{}{
+		"title": "Estimate", "description": "Time tracking test", "status": "Not Started",
+		"priority": "high", "story_points": 5, "estimate_hours": 8,
+	}, &created))
+	suite.Equal("high", created.Task.Priority)
+	suite.Equal(http.StatusBadRequest, suite.do(http.MethodPost, "/tasks", alice, map[string]interface{}{
+		"title": "Estimate", "description": "Time tracking test", "status": "Not Started", "priority": "someday",
+	}, nil))
+	suite.Equal(http.StatusBadRequest, suite.do(http.MethodPut, "/tasks/"+created.Task.ID, alice, map[string]interface{}{"story_points": 1000}, nil))
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodPut, "/tasks/"+created.Task.ID, alice, map[string]interface{}{"priority": "urgent"}, nil))
+
+	// One running timer per user, and only on visible tasks
+	path := "/tasks/" + created.Task.ID + "/time"
+	suite.Equal(http.StatusConflict, suite.do(http.MethodPost, path+"/stop", alice, nil, nil))
+	suite.Require().Equal(http.StatusCreated, suite.do(http.MethodPost, path+"/start", alice, nil, nil))
+	suite.Equal(http.StatusConflict, suite.do(http.MethodPost, path+"/start", alice, nil, nil))
+	suite.Equal(http.StatusNotFound, suite.do(http.MethodPost, path+"/start", bob, nil, nil))
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodPost, path+"/stop", alice, nil, nil))
+	suite.Require().Equal(http.StatusCreated, suite.do(http.MethodPost, path+"/start", admin, nil, nil))
+
+	var taskTime struct {
+		Time struct {
+			Entries []struct {
+				Running bool `json:"running"`
+			} `json:"entries"`
+			Users []struct {
+				Username string `json:"username"`
+			} `json:"users"`
+		} `json:"time"`
+	}
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodGet, path, alice, nil, &taskTime))
+	suite.Len(taskTime.Time.Entries, 2)
+	suite.Len(taskTime.Time.Users, 2)
+
+	// Users see their own totals, admins everyone's
+	var byUser struct {
+		Users []struct {
+			Username string `json:"username"`
+		} `json:"users"`
+	}
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodGet, "/time/users", alice, nil, &byUser))
+	suite.Require().Len(byUser.Users, 1)
+	suite.Equal("alice", byUser.Users[0].Username)
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodPost, path+"/stop", admin, nil, nil))
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodGet, "/time/users", admin, nil, &byUser))
+	suite.Len(byUser.Users, 2)
+
+	var me struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodGet, "/me", admin, nil, &me))
+	suite.Equal(http.StatusForbidden, suite.do(http.MethodGet, "/time/users?user_id="+me.Data.ID, alice, nil, nil))
+	suite.Equal(http.StatusBadRequest, suite.do(http.MethodGet, "/time/weeks?from=yesterday", alice, nil, nil))
+
+	var byWeek struct {
+		Weeks []struct {
+			Year int `json:"year"`
+			Week int `json:"week"`
+		} `json:"weeks"`
+	}
+	today := time.Now().UTC().Format(time.DateOnly)
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodGet, "/time/weeks?from="+today, admin, nil, &byWeek))
+	suite.Require().Len(byWeek.Weeks, 1)
+	year, week := time.Now().UTC().ISOWeek()
+	suite.Equal(year, byWeek.Weeks[0].Year)
+	suite.Equal(week, byWeek.Weeks[0].Week)
+}
+
+func (suite *AppSuite) TestBoard() {
+	alice := suite.login("alice", "user")
+	bob := suite.login("bob", "user")
+
+	ids := map[string]string{}
+	for _, title := range []string{"Design", "Build", "Ship"} {
+		var created struct {
+			Task struct {
+				ID string `json:"id"`
+			} `json:"task"`
+		}
+		suite.Require().Equal(http.StatusOK, suite.do(http.MethodPost, "/tasks", alice, map[string]interface{}{
+			"title": title, "description": "Board test", "status": "Not Started", "rank": "0",
+		}, &created))
+		ids[title] = created.Task.ID
+	}
+
+	type column struct {
+		Status string `json:"status"`
+		Tasks  []struct {
+			Title string `json:"title"`
+			Rank  string `json:"rank"`
+		} `json:"tasks"`
+	}
+	board := func() map[string][]string {
+		var result struct {
+			Board struct {
+				Columns []column `json:"columns"`
+			} `json:"board"`
+		}
+		suite.Require().Equal(http.StatusOK, suite.do(http.MethodGet, "/board", alice, nil, &result))
+		columns := map[string][]string{}
+		ranks := map[string]bool{}
+		for _, column := range result.Board.Columns {
+			columns[column.Status] = []string{}
+			for _, task := range column.Tasks {
+				suite.NotEmpty(task.Rank)
+				suite.False(ranks[task.Rank], "rank %q is not unique", task.Rank)
+				ranks[task.Rank] = true
+				columns[column.Status] = append(columns[column.Status], task.Title)
+			}
+		}
+		return columns
+	}
+	suite.Equal(map[string][]string{"Not Started": {"Design", "Build", "Ship"}, "In Progress": {}, "Completed": {}}, board())
+
+	// Moves change the column and the position, and keep across reloads
+	move := func(token, title string, body map[string]string) int {
+		return suite.do(http.MethodPost, "/tasks/"+ids[title]+"/move", token, body, nil)
+	}
+	suite.Require().Equal(http.StatusOK, move(alice, "Ship", map[string]string{"status": "Not Started", "before_id": ids["Design"]}))
+	suite.Require().Equal(http.StatusOK, move(alice, "Build", map[string]string{"status": "In Progress"}))
+	suite.Require().Equal(http.StatusOK, move(alice, "Design", map[string]string{"status": "In Progress", "before_id": ids["Build"]}))
+	suite.Equal(map[string][]string{"Not Started": {"Ship"}, "In Progress": {"Design", "Build"}, "Completed": {}}, board())
+	suite.Require().Equal(http.StatusOK, move(alice, "Ship", map[string]string{"status": "In Progress", "after_id": ids["Design"], "before_id": ids["Build"]}))
+	suite.Equal(map[string][]string{"Not Started": {}, "In Progress": {"Design", "Ship", "Build"}, "Completed": {}}, board())
+
+	// Ranks are unique across the columns of a board, so a move into a
+	// column never takes the rank of a task in another one
+	suite.Require().Equal(http.StatusOK, move(alice, "Design", map[string]string{"status": "Completed"}))
+	suite.Require().Equal(http.StatusOK, move(alice, "Build", map[string]string{"status": "Not Started"}))
+	suite.Require().Equal(http.StatusOK, move(alice, "Ship", map[string]string{"status": "Completed", "after_id": ids["Design"]}))
+	suite.Equal(map[string][]string{"Not Started": {"Build"}, "In Progress": {}, "Completed": {"Design", "Ship"}}, board())
+
+	suite.Equal(http.StatusBadRequest, move(alice, "Ship", map[string]string{"status": "Blocked"}))
+	suite.Equal(http.StatusBadRequest, move(alice, "Ship", map[string]string{"status": "Completed", "after_id": ids["Build"]}))
+	suite.Equal(http.StatusBadRequest, move(alice, "Ship", map[string]string{"status": "In Progress", "after_id": ids["Build"], "before_id": ids["Design"]}))
+	suite.Equal(http.StatusForbidden, move(bob, "Ship", map[string]string{"status": "Completed"}))
+	suite.Equal(http.StatusBadRequest, suite.do(http.MethodPut, "/tasks/"+ids["Ship"], alice, map[string]string{"rank": "0"}, nil))
+}
+
+func (suite *AppSuite) TestReports() {
+	alice := suite.login("alice", "user")
+	bob := suite.login("bob", "user")
+	admin := suite.login("carol", "admin")
+
+	create := func(token, title, dueDate string) string {
+		var created struct {
+			Task struct {
+				ID string `json:"id"`
+			} `json:"task"`
+		}
+		task := map[string]interface{}{"title": title, "description": "Report test", "status": "Not Started"}
+		if dueDate != "" {
+			task["due_date"] = dueDate
+		}
+		suite.Require().Equal(http.StatusOK, suite.do(http.MethodPost, "/tasks", token, task, &created))
+		return created.Task.ID
+	}
+	setStatus := func(token, id, status string) {
+		suite.Require().Equal(http.StatusOK, suite.do(http.MethodPut, "/tasks/"+id, token, map[string]string{"status": status}, nil))
+	}
+	yesterday := time.Now().UTC().Add(-24 * time.Hour).Format(time.RFC3339)
+	done := create(alice, "Done", "")
+	setStatus(alice, done, "In Progress")
+	setStatus(alice, done, "Completed")
+	create(alice, "Late", yesterday)
+	removed := create(alice, "Removed", "")
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodDelete, "/tasks/"+removed, alice, nil, nil))
+	create(bob, "Bob late", yesterday)
+
+	// Created and completed per day, the last day being today
+	var throughput struct {
+		Periods []struct {
+			Start     time.Time `json:"start"`
+			Created   int       `json:"created"`
+			Completed int       `json:"completed"`
+		} `json:"periods"`
+	}
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodGet, "/reports/throughput?period=day", alice, nil, &throughput))
+	suite.Require().NotEmpty(throughput.Periods)
+	today := throughput.Periods[len(throughput.Periods)-1]
+	suite.Equal(3, today.Created)
+	suite.Equal(1, today.Completed)
+	suite.Equal(http.StatusBadRequest, suite.do(http.MethodGet, "/reports/throughput?period=year", alice, nil, nil))
+
+	var cycleTime struct {
+		CycleTime struct {
+			Tasks []struct {
+				TaskID string `json:"task_id"`
+			} `json:"tasks"`
+		} `json:"cycle_time"`
+	}
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodGet, "/reports/cycle-time", alice, nil, &cycleTime))
+	suite.Require().Len(cycleTime.CycleTime.Tasks, 1)
+	suite.Equal(done, cycleTime.CycleTime.Tasks[0].TaskID)
+
+	// Users see their own overdue tasks, admins everyone's
+	var overdue struct {
+		Users []struct {
+			Username string `json:"username"`
+			Overdue  int    `json:"overdue"`
+		} `json:"users"`
+	}
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodGet, "/reports/overdue", alice, nil, &overdue))
+	suite.Require().Len(overdue.Users, 1)
+	suite.Equal("alice", overdue.Users[0].Username)
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodGet, "/reports/overdue", admin, nil, &overdue))
+	suite.Len(overdue.Users, 2)
+
+	// Only the late task is still open at the end of today
+	var burndown struct {
+		Points []struct {
+			Remaining int `json:"remaining"`
+		} `json:"points"`
+	}
+	from := time.Now().UTC().AddDate(0, 0, -6).Format(time.DateOnly)
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodGet, "/reports/burndown?from="+from, alice, nil, &burndown))
+	suite.Require().Len(burndown.Points, 7)
+	suite.Equal(0, burndown.Points[0].Remaining)
+	suite.Equal(1, burndown.Points[6].Remaining)
+}
+
+func (suite *AppSuite) TestOrganizations() {
+	root := suite.root()
+
+	createOrg := func(name string) string {
+		var created struct {
+			Organization struct {
+				ID string `json:"id"`
+			} `json:"organization"`
+		}
+		suite.Require().Equal(http.StatusCreated, suite.do(http.MethodPost, "/organizations", root, map[string]string{"name": name}, &created))
+		return created.Organization.ID
+	}
+	acme := createOrg("Acme")
+	globex := createOrg("Globex")
+	suite.Equal(http.StatusConflict, suite.do(http.MethodPost, "/organizations", root, map[string]string{"name": "acme"}, nil))
+	suite.Equal(http.StatusBadRequest, suite.do(http.MethodPost, "/users", root, map[string]string{"username": "eve", "password": "password", "org_id": "0123456789abcdef01234567"}, nil))
+
+	alice := suite.loginIn("alice", "user", acme)
+	anna := suite.loginIn("anna", "admin", acme)
+	bert := suite.loginIn("bert", "admin", globex)
+
+	// Organization admins are not root
+	suite.Equal(http.StatusForbidden, suite.do(http.MethodPost, "/organizations", anna, map[string]string{"name": "Initech"}, nil))
+	suite.Equal(http.StatusForbidden, suite.do(http.MethodGet, "/organizations", anna, nil, nil))
+	suite.Equal(http.StatusOK, suite.do(http.MethodGet, "/organizations/"+acme, anna, nil, nil))
+	suite.Equal(http.StatusNotFound, suite.do(http.MethodGet, "/organizations/"+acme, bert, nil, nil))
+
+	create := func(token, title string) string {
+		var created struct {
+			Task struct {
+				ID string `json:"id"`
+			} `json:"task"`
+		}
+		task := map[string]interface{}{"title": title, "description": "Tenancy test", "status": "Not Started"}
+		suite.Require().Equal(http.StatusOK, suite.do(http.MethodPost, "/tasks", token, task, &created))
+		return created.Task.ID
+	}
+	aliceTask := create(alice, "Acme task")
+	create(bert, "Globex task")
+
+	// Tasks of another organization cannot be found, even by its admins
+	suite.Equal(http.StatusOK, suite.do(http.MethodGet, "/tasks/"+aliceTask, anna, nil, nil))
+	suite.Equal(http.StatusNotFound, suite.do(http.MethodGet, "/tasks/"+aliceTask, bert, nil, nil))
+	suite.Equal(http.StatusNotFound, suite.do(http.MethodDelete, "/tasks/"+aliceTask, bert, nil, nil))
+
+	var all struct {
+		Tasks []struct {
+			Title string `json:"title"`
+		} `json:"tasks"`
+	}
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodGet, "/alltasks", bert, nil, &all))
+	suite.Require().Len(all.Tasks, 1)
+	suite.Equal("Globex task", all.Tasks[0].Title)
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodGet, "/alltasks", root, nil, &all))
+	suite.Len(all.Tasks, 2)
+
+	// Global labels are only global within their organization
+	label := func(token string) (int, string) {
+		var created struct {
+			Label struct {
+				ID string `json:"id"`
+			} `json:"label"`
+		}
+		status := suite.do(http.MethodPost, "/labels", token, map[string]string{"name": "Urgent", "scope": "global"}, &created)
+		return status, created.Label.ID
+	}
+	status, acmeLabel := label(anna)
+	suite.Require().Equal(http.StatusCreated, status)
+	status, _ = label(bert)
+	suite.Equal(http.StatusCreated, status)
+	var labels struct {
+		Labels []struct {
+			ID string `json:"id"`
+		} `json:"labels"`
+	}
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodGet, "/labels", bert, nil, &labels))
+	suite.Require().Len(labels.Labels, 1)
+	suite.NotEqual(acmeLabel, labels.Labels[0].ID)
+	suite.Equal(http.StatusNotFound, suite.do(http.MethodPatch, "/labels/"+acmeLabel, bert, map[string]string{"name": "Mine"}, nil))
+	suite.Equal(http.StatusNotFound, suite.do(http.MethodDelete, "/labels/"+acmeLabel, bert, nil, nil))
+	suite.Equal(http.StatusOK, suite.do(http.MethodPost, "/tasks/"+aliceTask+"/labels/"+acmeLabel, alice, nil, nil))
+
+	// Users only list and manage the users of their organization
+	var users struct {
+		Data []struct {
+			ID       string `json:"id"`
+			Username string `json:"username"`
+		} `json:"data"`
+	}
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodGet, "/users", anna, nil, &users))
+	suite.Require().Len(users.Data, 2)
+	aliceID := ""
+	for _, user := range users.Data {
+		suite.Contains([]string{"alice", "anna"}, user.Username)
+		if user.Username == "alice" {
+			aliceID = user.ID
+		}
+	}
+	suite.Equal(http.StatusNotFound, suite.do(http.MethodDelete, "/users/"+aliceID, bert, nil, nil))
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodGet, "/users", root, nil, &users))
+	suite.Len(users.Data, 4)
+}
+
+func (suite *AppSuite) TestUserCreation() {
+	// Public registration ignores the requested role and organization
+	status := suite.do(http.MethodPost, "/register", "", map[string]string{"username": "mallory", "password": "password", "role": "root", "org_id": primitive.NewObjectID().Hex()}, nil)
+	suite.Require().Equal(http.StatusCreated, status)
+	mallory := suite.token("mallory")
+	var me struct {
+		Data struct {
+			Role  string `json:"role"`
+			OrgID string `json:"org_id"`
+		} `json:"data"`
+	}
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodGet, "/me", mallory, nil, &me))
+	suite.Equal("user", me.Data.Role)
+	suite.Empty(me.Data.OrgID)
+	suite.Equal(http.StatusForbidden, suite.do(http.MethodPost, "/users", mallory, map[string]string{"username": "eve", "password": "password", "role": "admin"}, nil))
+
+	// Organization admins create users and admins of their own organization
+	var created struct {
+		Organization struct {
+			ID string `json:"id"`
+		} `json:"organization"`
+	}
+	suite.Require().Equal(http.StatusCreated, suite.do(http.MethodPost, "/organizations", suite.root(), map[string]string{"name": "Acme"}, &created))
+	acme := created.Organization.ID
+	anna := suite.loginIn("anna", "admin", acme)
+	suite.Equal(http.StatusCreated, suite.do(http.MethodPost, "/users", anna, map[string]string{"username": "alice", "password": "password"}, nil))
+	suite.Equal(http.StatusForbidden, suite.do(http.MethodPost, "/users", anna, map[string]string{"username": "eve", "password": "password", "role": "root"}, nil))
+	suite.Equal(http.StatusForbidden, suite.do(http.MethodPost, "/users", anna, map[string]string{"username": "eve", "password": "password", "org_id": primitive.NewObjectID().Hex()}, nil))
+	suite.Equal(http.StatusBadRequest, suite.do(http.MethodPost, "/users", anna, map[string]string{"username": "eve", "password": "password", "role": "owner"}, nil))
+
+	suite.Require().Equal(http.StatusOK, suite.do(http.MethodGet, "/me", suite.token("alice"), nil, &me))
+	suite.Equal("user", me.Data.Role)
+	suite.Equal(acme, me.Data.OrgID)
+
+	// Usernames are unique across organizations
+	suite.Equal(http.StatusConflict, suite.do(http.MethodPost, "/register", "", map[string]string{"username": "alice", "password": "password"}, nil))
+}
+
+func TestAppSuite(t *testing.T) {
+	suite.Run(t, new(AppSuite))
+}
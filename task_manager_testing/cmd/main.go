@@ -1,35 +1,172 @@
 package main
 
 import (
-	"log"
+	"context"
+	"errors"
+	"flag"
+	"log/slog"
+	"net/http"
 	"os"
-	"task_manager_testing/Delivery/routers"
+	"os/signal"
+	"syscall"
+	infrastructure "task_manager_testing/Infrastructure"
+	"task_manager_testing/bootstrap"
+	"task_manager_testing/config"
 	"task_manager_testing/config/database"
-	"github.com/joho/godotenv"
+	"time"
 )
 
 func main() {
-	// Load environment variables from a .env file
-	err := godotenv.Load()
+	// Load the configuration from the optional config file, the environment and flags
+	cfg, err := config.Load(os.Args[1:], os.Getenv)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		slog.Error("invalid configuration", slog.String("error", err.Error()))
+		os.Exit(2)
+	}
+
+	// Set up structured logging
+	logger := infrastructure.NewLogger(os.Stdout, cfg.Log.Level)
+	slog.SetDefault(logger)
+
+	infrastructure.ConfigureJWT(cfg.JWT.Secret, cfg.JWT.Issuer, cfg.JWT.AccessTokenTTL.Duration)
+
+	// Set up tracing
+	shutdownTracer, err := infrastructure.InitTracer(context.Background(), "task-manager", cfg.Tracing.Exporter)
 	if err != nil {
-		log.Fatal("Error loading .env file")
+		logger.Error("failed to set up tracing", slog.String("error", err.Error()))
+		os.Exit(1)
 	}
-	
+
 	// Connect to the MongoDB database
-	uri := os.Getenv("MONGO_DB_URI")
-	client, err := database.ConnectToMongoDB(uri)
+	client, err := database.ConnectToMongoDB(cfg.Mongo.URI)
 	if err != nil {
-		log.Fatal(err)
+		logger.Error("failed to connect to MongoDB", slog.String("error", err.Error()))
+		os.Exit(1)
 	}
-		
-		
 
+	indexCtx, cancelIndexes := context.WithTimeout(context.Background(), 30*time.Second)
+	err = bootstrap.EnsureMongoIndexes(indexCtx, cfg, client)
+	cancelIndexes()
+	if err != nil {
+		logger.Error("failed to create MongoDB indexes", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	// Set up the router and the HTTP server
+	infra := bootstrap.NewMongoInfrastructure(cfg, client, logger)
+	repos, err := bootstrap.NewMongoRepositories(cfg, client)
+	if err != nil {
+		logger.Error("failed to set up the repositories", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	rootCtx, cancelRoot := context.WithTimeout(context.Background(), 30*time.Second)
+	err = bootstrap.EnsureRootUser(rootCtx, cfg, repos)
+	cancelRoot()
+	if err != nil {
+		logger.Error("failed to create the root user", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	app := bootstrap.NewApp(cfg, repos, infra)
+	server := &http.Server{
+		Addr:              cfg.Addr(),
+		Handler:           app.Router,
+		ReadHeaderTimeout: cfg.Server.ReadTimeout.Duration,
+		ReadTimeout:       cfg.Server.ReadTimeout.Duration,
+		WriteTimeout:      cfg.Server.WriteTimeout.Duration,
+		IdleTimeout:       cfg.Server.IdleTimeout.Duration,
+	}
+	// Shutdown waits for the event streams, which only end when told to
+	server.RegisterOnShutdown(app.Streams.Close)
 
-	// Disconnect from the MongoDB database when the application closes
-	defer database.DisconnectFromMongoDB(client)
+	// Stop on SIGINT or SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
+	// Check due dates in the background until shutdown
+	remindersDone := make(chan struct{})
+	go func() {
+		defer close(remindersDone)
+		if cfg.Reminders.Enabled {
+			app.Reminders.Run(ctx)
+		}
+	}()
 
-	// Set up the router and start the application
-	r := routers.SetupRouter(client)
-	r.Run(":8080")
+	// Generate the next occurrence of past-due recurring tasks in the background until shutdown
+	recurrencesDone := make(chan struct{})
+	go func() {
+		defer close(recurrencesDone)
+		if cfg.Recurrence.Enabled {
+			app.Recurrences.Run(ctx)
+		}
+	}()
+
+	// Relay the task events stored in the outbox in the background until shutdown
+	outboxDone := make(chan struct{})
+	go func() {
+		defer close(outboxDone)
+		if cfg.Outbox.Enabled {
+			app.Outbox.Run(ctx)
+		}
+	}()
+
+	// Send queued webhook deliveries in the background until shutdown
+	webhooksDone := make(chan struct{})
+	go func() {
+		defer close(webhooksDone)
+		if cfg.Webhooks.Enabled {
+			app.Webhooks.Run(ctx)
+		}
+	}()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		logger.Info("server listening", slog.String("addr", server.Addr))
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+		close(serverErr)
+	}()
+
+	exitCode := 0
+	select {
+	case <-ctx.Done():
+		logger.Info("shutdown signal received, draining requests")
+	case err := <-serverErr:
+		logger.Error("server stopped unexpectedly", slog.String("error", err.Error()))
+		exitCode = 1
+	}
+
+	// Stop accepting new requests and wait for in-flight ones to finish
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout.Duration)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		logger.Error("graceful shutdown failed", slog.String("error", err.Error()))
+		exitCode = 1
+	}
+	stop()
+	<-remindersDone
+	<-recurrencesDone
+	<-webhooksDone
+	<-outboxDone
+
+	// Flush pending spans and close the database connection last
+	if err := shutdownTracer(shutdownCtx); err != nil {
+		logger.Error("failed to flush traces", slog.String("error", err.Error()))
+	}
+	if err := infra.Close(); err != nil {
+		logger.Error("failed to close shared services", slog.String("error", err.Error()))
+	}
+	if err := database.DisconnectFromMongoDB(shutdownCtx, client); err != nil {
+		logger.Error("failed to disconnect from MongoDB", slog.String("error", err.Error()))
+		exitCode = 1
+	}
+
+	logger.Info("server stopped")
+	if exitCode != 0 {
+		os.Exit(exitCode)
+	}
 }
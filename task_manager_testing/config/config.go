@@ -0,0 +1,761 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config holds every setting the task manager needs to start.
+type Config struct {
+	Server      ServerConfig      `json:"server"`
+	Mongo       MongoConfig       `json:"mongo"`
+	JWT         JWTConfig         `json:"jwt"`
+	Root        RootConfig        `json:"root"`
+	CORS        CORSConfig        `json:"cors"`
+	Security    SecurityConfig    `json:"security"`
+	Log         LogConfig         `json:"log"`
+	Tracing     TracingConfig     `json:"tracing"`
+	RateLimit   RateLimitConfig   `json:"rate_limit"`
+	Idempotency IdempotencyConfig `json:"idempotency"`
+	Reminders   RemindersConfig   `json:"reminders"`
+	Recurrence  RecurrenceConfig  `json:"recurrence"`
+	Webhooks    WebhooksConfig    `json:"webhooks"`
+	Events      EventsConfig      `json:"events"`
+	Outbox      OutboxConfig      `json:"outbox"`
+	Attachments AttachmentsConfig `json:"attachments"`
+}
+
+// ServerConfig configures the HTTP server.
+type ServerConfig struct {
+	Port            int      `json:"port"`
+	ReadTimeout     Duration `json:"read_timeout"`
+	WriteTimeout    Duration `json:"write_timeout"`
+	IdleTimeout     Duration `json:"idle_timeout"`
+	ShutdownTimeout Duration `json:"shutdown_timeout"`
+	// MaxBodyBytes is the largest request body accepted
+	MaxBodyBytes int64 `json:"max_body_bytes"`
+	// TrustedProxies lists the addresses and CIDR ranges of the reverse
+	// proxies whose X-Forwarded-For header is believed. By default none are,
+	// and clients are identified by the address they connect from.
+	TrustedProxies []string `json:"trusted_proxies"`
+}
+
+// MongoConfig configures the MongoDB connection and collections.
+type MongoConfig struct {
+	URI             string `json:"uri"`
+	Database        string `json:"database"`
+	TasksCollection string `json:"tasks_collection"`
+	UsersCollection string `json:"users_collection"`
+	// IdempotencyCollection stores the responses to requests with an Idempotency-Key
+	IdempotencyCollection string `json:"idempotency_collection"`
+	// NotificationsCollection stores the due-date reminders sent to users
+	NotificationsCollection string `json:"notifications_collection"`
+	// WebhooksCollection stores webhook subscriptions and
+	// WebhookDeliveriesCollection the log of what was sent to them
+	WebhooksCollection          string `json:"webhooks_collection"`
+	WebhookDeliveriesCollection string `json:"webhook_deliveries_collection"`
+	// OutboxCollection stores the task events still to be relayed
+	OutboxCollection string `json:"outbox_collection"`
+	// CommentsCollection stores the discussion on tasks
+	CommentsCollection string `json:"comments_collection"`
+	// AttachmentsCollection stores the metadata of task attachments and
+	// BlobsBucket is the GridFS bucket of their content
+	AttachmentsCollection string `json:"attachments_collection"`
+	BlobsBucket           string `json:"blobs_bucket"`
+	// LabelsCollection stores task labels and SavedFiltersCollection the
+	// named task filters of users
+	LabelsCollection       string `json:"labels_collection"`
+	SavedFiltersCollection string `json:"saved_filters_collection"`
+	// SeriesCollection stores the rules and templates of recurring tasks
+	SeriesCollection string `json:"series_collection"`
+	// TimeEntriesCollection stores the time tracked on tasks
+	TimeEntriesCollection string `json:"time_entries_collection"`
+	// StatusChangesCollection stores the status changes of tasks the reports
+	// are built on
+	StatusChangesCollection string `json:"status_changes_collection"`
+	// OrganizationsCollection stores the organizations tasks and users are
+	// isolated in
+	OrganizationsCollection string `json:"organizations_collection"`
+}
+
+// JWTConfig configures how access tokens are signed and validated.
+type JWTConfig struct {
+	Secret         string   `json:"secret"`
+	Issuer         string   `json:"issuer"`
+	AccessTokenTTL Duration `json:"access_token_ttl"`
+}
+
+// RootConfig is the root user created at startup when it does not exist yet.
+// Root users are global and create the other admins, so public registration
+// only creates regular users.
+type RootConfig struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// CORSConfig lists the browser origins allowed to call the API.
+type CORSConfig struct {
+	AllowedOrigins   []string `json:"allowed_origins"`
+	AllowCredentials bool     `json:"allow_credentials"`
+	// MaxAge is how long browsers may cache preflight responses
+	MaxAge Duration `json:"max_age"`
+}
+
+// SecurityConfig configures the security headers sent with every response.
+type SecurityConfig struct {
+	// HSTSMaxAge is sent in Strict-Transport-Security; zero disables HSTS
+	HSTSMaxAge            Duration `json:"hsts_max_age"`
+	ContentSecurityPolicy string   `json:"content_security_policy"`
+}
+
+// LogConfig configures structured logging.
+type LogConfig struct {
+	Level string `json:"level"`
+}
+
+// TracingConfig selects the OpenTelemetry exporter.
+type TracingConfig struct {
+	Exporter string `json:"exporter"`
+}
+
+// RateLimitConfig configures per-client rate limiting. Public routes are
+// limited by client IP and protected routes by user ID.
+type RateLimitConfig struct {
+	Enabled bool `json:"enabled"`
+	// Store is "memory", for a single instance, or "redis" to share limits
+	Store     string        `json:"store"`
+	RedisAddr string        `json:"redis_addr"`
+	Public    RateLimitRule `json:"public"`
+	Protected RateLimitRule `json:"protected"`
+}
+
+// RateLimitRule is a token bucket refilled at RequestsPerMinute that allows
+// bursts of up to Burst requests.
+type RateLimitRule struct {
+	RequestsPerMinute int `json:"requests_per_minute"`
+	Burst             int `json:"burst"`
+}
+
+// IdempotencyConfig configures how long responses to requests with an
+// Idempotency-Key header are kept for replay.
+type IdempotencyConfig struct {
+	TTL Duration `json:"ttl"`
+}
+
+// RemindersConfig configures the background job that notifies users about
+// tasks that are due soon or overdue.
+type RemindersConfig struct {
+	Enabled bool `json:"enabled"`
+	// Interval is how often tasks are checked
+	Interval Duration `json:"interval"`
+	// DueSoon is how far ahead of its due date a task is reported as due soon
+	DueSoon Duration `json:"due_soon"`
+}
+
+// RecurrenceConfig configures the background job that generates the next
+// occurrence of recurring tasks once their latest occurrence is past due.
+type RecurrenceConfig struct {
+	Enabled bool `json:"enabled"`
+	// Interval is how often recurring tasks are checked
+	Interval Duration `json:"interval"`
+}
+
+// WebhooksConfig configures how task events are delivered to webhooks.
+type WebhooksConfig struct {
+	Enabled bool `json:"enabled"`
+	// PollInterval is how often due deliveries are looked for
+	PollInterval Duration `json:"poll_interval"`
+	// Timeout bounds a single request to a receiver
+	Timeout     Duration `json:"timeout"`
+	MaxAttempts int      `json:"max_attempts"`
+	// InitialBackoff is the wait after the first failed attempt; it doubles
+	// after every further failure up to MaxBackoff
+	InitialBackoff Duration `json:"initial_backoff"`
+	MaxBackoff     Duration `json:"max_backoff"`
+	// AllowedNetworks lists the CIDR ranges of private receivers. Deliveries
+	// to loopback, link-local and private addresses are refused otherwise.
+	AllowedNetworks []string `json:"allowed_networks"`
+}
+
+// EventsConfig configures the real-time task event streams.
+type EventsConfig struct {
+	// History is how many recent events are kept for clients resuming a stream
+	History int `json:"history"`
+	// Heartbeat is how often an idle stream is sent a keep-alive
+	Heartbeat Duration `json:"heartbeat"`
+}
+
+// OutboxConfig configures the transactional outbox of task events. When it is
+// enabled every task change runs in a MongoDB transaction, which requires a
+// replica set or a sharded cluster: a standalone server rejects transactions,
+// so the outbox is off by default. When it is disabled events are published
+// right after each change and are lost if the process stops in between.
+type OutboxConfig struct {
+	Enabled bool `json:"enabled"`
+	// PollInterval is how often the outbox is checked for events to relay
+	PollInterval Duration `json:"poll_interval"`
+	// InitialBackoff is the wait after the first failed relay; it doubles
+	// after every further failure up to MaxBackoff
+	InitialBackoff Duration `json:"initial_backoff"`
+	MaxBackoff     Duration `json:"max_backoff"`
+	// MaxAttempts is how many failed relays an event gets before it is dead
+	// lettered and kept in the outbox without further attempts
+	MaxAttempts int `json:"max_attempts"`
+}
+
+// AttachmentsConfig configures where task attachments are stored and how
+// much users may upload.
+type AttachmentsConfig struct {
+	// Store keeps the content in MongoDB ("gridfs") or in Dir ("local")
+	Store string `json:"store"`
+	Dir   string `json:"dir"`
+	// MaxFileBytes is the largest single attachment
+	MaxFileBytes int64 `json:"max_file_bytes"`
+	// UserQuotaBytes is the total size of the attachments one user may keep
+	UserQuotaBytes int64 `json:"user_quota_bytes"`
+}
+
+// Addr returns the address the HTTP server listens on.
+func (c *Config) Addr() string {
+	return ":" + strconv.Itoa(c.Server.Port)
+}
+
+// Networks parses AllowedNetworks.
+func (w WebhooksConfig) Networks() ([]netip.Prefix, error) {
+	var networks []netip.Prefix
+	for _, network := range w.AllowedNetworks {
+		prefix, err := netip.ParsePrefix(network)
+		if err != nil {
+			return nil, fmt.Errorf("invalid webhook allowed network %q", network)
+		}
+		networks = append(networks, prefix)
+	}
+	return networks, nil
+}
+
+// Duration is a time.Duration that is written as a string such as "15s" in config files.
+type Duration struct {
+	time.Duration
+}
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("duration must be a string such as \"15s\": %w", err)
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	d.Duration = parsed
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// Default returns the configuration used when nothing else is provided.
+func Default() Config {
+	return Config{
+		Server: ServerConfig{
+			Port:            8080,
+			ReadTimeout:     Duration{15 * time.Second},
+			WriteTimeout:    Duration{15 * time.Second},
+			IdleTimeout:     Duration{60 * time.Second},
+			ShutdownTimeout: Duration{30 * time.Second},
+			MaxBodyBytes:    1 << 20,
+		},
+		Mongo: MongoConfig{
+			Database:        "taskdb",
+			TasksCollection: "tasks",
+			UsersCollection: "users",
+
+			IdempotencyCollection:   "idempotency_keys",
+			NotificationsCollection: "notifications",
+
+			WebhooksCollection:          "webhooks",
+			WebhookDeliveriesCollection: "webhook_deliveries",
+			OutboxCollection:            "outbox",
+			CommentsCollection:          "comments",
+			AttachmentsCollection:       "attachments",
+			BlobsBucket:                 "blobs",
+			LabelsCollection:            "labels",
+			SavedFiltersCollection:      "saved_filters",
+			SeriesCollection:            "task_series",
+			TimeEntriesCollection:       "time_entries",
+			StatusChangesCollection:     "status_changes",
+			OrganizationsCollection:     "organizations",
+		},
+		JWT: JWTConfig{
+			AccessTokenTTL: Duration{24 * time.Hour},
+		},
+		CORS: CORSConfig{
+			MaxAge: Duration{10 * time.Minute},
+		},
+		Security: SecurityConfig{
+			HSTSMaxAge: Duration{180 * 24 * time.Hour},
+		},
+		Log: LogConfig{
+			Level: "info",
+		},
+		Tracing: TracingConfig{
+			Exporter: "none",
+		},
+		RateLimit: RateLimitConfig{
+			Enabled:   true,
+			Store:     "memory",
+			Public:    RateLimitRule{RequestsPerMinute: 30, Burst: 10},
+			Protected: RateLimitRule{RequestsPerMinute: 300, Burst: 60},
+		},
+		Idempotency: IdempotencyConfig{
+			TTL: Duration{24 * time.Hour},
+		},
+		Reminders: RemindersConfig{
+			Enabled:  true,
+			Interval: Duration{time.Minute},
+			DueSoon:  Duration{24 * time.Hour},
+		},
+		Recurrence: RecurrenceConfig{
+			Enabled:  true,
+			Interval: Duration{time.Minute},
+		},
+		Webhooks: WebhooksConfig{
+			Enabled:        true,
+			PollInterval:   Duration{2 * time.Second},
+			Timeout:        Duration{10 * time.Second},
+			MaxAttempts:    8,
+			InitialBackoff: Duration{30 * time.Second},
+			MaxBackoff:     Duration{time.Hour},
+		},
+		Events: EventsConfig{
+			History:   1000,
+			Heartbeat: Duration{25 * time.Second},
+		},
+		Outbox: OutboxConfig{
+			Enabled:        false,
+			PollInterval:   Duration{500 * time.Millisecond},
+			InitialBackoff: Duration{time.Second},
+			MaxBackoff:     Duration{5 * time.Minute},
+			MaxAttempts:    20,
+		},
+		Attachments: AttachmentsConfig{
+			Store:          "gridfs",
+			Dir:            "data/attachments",
+			MaxFileBytes:   10 << 20,
+			UserQuotaBytes: 100 << 20,
+		},
+	}
+}
+
+// Load builds the configuration from, in increasing order of precedence, the
+// defaults, an optional JSON file, environment variables and command-line flags.
+// The file is given with -config or CONFIG_FILE. args excludes the program name.
+func Load(args []string, getenv func(string) string) (*Config, error) {
+	cfg := Default()
+
+	fs := flag.NewFlagSet("task_manager", flag.ContinueOnError)
+	configFile := fs.String("config", getenv("CONFIG_FILE"), "path to a JSON configuration file")
+	port := fs.Int("port", 0, "HTTP port to listen on")
+	mongoURI := fs.String("mongo-uri", "", "MongoDB connection string")
+	database := fs.String("db", "", "MongoDB database name")
+	logLevel := fs.String("log-level", "", "log level: debug, info, warn or error")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if *configFile != "" {
+		if err := loadFile(&cfg, *configFile); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := applyEnv(&cfg, getenv); err != nil {
+		return nil, err
+	}
+
+	// Flags override everything else when explicitly set
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "port":
+			cfg.Server.Port = *port
+		case "mongo-uri":
+			cfg.Mongo.URI = *mongoURI
+		case "db":
+			cfg.Mongo.Database = *database
+		case "log-level":
+			cfg.Log.Level = *logLevel
+		}
+	})
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// loadFile merges the JSON file at path over cfg.
+func loadFile(cfg *Config, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("reading config file: %w", err)
+	}
+	defer file.Close()
+
+	decoder := json.NewDecoder(file)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(cfg); err != nil {
+		return fmt.Errorf("parsing config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// applyEnv overrides cfg with the environment variables that are set.
+func applyEnv(cfg *Config, getenv func(string) string) error {
+	setString := func(key string, target *string) {
+		if value := getenv(key); value != "" {
+			*target = value
+		}
+	}
+	setDuration := func(key string, target *Duration) error {
+		value := getenv(key)
+		if value == "" {
+			return nil
+		}
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		target.Duration = parsed
+		return nil
+	}
+
+	if value := getenv("PORT"); value != "" {
+		port, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("PORT: %w", err)
+		}
+		cfg.Server.Port = port
+	}
+	setString("MONGO_DB_URI", &cfg.Mongo.URI)
+	setString("MONGO_DB_NAME", &cfg.Mongo.Database)
+	setString("MONGO_TASKS_COLLECTION", &cfg.Mongo.TasksCollection)
+	setString("MONGO_USERS_COLLECTION", &cfg.Mongo.UsersCollection)
+	setString("MONGO_IDEMPOTENCY_COLLECTION", &cfg.Mongo.IdempotencyCollection)
+	setString("MONGO_NOTIFICATIONS_COLLECTION", &cfg.Mongo.NotificationsCollection)
+	setString("MONGO_WEBHOOKS_COLLECTION", &cfg.Mongo.WebhooksCollection)
+	setString("MONGO_WEBHOOK_DELIVERIES_COLLECTION", &cfg.Mongo.WebhookDeliveriesCollection)
+	setString("MONGO_OUTBOX_COLLECTION", &cfg.Mongo.OutboxCollection)
+	setString("MONGO_COMMENTS_COLLECTION", &cfg.Mongo.CommentsCollection)
+	setString("MONGO_ATTACHMENTS_COLLECTION", &cfg.Mongo.AttachmentsCollection)
+	setString("MONGO_BLOBS_BUCKET", &cfg.Mongo.BlobsBucket)
+	setString("MONGO_LABELS_COLLECTION", &cfg.Mongo.LabelsCollection)
+	setString("MONGO_SAVED_FILTERS_COLLECTION", &cfg.Mongo.SavedFiltersCollection)
+	setString("MONGO_SERIES_COLLECTION", &cfg.Mongo.SeriesCollection)
+	setString("MONGO_TIME_ENTRIES_COLLECTION", &cfg.Mongo.TimeEntriesCollection)
+	setString("MONGO_STATUS_CHANGES_COLLECTION", &cfg.Mongo.StatusChangesCollection)
+	setString("MONGO_ORGANIZATIONS_COLLECTION", &cfg.Mongo.OrganizationsCollection)
+	setString("JWT_SECRET", &cfg.JWT.Secret)
+	setString("JWT_ISSUER", &cfg.JWT.Issuer)
+	setString("ROOT_USERNAME", &cfg.Root.Username)
+	setString("ROOT_PASSWORD", &cfg.Root.Password)
+	setString("LOG_LEVEL", &cfg.Log.Level)
+	setString("OTEL_TRACES_EXPORTER", &cfg.Tracing.Exporter)
+	setString("CONTENT_SECURITY_POLICY", &cfg.Security.ContentSecurityPolicy)
+	setString("RATE_LIMIT_STORE", &cfg.RateLimit.Store)
+	setString("REDIS_ADDR", &cfg.RateLimit.RedisAddr)
+	setString("ATTACHMENTS_STORE", &cfg.Attachments.Store)
+	setString("ATTACHMENTS_DIR", &cfg.Attachments.Dir)
+
+	if value := getenv("MAX_BODY_BYTES"); value != "" {
+		limit, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("MAX_BODY_BYTES: %w", err)
+		}
+		cfg.Server.MaxBodyBytes = limit
+	}
+	for key, target := range map[string]*int64{
+		"ATTACHMENT_MAX_FILE_BYTES":   &cfg.Attachments.MaxFileBytes,
+		"ATTACHMENT_USER_QUOTA_BYTES": &cfg.Attachments.UserQuotaBytes,
+	} {
+		if value := getenv(key); value != "" {
+			bytes, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return fmt.Errorf("%s: %w", key, err)
+			}
+			*target = bytes
+		}
+	}
+	if value := getenv("CORS_ALLOW_CREDENTIALS"); value != "" {
+		allow, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("CORS_ALLOW_CREDENTIALS: %w", err)
+		}
+		cfg.CORS.AllowCredentials = allow
+	}
+	if value := getenv("RATE_LIMIT_ENABLED"); value != "" {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("RATE_LIMIT_ENABLED: %w", err)
+		}
+		cfg.RateLimit.Enabled = enabled
+	}
+	if value := getenv("REMINDERS_ENABLED"); value != "" {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("REMINDERS_ENABLED: %w", err)
+		}
+		cfg.Reminders.Enabled = enabled
+	}
+	if value := getenv("RECURRENCE_ENABLED"); value != "" {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("RECURRENCE_ENABLED: %w", err)
+		}
+		cfg.Recurrence.Enabled = enabled
+	}
+	if value := getenv("WEBHOOKS_ENABLED"); value != "" {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("WEBHOOKS_ENABLED: %w", err)
+		}
+		cfg.Webhooks.Enabled = enabled
+	}
+	if value := getenv("WEBHOOK_MAX_ATTEMPTS"); value != "" {
+		attempts, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("WEBHOOK_MAX_ATTEMPTS: %w", err)
+		}
+		cfg.Webhooks.MaxAttempts = attempts
+	}
+	if value := getenv("OUTBOX_ENABLED"); value != "" {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("OUTBOX_ENABLED: %w", err)
+		}
+		cfg.Outbox.Enabled = enabled
+	}
+	if value := getenv("OUTBOX_MAX_ATTEMPTS"); value != "" {
+		attempts, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("OUTBOX_MAX_ATTEMPTS: %w", err)
+		}
+		cfg.Outbox.MaxAttempts = attempts
+	}
+	if value := getenv("EVENTS_HISTORY"); value != "" {
+		history, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("EVENTS_HISTORY: %w", err)
+		}
+		cfg.Events.History = history
+	}
+
+	if value := getenv("WEBHOOK_ALLOWED_NETWORKS"); value != "" {
+		cfg.Webhooks.AllowedNetworks = nil
+		for _, network := range strings.Split(value, ",") {
+			if network = strings.TrimSpace(network); network != "" {
+				cfg.Webhooks.AllowedNetworks = append(cfg.Webhooks.AllowedNetworks, network)
+			}
+		}
+	}
+
+	if value := getenv("TRUSTED_PROXIES"); value != "" {
+		cfg.Server.TrustedProxies = nil
+		for _, proxy := range strings.Split(value, ",") {
+			if proxy = strings.TrimSpace(proxy); proxy != "" {
+				cfg.Server.TrustedProxies = append(cfg.Server.TrustedProxies, proxy)
+			}
+		}
+	}
+
+	if value := getenv("CORS_ALLOWED_ORIGINS"); value != "" {
+		cfg.CORS.AllowedOrigins = nil
+		for _, origin := range strings.Split(value, ",") {
+			if origin = strings.TrimSpace(origin); origin != "" {
+				cfg.CORS.AllowedOrigins = append(cfg.CORS.AllowedOrigins, origin)
+			}
+		}
+	}
+
+	for key, target := range map[string]*Duration{
+		"JWT_ACCESS_TOKEN_TTL":    &cfg.JWT.AccessTokenTTL,
+		"SERVER_READ_TIMEOUT":     &cfg.Server.ReadTimeout,
+		"SERVER_WRITE_TIMEOUT":    &cfg.Server.WriteTimeout,
+		"SERVER_IDLE_TIMEOUT":     &cfg.Server.IdleTimeout,
+		"SERVER_SHUTDOWN_TIMEOUT": &cfg.Server.ShutdownTimeout,
+		"CORS_MAX_AGE":            &cfg.CORS.MaxAge,
+		"HSTS_MAX_AGE":            &cfg.Security.HSTSMaxAge,
+		"IDEMPOTENCY_TTL":         &cfg.Idempotency.TTL,
+		"REMINDER_INTERVAL":       &cfg.Reminders.Interval,
+		"REMINDER_DUE_SOON":       &cfg.Reminders.DueSoon,
+		"RECURRENCE_INTERVAL":     &cfg.Recurrence.Interval,
+		"WEBHOOK_POLL_INTERVAL":   &cfg.Webhooks.PollInterval,
+		"WEBHOOK_TIMEOUT":         &cfg.Webhooks.Timeout,
+		"WEBHOOK_INITIAL_BACKOFF": &cfg.Webhooks.InitialBackoff,
+		"WEBHOOK_MAX_BACKOFF":     &cfg.Webhooks.MaxBackoff,
+		"EVENTS_HEARTBEAT":        &cfg.Events.Heartbeat,
+		"OUTBOX_POLL_INTERVAL":    &cfg.Outbox.PollInterval,
+		"OUTBOX_INITIAL_BACKOFF":  &cfg.Outbox.InitialBackoff,
+		"OUTBOX_MAX_BACKOFF":      &cfg.Outbox.MaxBackoff,
+	} {
+		if err := setDuration(key, target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Validate reports every problem with the configuration at once.
+func (c *Config) Validate() error {
+	var errs []error
+
+	if c.Server.Port < 1 || c.Server.Port > 65535 {
+		errs = append(errs, fmt.Errorf("server port must be between 1 and 65535, got %d", c.Server.Port))
+	}
+	durations := []struct {
+		name  string
+		value Duration
+	}{
+		{"server read timeout", c.Server.ReadTimeout},
+		{"server write timeout", c.Server.WriteTimeout},
+		{"server idle timeout", c.Server.IdleTimeout},
+		{"server shutdown timeout", c.Server.ShutdownTimeout},
+		{"JWT access token TTL", c.JWT.AccessTokenTTL},
+		{"idempotency TTL", c.Idempotency.TTL},
+		{"reminder interval", c.Reminders.Interval},
+		{"reminder due soon window", c.Reminders.DueSoon},
+		{"recurrence interval", c.Recurrence.Interval},
+		{"webhook poll interval", c.Webhooks.PollInterval},
+		{"webhook timeout", c.Webhooks.Timeout},
+		{"webhook initial backoff", c.Webhooks.InitialBackoff},
+		{"webhook max backoff", c.Webhooks.MaxBackoff},
+		{"event stream heartbeat", c.Events.Heartbeat},
+		{"outbox poll interval", c.Outbox.PollInterval},
+		{"outbox initial backoff", c.Outbox.InitialBackoff},
+		{"outbox max backoff", c.Outbox.MaxBackoff},
+	}
+	for _, d := range durations {
+		if d.value.Duration <= 0 {
+			errs = append(errs, fmt.Errorf("%s must be positive", d.name))
+		}
+	}
+
+	if c.Mongo.URI == "" {
+		errs = append(errs, errors.New("mongo URI is required (MONGO_DB_URI)"))
+	} else if !strings.HasPrefix(c.Mongo.URI, "mongodb://") && !strings.HasPrefix(c.Mongo.URI, "mongodb+srv://") {
+		errs = append(errs, errors.New("mongo URI must start with mongodb:// or mongodb+srv://"))
+	}
+	if c.Mongo.Database == "" {
+		errs = append(errs, errors.New("mongo database name is required"))
+	}
+	if c.Mongo.TasksCollection == "" || c.Mongo.UsersCollection == "" || c.Mongo.IdempotencyCollection == "" || c.Mongo.NotificationsCollection == "" ||
+		c.Mongo.WebhooksCollection == "" || c.Mongo.WebhookDeliveriesCollection == "" || c.Mongo.OutboxCollection == "" ||
+		c.Mongo.CommentsCollection == "" || c.Mongo.AttachmentsCollection == "" || c.Mongo.BlobsBucket == "" ||
+		c.Mongo.LabelsCollection == "" || c.Mongo.SavedFiltersCollection == "" || c.Mongo.SeriesCollection == "" ||
+		c.Mongo.TimeEntriesCollection == "" || c.Mongo.StatusChangesCollection == "" ||
+		c.Mongo.OrganizationsCollection == "" {
+		errs = append(errs, errors.New("mongo collection names cannot be empty"))
+	} else if c.Mongo.TasksCollection == c.Mongo.UsersCollection {
+		errs = append(errs, errors.New("tasks and users must be stored in different collections"))
+	}
+
+	if c.Webhooks.MaxAttempts < 1 {
+		errs = append(errs, errors.New("webhook max attempts must be at least 1"))
+	}
+	if c.Webhooks.MaxBackoff.Duration < c.Webhooks.InitialBackoff.Duration {
+		errs = append(errs, errors.New("webhook max backoff cannot be shorter than the initial backoff"))
+	}
+	if _, err := c.Webhooks.Networks(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if c.Outbox.MaxAttempts < 1 {
+		errs = append(errs, errors.New("outbox max attempts must be at least 1"))
+	}
+	if c.Outbox.MaxBackoff.Duration < c.Outbox.InitialBackoff.Duration {
+		errs = append(errs, errors.New("outbox max backoff cannot be shorter than the initial backoff"))
+	}
+	if c.Events.History < 1 {
+		errs = append(errs, errors.New("event history must be at least 1"))
+	}
+
+	if c.JWT.Secret == "" {
+		errs = append(errs, errors.New("JWT secret is required (JWT_SECRET)"))
+	}
+	if c.Root.Username != "" && c.Root.Password == "" {
+		errs = append(errs, errors.New("a password is required for the root user (ROOT_PASSWORD)"))
+	}
+
+	if c.Server.MaxBodyBytes <= 0 {
+		errs = append(errs, errors.New("server max body bytes must be positive"))
+	}
+	for _, proxy := range c.Server.TrustedProxies {
+		if _, err := netip.ParsePrefix(proxy); err == nil {
+			continue
+		}
+		if _, err := netip.ParseAddr(proxy); err != nil {
+			errs = append(errs, fmt.Errorf("invalid trusted proxy %q, use an IP address or a CIDR range", proxy))
+		}
+	}
+	switch c.Attachments.Store {
+	case "gridfs":
+	case "local":
+		if c.Attachments.Dir == "" {
+			errs = append(errs, errors.New("a directory is required for the local attachment store (ATTACHMENTS_DIR)"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("unknown attachment store %q, use gridfs or local", c.Attachments.Store))
+	}
+	if c.Attachments.MaxFileBytes <= 0 || c.Attachments.UserQuotaBytes <= 0 {
+		errs = append(errs, errors.New("attachment max file bytes and user quota must be positive"))
+	}
+	if c.CORS.MaxAge.Duration < 0 || c.Security.HSTSMaxAge.Duration < 0 {
+		errs = append(errs, errors.New("CORS max age and HSTS max age cannot be negative"))
+	}
+
+	for _, origin := range c.CORS.AllowedOrigins {
+		if origin == "*" {
+			if c.CORS.AllowCredentials {
+				errs = append(errs, errors.New("CORS credentials cannot be allowed for any origin (*)"))
+			}
+			continue
+		}
+		u, err := url.Parse(origin)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || (u.Path != "" && u.Path != "/") {
+			errs = append(errs, fmt.Errorf("invalid CORS origin %q", origin))
+		}
+	}
+
+	if c.RateLimit.Enabled {
+		switch c.RateLimit.Store {
+		case "memory":
+		case "redis":
+			if c.RateLimit.RedisAddr == "" {
+				errs = append(errs, errors.New("redis address is required for the redis rate limit store (REDIS_ADDR)"))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("unknown rate limit store %q", c.RateLimit.Store))
+		}
+		for name, rule := range map[string]RateLimitRule{"public": c.RateLimit.Public, "protected": c.RateLimit.Protected} {
+			if rule.RequestsPerMinute <= 0 || rule.Burst <= 0 {
+				errs = append(errs, fmt.Errorf("%s rate limit requests per minute and burst must be positive", name))
+			}
+		}
+	}
+
+	switch strings.ToLower(c.Log.Level) {
+	case "debug", "info", "warn", "warning", "error":
+	default:
+		errs = append(errs, fmt.Errorf("unknown log level %q", c.Log.Level))
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,113 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"task_manager_testing/config"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// ConfigSuite tests loading and validating the configuration
+type ConfigSuite struct {
+	suite.Suite
+	env map[string]string
+}
+
+func (suite *ConfigSuite) SetupTest() {
+	suite.env = map[string]string{
+		"MONGO_DB_URI": "mongodb://localhost:27017",
+		"JWT_SECRET":   "secret",
+	}
+}
+
+func (suite *ConfigSuite) getenv(key string) string {
+	return suite.env[key]
+}
+
+func (suite *ConfigSuite) TestDefaults() {
+	cfg, err := config.Load(nil, suite.getenv)
+	suite.Require().NoError(err)
+
+	suite.Equal(":8080", cfg.Addr())
+	suite.Equal("taskdb", cfg.Mongo.Database)
+	suite.Equal("tasks", cfg.Mongo.TasksCollection)
+	suite.Equal("users", cfg.Mongo.UsersCollection)
+	suite.Equal(24*time.Hour, cfg.JWT.AccessTokenTTL.Duration)
+}
+
+func (suite *ConfigSuite) TestPrecedence() {
+	path := filepath.Join(suite.T().TempDir(), "config.json")
+	err := os.WriteFile(path, []byte(`{
+		"server": {"port": 9000},
+		"mongo": {"database": "filedb", "tasks_collection": "filetasks"},
+		"jwt": {"access_token_ttl": "1h"}
+	}`), 0o600)
+	suite.Require().NoError(err)
+
+	suite.env["CONFIG_FILE"] = path
+	suite.env["MONGO_DB_NAME"] = "envdb"
+	suite.env["CORS_ALLOWED_ORIGINS"] = "https://app.example.com, http://localhost:3000"
+	suite.env["TRUSTED_PROXIES"] = "10.0.0.0/8, 192.168.1.1"
+
+	cfg, err := config.Load([]string{"-db", "flagdb"}, suite.getenv)
+	suite.Require().NoError(err)
+
+	// The file overrides defaults, the environment overrides the file and flags override both
+	suite.Equal(9000, cfg.Server.Port)
+	suite.Equal("filetasks", cfg.Mongo.TasksCollection)
+	suite.Equal(time.Hour, cfg.JWT.AccessTokenTTL.Duration)
+	suite.Equal("flagdb", cfg.Mongo.Database)
+	suite.Equal([]string{"https://app.example.com", "http://localhost:3000"}, cfg.CORS.AllowedOrigins)
+	suite.Equal([]string{"10.0.0.0/8", "192.168.1.1"}, cfg.Server.TrustedProxies)
+}
+
+func (suite *ConfigSuite) TestValidation() {
+	testCases := []struct {
+		name string
+		env  map[string]string
+		args []string
+	}{
+		{name: "Missing Mongo URI", env: map[string]string{"MONGO_DB_URI": ""}},
+		{name: "Invalid Mongo URI", env: map[string]string{"MONGO_DB_URI": "localhost:27017"}},
+		{name: "Missing JWT secret", env: map[string]string{"JWT_SECRET": ""}},
+		{name: "Port out of range", args: []string{"-port", "70000"}},
+		{name: "Same collection twice", env: map[string]string{"MONGO_USERS_COLLECTION": "tasks"}},
+		{name: "Invalid CORS origin", env: map[string]string{"CORS_ALLOWED_ORIGINS": "app.example.com"}},
+		{name: "Invalid TTL", env: map[string]string{"JWT_ACCESS_TOKEN_TTL": "forever"}},
+		{name: "Unknown log level", args: []string{"-log-level", "loud"}},
+		{name: "Credentials for any origin", env: map[string]string{"CORS_ALLOWED_ORIGINS": "*", "CORS_ALLOW_CREDENTIALS": "true"}},
+		{name: "Invalid trusted proxy", env: map[string]string{"TRUSTED_PROXIES": "10.0.0.1, proxy.internal"}},
+		{name: "Invalid max body size", env: map[string]string{"MAX_BODY_BYTES": "0"}},
+		{name: "Unknown rate limit store", env: map[string]string{"RATE_LIMIT_STORE": "disk"}},
+		{name: "Redis store without address", env: map[string]string{"RATE_LIMIT_STORE": "redis"}},
+		{name: "Invalid rate limit flag", env: map[string]string{"RATE_LIMIT_ENABLED": "sometimes"}},
+		{name: "Invalid webhook allowed network", env: map[string]string{"WEBHOOK_ALLOWED_NETWORKS": "10.0.0.0/8,internal"}},
+		{name: "Root user without password", env: map[string]string{"ROOT_USERNAME": "root"}},
+		{name: "Outbox without attempts", env: map[string]string{"OUTBOX_MAX_ATTEMPTS": "0"}},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			suite.SetupTest()
+			for key, value := range tc.env {
+				suite.env[key] = value
+			}
+
+			_, err := config.Load(tc.args, suite.getenv)
+			suite.Error(err)
+		})
+	}
+}
+
+func (suite *ConfigSuite) TestMissingConfigFile() {
+	_, err := config.Load([]string{"-config", filepath.Join(suite.T().TempDir(), "missing.json")}, suite.getenv)
+	suite.Error(err)
+}
+
+func TestConfigSuite(t *testing.T) {
+	suite.Run(t, new(ConfigSuite))
+}
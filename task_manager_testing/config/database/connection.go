@@ -2,10 +2,11 @@ package database
 
 import (
 	"context"
-	"fmt"
+	"log/slog"
 	"go.mongodb.org/mongo-driver/mongo"
 
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"
 )
 
 
@@ -13,6 +14,8 @@ import (
 func ConnectToMongoDB(uri string) (*mongo.Client, error) {
 	// Set client options
 	clientOptions := options.Client().ApplyURI(uri)
+	// Trace every command sent to MongoDB
+	clientOptions.SetMonitor(otelmongo.NewMonitor())
 	client, err := mongo.Connect(context.TODO(), clientOptions)
 	if err != nil {
 		return nil, err
@@ -25,18 +28,19 @@ func ConnectToMongoDB(uri string) (*mongo.Client, error) {
 	}
 
 
-	fmt.Println("Connected to MongoDB!")
+	slog.Info("connected to MongoDB")
 	return client, nil
 }
 
 
-// DisconnectFromMongoDB closes the connection to a MongoDB database.
-func DisconnectFromMongoDB(client *mongo.Client) error {
-	err := client.Disconnect(context.TODO())
+// DisconnectFromMongoDB closes the connection to a MongoDB database, waiting
+// for in-use connections to be returned until ctx expires.
+func DisconnectFromMongoDB(ctx context.Context, client *mongo.Client) error {
+	err := client.Disconnect(ctx)
 	if err != nil {
 		return err
 	}
 
-	fmt.Println("Disconnected from MongoDB!")
+	slog.Info("disconnected from MongoDB")
 	return nil
 }
\ No newline at end of file
@@ -0,0 +1,23 @@
+package database
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// MongoPinger checks that the MongoDB primary can be reached.
+type MongoPinger struct {
+	client *mongo.Client
+}
+
+// NewMongoPinger creates a MongoPinger for the given client.
+func NewMongoPinger(client *mongo.Client) *MongoPinger {
+	return &MongoPinger{client: client}
+}
+
+// Ping sends a ping command to the primary.
+func (mp *MongoPinger) Ping(ctx context.Context) error {
+	return mp.client.Ping(ctx, readpref.Primary())
+}
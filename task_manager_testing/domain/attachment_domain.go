@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	// ErrInvalidAttachment wraps validation errors of uploaded files.
+	ErrInvalidAttachment = errors.New("invalid attachment")
+	// ErrAttachmentTooLarge is returned for files over the size limit.
+	ErrAttachmentTooLarge = errors.New("attachment is too large")
+	// ErrQuotaExceeded is returned when an upload would take a user over
+	// their storage quota.
+	ErrQuotaExceeded = errors.New("attachment quota exceeded")
+	// ErrAttachmentCorrupted is returned when stored content no longer
+	// matches the checksum recorded at upload.
+	ErrAttachmentCorrupted = errors.New("attachment content is corrupted")
+	// ErrBlobNotFound is returned by a BlobStore for unknown keys.
+	ErrBlobNotFound = errors.New("blob not found")
+)
+
+// Attachment describes a file uploaded to a task. The content is kept in a
+// BlobStore under BlobKey.
+type Attachment struct {
+	ID      primitive.ObjectID `json:"id" bson:"_id"`
+	TaskID  primitive.ObjectID `json:"task_id" bson:"task_id"`
+	OwnerID primitive.ObjectID `json:"owner_id" bson:"owner_id"`
+	// Filename is the base name given by the uploader
+	Filename string `json:"filename" bson:"filename"`
+	// ContentType is sniffed from the content, not taken from the client
+	ContentType string `json:"content_type" bson:"content_type"`
+	Size        int64  `json:"size" bson:"size"`
+	// SHA256 is the hex-encoded checksum of the content
+	SHA256    string             `json:"sha256" bson:"sha256"`
+	BlobKey   string             `json:"-" bson:"blob_key"`
+	CreatedAt primitive.DateTime `json:"created_at" bson:"created_at"`
+}
+
+// BlobStore keeps the content of attachments.
+type BlobStore interface {
+	// Put stores everything read from content under key.
+	Put(ctx context.Context, key string, content io.Reader) error
+	// Get opens the content stored under key, or returns ErrBlobNotFound.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the content stored under key, if any.
+	Delete(ctx context.Context, key string) error
+}
+
+type AttachmentRepository interface {
+	// CreateAttachment stores attachment unless it takes the usage of its
+	// owner over quota bytes, in which case it returns ErrQuotaExceeded.
+	// Concurrent uploads never exceed the quota together.
+	CreateAttachment(ctx context.Context, attachment Attachment, quota int64) error
+	GetAttachment(ctx context.Context, id primitive.ObjectID) (Attachment, error)
+	// GetAttachments returns the task's attachments, oldest first.
+	GetAttachments(ctx context.Context, taskID primitive.ObjectID) ([]Attachment, error)
+	DeleteAttachment(ctx context.Context, id primitive.ObjectID) error
+	// DeleteTaskAttachments removes the attachments of a deleted task and
+	// returns them, so that their content can be removed too.
+	DeleteTaskAttachments(ctx context.Context, taskID primitive.ObjectID) ([]Attachment, error)
+	// GetUsage returns the total size of the attachments uploaded by ownerID.
+	GetUsage(ctx context.Context, ownerID primitive.ObjectID) (int64, error)
+}
+
+// AttachmentUsecase manages the files of the tasks actor can see, with the
+// same visibility rules as comments.
+type AttachmentUsecase interface {
+	// Upload stores content as a new attachment of the task.
+	Upload(ctx context.Context, actor User, taskID primitive.ObjectID, filename string, content io.Reader) (Attachment, error)
+	GetAttachments(ctx context.Context, actor User, taskID primitive.ObjectID) ([]Attachment, error)
+	// Download returns an attachment with its content, after checking the
+	// content against the checksum recorded at upload.
+	Download(ctx context.Context, actor User, taskID, id primitive.ObjectID) (Attachment, io.ReadCloser, error)
+	// Delete removes an attachment uploaded by actor or by a user actor
+	// outranks. It returns an error wrapping ErrForbidden otherwise.
+	Delete(ctx context.Context, actor User, taskID, id primitive.ObjectID) error
+}
@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TaskStatuses lists the task statuses in the order of the board columns.
+var TaskStatuses = []string{"Not Started", TaskInProgress, TaskCompleted}
+
+// BoardColumn holds the tasks of one status, in rank order.
+type BoardColumn struct {
+	Status string `json:"status"`
+	Tasks  []Task `json:"tasks"`
+}
+
+// Board is the kanban view of a user's tasks, with a column per status.
+type Board struct {
+	Columns []BoardColumn `json:"columns"`
+}
+
+// TaskMove puts a task in the column of Status, between the tasks After and
+// Before of that column. After is zero at the top of the column and Before
+// at the bottom; without either the task goes to the bottom of the column.
+type TaskMove struct {
+	Status string
+	After  primitive.ObjectID
+	Before primitive.ObjectID
+}
+
+// ErrRankTaken is returned by a TaskRepository when a write gives a task the
+// rank of another task of its creator. Ranks are unique per creator across
+// every column, and a concurrent write can take a rank after it was chosen.
+var ErrRankTaken = errors.New("rank taken by another task")
+
+// rankDigits are the digits of a rank, in sort order.
+const rankDigits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// RankBetween returns a rank sorting after lower and before upper. An empty
+// lower sorts before every rank and an empty upper after every rank. lower
+// must sort before upper, and ranks made by RankBetween never end in '0', so
+// there is always room for another rank between two of them.
+func RankBetween(lower, upper string) string {
+	if upper != "" {
+		// Keep the common prefix, reading a short lower as padded with zeros
+		n := 0
+		for n < len(upper) && rankDigit(lower, n) == upper[n] {
+			n++
+		}
+		if n > 0 {
+			rest := ""
+			if n < len(lower) {
+				rest = lower[n:]
+			}
+			return upper[:n] + RankBetween(rest, upper[n:])
+		}
+	}
+
+	lo := strings.IndexByte(rankDigits, rankDigit(lower, 0))
+	hi := len(rankDigits)
+	if upper != "" {
+		hi = strings.IndexByte(rankDigits, upper[0])
+	}
+	switch {
+	case upper == "" && lo+1 < hi:
+		// Ranks appended at the end stay short
+		return string(rankDigits[lo+1])
+	case hi-lo > 1:
+		return string(rankDigits[(lo+hi)/2])
+	case len(upper) > 1:
+		return upper[:1]
+	}
+	rest := ""
+	if len(lower) > 1 {
+		rest = lower[1:]
+	}
+	return string(rankDigits[lo]) + RankBetween(rest, "")
+}
+
+// rankDigit returns digit i of rank, or '0' past its end.
+func rankDigit(rank string, i int) byte {
+	if i < len(rank) {
+		return rank[i]
+	}
+	return '0'
+}
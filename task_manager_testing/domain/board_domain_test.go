@@ -0,0 +1,74 @@
+package domain_test
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"task_manager_testing/domain"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// RankSuite defines the suite for board rank tests
+type RankSuite struct {
+	suite.Suite
+}
+
+// TestAppend tests that ranks appended at the end grow slowly
+func (suite *RankSuite) TestAppend() {
+	rank := ""
+	for i := 0; i < 1000; i++ {
+		next := domain.RankBetween(rank, "")
+		suite.Require().Greater(next, rank)
+		rank = next
+	}
+	suite.LessOrEqual(len(rank), 30)
+}
+
+// TestBetween tests ranks made between neighbours, including repeated moves to the same place
+func (suite *RankSuite) TestBetween() {
+	suite.Equal("1", domain.RankBetween("", ""))
+	suite.Equal("11", domain.RankBetween("1", "2"))
+	suite.Equal("01", domain.RankBetween("", "1"))
+	suite.Equal("1z1", domain.RankBetween("1z", "2"))
+
+	lower, upper := "1", "2"
+	for i := 0; i < 200; i++ {
+		rank := domain.RankBetween(lower, upper)
+		suite.Require().Greater(rank, lower)
+		suite.Require().Less(rank, upper)
+		suite.Require().False(strings.HasSuffix(rank, "0"))
+		if i%2 == 0 {
+			lower = rank
+		} else {
+			upper = rank
+		}
+	}
+}
+
+// TestRandomMoves tests that random moves keep every rank distinct and in order
+func (suite *RankSuite) TestRandomMoves() {
+	random := rand.New(rand.NewSource(1))
+	ranks := []string{}
+	for i := 0; i < 500; i++ {
+		at := random.Intn(len(ranks) + 1)
+		lower, upper := "", ""
+		if at > 0 {
+			lower = ranks[at-1]
+		}
+		if at < len(ranks) {
+			upper = ranks[at]
+		}
+		rank := domain.RankBetween(lower, upper)
+		ranks = append(ranks[:at], append([]string{rank}, ranks[at:]...)...)
+	}
+	for i := 1; i < len(ranks); i++ {
+		suite.Require().Less(ranks[i-1], ranks[i])
+	}
+}
+
+// TestRankSuite is the entry point for running the suite tests
+func TestRankSuite(t *testing.T) {
+	suite.Run(t, new(RankSuite))
+}
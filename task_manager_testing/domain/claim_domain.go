@@ -6,5 +6,8 @@ type Claims struct {
 	UserID   string `json:"userId"`
 	Username string `json:"username"`
 	Role     string `json:"role"`
+	// OrgID is the hex ID of the user's organization, empty for users
+	// without one
+	OrgID string `json:"orgId,omitempty"`
 	jwt.StandardClaims
 }
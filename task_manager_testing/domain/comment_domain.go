@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrInvalidComment wraps validation errors of comment input.
+var ErrInvalidComment = errors.New("invalid comment")
+
+// MaxCommentLength is the largest comment body accepted, in bytes.
+const MaxCommentLength = 10000
+
+// CommentMention links a user named with @username in a comment body.
+type CommentMention struct {
+	UserID   primitive.ObjectID `json:"user_id" bson:"user_id"`
+	Username string             `json:"username" bson:"username"`
+}
+
+// Comment is a Markdown message in the discussion of a task. Replies point to
+// the comment they answer with ParentID. A deleted comment that still has
+// replies is kept without its body so that the thread stays readable.
+type Comment struct {
+	ID       primitive.ObjectID  `json:"id" bson:"_id"`
+	TaskID   primitive.ObjectID  `json:"task_id" bson:"task_id"`
+	ParentID *primitive.ObjectID `json:"parent_id,omitempty" bson:"parent_id,omitempty"`
+	AuthorID primitive.ObjectID  `json:"author_id" bson:"author_id"`
+	// AuthorName is the author's username when the comment was written
+	AuthorName string             `json:"author_name" bson:"author_name"`
+	Body       string             `json:"body" bson:"body"`
+	Mentions   []CommentMention   `json:"mentions" bson:"mentions"`
+	CreatedAt  primitive.DateTime `json:"created_at" bson:"created_at"`
+	EditedAt   primitive.DateTime `json:"edited_at,omitempty" bson:"edited_at,omitempty"`
+	Deleted    bool               `json:"deleted,omitempty" bson:"deleted,omitempty"`
+	// Replies is filled in when a thread is returned; it is not stored
+	Replies []Comment `json:"replies,omitempty" bson:"-"`
+}
+
+type CommentRepository interface {
+	CreateComment(ctx context.Context, comment Comment) error
+	GetComment(ctx context.Context, id primitive.ObjectID) (Comment, error)
+	// GetComments returns every comment on the task, oldest first.
+	GetComments(ctx context.Context, taskID primitive.ObjectID) ([]Comment, error)
+	UpdateComment(ctx context.Context, comment Comment) error
+	DeleteComment(ctx context.Context, id primitive.ObjectID) error
+	// DeleteTaskComments removes every comment on a deleted task.
+	DeleteTaskComments(ctx context.Context, taskID primitive.ObjectID) error
+	CountReplies(ctx context.Context, id primitive.ObjectID) (int64, error)
+}
+
+// CommentUsecase manages the discussion of tasks on behalf of actor, who must
+// be able to see the task: users see their own tasks, admins and root users
+// every task. Comments on other tasks are reported as mongo.ErrNoDocuments.
+type CommentUsecase interface {
+	// AddComment stores a comment, or a reply when parentID is set, and
+	// notifies the users mentioned in body.
+	AddComment(ctx context.Context, actor User, taskID primitive.ObjectID, parentID *primitive.ObjectID, body string) (Comment, error)
+	// GetComments returns the task's top-level comments with their replies.
+	GetComments(ctx context.Context, actor User, taskID primitive.ObjectID) ([]Comment, error)
+	// UpdateComment changes the body of one of actor's own comments.
+	UpdateComment(ctx context.Context, actor User, taskID, id primitive.ObjectID, body string) (Comment, error)
+	// DeleteComment removes a comment written by actor or by a user actor
+	// outranks. It returns an error wrapping ErrForbidden otherwise.
+	DeleteComment(ctx context.Context, actor User, taskID, id primitive.ObjectID) error
+}
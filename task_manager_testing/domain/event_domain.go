@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Task lifecycle event types.
+const (
+	EventTaskCreated   = "task.created"
+	EventTaskUpdated   = "task.updated"
+	EventTaskDeleted   = "task.deleted"
+	EventTaskCompleted = "task.completed"
+)
+
+// TaskEventTypes lists every task lifecycle event type.
+var TaskEventTypes = []string{EventTaskCreated, EventTaskUpdated, EventTaskDeleted, EventTaskCompleted}
+
+// TaskEvent records a change to a task. Task is the task after the change,
+// or before it for task.deleted.
+type TaskEvent struct {
+	ID         string    `json:"id" bson:"id"`
+	Type       string    `json:"type" bson:"type"`
+	OccurredAt time.Time `json:"occurred_at" bson:"occurred_at"`
+	Task       Task      `json:"task" bson:"task"`
+}
+
+// TaskEventPublisher receives the events of every successful task change.
+type TaskEventPublisher interface {
+	PublishTaskEvent(ctx context.Context, event TaskEvent) error
+}
+
+// TaskEventStream keeps the latest task events and streams new ones to live
+// subscribers.
+type TaskEventStream interface {
+	// Subscribe returns the events published after lastEventID followed by a
+	// channel of the events published from then on. resumed is false when
+	// lastEventID is no longer known, and nothing is replayed then. The channel
+	// is closed by cancel, when the subscriber falls too far behind or when the
+	// server shuts down.
+	Subscribe(lastEventID string) (missed []TaskEvent, resumed bool, events <-chan TaskEvent, cancel func())
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	// ErrInvalidFilter wraps validation errors of saved filter input.
+	ErrInvalidFilter = errors.New("invalid filter")
+	// ErrFilterExists is returned when the user already has a filter with
+	// the same name.
+	ErrFilterExists = errors.New("filter already exists")
+)
+
+// SavedFilter is a named task filter a user can run again. It selects among
+// the user's own tasks, like GET /tasks.
+type SavedFilter struct {
+	ID     primitive.ObjectID `json:"id" bson:"_id"`
+	UserID primitive.ObjectID `json:"user_id" bson:"user_id"`
+	Name   string             `json:"name" bson:"name"`
+	// NameKey is the lowercased name names are kept unique by per user
+	NameKey    string               `json:"-" bson:"name_key"`
+	Status     string               `json:"status,omitempty" bson:"status,omitempty"`
+	Labels     []primitive.ObjectID `json:"labels" bson:"labels"`
+	LabelMatch string               `json:"match" bson:"match"`
+	// OrgID is the organization of the user, set by the repository
+	OrgID     *primitive.ObjectID `json:"-" bson:"org_id,omitempty"`
+	CreatedAt primitive.DateTime  `json:"created_at" bson:"created_at"`
+}
+
+// TaskFilter returns the filter selecting the matching tasks of the user.
+func (f SavedFilter) TaskFilter() TaskFilter {
+	return TaskFilter{CreatedBy: f.UserID, Status: f.Status, Labels: f.Labels, LabelMatch: f.LabelMatch}
+}
+
+type SavedFilterRepository interface {
+	// CreateFilter stores filter, or returns ErrFilterExists when its user
+	// already has a filter with its name.
+	CreateFilter(ctx context.Context, filter SavedFilter) error
+	GetFilter(ctx context.Context, id primitive.ObjectID) (SavedFilter, error)
+	// GetFilters returns the filters of userID, sorted by name.
+	GetFilters(ctx context.Context, userID primitive.ObjectID) ([]SavedFilter, error)
+	DeleteFilter(ctx context.Context, id primitive.ObjectID) error
+}
+
+// SavedFilterUsecase manages the saved filters of actor. Filters of other
+// users are reported as mongo.ErrNoDocuments.
+type SavedFilterUsecase interface {
+	// SaveFilter validates filter and stores it for actor.
+	SaveFilter(ctx context.Context, actor User, filter SavedFilter) (SavedFilter, error)
+	GetFilters(ctx context.Context, actor User) ([]SavedFilter, error)
+	DeleteFilter(ctx context.Context, actor User, id primitive.ObjectID) error
+	// RunFilter returns the filter with the tasks it currently matches.
+	RunFilter(ctx context.Context, actor User, id primitive.ObjectID) (SavedFilter, []Task, error)
+}
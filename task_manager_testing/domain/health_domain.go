@@ -0,0 +1,8 @@
+package domain
+
+import "context"
+
+// Pinger reports whether a backing service such as the database is reachable.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
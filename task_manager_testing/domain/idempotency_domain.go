@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// IdempotencyRecord is the stored outcome of a request sent with an
+// Idempotency-Key header. A record is pending until the first request
+// finishes and the response is saved for replay.
+type IdempotencyRecord struct {
+	Key         string    `json:"key" bson:"_id"`
+	RequestHash string    `json:"request_hash" bson:"request_hash"`
+	Completed   bool      `json:"completed" bson:"completed"`
+	StatusCode  int       `json:"status_code" bson:"status_code"`
+	ContentType string    `json:"content_type" bson:"content_type"`
+	Body        []byte    `json:"body" bson:"body"`
+	CreatedAt   time.Time `json:"created_at" bson:"created_at"`
+	ExpiresAt   time.Time `json:"expires_at" bson:"expires_at"`
+}
+
+// IdempotencyRepository stores idempotency records until they expire.
+type IdempotencyRepository interface {
+	// Reserve saves record unless an unexpired record with the same key
+	// exists, in which case that record is returned and reserved is false.
+	Reserve(ctx context.Context, record IdempotencyRecord) (existing IdempotencyRecord, reserved bool, err error)
+	// Complete replaces the pending record with the final response.
+	Complete(ctx context.Context, record IdempotencyRecord) error
+	// Release deletes a pending record so the request can be retried.
+	Release(ctx context.Context, key string) error
+}
@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	// ErrInvalidLabel wraps validation errors of label input.
+	ErrInvalidLabel = errors.New("invalid label")
+	// ErrLabelExists is returned when a label with the same name already
+	// exists in the same scope.
+	ErrLabelExists = errors.New("label already exists")
+)
+
+// Label scopes. Global labels are managed by admins and root users and can be
+// used by everyone in their organization; personal labels belong to one user.
+const (
+	LabelScopeGlobal   = "global"
+	LabelScopePersonal = "personal"
+)
+
+// MaxTaskLabels is the largest number of labels one task can carry.
+const MaxTaskLabels = 20
+
+// Label categorizes tasks. Tasks refer to their labels by ID in Task.Labels.
+type Label struct {
+	ID   primitive.ObjectID `json:"id" bson:"_id"`
+	Name string             `json:"name" bson:"name"`
+	// Color is a #rrggbb hex color
+	Color string `json:"color" bson:"color"`
+	Scope string `json:"scope" bson:"scope"`
+	// OwnerID is the user of a personal label and is nil for global labels
+	OwnerID *primitive.ObjectID `json:"owner_id,omitempty" bson:"owner_id"`
+	// NameKey is the lowercased name names are kept unique by in a scope
+	NameKey string `json:"-" bson:"name_key"`
+	// OrgID is the organization the label belongs to, set by the repository;
+	// global labels are only global within it
+	OrgID     *primitive.ObjectID `json:"-" bson:"org_id,omitempty"`
+	CreatedAt primitive.DateTime  `json:"created_at" bson:"created_at"`
+}
+
+// LabelUpdate lists the label fields to change; nil fields are left as is.
+type LabelUpdate struct {
+	Name  *string `json:"name"`
+	Color *string `json:"color"`
+}
+
+type LabelRepository interface {
+	// CreateLabel stores label, or returns ErrLabelExists when its name is
+	// taken in its scope.
+	CreateLabel(ctx context.Context, label Label) error
+	GetLabel(ctx context.Context, id primitive.ObjectID) (Label, error)
+	// GetLabels returns the global labels and the personal labels of ownerID,
+	// sorted by name.
+	GetLabels(ctx context.Context, ownerID primitive.ObjectID) ([]Label, error)
+	// UpdateLabel replaces label, or returns ErrLabelExists when its new name
+	// is taken in its scope.
+	UpdateLabel(ctx context.Context, label Label) error
+	DeleteLabel(ctx context.Context, id primitive.ObjectID) error
+}
+
+// LabelUsecase manages labels and the labels of tasks on behalf of actor.
+// Actor may use the global labels of their organization and their own
+// personal labels; others are reported as mongo.ErrNoDocuments.
+type LabelUsecase interface {
+	// CreateLabel stores a label in scope. Only admins and root users may
+	// create global labels.
+	CreateLabel(ctx context.Context, actor User, name, color, scope string) (Label, error)
+	GetLabels(ctx context.Context, actor User) ([]Label, error)
+	UpdateLabel(ctx context.Context, actor User, id primitive.ObjectID, update LabelUpdate) (Label, error)
+	// DeleteLabel removes the label and takes it off every task.
+	DeleteLabel(ctx context.Context, actor User, id primitive.ObjectID) error
+	// SetTaskLabels replaces the labels of a task actor may edit.
+	SetTaskLabels(ctx context.Context, actor User, taskID primitive.ObjectID, labelIDs []primitive.ObjectID) (Task, error)
+	// AddTaskLabel adds one label to a task actor may edit.
+	AddTaskLabel(ctx context.Context, actor User, taskID, labelID primitive.ObjectID) (Task, error)
+	// RemoveTaskLabel takes one label off a task actor may edit.
+	RemoveTaskLabel(ctx context.Context, actor User, taskID, labelID primitive.ObjectID) (Task, error)
+}
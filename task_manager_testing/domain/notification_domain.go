@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Notification kinds.
+const (
+	NotificationDueSoon = "due_soon"
+	NotificationOverdue = "overdue"
+	// NotificationMention tells a user they were mentioned in a comment
+	NotificationMention = "mention"
+)
+
+// MaxNotificationsPage is the largest number of notifications returned at once.
+const MaxNotificationsPage = 100
+
+// Notification tells a user about one of their tasks.
+type Notification struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id"`
+	UserID    primitive.ObjectID `json:"user_id" bson:"user_id"`
+	TaskID    primitive.ObjectID `json:"task_id" bson:"task_id"`
+	Kind      string             `json:"kind" bson:"kind"`
+	Message   string             `json:"message" bson:"message"`
+	DueDate   primitive.DateTime `json:"due_date" bson:"due_date"`
+	CreatedAt primitive.DateTime `json:"created_at" bson:"created_at"`
+	ReadAt    primitive.DateTime `json:"read_at,omitempty" bson:"read_at,omitempty"`
+	// Key identifies the event notified about; it is stored at most once
+	Key string `json:"-" bson:"key"`
+}
+
+type NotificationRepository interface {
+	// CreateNotification stores notification unless one with the same Key
+	// exists, and reports whether it was stored.
+	CreateNotification(ctx context.Context, notification Notification) (bool, error)
+	// GetNotifications returns the user's newest notifications first.
+	GetNotifications(ctx context.Context, userID primitive.ObjectID, unreadOnly bool, limit int) ([]Notification, error)
+	CountUnread(ctx context.Context, userID primitive.ObjectID) (int64, error)
+	// MarkRead marks one of the user's notifications as read. It returns
+	// mongo.ErrNoDocuments when the user has no such notification.
+	MarkRead(ctx context.Context, userID, id primitive.ObjectID, readAt time.Time) error
+	// MarkAllRead marks every unread notification of the user as read.
+	MarkAllRead(ctx context.Context, userID primitive.ObjectID, readAt time.Time) (int64, error)
+}
+
+type NotificationUsecase interface {
+	// CheckDueTasks notifies the owners of unfinished tasks that are overdue
+	// at now or due within dueSoon, and returns the number of new notifications.
+	CheckDueTasks(ctx context.Context, now time.Time, dueSoon time.Duration) (int, error)
+	// GetNotifications returns the user's notifications and their unread count.
+	GetNotifications(ctx context.Context, userID primitive.ObjectID, unreadOnly bool, limit int) ([]Notification, int64, error)
+	MarkRead(ctx context.Context, userID, id primitive.ObjectID) error
+	MarkAllRead(ctx context.Context, userID primitive.ObjectID) (int64, error)
+}
@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	// ErrInvalidOrganization wraps validation errors of organization input.
+	ErrInvalidOrganization = errors.New("invalid organization")
+	// ErrOrganizationExists is returned when an organization with the same
+	// name already exists.
+	ErrOrganizationExists = errors.New("organization already exists")
+)
+
+// MaxOrganizationNameLength is the longest organization name.
+const MaxOrganizationNameLength = 100
+
+// Organization is a tenant. Its users, and the tasks they create, only see
+// the users and tasks of the same organization. Users without an
+// organization form a tenant of their own.
+//
+// Admins administer their organization; root users are global and see every
+// organization.
+type Organization struct {
+	ID   primitive.ObjectID `json:"id" bson:"_id"`
+	Name string             `json:"name" bson:"name"`
+	// NameKey is the lowercased name names are kept unique by
+	NameKey   string             `json:"-" bson:"name_key"`
+	CreatedAt primitive.DateTime `json:"created_at" bson:"created_at"`
+}
+
+type orgContextKey struct{}
+
+// WithOrg returns a copy of ctx scoped to the organization orgID, or to the
+// users without an organization when orgID is primitive.NilObjectID.
+//
+// The task, user, time entry and report repositories only read and change
+// the documents of the organization ctx is scoped to, and store new documents
+// in it. A ctx without a scope, such as the one of a root user or of a
+// background job, reaches every organization.
+func WithOrg(ctx context.Context, orgID primitive.ObjectID) context.Context {
+	return context.WithValue(ctx, orgContextKey{}, orgID)
+}
+
+// OrgFromContext returns the organization ctx is scoped to, and false when it
+// is not scoped.
+func OrgFromContext(ctx context.Context) (primitive.ObjectID, bool) {
+	orgID, ok := ctx.Value(orgContextKey{}).(primitive.ObjectID)
+	return orgID, ok
+}
+
+// ScopedOrg returns the organization a document written in ctx belongs to:
+// the one ctx is scoped to, or current when ctx is not scoped.
+func ScopedOrg(ctx context.Context, current *primitive.ObjectID) *primitive.ObjectID {
+	orgID, ok := OrgFromContext(ctx)
+	if !ok {
+		return current
+	}
+	if orgID.IsZero() {
+		return nil
+	}
+	return &orgID
+}
+
+type OrganizationRepository interface {
+	// AddOrganization stores org, or returns ErrOrganizationExists when its
+	// name is taken.
+	AddOrganization(ctx context.Context, org Organization) error
+	GetOrganizationById(ctx context.Context, id primitive.ObjectID) (Organization, error)
+	// GetAllOrganizations returns the organizations sorted by name.
+	GetAllOrganizations(ctx context.Context) ([]Organization, error)
+}
+
+// OrganizationUsecase manages the organizations. Only root users create and
+// list them; other users see their own organization.
+type OrganizationUsecase interface {
+	CreateOrganization(ctx context.Context, actor User, name string) (Organization, error)
+	GetOrganizationById(ctx context.Context, actor User, id primitive.ObjectID) (Organization, error)
+	GetAllOrganizations(ctx context.Context, actor User) ([]Organization, error)
+}
+
+// SameOrg reports whether a and b are the same organization, where nil stands
+// for users without one.
+func SameOrg(a, b *primitive.ObjectID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// OutboxMessage is a task event waiting in the outbox to be relayed to the
+// event sinks.
+type OutboxMessage struct {
+	ID    primitive.ObjectID `bson:"_id"`
+	Event TaskEvent          `bson:"event"`
+	// DeliveredTo lists the sinks that already received the event
+	DeliveredTo   []string           `bson:"delivered_to"`
+	Attempts      int                `bson:"attempts"`
+	LastError     string             `bson:"last_error,omitempty"`
+	NextAttemptAt primitive.DateTime `bson:"next_attempt_at"`
+	CreatedAt     primitive.DateTime `bson:"created_at"`
+	// DeadLetteredAt is set once the relay gave up on the message; it is kept
+	// for inspection but never claimed again
+	DeadLetteredAt *primitive.DateTime `bson:"dead_lettered_at,omitempty"`
+}
+
+// OutboxRepository stores task events in the same transaction as the change
+// they describe, so that they are relayed even if the process stops right
+// after the change.
+type OutboxRepository interface {
+	// AddEvents stores events as part of the transaction running in ctx, if any
+	AddEvents(ctx context.Context, events []TaskEvent) error
+	// ClaimMessage returns the oldest message due at now that is not dead
+	// lettered and hides it from other relays for lease. It returns
+	// mongo.ErrNoDocuments when none is due.
+	ClaimMessage(ctx context.Context, now time.Time, lease time.Duration) (OutboxMessage, error)
+	// RetryMessage records a failed attempt and when to try again
+	RetryMessage(ctx context.Context, message OutboxMessage) error
+	// DeadLetterMessage records the last failed attempt of a message the
+	// relay gave up on
+	DeadLetterMessage(ctx context.Context, message OutboxMessage) error
+	// DeleteMessage removes a message every sink received
+	DeleteMessage(ctx context.Context, id primitive.ObjectID) error
+}
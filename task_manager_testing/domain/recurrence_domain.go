@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Recurrence frequencies.
+const (
+	RecurDaily   = "daily"
+	RecurWeekly  = "weekly"
+	RecurMonthly = "monthly"
+)
+
+// Scopes of an edit of a recurring task.
+const (
+	// EditThisOccurrence changes one occurrence only
+	EditThisOccurrence = "this"
+	// EditFutureOccurrences changes the occurrence, the later ones and the
+	// occurrences generated from then on
+	EditFutureOccurrences = "future"
+)
+
+// Recurrence is the rule a recurring task repeats by, a subset of the
+// iCalendar RRULE. The due date of the first occurrence is the start of the
+// rule.
+type Recurrence struct {
+	Frequency string `json:"frequency" bson:"frequency"`
+	// Interval is the number of days, weeks or months between occurrences;
+	// zero means 1
+	Interval int `json:"interval,omitempty" bson:"interval,omitempty"`
+	// ByWeekday lists the days of a weekly rule as MO, TU, WE, TH, FR, SA or SU
+	ByWeekday []string `json:"by_weekday,omitempty" bson:"by_weekday,omitempty"`
+	// Count is the total number of occurrences; zero means no limit
+	Count int `json:"count,omitempty" bson:"count,omitempty"`
+	// Until is the last due date an occurrence can have; zero means no limit
+	Until primitive.DateTime `json:"until,omitempty" bson:"until,omitempty"`
+}
+
+// TaskSeries holds the rule and the template of the occurrences of a
+// recurring task. Its ID is the ID of the first occurrence.
+type TaskSeries struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id"`
+	CreatedBy  primitive.ObjectID `json:"created_by" bson:"created_by"`
+	Recurrence Recurrence         `json:"recurrence" bson:"recurrence"`
+	// Start is the due date of occurrence StartOccurrence, which the rule
+	// counts from. It moves when the rule is changed for future occurrences.
+	Start           primitive.DateTime `json:"start" bson:"start"`
+	StartOccurrence int                `json:"start_occurrence" bson:"start_occurrence"`
+	Title           string             `json:"title" bson:"title"`
+	Description     string             `json:"description" bson:"description"`
+	// Occurrences is the number of the latest generated occurrence and
+	// LatestDue its due date
+	Occurrences int                `json:"occurrences" bson:"occurrences"`
+	LatestDue   primitive.DateTime `json:"latest_due" bson:"latest_due"`
+	// Ended is set once the rule yields no more occurrences
+	Ended bool `json:"ended" bson:"ended"`
+	// OrgID is the Organization the occurrences are created in, set by the
+	// repository
+	OrgID *primitive.ObjectID `json:"-" bson:"org_id,omitempty"`
+}
+
+type TaskSeriesRepository interface {
+	CreateSeries(ctx context.Context, series TaskSeries) error
+	GetSeries(ctx context.Context, id primitive.ObjectID) (TaskSeries, error)
+	// UpdateSeries replaces the rule and the template of series.
+	UpdateSeries(ctx context.Context, series TaskSeries) error
+	// AdvanceSeries records occurrence from+1, due at latestDue, as the latest
+	// one. It reports false, changing nothing, when the latest occurrence is no
+	// longer from, so every occurrence is generated once.
+	AdvanceSeries(ctx context.Context, id primitive.ObjectID, from int, latestDue primitive.DateTime) (bool, error)
+	// EndSeries marks the series as ended.
+	EndSeries(ctx context.Context, id primitive.ObjectID) error
+	// GetDueSeries returns the series that have not ended and whose latest
+	// occurrence is due before before.
+	GetDueSeries(ctx context.Context, before time.Time) ([]TaskSeries, error)
+}
@@ -0,0 +1,134 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Report periods.
+const (
+	ReportDay   = "day"
+	ReportWeek  = "week"
+	ReportMonth = "month"
+)
+
+// MaxReportPoints is the largest number of periods or days in one report.
+const MaxReportPoints = 366
+
+// ErrInvalidReport wraps validation errors of report queries.
+var ErrInvalidReport = errors.New("invalid report query")
+
+// PeriodStart returns the start of the day, week or month of t in UTC. Weeks
+// start on Monday.
+func PeriodStart(t time.Time, period string) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch period {
+	case ReportWeek:
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case ReportMonth:
+		return day.AddDate(0, 0, 1-day.Day())
+	}
+	return day
+}
+
+// StatusChange records a task entering a status. A created task has an empty
+// From and a deleted task an empty To.
+type StatusChange struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id"`
+	TaskID    primitive.ObjectID `json:"task_id" bson:"task_id"`
+	CreatedBy primitive.ObjectID `json:"created_by" bson:"created_by"`
+	From      string             `json:"from" bson:"from"`
+	To        string             `json:"to" bson:"to"`
+	At        primitive.DateTime `json:"at" bson:"at"`
+	// OrgID is the Organization of the task, set by the repository
+	OrgID *primitive.ObjectID `json:"-" bson:"org_id,omitempty"`
+}
+
+// ReportQuery selects the tasks created by CreatedBy, or every task when it
+// is primitive.NilObjectID, over [From, To). Period is the length of the
+// buckets of a throughput report.
+type ReportQuery struct {
+	CreatedBy primitive.ObjectID
+	From      time.Time
+	To        time.Time
+	Period    string
+}
+
+// PeriodCount is the number of tasks created and completed in the period
+// starting at Start.
+type PeriodCount struct {
+	Start     time.Time `json:"start"`
+	Created   int       `json:"created"`
+	Completed int       `json:"completed"`
+}
+
+// TaskCycleTime is the time a task took from first entering "In Progress" to
+// its last completion.
+type TaskCycleTime struct {
+	TaskID      primitive.ObjectID `json:"task_id" bson:"_id"`
+	StartedAt   time.Time          `json:"started_at" bson:"started_at"`
+	CompletedAt time.Time          `json:"completed_at" bson:"completed_at"`
+	Hours       float64            `json:"hours" bson:"hours"`
+}
+
+// CycleTimeReport summarizes the cycle times of the tasks completed in a
+// report range.
+type CycleTimeReport struct {
+	Tasks         []TaskCycleTime `json:"tasks"`
+	AverageHours  float64         `json:"average_hours"`
+	MedianHours   float64         `json:"median_hours"`
+	LongestHours  float64         `json:"longest_hours"`
+	ShortestHours float64         `json:"shortest_hours"`
+}
+
+// UserOverdue is the number of overdue tasks of a user.
+type UserOverdue struct {
+	UserID   primitive.ObjectID `json:"user_id" bson:"_id"`
+	Username string             `json:"username,omitempty" bson:"-"`
+	Overdue  int                `json:"overdue" bson:"overdue"`
+}
+
+// BurndownPoint is the number of tasks open at the end of the day starting at
+// Date, and the number an even burndown would have left.
+type BurndownPoint struct {
+	Date      time.Time `json:"date"`
+	Remaining int       `json:"remaining"`
+	Ideal     float64   `json:"ideal"`
+}
+
+// StatusChangeRepository stores the status changes of tasks.
+type StatusChangeRepository interface {
+	AddStatusChange(ctx context.Context, change StatusChange) error
+}
+
+// ReportRepository aggregates the tasks and their status changes. Tasks
+// changed before status changes were recorded only count in the overdue
+// report.
+type ReportRepository interface {
+	AddStatusChange(ctx context.Context, change StatusChange) error
+	// Throughput counts the tasks created and completed in query per period,
+	// oldest first, leaving out empty periods.
+	Throughput(ctx context.Context, query ReportQuery) ([]PeriodCount, error)
+	// CycleTimes returns the cycle times of the tasks whose last completion
+	// is in query, in order of completion.
+	CycleTimes(ctx context.Context, query ReportQuery) ([]TaskCycleTime, error)
+	// OverdueByUser counts the unfinished tasks due before now per creator,
+	// most overdue first.
+	OverdueByUser(ctx context.Context, createdBy primitive.ObjectID, now time.Time) ([]UserOverdue, error)
+	// OpenTasks returns the number of tasks open at each of ends.
+	OpenTasks(ctx context.Context, createdBy primitive.ObjectID, ends []time.Time) ([]int, error)
+}
+
+// ReportUsecase builds reports. Users see reports on their own tasks; admins
+// and root users see them on every task or on the tasks of one user.
+type ReportUsecase interface {
+	Throughput(ctx context.Context, actor User, query ReportQuery) ([]PeriodCount, error)
+	CycleTime(ctx context.Context, actor User, query ReportQuery) (CycleTimeReport, error)
+	Overdue(ctx context.Context, actor User, query ReportQuery) ([]UserOverdue, error)
+	// Burndown returns a point per day of query.
+	Burndown(ctx context.Context, actor User, query ReportQuery) ([]BurndownPoint, error)
+}
@@ -1,8 +1,12 @@
 package domain
 
-import "go.mongodb.org/mongo-driver/bson/primitive"
-
+import (
+	"context"
+	"errors"
+	"time"
 
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
 
 type Task struct {
 	ID          primitive.ObjectID `json:"id" bson:"_id"`
@@ -11,24 +15,202 @@ type Task struct {
 	DueDate     primitive.DateTime `json:"due_date" bson:"due_date"`
 	Status      string             `json:"status" bson:"status"`
 	CreatedBy   primitive.ObjectID `json:"created_by" bson:"created_by"`
+	// OrgID is the Organization of the task's creator, set by the repository.
+	// It is nil for tasks of users without an organization
+	OrgID *primitive.ObjectID `json:"org_id,omitempty" bson:"org_id,omitempty"`
+	// Priority is one of the Priority levels, or empty
+	Priority string `json:"priority,omitempty" bson:"priority,omitempty"`
+	// StoryPoints and EstimateHours estimate the effort of the task
+	StoryPoints   float64 `json:"story_points,omitempty" bson:"story_points,omitempty"`
+	EstimateHours float64 `json:"estimate_hours,omitempty" bson:"estimate_hours,omitempty"`
+	// Rank orders the task on its creator's board. The repository ranks a new
+	// task after the other tasks of its creator; moving the task changes it.
+	Rank string `json:"rank,omitempty" bson:"rank,omitempty"`
+	// Labels are the IDs of the task's labels, changed through a LabelUsecase
+	Labels []primitive.ObjectID `json:"labels,omitempty" bson:"labels,omitempty"`
+	// Recurrence makes the task repeat; it requires a due date
+	Recurrence *Recurrence `json:"recurrence,omitempty" bson:"recurrence,omitempty"`
+	// SeriesID is the TaskSeries of a recurring task and Occurrence its
+	// number in the series, starting at 1
+	SeriesID   *primitive.ObjectID `json:"series_id,omitempty" bson:"series_id,omitempty"`
+	Occurrence int                 `json:"occurrence,omitempty" bson:"occurrence,omitempty"`
+	// UpdatedAt is set by the repository on every write
+	UpdatedAt primitive.DateTime `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
+	// Overdue is computed when the task is read and is never stored
+	Overdue bool `json:"overdue" bson:"-"`
+}
+
+// TaskInProgress is the status of a started task and TaskCompleted the
+// status of a finished task.
+const (
+	TaskInProgress = "In Progress"
+	TaskCompleted  = "Completed"
+)
+
+// Task priority levels, from lowest to highest. Tasks without a priority
+// count as PriorityMedium.
+const (
+	PriorityLow    = "low"
+	PriorityMedium = "medium"
+	PriorityHigh   = "high"
+	PriorityUrgent = "urgent"
+)
+
+// Upper bounds of the estimates of a task.
+const (
+	MaxStoryPoints   = 100
+	MaxEstimateHours = 1000
+)
+
+// IsOverdue reports whether the task is unfinished and past its due date at now.
+func (t Task) IsOverdue(now time.Time) bool {
+	return t.DueDate != 0 && t.Status != TaskCompleted && t.DueDate.Time().Before(now)
+}
+
+// Label match modes of a TaskFilter.
+const (
+	LabelMatchAll = "all"
+	LabelMatchAny = "any"
+)
+
+// TaskFilter selects tasks. Zero fields match every task.
+type TaskFilter struct {
+	CreatedBy primitive.ObjectID
+	Status    string
+	Labels    []primitive.ObjectID
+	// LabelMatch is LabelMatchAll for tasks carrying every label in Labels,
+	// or LabelMatchAny for tasks carrying at least one of them
+	LabelMatch string
+	// SeriesID selects the occurrences of a recurring task
+	SeriesID primitive.ObjectID
+}
+
+// Bulk task operation kinds.
+const (
+	BulkCreate = "create"
+	BulkUpdate = "update"
+	BulkDelete = "delete"
+	BulkStatus = "status"
+)
+
+// MaxBulkTaskOperations is the largest number of operations in one bulk request.
+const MaxBulkTaskOperations = 100
+
+var (
+	// ErrForbidden is returned when the caller may not change a task.
+	ErrForbidden = errors.New("forbidden")
+	// ErrInvalidTask wraps validation errors of task input.
+	ErrInvalidTask = errors.New("invalid task")
+	// ErrRolledBack reports a bulk operation undone because another one failed.
+	ErrRolledBack = errors.New("rolled back because another operation failed")
+)
+
+// BulkTaskOperation is one item of a bulk request. Create uses Task, update
+// applies Fields as a partial update, delete removes the task with ID and
+// status sets the task's Status.
+type BulkTaskOperation struct {
+	Op     string                 `json:"op"`
+	ID     string                 `json:"id,omitempty"`
+	Task   *Task                  `json:"task,omitempty"`
+	Fields map[string]interface{} `json:"fields,omitempty"`
+	Status string                 `json:"status,omitempty"`
+}
+
+// BulkTaskResult reports the outcome of one bulk operation. Status is the
+// HTTP status the equivalent single request would have returned.
+type BulkTaskResult struct {
+	Index  int    `json:"index"`
+	Op     string `json:"op"`
+	ID     string `json:"id,omitempty"`
+	Status int    `json:"status"`
+	Error  string `json:"error,omitempty"`
+	Err    error  `json:"-"`
+}
+
+// TaskAuthorizer returns an error wrapping ErrForbidden when the caller may
+// not change task.
+type TaskAuthorizer func(ctx context.Context, task Task) error
+
+// MaxImportRows is the largest number of rows in one import.
+const MaxImportRows = 1000
+
+// Import row outcomes.
+const (
+	ImportCreated = "created"
+	ImportValid   = "valid"
+	ImportInvalid = "invalid"
+	ImportFailed  = "failed"
+)
+
+// TaskImportRow is a parsed row of an import. Errors lists the problems found
+// while parsing it.
+type TaskImportRow struct {
+	Row    int
+	Task   Task
+	Errors []string
+}
+
+// TaskImportResult reports the outcome of one imported row. Status is
+// ImportCreated, ImportValid for a dry run, ImportInvalid, or ImportFailed
+// when a valid task could not be stored.
+type TaskImportResult struct {
+	Row    int      `json:"row"`
+	ID     string   `json:"id,omitempty"`
+	Status string   `json:"status"`
+	Errors []string `json:"errors,omitempty"`
 }
 
 type TaskRepository interface {
-	AddTask(task Task) error
-	GetTaskById(id primitive.ObjectID) (Task, error)
-	GetAllTasks() ([]Task, error)
-	GetMyTasks(userId primitive.ObjectID) ([]Task, error)
-	UpdateFullTask(id primitive.ObjectID, task Task) error
-	UpdateSomeTask(id primitive.ObjectID, task map[string]interface{}) error
-	DeleteTask(id primitive.ObjectID) error
+	AddTask(ctx context.Context, task Task) error
+	GetTaskById(ctx context.Context, id primitive.ObjectID) (Task, error)
+	GetAllTasks(ctx context.Context) ([]Task, error)
+	GetMyTasks(ctx context.Context, userId primitive.ObjectID) ([]Task, error)
+	UpdateFullTask(ctx context.Context, id primitive.ObjectID, task Task) error
+	UpdateSomeTask(ctx context.Context, id primitive.ObjectID, task map[string]interface{}) error
+	DeleteTask(ctx context.Context, id primitive.ObjectID) error
+	CountTasksByStatus(ctx context.Context) (map[string]int64, error)
+	// StreamTasks calls fn for every task created by createdBy, or for every
+	// task when createdBy is primitive.NilObjectID, without loading them all
+	// at once. It stops at the first error returned by fn.
+	StreamTasks(ctx context.Context, createdBy primitive.ObjectID, fn func(Task) error) error
+	// GetTasksDueBefore returns the unfinished tasks with a due date before before.
+	GetTasksDueBefore(ctx context.Context, before time.Time) ([]Task, error)
+	// FindTasks returns the tasks matching filter in creation order.
+	FindTasks(ctx context.Context, filter TaskFilter) ([]Task, error)
+	// RemoveLabel takes the label off every task carrying it.
+	RemoveLabel(ctx context.Context, labelID primitive.ObjectID) error
 }
 
 type TaskUsecase interface {
-	AddTask(task Task) error
-	GetTaskById(id primitive.ObjectID) (Task, error)
-	GetAllTasks() ([]Task, error)
-	GetMyTasks(userId primitive.ObjectID) ([]Task, error)
-	UpdateFullTask(id primitive.ObjectID, task Task) error
-	UpdateSomeTask(id primitive.ObjectID, task map[string]interface{}) error
-	DeleteTask(id primitive.ObjectID) error
-}
\ No newline at end of file
+	AddTask(ctx context.Context, task Task) error
+	GetTaskById(ctx context.Context, id primitive.ObjectID) (Task, error)
+	GetAllTasks(ctx context.Context) ([]Task, error)
+	GetMyTasks(ctx context.Context, userId primitive.ObjectID) ([]Task, error)
+	UpdateFullTask(ctx context.Context, id primitive.ObjectID, task Task) error
+	UpdateSomeTask(ctx context.Context, id primitive.ObjectID, task map[string]interface{}) error
+	DeleteTask(ctx context.Context, id primitive.ObjectID) error
+	// BulkTasks runs ops in order. With atomic set, every change is rolled
+	// back if any operation fails.
+	BulkTasks(ctx context.Context, ops []BulkTaskOperation, atomic bool, authorize TaskAuthorizer) ([]BulkTaskResult, error)
+	// ExportTasks calls fn for every task created by createdBy, or for every
+	// task when createdBy is primitive.NilObjectID.
+	ExportTasks(ctx context.Context, createdBy primitive.ObjectID, fn func(Task) error) error
+	// ImportTasks validates every row and creates the valid ones unless dryRun is set.
+	ImportTasks(ctx context.Context, rows []TaskImportRow, dryRun bool) ([]TaskImportResult, error)
+	// FindTasks returns the tasks matching filter.
+	FindTasks(ctx context.Context, filter TaskFilter) ([]Task, error)
+	// SetTaskLabels replaces the labels of a task. The labels are not checked.
+	SetTaskLabels(ctx context.Context, id primitive.ObjectID, labels []primitive.ObjectID) error
+	// UpdateFutureOccurrences applies fields to the recurring task id, to the
+	// later occurrences already generated and to the ones generated from now on.
+	UpdateFutureOccurrences(ctx context.Context, id primitive.ObjectID, fields map[string]interface{}) error
+	// GetBoard returns the tasks created by createdBy in a column per status.
+	GetBoard(ctx context.Context, createdBy primitive.ObjectID) (Board, error)
+	// MoveTask changes the status and the rank of the task id in one write and
+	// returns the moved task.
+	MoveTask(ctx context.Context, id primitive.ObjectID, move TaskMove) (Task, error)
+	// GenerateDueOccurrences generates the next occurrence of every recurring
+	// task whose latest occurrence is due before now, and returns how many
+	// were generated.
+	GenerateDueOccurrences(ctx context.Context, now time.Time) (int, error)
+}
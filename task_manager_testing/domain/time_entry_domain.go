@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	// ErrTimerRunning is returned when a user starts a timer while another
+	// one of theirs is running.
+	ErrTimerRunning = errors.New("a timer is already running")
+	// ErrTimerNotRunning is returned when a user stops a timer they have not
+	// started on the task.
+	ErrTimerNotRunning = errors.New("no timer is running on this task")
+	// ErrInvalidTimeQuery wraps validation errors of time report queries.
+	ErrInvalidTimeQuery = errors.New("invalid time query")
+)
+
+// TimeEntry is time a user tracked on a task with a timer. A user has at most
+// one running entry.
+type TimeEntry struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id"`
+	TaskID    primitive.ObjectID `json:"task_id" bson:"task_id"`
+	UserID    primitive.ObjectID `json:"user_id" bson:"user_id"`
+	StartedAt primitive.DateTime `json:"started_at" bson:"started_at"`
+	StoppedAt primitive.DateTime `json:"stopped_at,omitempty" bson:"stopped_at,omitempty"`
+	Running   bool               `json:"running" bson:"running"`
+	// Seconds is the tracked time, set when the entry is stopped
+	Seconds int64 `json:"seconds" bson:"seconds"`
+	// OrgID is the Organization of the user, set by the repository
+	OrgID *primitive.ObjectID `json:"-" bson:"org_id,omitempty"`
+}
+
+// UserTime is the time a user tracked.
+type UserTime struct {
+	UserID   primitive.ObjectID `json:"user_id" bson:"_id"`
+	Username string             `json:"username,omitempty" bson:"-"`
+	Seconds  int64              `json:"seconds" bson:"seconds"`
+}
+
+// WeekTime is the time tracked in an ISO 8601 week.
+type WeekTime struct {
+	Year    int   `json:"year"`
+	Week    int   `json:"week"`
+	Seconds int64 `json:"seconds"`
+}
+
+// TaskTime is the time tracked on a task, in total and per user. Running
+// entries count up to the time of the request.
+type TaskTime struct {
+	Entries []TimeEntry `json:"entries"`
+	Users   []UserTime  `json:"users"`
+	Seconds int64       `json:"seconds"`
+}
+
+// TimeQuery selects the stopped entries of UserID, or of every user of the
+// organization of the request when it is primitive.NilObjectID, started in
+// [From, To). Zero times are unbounded.
+type TimeQuery struct {
+	UserID primitive.ObjectID
+	From   time.Time
+	To     time.Time
+}
+
+type TimeEntryRepository interface {
+	// StartEntry stores a running entry, or returns ErrTimerRunning when its
+	// user already has one.
+	StartEntry(ctx context.Context, entry TimeEntry) error
+	// StopEntry stops the running entry of userID on taskID at stoppedAt, or
+	// returns ErrTimerNotRunning.
+	StopEntry(ctx context.Context, userID, taskID primitive.ObjectID, stoppedAt time.Time) (TimeEntry, error)
+	// GetEntries returns the entries of the task, oldest first.
+	GetEntries(ctx context.Context, taskID primitive.ObjectID) ([]TimeEntry, error)
+	// TimeByUser returns the time of the entries matching query per user,
+	// most time first.
+	TimeByUser(ctx context.Context, query TimeQuery) ([]UserTime, error)
+	// TimeByWeek returns the time of the entries matching query per week of
+	// their start, oldest week first.
+	TimeByWeek(ctx context.Context, query TimeQuery) ([]WeekTime, error)
+}
+
+// TimeEntryUsecase tracks time on the tasks actor may see, which other tasks
+// are reported as mongo.ErrNoDocuments. Users see their own tracked time;
+// admins and root users see everyone's.
+type TimeEntryUsecase interface {
+	StartTimer(ctx context.Context, actor User, taskID primitive.ObjectID) (TimeEntry, error)
+	StopTimer(ctx context.Context, actor User, taskID primitive.ObjectID) (TimeEntry, error)
+	// GetTaskTime returns the entries of the task with their totals.
+	GetTaskTime(ctx context.Context, actor User, taskID primitive.ObjectID) (TaskTime, error)
+	// TimeByUser returns the time tracked per user.
+	TimeByUser(ctx context.Context, actor User, query TimeQuery) ([]UserTime, error)
+	// TimeByWeek returns the time tracked per week.
+	TimeByWeek(ctx context.Context, actor User, query TimeQuery) ([]WeekTime, error)
+}
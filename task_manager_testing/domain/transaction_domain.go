@@ -0,0 +1,11 @@
+package domain
+
+import "context"
+
+// Transactor runs a function inside a database transaction. Repository calls
+// made with the context passed to fn take part in the transaction, and every
+// change is rolled back when fn returns an error. fn may be called more than
+// once when the transaction is retried.
+type Transactor interface {
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -1,31 +1,60 @@
 package domain
 
-import "go.mongodb.org/mongo-driver/bson/primitive"
+import (
+	"context"
+	"errors"
 
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
 
+// ErrInvalidPassword is returned when a supplied password does not match the stored one.
+var ErrInvalidPassword = errors.New("the provided password is incorrect")
+
+// ErrEmptyPassword is returned when a new password is blank.
+var ErrEmptyPassword = errors.New("password cannot be empty")
+
+// ErrUserExists is returned when the username is taken. Usernames are unique
+// across organizations because users log in by username alone.
+var ErrUserExists = errors.New("username already exists")
 
 type User struct {
 	ID       primitive.ObjectID `json:"id" bson:"_id"`
 	Username string             `json:"username"`
 	Password string             `json:"password"`
 	Role     string             `json:"role"`
+	// OrgID is the user's Organization, set by the repository from the
+	// organization the user registered in, or nil
+	OrgID *primitive.ObjectID `json:"org_id,omitempty" bson:"org_id,omitempty"`
+	// CalendarToken is the SHA-256 hash of the secret in the user's calendar feed URL
+	CalendarToken string `json:"-" bson:"calendar_token,omitempty"`
 }
 
 type UserRepository interface {
-	RegisterUser(username, password, role string) error
-	Login(username, password string) (User, error)
-	UpdateUser(id primitive.ObjectID, user User) error
-	GetUserById(id primitive.ObjectID) (User, error)
-	DeleteUser(id primitive.ObjectID) error
-	GetAllUsers() ([]User, error)
+	// RegisterUser stores a new user, or returns ErrUserExists when the
+	// username is taken in any organization.
+	RegisterUser(ctx context.Context, username, password, role string) error
+	Login(ctx context.Context, username, password string) (User, error)
+	UpdateUser(ctx context.Context, id primitive.ObjectID, user User) error
+	GetUserById(ctx context.Context, id primitive.ObjectID) (User, error)
+	DeleteUser(ctx context.Context, id primitive.ObjectID) error
+	GetAllUsers(ctx context.Context) ([]User, error)
+	// GetUserByCalendarToken finds the user whose calendar token hash is tokenHash.
+	GetUserByCalendarToken(ctx context.Context, tokenHash string) (User, error)
+	GetUserByUsername(ctx context.Context, username string) (User, error)
 }
 
 
 type UserUsecase interface {
-	RegisterUser(username, password, role string) error
-	Login(username, password string) (User, error)
-	UpdateUser(id primitive.ObjectID, user User) error
-	GetUserById(id primitive.ObjectID) (User, error)
-	DeleteUser(id primitive.ObjectID) error
-	GetAllUsers() ([]User, error)
-}
\ No newline at end of file
+	RegisterUser(ctx context.Context, username, password, role string) error
+	Login(ctx context.Context, username, password string) (User, error)
+	UpdateUser(ctx context.Context, id primitive.ObjectID, user User) error
+	ChangePassword(ctx context.Context, id primitive.ObjectID, oldPassword, newPassword string) error
+	GetUserById(ctx context.Context, id primitive.ObjectID) (User, error)
+	DeleteUser(ctx context.Context, id primitive.ObjectID) error
+	GetAllUsers(ctx context.Context) ([]User, error)
+	// RotateCalendarToken issues a new calendar feed token, invalidating the old one.
+	RotateCalendarToken(ctx context.Context, id primitive.ObjectID) (string, error)
+	// RevokeCalendarToken disables the user's calendar feed.
+	RevokeCalendarToken(ctx context.Context, id primitive.ObjectID) error
+	GetUserByCalendarToken(ctx context.Context, token string) (User, error)
+}
@@ -0,0 +1,142 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrInvalidWebhook wraps validation errors of webhook input.
+var ErrInvalidWebhook = errors.New("invalid webhook")
+
+// Webhook delivery states.
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+)
+
+// MaxWebhookDeliveriesPage is the largest number of deliveries listed at once.
+const MaxWebhookDeliveriesPage = 100
+
+// Webhook subscribes a URL to task events. Users receive the events of their
+// own tasks; webhooks with AllTasks, which only admins can create, receive
+// every event of the owner's organization, or of every organization with
+// AllOrgs, which only root users' webhooks have.
+type Webhook struct {
+	ID      primitive.ObjectID `json:"id" bson:"_id"`
+	OwnerID primitive.ObjectID `json:"owner_id" bson:"owner_id"`
+	URL     string             `json:"url" bson:"url"`
+	// Secret signs the deliveries; it is only shown when the webhook is created
+	Secret   string   `json:"-" bson:"secret"`
+	Events   []string `json:"events" bson:"events"`
+	AllTasks bool     `json:"all_tasks" bson:"all_tasks"`
+	AllOrgs  bool     `json:"all_orgs" bson:"all_orgs"`
+	// OrgID is the owner's organization, nil for users without one
+	OrgID     *primitive.ObjectID `json:"-" bson:"org_id,omitempty"`
+	Active    bool                `json:"active" bson:"active"`
+	CreatedAt primitive.DateTime  `json:"created_at" bson:"created_at"`
+}
+
+// Wants reports whether the webhook should receive event.
+func (w Webhook) Wants(event TaskEvent) bool {
+	if !w.Active || (!w.AllTasks && event.Task.CreatedBy != w.OwnerID) {
+		return false
+	}
+	if !w.AllOrgs && !SameOrg(w.OrgID, event.Task.OrgID) {
+		return false
+	}
+	for _, eventType := range w.Events {
+		if eventType == event.Type {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookUpdate is a partial update of a webhook; nil fields are unchanged.
+type WebhookUpdate struct {
+	URL    *string   `json:"url"`
+	Events *[]string `json:"events"`
+	Active *bool     `json:"active"`
+}
+
+// WebhookAttempt records one try to deliver an event.
+type WebhookAttempt struct {
+	At           primitive.DateTime `json:"at" bson:"at"`
+	StatusCode   int                `json:"status_code,omitempty" bson:"status_code,omitempty"`
+	Error        string             `json:"error,omitempty" bson:"error,omitempty"`
+	DurationMsec int64              `json:"duration_ms" bson:"duration_ms"`
+}
+
+// WebhookDelivery is one event sent to one webhook, retried until it succeeds
+// or runs out of attempts.
+type WebhookDelivery struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id"`
+	WebhookID primitive.ObjectID `json:"webhook_id" bson:"webhook_id"`
+	EventID   string             `json:"event_id" bson:"event_id"`
+	Event     string             `json:"event" bson:"event"`
+	// Payload is the exact body sent, so redeliveries are byte-identical
+	Payload       string             `json:"payload" bson:"payload"`
+	Status        string             `json:"status" bson:"status"`
+	Attempts      []WebhookAttempt   `json:"attempts" bson:"attempts"`
+	NextAttemptAt primitive.DateTime `json:"next_attempt_at,omitempty" bson:"next_attempt_at,omitempty"`
+	CreatedAt     primitive.DateTime `json:"created_at" bson:"created_at"`
+	// RedeliveryOf is the delivery this one repeats
+	RedeliveryOf *primitive.ObjectID `json:"redelivery_of,omitempty" bson:"redelivery_of,omitempty"`
+}
+
+// WebhookRequest is a signed HTTP request delivering an event.
+type WebhookRequest struct {
+	URL        string
+	Secret     string
+	Event      string
+	DeliveryID string
+	Payload    []byte
+}
+
+// WebhookSender sends webhook requests. It returns the response status code,
+// or an error when no response was received.
+type WebhookSender interface {
+	SendWebhook(ctx context.Context, request WebhookRequest) (int, error)
+}
+
+type WebhookRepository interface {
+	CreateWebhook(ctx context.Context, webhook Webhook) error
+	GetWebhook(ctx context.Context, id primitive.ObjectID) (Webhook, error)
+	GetWebhooks(ctx context.Context, ownerID primitive.ObjectID) ([]Webhook, error)
+	// GetWebhooksForEvent returns the active webhooks subscribed to eventType.
+	GetWebhooksForEvent(ctx context.Context, eventType string) ([]Webhook, error)
+	UpdateWebhook(ctx context.Context, webhook Webhook) error
+	// DeleteWebhook removes the webhook and its deliveries.
+	DeleteWebhook(ctx context.Context, id primitive.ObjectID) error
+
+	CreateDelivery(ctx context.Context, delivery WebhookDelivery) error
+	GetDelivery(ctx context.Context, id primitive.ObjectID) (WebhookDelivery, error)
+	// GetDeliveries returns the webhook's newest deliveries first.
+	GetDeliveries(ctx context.Context, webhookID primitive.ObjectID, limit int) ([]WebhookDelivery, error)
+	// ClaimDueDelivery picks a pending delivery whose next attempt is due at
+	// now and postpones it by lease, so that no other worker sends it at the
+	// same time. It returns mongo.ErrNoDocuments when nothing is due.
+	ClaimDueDelivery(ctx context.Context, now time.Time, lease time.Duration) (WebhookDelivery, error)
+	UpdateDelivery(ctx context.Context, delivery WebhookDelivery) error
+}
+
+type WebhookUsecase interface {
+	TaskEventPublisher
+	// CreateWebhook validates and stores webhook, generating its ID and a
+	// secret when none is given, and returns the stored webhook with its secret.
+	CreateWebhook(ctx context.Context, webhook Webhook) (Webhook, error)
+	GetWebhooks(ctx context.Context, ownerID primitive.ObjectID) ([]Webhook, error)
+	// GetWebhook returns mongo.ErrNoDocuments for webhooks of other users.
+	GetWebhook(ctx context.Context, ownerID, id primitive.ObjectID) (Webhook, error)
+	UpdateWebhook(ctx context.Context, ownerID, id primitive.ObjectID, update WebhookUpdate) (Webhook, error)
+	DeleteWebhook(ctx context.Context, ownerID, id primitive.ObjectID) error
+	GetDeliveries(ctx context.Context, ownerID, webhookID primitive.ObjectID, limit int) ([]WebhookDelivery, error)
+	// Redeliver queues a new delivery with the payload of an earlier one.
+	Redeliver(ctx context.Context, ownerID, webhookID, deliveryID primitive.ObjectID) (WebhookDelivery, error)
+	// DeliverDue sends the deliveries due at now and returns how many were attempted.
+	DeliverDue(ctx context.Context, now time.Time) (int, error)
+}
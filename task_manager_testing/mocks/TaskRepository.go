@@ -3,11 +3,15 @@
 package mocks
 
 import (
+	context "context"
+
 	domain "task_manager_testing/domain"
 
 	mock "github.com/stretchr/testify/mock"
 
 	primitive "go.mongodb.org/mongo-driver/bson/primitive"
+
+	time "time"
 )
 
 // TaskRepository is an autogenerated mock type for the TaskRepository type
@@ -15,17 +19,17 @@ type TaskRepository struct {
 	mock.Mock
 }
 
-// AddTask provides a mock function with given fields: task
-func (_m *TaskRepository) AddTask(task domain.Task) error {
-	ret := _m.Called(task)
+// AddTask provides a mock function with given fields: ctx, task
+func (_m *TaskRepository) AddTask(ctx context.Context, task domain.Task) error {
+	ret := _m.Called(ctx, task)
 
 	if len(ret) == 0 {
 		panic("no return value specified for AddTask")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(domain.Task) error); ok {
-		r0 = rf(task)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Task) error); ok {
+		r0 = rf(ctx, task)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -33,17 +37,47 @@ func (_m *TaskRepository) AddTask(task domain.Task) error {
 	return r0
 }
 
-// DeleteTask provides a mock function with given fields: id
-func (_m *TaskRepository) DeleteTask(id primitive.ObjectID) error {
-	ret := _m.Called(id)
+// CountTasksByStatus provides a mock function with given fields: ctx
+func (_m *TaskRepository) CountTasksByStatus(ctx context.Context) (map[string]int64, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountTasksByStatus")
+	}
+
+	var r0 map[string]int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (map[string]int64, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) map[string]int64); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]int64)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteTask provides a mock function with given fields: ctx, id
+func (_m *TaskRepository) DeleteTask(ctx context.Context, id primitive.ObjectID) error {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for DeleteTask")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(primitive.ObjectID) error); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) error); ok {
+		r0 = rf(ctx, id)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -51,9 +85,39 @@ func (_m *TaskRepository) DeleteTask(id primitive.ObjectID) error {
 	return r0
 }
 
-// GetAllTasks provides a mock function with given fields:
-func (_m *TaskRepository) GetAllTasks() ([]domain.Task, error) {
-	ret := _m.Called()
+// FindTasks provides a mock function with given fields: ctx, filter
+func (_m *TaskRepository) FindTasks(ctx context.Context, filter domain.TaskFilter) ([]domain.Task, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindTasks")
+	}
+
+	var r0 []domain.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.TaskFilter) ([]domain.Task, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.TaskFilter) []domain.Task); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.TaskFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAllTasks provides a mock function with given fields: ctx
+func (_m *TaskRepository) GetAllTasks(ctx context.Context) ([]domain.Task, error) {
+	ret := _m.Called(ctx)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetAllTasks")
@@ -61,19 +125,19 @@ func (_m *TaskRepository) GetAllTasks() ([]domain.Task, error) {
 
 	var r0 []domain.Task
 	var r1 error
-	if rf, ok := ret.Get(0).(func() ([]domain.Task, error)); ok {
-		return rf()
+	if rf, ok := ret.Get(0).(func(context.Context) ([]domain.Task, error)); ok {
+		return rf(ctx)
 	}
-	if rf, ok := ret.Get(0).(func() []domain.Task); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) []domain.Task); ok {
+		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]domain.Task)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -81,9 +145,9 @@ func (_m *TaskRepository) GetAllTasks() ([]domain.Task, error) {
 	return r0, r1
 }
 
-// GetMyTasks provides a mock function with given fields: userId
-func (_m *TaskRepository) GetMyTasks(userId primitive.ObjectID) ([]domain.Task, error) {
-	ret := _m.Called(userId)
+// GetMyTasks provides a mock function with given fields: ctx, userId
+func (_m *TaskRepository) GetMyTasks(ctx context.Context, userId primitive.ObjectID) ([]domain.Task, error) {
+	ret := _m.Called(ctx, userId)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetMyTasks")
@@ -91,19 +155,19 @@ func (_m *TaskRepository) GetMyTasks(userId primitive.ObjectID) ([]domain.Task,
 
 	var r0 []domain.Task
 	var r1 error
-	if rf, ok := ret.Get(0).(func(primitive.ObjectID) ([]domain.Task, error)); ok {
-		return rf(userId)
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) ([]domain.Task, error)); ok {
+		return rf(ctx, userId)
 	}
-	if rf, ok := ret.Get(0).(func(primitive.ObjectID) []domain.Task); ok {
-		r0 = rf(userId)
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) []domain.Task); ok {
+		r0 = rf(ctx, userId)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]domain.Task)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(primitive.ObjectID) error); ok {
-		r1 = rf(userId)
+	if rf, ok := ret.Get(1).(func(context.Context, primitive.ObjectID) error); ok {
+		r1 = rf(ctx, userId)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -111,9 +175,9 @@ func (_m *TaskRepository) GetMyTasks(userId primitive.ObjectID) ([]domain.Task,
 	return r0, r1
 }
 
-// GetTaskById provides a mock function with given fields: id
-func (_m *TaskRepository) GetTaskById(id primitive.ObjectID) (domain.Task, error) {
-	ret := _m.Called(id)
+// GetTaskById provides a mock function with given fields: ctx, id
+func (_m *TaskRepository) GetTaskById(ctx context.Context, id primitive.ObjectID) (domain.Task, error) {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetTaskById")
@@ -121,17 +185,47 @@ func (_m *TaskRepository) GetTaskById(id primitive.ObjectID) (domain.Task, error
 
 	var r0 domain.Task
 	var r1 error
-	if rf, ok := ret.Get(0).(func(primitive.ObjectID) (domain.Task, error)); ok {
-		return rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) (domain.Task, error)); ok {
+		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(primitive.ObjectID) domain.Task); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) domain.Task); ok {
+		r0 = rf(ctx, id)
 	} else {
 		r0 = ret.Get(0).(domain.Task)
 	}
 
-	if rf, ok := ret.Get(1).(func(primitive.ObjectID) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(context.Context, primitive.ObjectID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTasksDueBefore provides a mock function with given fields: ctx, before
+func (_m *TaskRepository) GetTasksDueBefore(ctx context.Context, before time.Time) ([]domain.Task, error) {
+	ret := _m.Called(ctx, before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTasksDueBefore")
+	}
+
+	var r0 []domain.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) ([]domain.Task, error)); ok {
+		return rf(ctx, before)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) []domain.Task); ok {
+		r0 = rf(ctx, before)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, before)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -139,17 +233,53 @@ func (_m *TaskRepository) GetTaskById(id primitive.ObjectID) (domain.Task, error
 	return r0, r1
 }
 
-// UpdateFullTask provides a mock function with given fields: id, task
-func (_m *TaskRepository) UpdateFullTask(id primitive.ObjectID, task domain.Task) error {
-	ret := _m.Called(id, task)
+// RemoveLabel provides a mock function with given fields: ctx, labelID
+func (_m *TaskRepository) RemoveLabel(ctx context.Context, labelID primitive.ObjectID) error {
+	ret := _m.Called(ctx, labelID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveLabel")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) error); ok {
+		r0 = rf(ctx, labelID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StreamTasks provides a mock function with given fields: ctx, createdBy, fn
+func (_m *TaskRepository) StreamTasks(ctx context.Context, createdBy primitive.ObjectID, fn func(domain.Task) error) error {
+	ret := _m.Called(ctx, createdBy, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StreamTasks")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID, func(domain.Task) error) error); ok {
+		r0 = rf(ctx, createdBy, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateFullTask provides a mock function with given fields: ctx, id, task
+func (_m *TaskRepository) UpdateFullTask(ctx context.Context, id primitive.ObjectID, task domain.Task) error {
+	ret := _m.Called(ctx, id, task)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateFullTask")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(primitive.ObjectID, domain.Task) error); ok {
-		r0 = rf(id, task)
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID, domain.Task) error); ok {
+		r0 = rf(ctx, id, task)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -157,17 +287,17 @@ func (_m *TaskRepository) UpdateFullTask(id primitive.ObjectID, task domain.Task
 	return r0
 }
 
-// UpdateSomeTask provides a mock function with given fields: id, task
-func (_m *TaskRepository) UpdateSomeTask(id primitive.ObjectID, task map[string]interface{}) error {
-	ret := _m.Called(id, task)
+// UpdateSomeTask provides a mock function with given fields: ctx, id, task
+func (_m *TaskRepository) UpdateSomeTask(ctx context.Context, id primitive.ObjectID, task map[string]interface{}) error {
+	ret := _m.Called(ctx, id, task)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateSomeTask")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(primitive.ObjectID, map[string]interface{}) error); ok {
-		r0 = rf(id, task)
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID, map[string]interface{}) error); ok {
+		r0 = rf(ctx, id, task)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -3,11 +3,15 @@
 package mocks
 
 import (
+	context "context"
+
 	domain "task_manager_testing/domain"
 
 	mock "github.com/stretchr/testify/mock"
 
 	primitive "go.mongodb.org/mongo-driver/bson/primitive"
+
+	time "time"
 )
 
 // TaskUsecase is an autogenerated mock type for the TaskUsecase type
@@ -15,17 +19,17 @@ type TaskUsecase struct {
 	mock.Mock
 }
 
-// AddTask provides a mock function with given fields: task
-func (_m *TaskUsecase) AddTask(task domain.Task) error {
-	ret := _m.Called(task)
+// AddTask provides a mock function with given fields: ctx, task
+func (_m *TaskUsecase) AddTask(ctx context.Context, task domain.Task) error {
+	ret := _m.Called(ctx, task)
 
 	if len(ret) == 0 {
 		panic("no return value specified for AddTask")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(domain.Task) error); ok {
-		r0 = rf(task)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Task) error); ok {
+		r0 = rf(ctx, task)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -33,17 +37,65 @@ func (_m *TaskUsecase) AddTask(task domain.Task) error {
 	return r0
 }
 
-// DeleteTask provides a mock function with given fields: id
-func (_m *TaskUsecase) DeleteTask(id primitive.ObjectID) error {
-	ret := _m.Called(id)
+// BulkTasks provides a mock function with given fields: ctx, ops, atomic, authorize
+func (_m *TaskUsecase) BulkTasks(ctx context.Context, ops []domain.BulkTaskOperation, atomic bool, authorize domain.TaskAuthorizer) ([]domain.BulkTaskResult, error) {
+	ret := _m.Called(ctx, ops, atomic, authorize)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BulkTasks")
+	}
+
+	var r0 []domain.BulkTaskResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []domain.BulkTaskOperation, bool, domain.TaskAuthorizer) ([]domain.BulkTaskResult, error)); ok {
+		return rf(ctx, ops, atomic, authorize)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []domain.BulkTaskOperation, bool, domain.TaskAuthorizer) []domain.BulkTaskResult); ok {
+		r0 = rf(ctx, ops, atomic, authorize)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.BulkTaskResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []domain.BulkTaskOperation, bool, domain.TaskAuthorizer) error); ok {
+		r1 = rf(ctx, ops, atomic, authorize)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteTask provides a mock function with given fields: ctx, id
+func (_m *TaskUsecase) DeleteTask(ctx context.Context, id primitive.ObjectID) error {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for DeleteTask")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(primitive.ObjectID) error); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ExportTasks provides a mock function with given fields: ctx, createdBy, fn
+func (_m *TaskUsecase) ExportTasks(ctx context.Context, createdBy primitive.ObjectID, fn func(domain.Task) error) error {
+	ret := _m.Called(ctx, createdBy, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExportTasks")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID, func(domain.Task) error) error); ok {
+		r0 = rf(ctx, createdBy, fn)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -51,9 +103,67 @@ func (_m *TaskUsecase) DeleteTask(id primitive.ObjectID) error {
 	return r0
 }
 
-// GetAllTasks provides a mock function with given fields:
-func (_m *TaskUsecase) GetAllTasks() ([]domain.Task, error) {
-	ret := _m.Called()
+// FindTasks provides a mock function with given fields: ctx, filter
+func (_m *TaskUsecase) FindTasks(ctx context.Context, filter domain.TaskFilter) ([]domain.Task, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindTasks")
+	}
+
+	var r0 []domain.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.TaskFilter) ([]domain.Task, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.TaskFilter) []domain.Task); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.TaskFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GenerateDueOccurrences provides a mock function with given fields: ctx, now
+func (_m *TaskUsecase) GenerateDueOccurrences(ctx context.Context, now time.Time) (int, error) {
+	ret := _m.Called(ctx, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GenerateDueOccurrences")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int, error)); ok {
+		return rf(ctx, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int); ok {
+		r0 = rf(ctx, now)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAllTasks provides a mock function with given fields: ctx
+func (_m *TaskUsecase) GetAllTasks(ctx context.Context) ([]domain.Task, error) {
+	ret := _m.Called(ctx)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetAllTasks")
@@ -61,19 +171,47 @@ func (_m *TaskUsecase) GetAllTasks() ([]domain.Task, error) {
 
 	var r0 []domain.Task
 	var r1 error
-	if rf, ok := ret.Get(0).(func() ([]domain.Task, error)); ok {
-		return rf()
+	if rf, ok := ret.Get(0).(func(context.Context) ([]domain.Task, error)); ok {
+		return rf(ctx)
 	}
-	if rf, ok := ret.Get(0).(func() []domain.Task); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) []domain.Task); ok {
+		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]domain.Task)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBoard provides a mock function with given fields: ctx, createdBy
+func (_m *TaskUsecase) GetBoard(ctx context.Context, createdBy primitive.ObjectID) (domain.Board, error) {
+	ret := _m.Called(ctx, createdBy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBoard")
+	}
+
+	var r0 domain.Board
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) (domain.Board, error)); ok {
+		return rf(ctx, createdBy)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) domain.Board); ok {
+		r0 = rf(ctx, createdBy)
+	} else {
+		r0 = ret.Get(0).(domain.Board)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, primitive.ObjectID) error); ok {
+		r1 = rf(ctx, createdBy)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -81,9 +219,9 @@ func (_m *TaskUsecase) GetAllTasks() ([]domain.Task, error) {
 	return r0, r1
 }
 
-// GetMyTasks provides a mock function with given fields: userId
-func (_m *TaskUsecase) GetMyTasks(userId primitive.ObjectID) ([]domain.Task, error) {
-	ret := _m.Called(userId)
+// GetMyTasks provides a mock function with given fields: ctx, userId
+func (_m *TaskUsecase) GetMyTasks(ctx context.Context, userId primitive.ObjectID) ([]domain.Task, error) {
+	ret := _m.Called(ctx, userId)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetMyTasks")
@@ -91,19 +229,19 @@ func (_m *TaskUsecase) GetMyTasks(userId primitive.ObjectID) ([]domain.Task, err
 
 	var r0 []domain.Task
 	var r1 error
-	if rf, ok := ret.Get(0).(func(primitive.ObjectID) ([]domain.Task, error)); ok {
-		return rf(userId)
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) ([]domain.Task, error)); ok {
+		return rf(ctx, userId)
 	}
-	if rf, ok := ret.Get(0).(func(primitive.ObjectID) []domain.Task); ok {
-		r0 = rf(userId)
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) []domain.Task); ok {
+		r0 = rf(ctx, userId)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]domain.Task)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(primitive.ObjectID) error); ok {
-		r1 = rf(userId)
+	if rf, ok := ret.Get(1).(func(context.Context, primitive.ObjectID) error); ok {
+		r1 = rf(ctx, userId)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -111,9 +249,9 @@ func (_m *TaskUsecase) GetMyTasks(userId primitive.ObjectID) ([]domain.Task, err
 	return r0, r1
 }
 
-// GetTaskById provides a mock function with given fields: id
-func (_m *TaskUsecase) GetTaskById(id primitive.ObjectID) (domain.Task, error) {
-	ret := _m.Called(id)
+// GetTaskById provides a mock function with given fields: ctx, id
+func (_m *TaskUsecase) GetTaskById(ctx context.Context, id primitive.ObjectID) (domain.Task, error) {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetTaskById")
@@ -121,17 +259,75 @@ func (_m *TaskUsecase) GetTaskById(id primitive.ObjectID) (domain.Task, error) {
 
 	var r0 domain.Task
 	var r1 error
-	if rf, ok := ret.Get(0).(func(primitive.ObjectID) (domain.Task, error)); ok {
-		return rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) (domain.Task, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) domain.Task); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(domain.Task)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, primitive.ObjectID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ImportTasks provides a mock function with given fields: ctx, rows, dryRun
+func (_m *TaskUsecase) ImportTasks(ctx context.Context, rows []domain.TaskImportRow, dryRun bool) ([]domain.TaskImportResult, error) {
+	ret := _m.Called(ctx, rows, dryRun)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ImportTasks")
+	}
+
+	var r0 []domain.TaskImportResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []domain.TaskImportRow, bool) ([]domain.TaskImportResult, error)); ok {
+		return rf(ctx, rows, dryRun)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []domain.TaskImportRow, bool) []domain.TaskImportResult); ok {
+		r0 = rf(ctx, rows, dryRun)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.TaskImportResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []domain.TaskImportRow, bool) error); ok {
+		r1 = rf(ctx, rows, dryRun)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MoveTask provides a mock function with given fields: ctx, id, move
+func (_m *TaskUsecase) MoveTask(ctx context.Context, id primitive.ObjectID, move domain.TaskMove) (domain.Task, error) {
+	ret := _m.Called(ctx, id, move)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MoveTask")
+	}
+
+	var r0 domain.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID, domain.TaskMove) (domain.Task, error)); ok {
+		return rf(ctx, id, move)
 	}
-	if rf, ok := ret.Get(0).(func(primitive.ObjectID) domain.Task); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID, domain.TaskMove) domain.Task); ok {
+		r0 = rf(ctx, id, move)
 	} else {
 		r0 = ret.Get(0).(domain.Task)
 	}
 
-	if rf, ok := ret.Get(1).(func(primitive.ObjectID) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(context.Context, primitive.ObjectID, domain.TaskMove) error); ok {
+		r1 = rf(ctx, id, move)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -139,17 +335,53 @@ func (_m *TaskUsecase) GetTaskById(id primitive.ObjectID) (domain.Task, error) {
 	return r0, r1
 }
 
-// UpdateFullTask provides a mock function with given fields: id, task
-func (_m *TaskUsecase) UpdateFullTask(id primitive.ObjectID, task domain.Task) error {
-	ret := _m.Called(id, task)
+// SetTaskLabels provides a mock function with given fields: ctx, id, labels
+func (_m *TaskUsecase) SetTaskLabels(ctx context.Context, id primitive.ObjectID, labels []primitive.ObjectID) error {
+	ret := _m.Called(ctx, id, labels)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetTaskLabels")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID, []primitive.ObjectID) error); ok {
+		r0 = rf(ctx, id, labels)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateFullTask provides a mock function with given fields: ctx, id, task
+func (_m *TaskUsecase) UpdateFullTask(ctx context.Context, id primitive.ObjectID, task domain.Task) error {
+	ret := _m.Called(ctx, id, task)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateFullTask")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(primitive.ObjectID, domain.Task) error); ok {
-		r0 = rf(id, task)
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID, domain.Task) error); ok {
+		r0 = rf(ctx, id, task)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateFutureOccurrences provides a mock function with given fields: ctx, id, fields
+func (_m *TaskUsecase) UpdateFutureOccurrences(ctx context.Context, id primitive.ObjectID, fields map[string]interface{}) error {
+	ret := _m.Called(ctx, id, fields)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateFutureOccurrences")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID, map[string]interface{}) error); ok {
+		r0 = rf(ctx, id, fields)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -157,17 +389,17 @@ func (_m *TaskUsecase) UpdateFullTask(id primitive.ObjectID, task domain.Task) e
 	return r0
 }
 
-// UpdateSomeTask provides a mock function with given fields: id, task
-func (_m *TaskUsecase) UpdateSomeTask(id primitive.ObjectID, task map[string]interface{}) error {
-	ret := _m.Called(id, task)
+// UpdateSomeTask provides a mock function with given fields: ctx, id, task
+func (_m *TaskUsecase) UpdateSomeTask(ctx context.Context, id primitive.ObjectID, task map[string]interface{}) error {
+	ret := _m.Called(ctx, id, task)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateSomeTask")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(primitive.ObjectID, map[string]interface{}) error); ok {
-		r0 = rf(id, task)
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID, map[string]interface{}) error); ok {
+		r0 = rf(ctx, id, task)
 	} else {
 		r0 = ret.Error(0)
 	}
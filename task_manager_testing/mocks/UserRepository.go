@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	domain "task_manager_testing/domain"
 
 	mock "github.com/stretchr/testify/mock"
@@ -15,17 +17,17 @@ type UserRepository struct {
 	mock.Mock
 }
 
-// DeleteUser provides a mock function with given fields: id
-func (_m *UserRepository) DeleteUser(id primitive.ObjectID) error {
-	ret := _m.Called(id)
+// DeleteUser provides a mock function with given fields: ctx, id
+func (_m *UserRepository) DeleteUser(ctx context.Context, id primitive.ObjectID) error {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for DeleteUser")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(primitive.ObjectID) error); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) error); ok {
+		r0 = rf(ctx, id)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -33,9 +35,9 @@ func (_m *UserRepository) DeleteUser(id primitive.ObjectID) error {
 	return r0
 }
 
-// GetAllUsers provides a mock function with given fields:
-func (_m *UserRepository) GetAllUsers() ([]domain.User, error) {
-	ret := _m.Called()
+// GetAllUsers provides a mock function with given fields: ctx
+func (_m *UserRepository) GetAllUsers(ctx context.Context) ([]domain.User, error) {
+	ret := _m.Called(ctx)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetAllUsers")
@@ -43,19 +45,47 @@ func (_m *UserRepository) GetAllUsers() ([]domain.User, error) {
 
 	var r0 []domain.User
 	var r1 error
-	if rf, ok := ret.Get(0).(func() ([]domain.User, error)); ok {
-		return rf()
+	if rf, ok := ret.Get(0).(func(context.Context) ([]domain.User, error)); ok {
+		return rf(ctx)
 	}
-	if rf, ok := ret.Get(0).(func() []domain.User); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) []domain.User); ok {
+		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]domain.User)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserByCalendarToken provides a mock function with given fields: ctx, tokenHash
+func (_m *UserRepository) GetUserByCalendarToken(ctx context.Context, tokenHash string) (domain.User, error) {
+	ret := _m.Called(ctx, tokenHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserByCalendarToken")
+	}
+
+	var r0 domain.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.User, error)); ok {
+		return rf(ctx, tokenHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.User); ok {
+		r0 = rf(ctx, tokenHash)
+	} else {
+		r0 = ret.Get(0).(domain.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, tokenHash)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -63,9 +93,9 @@ func (_m *UserRepository) GetAllUsers() ([]domain.User, error) {
 	return r0, r1
 }
 
-// GetUserById provides a mock function with given fields: id
-func (_m *UserRepository) GetUserById(id primitive.ObjectID) (domain.User, error) {
-	ret := _m.Called(id)
+// GetUserById provides a mock function with given fields: ctx, id
+func (_m *UserRepository) GetUserById(ctx context.Context, id primitive.ObjectID) (domain.User, error) {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetUserById")
@@ -73,17 +103,45 @@ func (_m *UserRepository) GetUserById(id primitive.ObjectID) (domain.User, error
 
 	var r0 domain.User
 	var r1 error
-	if rf, ok := ret.Get(0).(func(primitive.ObjectID) (domain.User, error)); ok {
-		return rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) (domain.User, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) domain.User); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(domain.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, primitive.ObjectID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserByUsername provides a mock function with given fields: ctx, username
+func (_m *UserRepository) GetUserByUsername(ctx context.Context, username string) (domain.User, error) {
+	ret := _m.Called(ctx, username)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserByUsername")
+	}
+
+	var r0 domain.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.User, error)); ok {
+		return rf(ctx, username)
 	}
-	if rf, ok := ret.Get(0).(func(primitive.ObjectID) domain.User); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.User); ok {
+		r0 = rf(ctx, username)
 	} else {
 		r0 = ret.Get(0).(domain.User)
 	}
 
-	if rf, ok := ret.Get(1).(func(primitive.ObjectID) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, username)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -91,9 +149,9 @@ func (_m *UserRepository) GetUserById(id primitive.ObjectID) (domain.User, error
 	return r0, r1
 }
 
-// Login provides a mock function with given fields: username, password
-func (_m *UserRepository) Login(username string, password string) (domain.User, error) {
-	ret := _m.Called(username, password)
+// Login provides a mock function with given fields: ctx, username, password
+func (_m *UserRepository) Login(ctx context.Context, username string, password string) (domain.User, error) {
+	ret := _m.Called(ctx, username, password)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Login")
@@ -101,17 +159,17 @@ func (_m *UserRepository) Login(username string, password string) (domain.User,
 
 	var r0 domain.User
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, string) (domain.User, error)); ok {
-		return rf(username, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (domain.User, error)); ok {
+		return rf(ctx, username, password)
 	}
-	if rf, ok := ret.Get(0).(func(string, string) domain.User); ok {
-		r0 = rf(username, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) domain.User); ok {
+		r0 = rf(ctx, username, password)
 	} else {
 		r0 = ret.Get(0).(domain.User)
 	}
 
-	if rf, ok := ret.Get(1).(func(string, string) error); ok {
-		r1 = rf(username, password)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, username, password)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -119,17 +177,17 @@ func (_m *UserRepository) Login(username string, password string) (domain.User,
 	return r0, r1
 }
 
-// RegisterUser provides a mock function with given fields: username, password, role
-func (_m *UserRepository) RegisterUser(username string, password string, role string) error {
-	ret := _m.Called(username, password, role)
+// RegisterUser provides a mock function with given fields: ctx, username, password, role
+func (_m *UserRepository) RegisterUser(ctx context.Context, username string, password string, role string) error {
+	ret := _m.Called(ctx, username, password, role)
 
 	if len(ret) == 0 {
 		panic("no return value specified for RegisterUser")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
-		r0 = rf(username, password, role)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(ctx, username, password, role)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -137,17 +195,17 @@ func (_m *UserRepository) RegisterUser(username string, password string, role st
 	return r0
 }
 
-// UpdateUser provides a mock function with given fields: id, user
-func (_m *UserRepository) UpdateUser(id primitive.ObjectID, user domain.User) error {
-	ret := _m.Called(id, user)
+// UpdateUser provides a mock function with given fields: ctx, id, user
+func (_m *UserRepository) UpdateUser(ctx context.Context, id primitive.ObjectID, user domain.User) error {
+	ret := _m.Called(ctx, id, user)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateUser")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(primitive.ObjectID, domain.User) error); ok {
-		r0 = rf(id, user)
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID, domain.User) error); ok {
+		r0 = rf(ctx, id, user)
 	} else {
 		r0 = ret.Error(0)
 	}
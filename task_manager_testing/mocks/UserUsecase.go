@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	domain "task_manager_testing/domain"
 
 	mock "github.com/stretchr/testify/mock"
@@ -15,17 +17,35 @@ type UserUsecase struct {
 	mock.Mock
 }
 
-// DeleteUser provides a mock function with given fields: id
-func (_m *UserUsecase) DeleteUser(id primitive.ObjectID) error {
-	ret := _m.Called(id)
+// ChangePassword provides a mock function with given fields: ctx, id, oldPassword, newPassword
+func (_m *UserUsecase) ChangePassword(ctx context.Context, id primitive.ObjectID, oldPassword string, newPassword string) error {
+	ret := _m.Called(ctx, id, oldPassword, newPassword)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChangePassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID, string, string) error); ok {
+		r0 = rf(ctx, id, oldPassword, newPassword)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteUser provides a mock function with given fields: ctx, id
+func (_m *UserUsecase) DeleteUser(ctx context.Context, id primitive.ObjectID) error {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for DeleteUser")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(primitive.ObjectID) error); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) error); ok {
+		r0 = rf(ctx, id)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -33,9 +53,9 @@ func (_m *UserUsecase) DeleteUser(id primitive.ObjectID) error {
 	return r0
 }
 
-// GetAllUsers provides a mock function with given fields:
-func (_m *UserUsecase) GetAllUsers() ([]domain.User, error) {
-	ret := _m.Called()
+// GetAllUsers provides a mock function with given fields: ctx
+func (_m *UserUsecase) GetAllUsers(ctx context.Context) ([]domain.User, error) {
+	ret := _m.Called(ctx)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetAllUsers")
@@ -43,19 +63,19 @@ func (_m *UserUsecase) GetAllUsers() ([]domain.User, error) {
 
 	var r0 []domain.User
 	var r1 error
-	if rf, ok := ret.Get(0).(func() ([]domain.User, error)); ok {
-		return rf()
+	if rf, ok := ret.Get(0).(func(context.Context) ([]domain.User, error)); ok {
+		return rf(ctx)
 	}
-	if rf, ok := ret.Get(0).(func() []domain.User); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) []domain.User); ok {
+		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]domain.User)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -63,9 +83,37 @@ func (_m *UserUsecase) GetAllUsers() ([]domain.User, error) {
 	return r0, r1
 }
 
-// GetUserById provides a mock function with given fields: id
-func (_m *UserUsecase) GetUserById(id primitive.ObjectID) (domain.User, error) {
-	ret := _m.Called(id)
+// GetUserByCalendarToken provides a mock function with given fields: ctx, token
+func (_m *UserUsecase) GetUserByCalendarToken(ctx context.Context, token string) (domain.User, error) {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserByCalendarToken")
+	}
+
+	var r0 domain.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.User, error)); ok {
+		return rf(ctx, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.User); ok {
+		r0 = rf(ctx, token)
+	} else {
+		r0 = ret.Get(0).(domain.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserById provides a mock function with given fields: ctx, id
+func (_m *UserUsecase) GetUserById(ctx context.Context, id primitive.ObjectID) (domain.User, error) {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetUserById")
@@ -73,17 +121,17 @@ func (_m *UserUsecase) GetUserById(id primitive.ObjectID) (domain.User, error) {
 
 	var r0 domain.User
 	var r1 error
-	if rf, ok := ret.Get(0).(func(primitive.ObjectID) (domain.User, error)); ok {
-		return rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) (domain.User, error)); ok {
+		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(primitive.ObjectID) domain.User); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) domain.User); ok {
+		r0 = rf(ctx, id)
 	} else {
 		r0 = ret.Get(0).(domain.User)
 	}
 
-	if rf, ok := ret.Get(1).(func(primitive.ObjectID) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(context.Context, primitive.ObjectID) error); ok {
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -91,9 +139,9 @@ func (_m *UserUsecase) GetUserById(id primitive.ObjectID) (domain.User, error) {
 	return r0, r1
 }
 
-// Login provides a mock function with given fields: username, password
-func (_m *UserUsecase) Login(username string, password string) (domain.User, error) {
-	ret := _m.Called(username, password)
+// Login provides a mock function with given fields: ctx, username, password
+func (_m *UserUsecase) Login(ctx context.Context, username string, password string) (domain.User, error) {
+	ret := _m.Called(ctx, username, password)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Login")
@@ -101,17 +149,17 @@ func (_m *UserUsecase) Login(username string, password string) (domain.User, err
 
 	var r0 domain.User
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, string) (domain.User, error)); ok {
-		return rf(username, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (domain.User, error)); ok {
+		return rf(ctx, username, password)
 	}
-	if rf, ok := ret.Get(0).(func(string, string) domain.User); ok {
-		r0 = rf(username, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) domain.User); ok {
+		r0 = rf(ctx, username, password)
 	} else {
 		r0 = ret.Get(0).(domain.User)
 	}
 
-	if rf, ok := ret.Get(1).(func(string, string) error); ok {
-		r1 = rf(username, password)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, username, password)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -119,17 +167,17 @@ func (_m *UserUsecase) Login(username string, password string) (domain.User, err
 	return r0, r1
 }
 
-// RegisterUser provides a mock function with given fields: username, password, role
-func (_m *UserUsecase) RegisterUser(username string, password string, role string) error {
-	ret := _m.Called(username, password, role)
+// RegisterUser provides a mock function with given fields: ctx, username, password, role
+func (_m *UserUsecase) RegisterUser(ctx context.Context, username string, password string, role string) error {
+	ret := _m.Called(ctx, username, password, role)
 
 	if len(ret) == 0 {
 		panic("no return value specified for RegisterUser")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
-		r0 = rf(username, password, role)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(ctx, username, password, role)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -137,17 +185,63 @@ func (_m *UserUsecase) RegisterUser(username string, password string, role strin
 	return r0
 }
 
-// UpdateUser provides a mock function with given fields: id, user
-func (_m *UserUsecase) UpdateUser(id primitive.ObjectID, user domain.User) error {
-	ret := _m.Called(id, user)
+// RevokeCalendarToken provides a mock function with given fields: ctx, id
+func (_m *UserUsecase) RevokeCalendarToken(ctx context.Context, id primitive.ObjectID) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeCalendarToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RotateCalendarToken provides a mock function with given fields: ctx, id
+func (_m *UserUsecase) RotateCalendarToken(ctx context.Context, id primitive.ObjectID) (string, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RotateCalendarToken")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) (string, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) string); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, primitive.ObjectID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateUser provides a mock function with given fields: ctx, id, user
+func (_m *UserUsecase) UpdateUser(ctx context.Context, id primitive.ObjectID, user domain.User) error {
+	ret := _m.Called(ctx, id, user)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateUser")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(primitive.ObjectID, domain.User) error); ok {
-		r0 = rf(id, user)
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID, domain.User) error); ok {
+		r0 = rf(ctx, id, user)
 	} else {
 		r0 = ret.Error(0)
 	}